@@ -0,0 +1,326 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\archive.go
+ * @Description: 轮转文件归档上传：作为轮转后置钩子将已关闭的轮转文件上传到对象存储（S3 兼容协议、GCS、阿里云 OSS）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultArchivePrefix  = "{host}/{year}/{month}/{day}/" // 默认对象键前缀模板
+	DefaultArchiveTimeout = 5 * time.Minute                // 默认单次上传超时
+	DefaultArchiveRetries = 2                              // 默认失败重试次数
+)
+
+// ArchiveUploader 对象存储上传接口，可基于各云厂商 SDK 实现，内置 S3Uploader
+type ArchiveUploader interface {
+	Upload(ctx context.Context, key string, body io.ReadSeeker, size int64) error
+}
+
+// ArchiveUploaderFunc 函数形式的上传器
+type ArchiveUploaderFunc func(ctx context.Context, key string, body io.ReadSeeker, size int64) error
+
+// Upload 实现 ArchiveUploader 接口
+func (f ArchiveUploaderFunc) Upload(ctx context.Context, key string, body io.ReadSeeker, size int64) error {
+	return f(ctx, key, body, size)
+}
+
+// Archiver 轮转文件归档上传器：
+//
+//	archiver := logger.NewArchiver(logger.NewS3Uploader(logger.S3Config{...}),
+//		logger.WithArchivePrefix("logs/{host}/{date}/"),
+//		logger.WithArchiveDelete(true),
+//	)
+//	w := logger.NewRotateWriter(logger.WithFilePath("/var/log/app.log"), logger.WithCompress(true), logger.WithRotateHook(archiver.Hook()))
+//
+// 对象键为 前缀 + 文件修改时间（UTC，纳秒精度）+ "-" + 文件名，如 logs/web-1/2026-10-15/20261015T080000.123456789Z-app.log.1.gz
+// 前缀模板支持 {host}、{date}、{year}、{month}、{day}、{hour}
+type Archiver struct {
+	uploader ArchiveUploader
+	prefix   string
+	delete   bool
+	checksum bool
+	timeout  time.Duration
+	retries  int
+	host     string
+
+	uploaded atomic.Int64
+	failed   atomic.Int64
+}
+
+// ArchiverOption 归档上传器配置选项
+type ArchiverOption func(*Archiver)
+
+// WithArchivePrefix 设置对象键前缀模板（默认 DefaultArchivePrefix）
+func WithArchivePrefix(prefix string) ArchiverOption {
+	return func(a *Archiver) {
+		a.prefix = prefix
+	}
+}
+
+// WithArchiveDelete 设置上传成功后是否删除本地文件
+func WithArchiveDelete(enabled bool) ArchiverOption {
+	return func(a *Archiver) {
+		a.delete = enabled
+	}
+}
+
+// WithArchiveChecksum 设置是否同时上传校验和文件（存在时，默认开启）
+func WithArchiveChecksum(enabled bool) ArchiverOption {
+	return func(a *Archiver) {
+		a.checksum = enabled
+	}
+}
+
+// WithArchiveTimeout 设置单次上传超时（默认 DefaultArchiveTimeout）
+func WithArchiveTimeout(timeout time.Duration) ArchiverOption {
+	return func(a *Archiver) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithArchiveRetries 设置失败重试次数（默认 DefaultArchiveRetries）
+func WithArchiveRetries(retries int) ArchiverOption {
+	return func(a *Archiver) {
+		if retries >= 0 {
+			a.retries = retries
+		}
+	}
+}
+
+// WithArchiveHost 设置模板中 {host} 的值（默认主机名）
+func WithArchiveHost(host string) ArchiverOption {
+	return func(a *Archiver) {
+		a.host = host
+	}
+}
+
+// NewArchiver 创建归档上传器
+func NewArchiver(uploader ArchiveUploader, opts ...ArchiverOption) *Archiver {
+	a := &Archiver{
+		uploader: uploader,
+		prefix:   DefaultArchivePrefix,
+		checksum: true,
+		timeout:  DefaultArchiveTimeout,
+		retries:  DefaultArchiveRetries,
+	}
+	a.host, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Hook 返回轮转后置钩子，失败时保留本地文件并上报内部错误（组件 archive）
+func (a *Archiver) Hook() RotateHook {
+	return func(path string) {
+		if _, err := a.Archive(context.Background(), path); err != nil {
+			reportInternalError("archive", err)
+		}
+	}
+}
+
+// Archive 上传文件（及其校验和文件），返回对象键；开启删除时上传成功后删除本地文件
+func (a *Archiver) Archive(ctx context.Context, path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		a.failed.Add(1)
+		return "", err
+	}
+	key := a.Key(path, info.ModTime())
+	if err := a.upload(ctx, key, path); err != nil {
+		a.failed.Add(1)
+		return key, err
+	}
+
+	sidecar := path + ChecksumExt
+	hasSidecar := false
+	if a.checksum {
+		if _, err := os.Stat(sidecar); err == nil {
+			hasSidecar = true
+			if err := a.upload(ctx, key+ChecksumExt, sidecar); err != nil {
+				a.failed.Add(1)
+				return key, err
+			}
+		}
+	}
+	a.uploaded.Add(1)
+
+	if a.delete {
+		if err := os.Remove(path); err != nil {
+			return key, err
+		}
+		if hasSidecar {
+			os.Remove(sidecar)
+		}
+	}
+	return key, nil
+}
+
+// Key 计算文件的对象键
+func (a *Archiver) Key(path string, modTime time.Time) string {
+	t := modTime.UTC()
+	prefix := strings.NewReplacer(
+		"{host}", a.host,
+		"{date}", t.Format("2006-01-02"),
+		"{year}", t.Format("2006"),
+		"{month}", t.Format("01"),
+		"{day}", t.Format("02"),
+		"{hour}", t.Format("15"),
+	).Replace(a.prefix)
+	return prefix + t.Format("20060102T150405.000000000Z") + "-" + filepath.Base(path)
+}
+
+// upload 上传单个文件（失败时按次数递增退避重试）
+func (a *Archiver) upload(ctx context.Context, key, path string) error {
+	var err error
+	for attempt := 0; attempt <= a.retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if err = a.uploadOnce(ctx, key, path); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("archive %s to %s: %w", path, key, err)
+}
+
+// uploadOnce 执行一次上传
+func (a *Archiver) uploadOnce(ctx context.Context, key, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+	return a.uploader.Upload(ctx, key, f, info.Size())
+}
+
+// Stats 返回上传成功与失败的文件数
+func (a *Archiver) Stats() (uploaded, failed int64) {
+	return a.uploaded.Load(), a.failed.Load()
+}
+
+// ============================================================================
+// S3 兼容协议上传器（AWS Signature V4）
+// ============================================================================
+
+// S3Config S3 兼容对象存储配置
+//   - AWS S3：Endpoint 为 https://s3.<region>.amazonaws.com
+//   - MinIO 等自建服务：Endpoint 为服务地址，通常开启 PathStyle
+//   - GCS：Endpoint 为 https://storage.googleapis.com，使用 HMAC 密钥，Region 为 auto
+//   - 阿里云 OSS：Endpoint 为 https://oss-<region>.aliyuncs.com（S3 兼容接口）
+type S3Config struct {
+	Endpoint     string `json:"endpoint" yaml:"endpoint"`
+	Region       string `json:"region" yaml:"region"` // 默认 us-east-1
+	Bucket       string `json:"bucket" yaml:"bucket"`
+	AccessKey    string `json:"access_key" yaml:"access_key"`
+	SecretKey    string `json:"secret_key" yaml:"secret_key"`
+	SessionToken string `json:"session_token" yaml:"session_token"`
+	PathStyle    bool   `json:"path_style" yaml:"path_style"`       // 使用 endpoint/bucket/key 而非 bucket.endpoint/key
+	StorageClass string `json:"storage_class" yaml:"storage_class"` // 存储类型（如 STANDARD_IA、GLACIER），为空时使用默认
+}
+
+// S3Uploader 基于 PUT Object 的 S3 兼容上传器
+type S3Uploader struct {
+	cfg    S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Uploader 创建 S3 兼容上传器，client 为 nil 时使用默认传输（见 SetDefaultHTTPTransport，超时由 Archiver 控制）
+func NewS3Uploader(cfg S3Config, client *http.Client) *S3Uploader {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	if client == nil {
+		client = newHTTPClient(0)
+	}
+	return &S3Uploader{cfg: cfg, client: client, now: time.Now}
+}
+
+// Upload 实现 ArchiveUploader 接口
+func (u *S3Uploader) Upload(ctx context.Context, key string, body io.ReadSeeker, size int64) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, body); err != nil {
+		return err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	target, err := u.objectURL(key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), io.NopCloser(body))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if u.cfg.StorageClass != "" {
+		req.Header.Set("X-Amz-Storage-Class", u.cfg.StorageClass)
+	}
+	creds := AWSCredentials{AccessKeyID: u.cfg.AccessKey, SecretAccessKey: u.cfg.SecretKey, SessionToken: u.cfg.SessionToken}
+	signAWSV4(req, hex.EncodeToString(h.Sum(nil)), "s3", u.cfg.Region, creds, u.now())
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("put %s responded with status %d: %s", key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// objectURL 计算对象地址
+func (u *S3Uploader) objectURL(key string) (*url.URL, error) {
+	target, err := url.Parse(u.cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	path := "/" + strings.TrimPrefix(key, "/")
+	if u.cfg.PathStyle {
+		path = "/" + u.cfg.Bucket + path
+	} else {
+		target.Host = u.cfg.Bucket + "." + target.Host
+	}
+	target.Path = path
+	target.RawPath = awsEscapePath(path)
+	return target, nil
+}
@@ -0,0 +1,264 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\archive_test.go
+ * @Description: 轮转文件归档上传测试（对象键模板、校验和文件、失败保留与 S3 上传）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeUploader 记录上传的对象，前 failures 次上传返回错误
+type fakeUploader struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	objects  map[string]string
+}
+
+func (u *fakeUploader) Upload(_ context.Context, key string, body io.ReadSeeker, size int64) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.calls++
+	if u.calls <= u.failures {
+		return errors.New("bucket unavailable")
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	if u.objects == nil {
+		u.objects = map[string]string{}
+	}
+	u.objects[key] = string(data)
+	return nil
+}
+
+// keys 返回已上传对象键的文件名部分（去掉前缀与时间戳）
+func (u *fakeUploader) keys() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var names []string
+	for key := range u.objects {
+		names = append(names, key[strings.Index(key, "Z-")+2:])
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestArchiverKey 前缀模板按文件修改时间（UTC）展开
+func TestArchiverKey(t *testing.T) {
+	modTime := time.Date(2026, 10, 15, 8, 30, 0, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		prefix string
+		want   string
+	}{
+		{DefaultArchivePrefix, "web-1/2026/10/15/20261015T003000.123456789Z-app.log.1.gz"},
+		{"logs/{host}/{date}/{hour}/", "logs/web-1/2026-10-15/00/20261015T003000.123456789Z-app.log.1.gz"},
+		{"", "20261015T003000.123456789Z-app.log.1.gz"},
+	}
+	for _, tt := range tests {
+		a := NewArchiver(&fakeUploader{}, WithArchivePrefix(tt.prefix), WithArchiveHost("web-1"))
+		if got := a.Key("/var/log/app.log.1.gz", modTime); got != tt.want {
+			t.Errorf("Key with prefix %q = %q, want %q", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+// TestArchiverArchive 上传文件与校验和文件，失败时重试，重试耗尽后保留本地文件
+func TestArchiverArchive(t *testing.T) {
+	tests := []struct {
+		name      string
+		sidecar   bool
+		opts      []ArchiverOption
+		failures  int
+		wantErr   bool
+		wantKeys  []string
+		wantLocal bool
+	}{
+		{"plain", false, nil, 0, false, []string{"app.log.1"}, true},
+		{"with checksum", true, nil, 0, false, []string{"app.log.1", "app.log.1.sha256"}, true},
+		{"checksum disabled", true, []ArchiverOption{WithArchiveChecksum(false)}, 0, false, []string{"app.log.1"}, true},
+		{"delete after upload", true, []ArchiverOption{WithArchiveDelete(true)}, 0, false, []string{"app.log.1", "app.log.1.sha256"}, false},
+		{"retry then succeed", false, []ArchiverOption{WithArchiveRetries(1)}, 1, false, []string{"app.log.1"}, true},
+		{"failure keeps file", false, []ArchiverOption{WithArchiveRetries(0), WithArchiveDelete(true)}, 1, true, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log.1")
+			if err := os.WriteFile(path, []byte("rotated\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.sidecar {
+				if _, err := WriteChecksumFile(path); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			uploader := &fakeUploader{failures: tt.failures}
+			a := NewArchiver(uploader, tt.opts...)
+			_, err := a.Archive(context.Background(), path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Archive error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := uploader.keys(); strings.Join(got, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("uploaded %v, want %v", got, tt.wantKeys)
+			}
+			if _, err := os.Stat(path); (err == nil) != tt.wantLocal {
+				t.Errorf("local file exists = %v, want %v", err == nil, tt.wantLocal)
+			}
+			uploaded, failed := a.Stats()
+			if tt.wantErr && (uploaded != 0 || failed != 1) || !tt.wantErr && (uploaded != 1 || failed != 0) {
+				t.Errorf("stats uploaded=%d failed=%d", uploaded, failed)
+			}
+		})
+	}
+}
+
+// TestArchiverRotateHook 作为轮转后置钩子上传压缩后的轮转文件与校验和文件，失败时上报内部错误
+func TestArchiverRotateHook(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		wantKeys     []string
+		wantReported int
+	}{
+		{"uploaded and deleted", 0, []string{"app.log.1.gz", "app.log.1.gz.sha256"}, 0},
+		{"upload failure reported", 1, nil, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var reported []InternalError
+			SetInternalErrorHandler(func(e InternalError) {
+				mu.Lock()
+				reported = append(reported, e)
+				mu.Unlock()
+			})
+			defer SetInternalErrorHandler(nil)
+
+			uploader := &fakeUploader{failures: tt.failures}
+			archiver := NewArchiver(uploader, WithArchiveDelete(true), WithArchiveRetries(0))
+			path := filepath.Join(t.TempDir(), "app.log")
+			w := NewRotateWriter(WithFilePath(path), WithMaxSize(16), WithCompress(true), WithRotateChecksum(true), WithRotateHook(archiver.Hook()))
+			for _, line := range []string{"first line\n", "second line\n"} {
+				if _, err := w.Write([]byte(line)); err != nil {
+					t.Fatalf("Write: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			if got := uploader.keys(); strings.Join(got, ",") != strings.Join(tt.wantKeys, ",") {
+				t.Errorf("uploaded %v, want %v", got, tt.wantKeys)
+			}
+			_, err := os.Stat(path + ".1.gz")
+			if kept := err == nil; kept != (tt.wantReported > 0) {
+				t.Errorf("rotated file kept = %v, want kept only on failure", kept)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(reported) != tt.wantReported || (len(reported) > 0 && reported[0].Component != "archive") {
+				t.Errorf("internal errors = %v, want %d archive errors", reported, tt.wantReported)
+			}
+		})
+	}
+}
+
+// TestS3UploaderPut 以 PUT Object 上传并签名，PathStyle 决定 bucket 位置
+func TestS3UploaderPut(t *testing.T) {
+	tests := []struct {
+		name      string
+		pathStyle bool
+		wantPath  string
+		wantHost  string
+	}{
+		{"path style", true, "/logs-bucket/web-1/app.log.1.gz", ""},
+		{"virtual hosted", false, "/web-1/app.log.1.gz", "logs-bucket."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotMethod, gotPath, gotHost, gotAuth, gotClass, gotBody string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotMethod, gotPath, gotHost, gotBody = r.Method, r.URL.Path, r.Host, string(body)
+				gotAuth, gotClass = r.Header.Get("Authorization"), r.Header.Get("X-Amz-Storage-Class")
+			}))
+			defer server.Close()
+
+			client := server.Client()
+			if !tt.pathStyle {
+				// 虚拟主机风格的 bucket.host 无法解析，直接连到测试服务
+				client.Transport = &http.Transport{DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+				}}
+			}
+			u := NewS3Uploader(S3Config{
+				Endpoint:     server.URL,
+				Bucket:       "logs-bucket",
+				AccessKey:    "AKID",
+				SecretKey:    "secret",
+				PathStyle:    tt.pathStyle,
+				StorageClass: "STANDARD_IA",
+			}, client)
+
+			body := strings.NewReader("compressed")
+			if err := u.Upload(context.Background(), "web-1/app.log.1.gz", body, body.Size()); err != nil {
+				t.Fatalf("Upload: %v", err)
+			}
+			if gotMethod != http.MethodPut || gotPath != tt.wantPath || gotBody != "compressed" {
+				t.Errorf("request = %s %s %q", gotMethod, gotPath, gotBody)
+			}
+			if !strings.HasPrefix(gotHost, tt.wantHost) {
+				t.Errorf("host = %q, want prefix %q", gotHost, tt.wantHost)
+			}
+			if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(gotAuth, "/us-east-1/s3/aws4_request") {
+				t.Errorf("Authorization = %q", gotAuth)
+			}
+			if gotClass != "STANDARD_IA" {
+				t.Errorf("storage class = %q", gotClass)
+			}
+		})
+	}
+}
+
+// TestS3UploaderError 非 2xx 响应返回包含状态码的错误
+func TestS3UploaderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	u := NewS3Uploader(S3Config{Endpoint: server.URL, Bucket: "b", PathStyle: true}, nil)
+	body := strings.NewReader("x")
+	err := u.Upload(context.Background(), "k", body, body.Size())
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Errorf("Upload error = %v, want 403 AccessDenied", err)
+	}
+}
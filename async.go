@@ -0,0 +1,390 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\async.go
+ * @Description: 无锁多生产者单消费者环形缓冲与单写入协程
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultAsyncRingSize     = 8192                   // 默认环形缓冲槽位数
+	defaultAsyncBatchSize    = 100                    // 默认单次批量写入条数
+	defaultAsyncBatchTimeout = 100 * time.Millisecond // 默认批量刷新间隔
+)
+
+// lineBufPool 异步模式下日志行的拷贝缓冲池（写入完成后由消费者归还）
+var lineBufPool = newStatPool("async_line", func() any {
+	buf := make([]byte, 0, maxLogMessageSize)
+	return &buf
+})
+
+// ringSlot 环形缓冲槽位
+type ringSlot struct {
+	seq   atomic.Uint64
+	data  *[]byte
+	level LogLevel
+}
+
+// asyncRing 有界无锁 MPSC 队列（Vyukov 算法），生产者之间仅通过 CAS 竞争
+type asyncRing struct {
+	_       [64]byte // 缓存行填充，避免伪共享
+	enqueue atomic.Uint64
+	_       [56]byte
+	dequeue uint64 // 仅由消费者访问
+	mask    uint64
+	slots   []ringSlot
+}
+
+// newAsyncRing 创建环形缓冲（容量向上取整到 2 的幂）
+func newAsyncRing(size int) *asyncRing {
+	capacity := 1
+	for capacity < size {
+		capacity <<= 1
+	}
+
+	r := &asyncRing{
+		mask:  uint64(capacity - 1),
+		slots: make([]ringSlot, capacity),
+	}
+	for i := range r.slots {
+		r.slots[i].seq.Store(uint64(i))
+	}
+	return r
+}
+
+// push 入队，队列已满时返回 false
+func (r *asyncRing) push(level LogLevel, data *[]byte) bool {
+	pos := r.enqueue.Load()
+	for {
+		slot := &r.slots[pos&r.mask]
+		seq := slot.seq.Load()
+		switch diff := int64(seq) - int64(pos); {
+		case diff == 0:
+			if r.enqueue.CompareAndSwap(pos, pos+1) {
+				slot.data = data
+				slot.level = level
+				slot.seq.Store(pos + 1)
+				return true
+			}
+			pos = r.enqueue.Load()
+		case diff < 0:
+			return false
+		default:
+			pos = r.enqueue.Load()
+		}
+	}
+}
+
+// ready 检查队首是否已有可读数据（仅消费者调用）
+func (r *asyncRing) ready() bool {
+	return r.slots[r.dequeue&r.mask].seq.Load() == r.dequeue+1
+}
+
+// pop 出队（仅消费者调用），队列为空时返回 false
+func (r *asyncRing) pop() (*[]byte, LogLevel, bool) {
+	slot := &r.slots[r.dequeue&r.mask]
+	if slot.seq.Load() != r.dequeue+1 {
+		return nil, 0, false
+	}
+	data, level := slot.data, slot.level
+	slot.data = nil
+	slot.seq.Store(r.dequeue + r.mask + 1)
+	r.dequeue++
+	return data, level, true
+}
+
+// writerHolder 包装 io.Writer 以便存入 atomic.Value
+type writerHolder struct {
+	w io.Writer
+}
+
+// asyncWriter 异步写入器：生产者无锁入队，单个协程批量写出
+type asyncWriter struct {
+	ringSize     int
+	batchSize    int
+	batchTimeout time.Duration
+
+	output   atomic.Value // writerHolder
+	ring     *asyncRing
+	started  atomic.Bool
+	closed   atomic.Bool
+	sleeping atomic.Bool
+	notify   chan struct{}
+	flushReq chan chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+
+	startOnce sync.Once
+	closeOnce sync.Once
+	lateMu    sync.Mutex // 写入协程退出后，生产者补写关闭期间入队的日志时互斥
+
+	batch []byte    // 合并写入缓冲（仅写入协程访问）
+	vecs  [][]byte  // 向量化写入缓冲（仅写入协程访问）
+	lines []*[]byte // 本批次待归还的行缓冲（仅写入协程访问）
+}
+
+// newAsyncWriter 创建异步写入器（首次写入时才启动写入协程）
+func newAsyncWriter(output io.Writer, ringSize, batchSize int, batchTimeout time.Duration) *asyncWriter {
+	w := &asyncWriter{
+		notify:   make(chan struct{}, 1),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	w.configure(ringSize, batchSize, batchTimeout)
+	w.setOutput(output)
+	return w
+}
+
+// configure 更新容量和批量参数（仅在启动前生效）
+func (w *asyncWriter) configure(ringSize, batchSize int, batchTimeout time.Duration) {
+	w.ringSize = ringSize
+	if w.ringSize <= 0 {
+		w.ringSize = defaultAsyncRingSize
+	}
+	w.batchSize = batchSize
+	if w.batchSize <= 0 {
+		w.batchSize = defaultAsyncBatchSize
+	}
+	w.batchTimeout = batchTimeout
+	if w.batchTimeout <= 0 {
+		w.batchTimeout = defaultAsyncBatchTimeout
+	}
+}
+
+// setOutput 切换输出目标（写入协程在下一批次生效）
+func (w *asyncWriter) setOutput(output io.Writer) {
+	w.output.Store(writerHolder{w: output})
+}
+
+// start 启动写入协程
+func (w *asyncWriter) start() {
+	w.startOnce.Do(func() {
+		w.ring = newAsyncRing(w.ringSize)
+		w.started.Store(true)
+		go w.run()
+	})
+}
+
+// write 拷贝日志行并入队，队列满时让出调度等待消费者腾出空间
+// 写入器已关闭（或写入协程已退出）时返回 false，由调用方改为同步写入
+func (w *asyncWriter) write(level LogLevel, line []byte) bool {
+	if w.closed.Load() {
+		return false
+	}
+	w.start()
+	if !w.started.Load() {
+		return false
+	}
+
+	data := lineBufPool.Get().(*[]byte)
+	*data = append((*data)[:0], line...)
+	if !w.ring.push(level, data) {
+		// 队列已满，等待写入协程腾出空间（每次阻塞上报一次溢出事件）
+		reportOverflow(OverflowComponentAsync, OverflowBlocked, w.ringSize)
+		for !w.ring.push(level, data) {
+			select {
+			case <-w.stopped:
+				// 写入协程已退出，不会再腾出空间
+				putAsyncLine(data)
+				return false
+			default:
+			}
+			w.wake()
+			runtime.Gosched()
+		}
+	}
+
+	// 入队后再检查关闭标记：未关闭时，写入协程退出前的最后一次取空一定能取到该行；
+	// 已关闭时该行可能错过最后一次取空，等写入协程退出后由生产者补写
+	if w.closed.Load() {
+		<-w.stopped
+		w.drainLate()
+		return true
+	}
+	if w.sleeping.Load() {
+		w.wake()
+	}
+	return true
+}
+
+// drainLate 写入协程退出后取空队列中关闭期间入队的日志
+func (w *asyncWriter) drainLate() {
+	w.lateMu.Lock()
+	defer w.lateMu.Unlock()
+	w.drain()
+}
+
+// putAsyncLine 归还日志行缓冲，过大的缓冲直接丢弃，避免个别超长日志长期占用池内存
+func putAsyncLine(data *[]byte) {
+	if cap(*data) > maxPooledBufferSize {
+		return
+	}
+	lineBufPool.Put(data)
+}
+
+// wake 唤醒休眠中的写入协程
+func (w *asyncWriter) wake() {
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+}
+
+// run 写入协程主循环
+func (w *asyncWriter) run() {
+	defer close(w.stopped)
+
+	w.batch = make([]byte, 0, w.batchSize*maxLogMessageSize/4)
+	w.vecs = make([][]byte, 0, w.batchSize)
+	w.lines = make([]*[]byte, 0, w.batchSize)
+	ticker := time.NewTicker(w.batchTimeout)
+	defer ticker.Stop()
+
+	for {
+		w.drain()
+
+		w.sleeping.Store(true)
+		// 休眠前再检查一次，避免错过刚入队的数据
+		if w.ring.ready() {
+			w.sleeping.Store(false)
+			continue
+		}
+
+		select {
+		case <-w.notify:
+		case <-ticker.C: // 兜底唤醒，防止极端情况下错过通知
+		case reply := <-w.flushReq:
+			w.drain()
+			close(reply)
+		case <-w.done:
+			w.sleeping.Store(false)
+			w.drain()
+			return
+		}
+		w.sleeping.Store(false)
+	}
+}
+
+// drain 取空队列，每 batchSize 条一次写出
+// 输出支持向量化写入时直接以 writev 写出各行，省去合并拷贝
+func (w *asyncWriter) drain() {
+	for {
+		output := w.output.Load().(writerHolder).w
+		if router, ok := output.(*outputRouter); ok {
+			if !w.drainRouted(router) {
+				return
+			}
+			continue
+		}
+		if supportsVectors(output) {
+			if !w.drainVectors(output) {
+				return
+			}
+			continue
+		}
+
+		w.batch = w.batch[:0]
+		count := 0
+		for count < w.batchSize {
+			data, _, ok := w.ring.pop()
+			if !ok {
+				break
+			}
+			w.batch = append(w.batch, *data...)
+			putAsyncLine(data)
+			count++
+		}
+		if count == 0 {
+			return
+		}
+		if _, err := output.Write(w.batch); err != nil {
+			reportInternalError("output", err)
+		}
+	}
+}
+
+// drainVectors 取出一批日志行并以向量化方式写出，队列为空时返回 false
+func (w *asyncWriter) drainVectors(output io.Writer) bool {
+	w.vecs = w.vecs[:0]
+	w.lines = w.lines[:0]
+	for len(w.lines) < w.batchSize {
+		data, _, ok := w.ring.pop()
+		if !ok {
+			break
+		}
+		w.lines = append(w.lines, data)
+		w.vecs = append(w.vecs, *data)
+	}
+	if len(w.lines) == 0 {
+		return false
+	}
+
+	if _, err := WriteVectors(output, w.vecs); err != nil {
+		reportInternalError("output", err)
+	}
+	for i, data := range w.lines {
+		putAsyncLine(data)
+		w.lines[i] = nil
+		w.vecs[i] = nil
+	}
+	return true
+}
+
+// drainRouted 取出一批日志行并按级别分发到各输出，队列为空时返回 false
+func (w *asyncWriter) drainRouted(router *outputRouter) bool {
+	count := 0
+	for count < w.batchSize {
+		data, level, ok := w.ring.pop()
+		if !ok {
+			break
+		}
+		if _, err := router.WriteLevel(level, *data); err != nil {
+			reportInternalError("output", err)
+		}
+		putAsyncLine(data)
+		count++
+	}
+	return count > 0
+}
+
+// Flush 等待当前已入队的日志全部写出
+func (w *asyncWriter) Flush() error {
+	if !w.started.Load() {
+		return nil
+	}
+	reply := make(chan struct{})
+	select {
+	case w.flushReq <- reply:
+		<-reply
+	case <-w.stopped:
+	}
+	return nil
+}
+
+// Close 写出剩余日志并停止写入协程
+func (w *asyncWriter) Close() error {
+	w.closeOnce.Do(func() {
+		w.closed.Store(true)
+		// 未启动过则阻止后续启动
+		w.startOnce.Do(func() {})
+		if !w.started.Load() {
+			return
+		}
+		close(w.done)
+		<-w.stopped
+	})
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\async_test.go
+ * @Description: 异步写入器关闭与队列满场景测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer 并发安全的缓冲（异步写入协程与同步回退写入可能同时写入）
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) lines() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Count(b.buf.Bytes(), []byte("\n"))
+}
+
+// TestAsyncWriteRacingClose 与 Close 并发的写入要么在写入协程退出前入队，要么返回 false 由调用方同步写入，不会丢失
+func TestAsyncWriteRacingClose(t *testing.T) {
+	for round := 0; round < 20; round++ {
+		out := &lockedBuffer{}
+		w := newAsyncWriter(out, 16, 4, time.Millisecond)
+
+		const writers, perWriter = 8, 50
+		var wg sync.WaitGroup
+		for g := 0; g < writers; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < perWriter; i++ {
+					if !w.write(INFO, []byte("line\n")) {
+						out.Write([]byte("line\n"))
+					}
+				}
+			}()
+		}
+		time.Sleep(time.Duration(round%4) * 100 * time.Microsecond)
+		w.Close()
+		wg.Wait()
+
+		if got := out.lines(); got != writers*perWriter {
+			t.Fatalf("round %d: got %d lines, want %d", round, got, writers*perWriter)
+		}
+	}
+}
+
+// TestAsyncWriteFullAfterStop 写入协程已退出且队列已满时写入返回 false，而不是无限等待
+func TestAsyncWriteFullAfterStop(t *testing.T) {
+	w := newAsyncWriter(&lockedBuffer{}, 2, 1, time.Millisecond)
+	w.start()
+	close(w.done)
+	<-w.stopped
+
+	done := make(chan int)
+	go func() {
+		accepted := 0
+		for i := 0; i < 3; i++ {
+			if w.write(INFO, []byte("line\n")) {
+				accepted++
+			}
+		}
+		done <- accepted
+	}()
+	select {
+	case accepted := <-done:
+		if accepted != 2 {
+			t.Errorf("accepted %d lines, want 2 (ring capacity)", accepted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write spun on a full ring after the writer goroutine exited")
+	}
+}
+
+// TestAsyncCloneOutputIsolation 子 Logger 修改输出、异步参数或关闭异步写入时不影响共享异步写入器的父 Logger
+func TestAsyncCloneOutputIsolation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(child *Logger, out *lockedBuffer)
+	}{
+		{"WithOutput", func(child *Logger, out *lockedBuffer) { child.WithOutput(out) }},
+		{"WithAsyncWriteFalse", func(child *Logger, out *lockedBuffer) { child.WithAsyncWrite(false).WithOutput(out) }},
+		{"WithBufferSize", func(child *Logger, out *lockedBuffer) { child.WithBufferSize(64).WithOutput(out) }},
+		{"Close", func(child *Logger, out *lockedBuffer) { child.Close(); child.WithOutput(out) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parentOut, childOut := &lockedBuffer{}, &lockedBuffer{}
+			parent := NewLogger().WithOutput(parentOut).WithColorful(false).WithFormat(FormatText).WithAsyncWrite(true)
+			defer parent.Close()
+			parent.InfoMsg("before")
+
+			child := parent.With("child", true)
+			tt.modify(child, childOut)
+			defer child.Close()
+
+			const rounds = 50
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					parent.InfoMsg("parent")
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					child.InfoMsg("child")
+				}
+			}()
+			wg.Wait()
+			parent.Flush()
+			child.Flush()
+
+			if got := parentOut.lines(); got != rounds+1 {
+				t.Errorf("parent output has %d lines, want %d", got, rounds+1)
+			}
+			if got := childOut.lines(); got != rounds {
+				t.Errorf("child output has %d lines, want %d", got, rounds)
+			}
+			if parent.async == nil || parent.async.closed.Load() {
+				t.Error("parent async writer was stopped by the child")
+			}
+		})
+	}
+}
@@ -0,0 +1,343 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\audit.go
+ * @Description: 防篡改审计日志（哈希链 + 可选 HMAC 签名，仅追加写入）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// auditGenesisHash 链首记录的前序哈希
+var auditGenesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+var (
+	ErrAuditChainBroken   = errors.New("audit chain broken")
+	ErrAuditHashMismatch  = errors.New("audit record hash mismatch")
+	ErrAuditBadSignature  = errors.New("audit record signature invalid")
+	ErrAuditLoggerClosed  = errors.New("audit logger closed")
+	ErrAuditSeqOutOfOrder = errors.New("audit record sequence out of order")
+	ErrAuditTruncated     = errors.New("audit log does not start at the expected record")
+)
+
+// AuditRecord 审计记录（每行一条 JSON）
+// Hash = SHA256(不含 Hash/Signature 的记录 JSON)，PrevHash 指向上一条记录的 Hash，
+// 任意一条被修改、删除或插入都会导致后续校验失败
+type AuditRecord struct {
+	Seq       uint64         `json:"seq"`
+	Timestamp time.Time      `json:"ts"`
+	User      string         `json:"user"`
+	Action    string         `json:"action"`
+	Resource  string         `json:"resource"`
+	Result    string         `json:"result"`
+	Fields    map[string]any `json:"fields,omitempty"`
+	PrevHash  string         `json:"prev_hash"`
+	Hash      string         `json:"hash,omitempty"`
+	Signature string         `json:"sig,omitempty"`
+}
+
+// computeHash 计算记录哈希（忽略 Hash 和 Signature 字段）
+func (r *AuditRecord) computeHash() (string, error) {
+	unsigned := *r
+	unsigned.Hash = ""
+	unsigned.Signature = ""
+	data, err := json.Marshal(&unsigned)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// auditSign 计算记录哈希的 HMAC 签名
+func auditSign(key []byte, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(hash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AuditLogger 审计日志记录器
+type AuditLogger struct {
+	mu       sync.Mutex
+	output   io.Writer
+	file     *os.File
+	hmacKey  []byte
+	sync     bool
+	seq      uint64
+	prevHash string
+	closed   bool
+}
+
+// AuditLoggerOption 审计日志记录器配置选项
+type AuditLoggerOption func(*AuditLogger)
+
+// WithAuditHMACKey 设置 HMAC 签名密钥（为空则不签名）
+func WithAuditHMACKey(key []byte) AuditLoggerOption {
+	return func(a *AuditLogger) {
+		a.hmacKey = append([]byte(nil), key...)
+	}
+}
+
+// WithAuditSync 设置每条记录写入后是否立即落盘
+func WithAuditSync(sync bool) AuditLoggerOption {
+	return func(a *AuditLogger) {
+		a.sync = sync
+	}
+}
+
+// NewAuditLogger 以仅追加模式打开审计日志文件，已有文件会从最后一条记录继续哈希链
+func NewAuditLogger(path string, opts ...AuditLoggerOption) (*AuditLogger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), DefaultDirPermission); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, DefaultFilePermission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+
+	a := newAuditLogger(file, opts...)
+	a.file = file
+
+	last, err := readLastLine(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read audit file: %w", err)
+	}
+	if len(last) > 0 {
+		var record AuditRecord
+		if err := json.Unmarshal(last, &record); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to resume audit chain: %w", err)
+		}
+		a.seq = record.Seq
+		a.prevHash = record.Hash
+	}
+	return a, nil
+}
+
+// NewAuditLoggerWriter 使用任意输出创建审计日志记录器（从链首开始）
+func NewAuditLoggerWriter(output io.Writer, opts ...AuditLoggerOption) *AuditLogger {
+	return newAuditLogger(output, opts...)
+}
+
+func newAuditLogger(output io.Writer, opts ...AuditLoggerOption) *AuditLogger {
+	a := &AuditLogger{
+		output:   output,
+		prevHash: auditGenesisHash,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Log 追加一条审计记录
+func (a *AuditLogger) Log(action, user, resource, result string, fields map[string]any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return ErrAuditLoggerClosed
+	}
+
+	normalized, err := normalizeAuditFields(fields)
+	if err != nil {
+		return err
+	}
+
+	record := AuditRecord{
+		Seq:       a.seq + 1,
+		Timestamp: time.Now().UTC(),
+		User:      user,
+		Action:    action,
+		Resource:  resource,
+		Result:    result,
+		Fields:    normalized,
+		PrevHash:  a.prevHash,
+	}
+	hash, err := record.computeHash()
+	if err != nil {
+		return err
+	}
+	record.Hash = hash
+	if len(a.hmacKey) > 0 {
+		record.Signature = auditSign(a.hmacKey, hash)
+	}
+
+	line, err := json.Marshal(&record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := a.output.Write(line); err != nil {
+		return err
+	}
+	if a.sync && a.file != nil {
+		if err := a.file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	a.seq = record.Seq
+	a.prevHash = hash
+	return nil
+}
+
+// normalizeAuditFields 将字段经 JSON 往返规范化（结构体转为按键排序的 map），
+// 保证写入时计算的哈希与校验时重新解析后计算的一致
+func normalizeAuditFields(fields map[string]any) (map[string]any, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// LastHash 获取最后一条记录的序号与哈希（可定期外部存证作为锚点，配合 VerifyAuditLogFrom 使用）
+func (a *AuditLogger) LastHash() (uint64, string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.seq, a.prevHash
+}
+
+// Close 关闭审计日志
+func (a *AuditLogger) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	if a.file != nil {
+		a.file.Sync()
+		return a.file.Close()
+	}
+	return nil
+}
+
+// VerifyAuditLog 校验审计日志的哈希链与签名，返回校验通过的记录数
+// 第一条记录必须是链首（Seq 为 1 且 PrevHash 为创世哈希），开头被截掉的日志返回 ErrAuditTruncated；
+// hmacKey 为空时跳过签名校验；出错时返回的计数为出错记录之前的记录数
+func VerifyAuditLog(r io.Reader, hmacKey []byte) (int, error) {
+	return VerifyAuditLogFrom(r, hmacKey, 0, auditGenesisHash)
+}
+
+// VerifyAuditLogFrom 从外部存证的锚点（LastHash 返回的 seq 与 hash）开始校验：
+// 第一条记录必须紧接锚点（Seq 为 seq+1 且 PrevHash 为 hash），用于校验轮转或归档后的后续日志段
+func VerifyAuditLogFrom(r io.Reader, hmacKey []byte, seq uint64, hash string) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	prevHash := hash
+	prevSeq := seq
+	count := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		// 数字按原文保留，避免大整数经 float64 往返后哈希不一致
+		var record AuditRecord
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		if err := decoder.Decode(&record); err != nil {
+			return count, fmt.Errorf("record %d: %w", count+1, err)
+		}
+		if count == 0 && (record.Seq != prevSeq+1 || record.PrevHash != prevHash) {
+			return count, fmt.Errorf("record 1 (seq %d): %w", record.Seq, ErrAuditTruncated)
+		}
+		if record.Seq != prevSeq+1 {
+			return count, fmt.Errorf("record %d: %w", count+1, ErrAuditSeqOutOfOrder)
+		}
+		if record.PrevHash != prevHash {
+			return count, fmt.Errorf("record %d: %w", count+1, ErrAuditChainBroken)
+		}
+		hash, err := record.computeHash()
+		if err != nil {
+			return count, fmt.Errorf("record %d: %w", count+1, err)
+		}
+		if hash != record.Hash {
+			return count, fmt.Errorf("record %d: %w", count+1, ErrAuditHashMismatch)
+		}
+		if len(hmacKey) > 0 && !hmac.Equal([]byte(auditSign(hmacKey, hash)), []byte(record.Signature)) {
+			return count, fmt.Errorf("record %d: %w", count+1, ErrAuditBadSignature)
+		}
+
+		prevHash = record.Hash
+		prevSeq = record.Seq
+		count++
+	}
+	return count, scanner.Err()
+}
+
+// readLastLine 从文件末尾读取最后一个非空行
+func readLastLine(file *os.File) ([]byte, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	const chunkSize = 4096
+	var tail []byte
+	for offset := info.Size(); offset > 0; {
+		size := int64(chunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+
+		chunk := make([]byte, size)
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		tail = append(chunk, tail...)
+
+		trimmed := bytes.TrimRight(tail, "\r\n")
+		if idx := bytes.LastIndexByte(trimmed, '\n'); idx >= 0 {
+			return trimmed[idx+1:], nil
+		}
+		if offset == 0 {
+			return trimmed, nil
+		}
+	}
+	return nil, nil
+}
+
+// ============================================================================
+// Logger 集成
+// ============================================================================
+
+// WithAuditLogger 设置审计日志通道，Audit 调用会同时写入防篡改的审计链（不受日志级别影响）
+func (l *Logger) WithAuditLogger(audit *AuditLogger) *Logger {
+	l.auditLogger = audit
+	return l
+}
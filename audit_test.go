@@ -0,0 +1,127 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\audit_test.go
+ * @Description: 审计日志哈希链校验测试（篡改、删除、截断、锚点续验）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testAuditKey = []byte("audit-test-key")
+
+// writeAuditRecords 写入 n 条审计记录，返回按行拆分的日志
+func writeAuditRecords(t *testing.T, n int) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	a := NewAuditLoggerWriter(&buf, WithAuditHMACKey(testAuditKey))
+	for i := 1; i <= n; i++ {
+		fields := map[string]any{"index": i, "big": uint64(1) << 60}
+		if err := a.Log("update", "alice", fmt.Sprintf("doc/%d", i), "ok", fields); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return strings.SplitAfter(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	lines := writeAuditRecords(t, 5)
+	tampered := append([]string(nil), lines...)
+	tampered[2] = strings.Replace(tampered[2], `"alice"`, `"mallory"`, 1)
+
+	cases := []struct {
+		name      string
+		lines     []string
+		key       []byte
+		wantCount int
+		wantErr   error
+	}{
+		{"intact", lines, testAuditKey, 5, nil},
+		{"intact without key", lines, nil, 5, nil},
+		{"tampered record", tampered, testAuditKey, 2, ErrAuditHashMismatch},
+		{"deleted record", append(append([]string(nil), lines[:2]...), lines[3:]...), testAuditKey, 2, ErrAuditSeqOutOfOrder},
+		{"reordered records", []string{lines[0], lines[2], lines[1]}, testAuditKey, 1, ErrAuditSeqOutOfOrder},
+		{"head truncated", lines[2:], testAuditKey, 0, ErrAuditTruncated},
+		{"wrong key", lines, []byte("other-key"), 0, ErrAuditBadSignature},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			count, err := VerifyAuditLog(strings.NewReader(strings.Join(tc.lines, "")), tc.key)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if count != tc.wantCount {
+				t.Errorf("count = %d, want %d", count, tc.wantCount)
+			}
+		})
+	}
+}
+
+// TestVerifyAuditLogFrom 从外部存证的锚点续验后续日志段，锚点之后的首条记录缺失时报告截断
+func TestVerifyAuditLogFrom(t *testing.T) {
+	var first, second bytes.Buffer
+	a := NewAuditLoggerWriter(&first)
+	for i := 0; i < 3; i++ {
+		if err := a.Log("login", "bob", "session", "ok", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seq, hash := a.LastHash()
+	a.output = &second
+	for i := 0; i < 3; i++ {
+		if err := a.Log("logout", "bob", "session", "ok", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count, err := VerifyAuditLogFrom(bytes.NewReader(second.Bytes()), nil, seq, hash); err != nil || count != 3 {
+		t.Fatalf("VerifyAuditLogFrom = %d, %v; want 3, nil", count, err)
+	}
+	if _, err := VerifyAuditLog(bytes.NewReader(second.Bytes()), nil); !errors.Is(err, ErrAuditTruncated) {
+		t.Errorf("segment verified without anchor: err = %v", err)
+	}
+	rest := second.Bytes()[bytes.IndexByte(second.Bytes(), '\n')+1:]
+	if _, err := VerifyAuditLogFrom(bytes.NewReader(rest), nil, seq, hash); !errors.Is(err, ErrAuditTruncated) {
+		t.Errorf("missing first record after anchor: err = %v, want ErrAuditTruncated", err)
+	}
+}
+
+// TestAuditLoggerResume 重新打开已有审计文件时从最后一条记录继续哈希链
+func TestAuditLoggerResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for i := 0; i < 2; i++ {
+		a, err := NewAuditLogger(path, WithAuditHMACKey(testAuditKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 3; j++ {
+			if err := a.Log("delete", "carol", "bucket", "denied", map[string]any{"run": i}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := a.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if count, err := VerifyAuditLog(f, testAuditKey); err != nil || count != 6 {
+		t.Fatalf("VerifyAuditLog = %d, %v; want 6, nil", count, err)
+	}
+}
@@ -0,0 +1,251 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\aws.go
+ * @Description: AWS 凭证（环境变量、ECS 任务角色）与 Signature V4 签名，供 S3 归档与 CloudWatch Logs 使用
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrAWSCredentialsNotFound 未找到 AWS 凭证
+var ErrAWSCredentialsNotFound = errors.New("logger: aws credentials not found")
+
+// AWSCredentials AWS 访问凭证
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expires         time.Time // 零值表示不过期
+}
+
+// AWSCredentialsProvider 获取 AWS 凭证（可能被频繁调用，实现方应自行缓存）
+type AWSCredentialsProvider func(ctx context.Context) (AWSCredentials, error)
+
+// StaticAWSCredentials 固定凭证
+func StaticAWSCredentials(accessKeyID, secretAccessKey, sessionToken string) AWSCredentialsProvider {
+	creds := AWSCredentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}
+	return func(context.Context) (AWSCredentials, error) {
+		return creds, nil
+	}
+}
+
+// EnvAWSCredentials 从 AWS_ACCESS_KEY_ID、AWS_SECRET_ACCESS_KEY、AWS_SESSION_TOKEN 读取凭证（Lambda 运行时会设置）
+func EnvAWSCredentials() AWSCredentialsProvider {
+	return func(context.Context) (AWSCredentials, error) {
+		creds := AWSCredentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return creds, ErrAWSCredentialsNotFound
+		}
+		return creds, nil
+	}
+}
+
+// ECSAWSCredentials 从 ECS 容器凭证端点获取任务角色凭证（AWS_CONTAINER_CREDENTIALS_RELATIVE_URI 或
+// AWS_CONTAINER_CREDENTIALS_FULL_URI + AWS_CONTAINER_AUTHORIZATION_TOKEN），过期前 5 分钟刷新
+func ECSAWSCredentials(client *http.Client) AWSCredentialsProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	var (
+		mu     sync.Mutex
+		cached AWSCredentials
+	)
+	return func(ctx context.Context) (AWSCredentials, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cached.AccessKeyID != "" && (cached.Expires.IsZero() || time.Until(cached.Expires) > 5*time.Minute) {
+			return cached, nil
+		}
+
+		endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+		if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+			endpoint = "http://169.254.170.2" + uri
+		}
+		if endpoint == "" {
+			return AWSCredentials{}, ErrAWSCredentialsNotFound
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return AWSCredentials{}, err
+		}
+		if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return AWSCredentials{}, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return AWSCredentials{}, fmt.Errorf("container credentials endpoint responded with status %d", resp.StatusCode)
+		}
+
+		var body struct {
+			AccessKeyID     string    `json:"AccessKeyId"`
+			SecretAccessKey string    `json:"SecretAccessKey"`
+			Token           string    `json:"Token"`
+			Expiration      time.Time `json:"Expiration"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return AWSCredentials{}, err
+		}
+		cached = AWSCredentials{
+			AccessKeyID:     body.AccessKeyID,
+			SecretAccessKey: body.SecretAccessKey,
+			SessionToken:    body.Token,
+			Expires:         body.Expiration,
+		}
+		return cached, nil
+	}
+}
+
+// DefaultAWSCredentials 依次尝试环境变量与 ECS 任务角色
+func DefaultAWSCredentials() AWSCredentialsProvider {
+	env, ecs := EnvAWSCredentials(), ECSAWSCredentials(nil)
+	return func(ctx context.Context) (AWSCredentials, error) {
+		if creds, err := env(ctx); err == nil {
+			return creds, nil
+		}
+		return ecs(ctx)
+	}
+}
+
+// awsRegion 从 AWS_REGION、AWS_DEFAULT_REGION 读取区域
+func awsRegion() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// signAWSV4 为请求添加 AWS Signature V4 认证头（签名 host、content-type、range 与 x-amz-* 请求头）
+func signAWSV4(req *http.Request, payloadHash, service, region string, creds AWSCredentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	scope := amzDate[:8] + "/" + region + "/" + service + "/aws4_request"
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	canonical.WriteString(req.Method + "\n" + req.URL.EscapedPath() + "\n" + awsCanonicalQuery(req.URL.RawQuery) + "\n")
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical.WriteString("\n" + signedHeaders + "\n" + payloadHash)
+
+	digest := sha256.Sum256([]byte(canonical.String()))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(digest[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), amzDate[:8])
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// hmacSHA256 计算 HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsCanonicalQuery 按 SigV4 规则规范化查询串：参数按名称（同名按值）排序，名称与值分别编码，
+// 无值的参数写为 name=（如 ?lifecycle 规范化为 lifecycle=）
+func awsCanonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	var params [][2]string
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			value = unescaped
+		}
+		params = append(params, [2]string{awsEscape(name, false), awsEscape(value, false)})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i][0] != params[j][0] {
+			return params[i][0] < params[j][0]
+		}
+		return params[i][1] < params[j][1]
+	})
+
+	var b strings.Builder
+	for i, p := range params {
+		if i > 0 {
+			b.WriteByte('&')
+		}
+		b.WriteString(p[0] + "=" + p[1])
+	}
+	return b.String()
+}
+
+// awsEscapePath 按 SigV4 规则编码路径（保留 / 与非保留字符）
+func awsEscapePath(path string) string {
+	return awsEscape(path, true)
+}
+
+// awsEscape 按 SigV4 规则编码（仅保留非保留字符，keepSlash 时同时保留 /）
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c == '/' && keepSlash) || c == '-' || c == '_' || c == '.' || c == '~' ||
+			(c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\badkey.go
+ * @Description: 格式错误的键值对处理（奇数个参数、非字符串键）与测试用校验
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BadKey 格式错误的键值对元素使用的字段名：
+//   - 键位置不是字符串（也不是 Field）时，该元素单独作为 BadKey 的值，后续元素重新从键开始配对
+//   - 末尾的键没有值时，该键作为 BadKey 的值
+const BadKey = "!BADKEY"
+
+// ErrMalformedKV 键值对格式错误
+var ErrMalformedKV = errors.New("logger: malformed key-value pairs")
+
+// ValidateKV 检查键值对格式，返回所有格式错误（均包装 ErrMalformedKV），无错误时返回 nil。
+// 日志调用本身从不因格式错误失败或 panic：错误元素以 BadKey 字段输出并上报内部错误（组件 kv）；
+// 测试中可直接断言 ValidateKV 的结果，或通过 SetInternalErrorHandler 捕获组件 kv 的错误使测试失败
+func ValidateKV(keysAndValues ...any) error {
+	var errs []error
+	for i := 0; i < len(keysAndValues); {
+		if _, ok := keysAndValues[i].(Field); ok {
+			i++
+			continue
+		}
+		var err error
+		if _, _, i, err = parseKV(keysAndValues, i); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// kvPair 解析 keysAndValues[i] 开始的键值对，返回键、值与下一个键的位置（调用方已处理 Field），格式错误时上报内部错误
+func kvPair(keysAndValues []any, i int) (string, any, int) {
+	key, value, next, err := parseKV(keysAndValues, i)
+	if err != nil {
+		reportInternalError("kv", err)
+	}
+	return key, value, next
+}
+
+// parseKV 解析单个键值对，格式错误时返回 BadKey 字段与错误
+func parseKV(keysAndValues []any, i int) (string, any, int, error) {
+	key, ok := keysAndValues[i].(string)
+	if !ok {
+		return BadKey, keysAndValues[i], i + 1, fmt.Errorf("%w: non-string key %T at position %d", ErrMalformedKV, keysAndValues[i], i)
+	}
+	if i+1 >= len(keysAndValues) {
+		return BadKey, key, i + 1, fmt.Errorf("%w: missing value for key %q", ErrMalformedKV, key)
+	}
+	return key, keysAndValues[i+1], i + 2, nil
+}
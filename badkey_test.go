@@ -0,0 +1,86 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\badkey_test.go
+ * @Description: 格式错误的键值对测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestValidateKV 校验键值对格式，Field 元素单独占位
+func TestValidateKV(t *testing.T) {
+	tests := []struct {
+		name string
+		kv   []any
+		errs int
+	}{
+		{"empty", nil, 0},
+		{"pairs", []any{"a", 1, "b", "x"}, 0},
+		{"field", []any{PII("email", "a@b.c"), "result", "ok"}, 0},
+		{"odd", []any{"a", 1, "dangling"}, 1},
+		{"non-string key", []any{42, "a", 1}, 1},
+		{"both", []any{42, "a", 1, "dangling"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKV(tt.kv...)
+			if tt.errs == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMalformedKV) {
+				t.Fatalf("error %v does not wrap ErrMalformedKV", err)
+			}
+			if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != tt.errs {
+				t.Errorf("got %d errors, want %d: %v", got, tt.errs, err)
+			}
+		})
+	}
+}
+
+// TestMalformedKVDoesNotPanic 格式错误的键值对以 BadKey 输出并上报内部错误，日志调用不会 panic
+func TestMalformedKVDoesNotPanic(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		reported []InternalError
+	)
+	SetInternalErrorHandler(func(e InternalError) {
+		mu.Lock()
+		reported = append(reported, e)
+		mu.Unlock()
+	})
+	defer SetInternalErrorHandler(nil)
+
+	var buf bytes.Buffer
+	l := NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText)
+	l.InfoKV("odd", "user", "alice", "dangling")
+	l.InfoKV("non-string key", 42, "k", "v")
+
+	out := buf.String()
+	if strings.Count(out, BadKey) != 2 || !strings.Contains(out, "dangling") {
+		t.Errorf("BadKey fields missing from output:\n%s", out)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 2 {
+		t.Fatalf("got %d internal errors, want 2", len(reported))
+	}
+	for _, e := range reported {
+		if e.Component != "kv" || !errors.Is(e, ErrMalformedKV) {
+			t.Errorf("unexpected internal error: %v", e)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\baggage.go
+ * @Description: Baggage / gRPC metadata 整体提取，按白名单映射为日志字段（带数量与长度限制）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// BaggageKey W3C Baggage 在 HTTP 头与 gRPC metadata 中的键名
+const BaggageKey = "baggage"
+
+// BaggageSource 从上下文读取 baggage 成员（如 OpenTelemetry 的 baggage.FromContext）
+type BaggageSource func(ctx context.Context) map[string]string
+
+// BaggageExtractor baggage / metadata 提取器
+// 依次读取自定义来源、gRPC metadata 中的 W3C baggage 头与其余 metadata 键，同名键先到先得
+type BaggageExtractor struct {
+	sources      []BaggageSource
+	metadata     bool
+	allow        map[string]struct{}
+	prefix       string
+	maxFields    int
+	maxValueSize int
+}
+
+// BaggageOption 提取器配置选项
+type BaggageOption func(*BaggageExtractor)
+
+// WithBaggageSource 追加 baggage 来源，如接入 OpenTelemetry：
+//
+//	logger.WithBaggageSource(func(ctx context.Context) map[string]string {
+//		m := make(map[string]string)
+//		for _, member := range baggage.FromContext(ctx).Members() {
+//			m[member.Key()] = member.Value()
+//		}
+//		return m
+//	})
+func WithBaggageSource(source BaggageSource) BaggageOption {
+	return func(e *BaggageExtractor) {
+		if source != nil {
+			e.sources = append(e.sources, source)
+		}
+	}
+}
+
+// WithBaggageMetadata 是否读取 gRPC incoming metadata（默认开启）
+func WithBaggageMetadata(enabled bool) BaggageOption {
+	return func(e *BaggageExtractor) {
+		e.metadata = enabled
+	}
+}
+
+// WithBaggageKeys 设置允许映射为字段的键（不区分大小写），未设置时允许所有键
+func WithBaggageKeys(keys ...string) BaggageOption {
+	return func(e *BaggageExtractor) {
+		e.allow = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			e.allow[strings.ToLower(key)] = struct{}{}
+		}
+	}
+}
+
+// WithBaggagePrefix 设置字段名前缀（如 "baggage."），避免与业务字段冲突
+func WithBaggagePrefix(prefix string) BaggageOption {
+	return func(e *BaggageExtractor) {
+		e.prefix = prefix
+	}
+}
+
+// WithBaggageLimits 设置最多映射的字段数（默认 16）与单个值的最大字节数（默认 256，超出部分截断）
+func WithBaggageLimits(maxFields, maxValueSize int) BaggageOption {
+	return func(e *BaggageExtractor) {
+		if maxFields > 0 {
+			e.maxFields = maxFields
+		}
+		if maxValueSize > 0 {
+			e.maxValueSize = maxValueSize
+		}
+	}
+}
+
+// NewBaggageExtractor 创建 baggage / metadata 提取器
+func NewBaggageExtractor(opts ...BaggageOption) *BaggageExtractor {
+	e := &BaggageExtractor{
+		metadata:     true,
+		maxFields:    16,
+		maxValueSize: 256,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Extract 提取上下文中的 baggage，返回字段名与值交替的键值对（按字段名排序）
+func (e *BaggageExtractor) Extract(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+
+	found := make(map[string]string)
+	add := func(key, value string) {
+		key = strings.ToLower(key)
+		if value == "" || len(found) >= e.maxFields {
+			return
+		}
+		if _, dup := found[key]; dup {
+			return
+		}
+		if e.allow != nil {
+			if _, ok := e.allow[key]; !ok {
+				return
+			}
+		}
+		found[key] = string(truncateTail([]byte(value), 0, e.maxValueSize))
+	}
+
+	for _, source := range e.sources {
+		for k, v := range source(ctx) {
+			add(k, v)
+		}
+	}
+	if e.metadata {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, header := range md.Get(BaggageKey) {
+				parseBaggage(header, add)
+			}
+			for k, values := range md {
+				if k == BaggageKey || strings.HasPrefix(k, ":") || strings.HasSuffix(k, "-bin") || len(values) == 0 {
+					continue
+				}
+				add(k, values[0])
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(found))
+	for k := range found {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kv := make([]any, 0, len(keys)*2)
+	for _, k := range keys {
+		kv = append(kv, e.prefix+k, found[k])
+	}
+	return kv
+}
+
+// parseBaggage 解析 W3C Baggage 头："k1=v1;prop, k2=v2"（值为百分号编码，成员属性忽略）
+func parseBaggage(header string, fn func(key, value string)) {
+	for _, member := range strings.Split(header, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if decoded, err := url.PathUnescape(value); err == nil {
+			value = decoded
+		}
+		if key != "" {
+			fn(key, value)
+		}
+	}
+}
+
+// WithBaggage 设置 baggage 提取器：FromContext、WithContext 返回的 Logger 及所有 *Context 方法
+// 会将提取到的键值对作为字段附加到日志
+func (l *Logger) WithBaggage(extractor *BaggageExtractor) *Logger {
+	l.baggage = extractor
+	return l
+}
+
+// baggageLogger 返回附加了上下文 baggage 字段的子 Logger（没有时返回自身）
+func (l *Logger) baggageLogger(ctx context.Context) *Logger {
+	if l.baggage == nil {
+		return l
+	}
+	kv := l.baggage.Extract(ctx)
+	if len(kv) == 0 {
+		return l
+	}
+	return l.With(kv...)
+}
@@ -0,0 +1,92 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\banner.go
+ * @Description: 启动横幅（应用信息、运行环境、配置摘要、已启用输出）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// BannerInfo 启动横幅信息
+type BannerInfo struct {
+	Name        string         // 应用名称
+	Version     string         // 应用版本
+	Commit      string         // Git 提交（为空时从构建信息读取 vcs.revision）
+	Environment string         // 运行环境（如 dev、staging、prod）
+	Config      map[string]any // 配置摘要（注意不要放入密钥）
+	Adapters    []string       // 已启用的输出（为空时按 Logger 的写入器类型生成）
+	Extra       map[string]any // 其他信息，追加到应用信息表
+}
+
+// Banner 使用全局 Logger 输出启动横幅
+func Banner(info BannerInfo) {
+	globalLogger().Banner(info)
+}
+
+// Banner 输出启动横幅：应用与运行环境信息表、配置摘要表、已启用输出列表
+func (l *Logger) Banner(info BannerInfo) {
+	title := info.Name
+	if title == "" {
+		title = "application"
+	}
+	if info.Version != "" {
+		title += " " + info.Version
+	}
+
+	cg := l.NewConsoleGroup()
+	cg.Group("🚀 %s", title)
+
+	summary := map[string]any{
+		"go_version": runtime.Version(),
+		"os_arch":    runtime.GOOS + "/" + runtime.GOARCH,
+		"pid":        os.Getpid(),
+		"started_at": time.Now().Format(time.RFC3339),
+		"log_level":  l.level.String(),
+	}
+	if host, err := os.Hostname(); err == nil {
+		summary["hostname"] = host
+	}
+	if commit := info.Commit; commit != "" {
+		summary["commit"] = commit
+	} else if bi := ReadBuildInfo(); bi.Revision != "" {
+		summary["commit"] = bi.Revision
+		if bi.Dirty {
+			summary["commit"] = bi.Revision + "-dirty"
+		}
+	}
+	if info.Environment != "" {
+		summary["environment"] = info.Environment
+	}
+	for k, v := range info.Extra {
+		summary[k] = v
+	}
+	cg.Table(summary)
+
+	if len(info.Config) > 0 {
+		cg.Group("Config")
+		cg.Table(info.Config)
+		cg.GroupEnd()
+	}
+
+	adapters := info.Adapters
+	if len(adapters) == 0 {
+		for _, w := range l.writers {
+			adapters = append(adapters, strings.TrimPrefix(fmt.Sprintf("%T", w), "*logger."))
+		}
+	}
+	if len(adapters) > 0 {
+		cg.Info("Adapters: %s", strings.Join(adapters, ", "))
+	}
+	cg.GroupEnd()
+}
@@ -0,0 +1,104 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\baseadapter.go
+ * @Description: 可嵌入的适配器基类，提供 IAdapter/ILogger 全部方法的默认实现
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// EmitFunc 适配器的输出函数，参数含义同 CoreLogger.Emit
+type EmitFunc func(ctx context.Context, level LogLevel, msg string, fields map[string]any)
+
+// BaseAdapter 可嵌入的适配器基类：ILogger 的全部方法由 Extend 从 emit 派生，生命周期方法为默认实现，
+// 自定义适配器嵌入后只需提供 emit 并按需覆盖个别方法：
+//
+//	type MemoryAdapter struct {
+//		*logger.BaseAdapter
+//		entries []string
+//	}
+//
+//	func NewMemoryAdapter() *MemoryAdapter {
+//		m := &MemoryAdapter{}
+//		m.BaseAdapter = logger.NewBaseAdapter("memory", "1.0.0", m.emit)
+//		return m
+//	}
+//
+// 注意 Go 的嵌入没有虚方法：外层覆盖的 InfoKV 等方法不会影响基类中其他方法，自定义输出应放在 emit 中
+type BaseAdapter struct {
+	ILogger
+	name    string
+	version string
+	level   atomic.Int32
+	healthy atomic.Bool
+}
+
+// baseAdapterCore 将 EmitFunc 与基类的级别组合为 CoreLogger
+type baseAdapterCore struct {
+	base *BaseAdapter
+	emit EmitFunc
+}
+
+func (c *baseAdapterCore) Emit(ctx context.Context, level LogLevel, msg string, fields map[string]any) {
+	c.emit(ctx, level, msg, fields)
+}
+func (c *baseAdapterCore) SetLevel(level LogLevel) { c.base.level.Store(int32(level)) }
+func (c *baseAdapterCore) GetLevel() LogLevel      { return LogLevel(c.base.level.Load()) }
+
+// NewBaseAdapter 创建适配器基类（默认 INFO 级别、健康状态），emit 为 nil 时丢弃所有日志
+func NewBaseAdapter(name, version string, emit EmitFunc) *BaseAdapter {
+	if emit == nil {
+		emit = func(context.Context, LogLevel, string, map[string]any) {}
+	}
+	b := &BaseAdapter{name: name, version: version}
+	b.level.Store(int32(INFO))
+	b.healthy.Store(true)
+	b.ILogger = Extend(&baseAdapterCore{base: b, emit: emit})
+	return b
+}
+
+// Initialize 初始化适配器（默认无操作）
+func (b *BaseAdapter) Initialize() error {
+	return nil
+}
+
+// Close 关闭适配器（默认将健康状态置为 false）
+func (b *BaseAdapter) Close() error {
+	b.healthy.Store(false)
+	return nil
+}
+
+// Flush 刷新缓冲区（默认无操作）
+func (b *BaseAdapter) Flush() error {
+	return nil
+}
+
+// GetAdapterName 获取适配器名称
+func (b *BaseAdapter) GetAdapterName() string {
+	return b.name
+}
+
+// GetAdapterVersion 获取适配器版本
+func (b *BaseAdapter) GetAdapterVersion() string {
+	return b.version
+}
+
+// IsHealthy 检查适配器健康状态
+func (b *BaseAdapter) IsHealthy() bool {
+	return b.healthy.Load()
+}
+
+// SetHealthy 设置适配器健康状态
+func (b *BaseAdapter) SetHealthy(healthy bool) {
+	b.healthy.Store(healthy)
+}
+
+var _ IAdapter = (*BaseAdapter)(nil)
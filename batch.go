@@ -0,0 +1,104 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\batch.go
+ * @Description: 批量日志写入（导入任务、回放工具等一次产生大量日志的场景）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// Entry 批量日志条目
+type Entry struct {
+	Level   LogLevel       `json:"level"`
+	Message string         `json:"message"`
+	Time    time.Time      `json:"time"`             // 为零值时使用写入时间
+	Fields  map[string]any `json:"fields,omitempty"` // 可选字段
+}
+
+// LogBatch 批量写入日志
+// 快速路径下所有条目编码进同一缓冲区后一次写出，只加一次锁；
+// 启用结构化管道时逐条经过中间件与钩子；包含 FATAL 条目时在整批写出后退出
+func (l *Logger) LogBatch(entries []Entry) {
+	if l.logBatch(entries) {
+		l.exitFatal()
+	}
+}
+
+// ReplayBatch 批量写入历史日志（回放工具使用），与 LogBatch 相同但 FATAL 条目不会导致退出
+func (l *Logger) ReplayBatch(entries []Entry) {
+	l.logBatch(entries)
+}
+
+// logBatch 批量写入日志，返回是否包含 FATAL 条目
+func (l *Logger) logBatch(entries []Entry) bool {
+	if len(entries) == 0 {
+		return false
+	}
+	now := l.now()
+	fatal := false
+
+	if l.hasPipeline() {
+		for i := range entries {
+			e := &entries[i]
+			if e.Level < l.level {
+				continue
+			}
+			l.dispatchAt(entryTime(e.Time, now), e.Level, e.Message, e.Message, e.Fields, nil, 2)
+			fatal = fatal || e.Level == FATAL
+		}
+		return fatal
+	}
+
+	// 配额、请求缓冲、首错误范围、构建信息、按级别分发的输出需要逐行判断
+	_, routed := l.output.(*outputRouter)
+	perLine := l.quota != nil || l.reqBuffer != nil || l.errScope != nil || l.buildInfoPending != nil || routed
+
+	top := DEBUG
+
+	bp := bytePool.Get().(*[]byte)
+	buf := (*bp)[:0]
+	for i := range entries {
+		e := &entries[i]
+		if e.Level < l.level {
+			continue
+		}
+		lineStart := len(buf)
+		buf = l.appendHeaderAt(buf, e.Level, entryTime(e.Time, now), 2)
+		msgStart := len(buf)
+		buf = append(buf, convert.S2B(e.Message)...)
+		buf = l.limitMessage(buf, msgStart)
+		if len(e.Fields) > 0 || l.hasStaticFields() {
+			buf = l.appendFieldBlock(buf, e.Level, nil, e.Fields)
+		}
+		buf = append(buf, newline...)
+
+		if perLine {
+			l.writeOutput(e.Level, buf[lineStart:])
+			buf = buf[:lineStart]
+		}
+		top = max(top, e.Level)
+		fatal = fatal || e.Level == FATAL
+	}
+	if len(buf) > 0 {
+		l.writeRaw(top, buf)
+	}
+	putLineBuf(bp, buf)
+	return fatal
+}
+
+// entryTime 条目时间为零值时使用默认时间
+func entryTime(t, def time.Time) time.Time {
+	if t.IsZero() {
+		return def
+	}
+	return t
+}
@@ -0,0 +1,436 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\benchcmp\benchmarks_test.go
+ * @Description: go-logger / zap / zerolog / slog 同场景基准测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package benchcmp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/rs/zerolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const message = "request handled"
+
+// 场景字段（各库使用相同的键与值）
+var (
+	kv5 = []any{
+		"method", "GET",
+		"status", 200,
+		"latency_ms", 12.5,
+		"user_id", int64(42),
+		"cached", true,
+	}
+	kv10 = append(append([]any(nil), kv5...),
+		"path", "/api/users",
+		"bytes", 5120,
+		"ratio", 0.75,
+		"trace_id", int64(1234567890),
+		"retry", false,
+	)
+)
+
+// ============================================================================
+// 日志器构造
+// ============================================================================
+
+func newGoLogger(caller bool) *logger.Logger {
+	return logger.NewLogger().WithOutput(io.Discard).WithColorful(false).WithShowCaller(caller)
+}
+
+func newGoLoggerJSON(caller bool) *logger.Logger {
+	return newGoLogger(caller).WithFormatter(logger.NewJSONFormatter())
+}
+
+func newZap(caller bool) *zap.Logger {
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(io.Discard),
+		zapcore.InfoLevel,
+	)
+	if caller {
+		return zap.New(core, zap.AddCaller())
+	}
+	return zap.New(core)
+}
+
+func newZerolog(caller bool) zerolog.Logger {
+	ctx := zerolog.New(io.Discard).With().Timestamp()
+	if caller {
+		ctx = ctx.Caller()
+	}
+	return ctx.Logger()
+}
+
+func newSlog(caller bool) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(io.Discard, &slog.HandlerOptions{AddSource: caller}))
+}
+
+// ============================================================================
+// Msg 纯消息
+// ============================================================================
+
+func BenchmarkMsg_GoLogger(b *testing.B) {
+	l := newGoLogger(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoMsg(message)
+	}
+}
+
+func BenchmarkMsg_GoLoggerJSON(b *testing.B) {
+	l := newGoLoggerJSON(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoMsg(message)
+	}
+}
+
+func BenchmarkMsg_Zap(b *testing.B) {
+	l := newZap(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message)
+	}
+}
+
+func BenchmarkMsg_ZapSugar(b *testing.B) {
+	l := newZap(false).Sugar()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message)
+	}
+}
+
+func BenchmarkMsg_Zerolog(b *testing.B) {
+	l := newZerolog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().Msg(message)
+	}
+}
+
+func BenchmarkMsg_Slog(b *testing.B) {
+	l := newSlog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message)
+	}
+}
+
+// ============================================================================
+// Fields5 5 个字段
+// ============================================================================
+
+func BenchmarkFields5_GoLogger(b *testing.B) {
+	l := newGoLogger(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV(message, "method", "GET", "status", 200, "latency_ms", 12.5, "user_id", int64(42), "cached", true)
+	}
+}
+
+func BenchmarkFields5_GoLoggerJSON(b *testing.B) {
+	l := newGoLoggerJSON(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV(message, "method", "GET", "status", 200, "latency_ms", 12.5, "user_id", int64(42), "cached", true)
+	}
+}
+
+func BenchmarkFields5_Zap(b *testing.B) {
+	l := newZap(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message,
+			zap.String("method", "GET"),
+			zap.Int("status", 200),
+			zap.Float64("latency_ms", 12.5),
+			zap.Int64("user_id", 42),
+			zap.Bool("cached", true),
+		)
+	}
+}
+
+func BenchmarkFields5_ZapSugar(b *testing.B) {
+	l := newZap(false).Sugar()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Infow(message, kv5...)
+	}
+}
+
+func BenchmarkFields5_Zerolog(b *testing.B) {
+	l := newZerolog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().
+			Str("method", "GET").
+			Int("status", 200).
+			Float64("latency_ms", 12.5).
+			Int64("user_id", 42).
+			Bool("cached", true).
+			Msg(message)
+	}
+}
+
+func BenchmarkFields5_Slog(b *testing.B) {
+	l := newSlog(false)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.LogAttrs(ctx, slog.LevelInfo, message,
+			slog.String("method", "GET"),
+			slog.Int("status", 200),
+			slog.Float64("latency_ms", 12.5),
+			slog.Int64("user_id", 42),
+			slog.Bool("cached", true),
+		)
+	}
+}
+
+func BenchmarkFields5_SlogKV(b *testing.B) {
+	l := newSlog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message, kv5...)
+	}
+}
+
+// ============================================================================
+// Fields10 10 个字段
+// ============================================================================
+
+func BenchmarkFields10_GoLogger(b *testing.B) {
+	l := newGoLogger(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV(message, kv10...)
+	}
+}
+
+func BenchmarkFields10_GoLoggerJSON(b *testing.B) {
+	l := newGoLoggerJSON(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV(message, kv10...)
+	}
+}
+
+func BenchmarkFields10_Zap(b *testing.B) {
+	l := newZap(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message,
+			zap.String("method", "GET"),
+			zap.Int("status", 200),
+			zap.Float64("latency_ms", 12.5),
+			zap.Int64("user_id", 42),
+			zap.Bool("cached", true),
+			zap.String("path", "/api/users"),
+			zap.Int("bytes", 5120),
+			zap.Float64("ratio", 0.75),
+			zap.Int64("trace_id", 1234567890),
+			zap.Bool("retry", false),
+		)
+	}
+}
+
+func BenchmarkFields10_ZapSugar(b *testing.B) {
+	l := newZap(false).Sugar()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Infow(message, kv10...)
+	}
+}
+
+func BenchmarkFields10_Zerolog(b *testing.B) {
+	l := newZerolog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().
+			Str("method", "GET").
+			Int("status", 200).
+			Float64("latency_ms", 12.5).
+			Int64("user_id", 42).
+			Bool("cached", true).
+			Str("path", "/api/users").
+			Int("bytes", 5120).
+			Float64("ratio", 0.75).
+			Int64("trace_id", 1234567890).
+			Bool("retry", false).
+			Msg(message)
+	}
+}
+
+func BenchmarkFields10_Slog(b *testing.B) {
+	l := newSlog(false)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.LogAttrs(ctx, slog.LevelInfo, message,
+			slog.String("method", "GET"),
+			slog.Int("status", 200),
+			slog.Float64("latency_ms", 12.5),
+			slog.Int64("user_id", 42),
+			slog.Bool("cached", true),
+			slog.String("path", "/api/users"),
+			slog.Int("bytes", 5120),
+			slog.Float64("ratio", 0.75),
+			slog.Int64("trace_id", 1234567890),
+			slog.Bool("retry", false),
+		)
+	}
+}
+
+func BenchmarkFields10_SlogKV(b *testing.B) {
+	l := newSlog(false)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message, kv10...)
+	}
+}
+
+// ============================================================================
+// Caller 调用者信息
+// ============================================================================
+
+func BenchmarkCaller_GoLogger(b *testing.B) {
+	l := newGoLogger(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoMsg(message)
+	}
+}
+
+func BenchmarkCaller_GoLoggerJSON(b *testing.B) {
+	l := newGoLoggerJSON(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoMsg(message)
+	}
+}
+
+func BenchmarkCaller_Zap(b *testing.B) {
+	l := newZap(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message)
+	}
+}
+
+func BenchmarkCaller_Zerolog(b *testing.B) {
+	l := newZerolog(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info().Msg(message)
+	}
+}
+
+func BenchmarkCaller_Slog(b *testing.B) {
+	l := newSlog(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info(message)
+	}
+}
+
+// ============================================================================
+// 并发
+// ============================================================================
+
+func BenchmarkParallelFields5_GoLogger(b *testing.B) {
+	l := newGoLogger(false)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.InfoKV(message, "method", "GET", "status", 200, "latency_ms", 12.5, "user_id", int64(42), "cached", true)
+		}
+	})
+}
+
+func BenchmarkParallelFields5_Zap(b *testing.B) {
+	l := newZap(false)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info(message,
+				zap.String("method", "GET"),
+				zap.Int("status", 200),
+				zap.Float64("latency_ms", 12.5),
+				zap.Int64("user_id", 42),
+				zap.Bool("cached", true),
+			)
+		}
+	})
+}
+
+func BenchmarkParallelFields5_Zerolog(b *testing.B) {
+	l := newZerolog(false)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Info().
+				Str("method", "GET").
+				Int("status", 200).
+				Float64("latency_ms", 12.5).
+				Int64("user_id", 42).
+				Bool("cached", true).
+				Msg(message)
+		}
+	})
+}
+
+func BenchmarkParallelFields5_Slog(b *testing.B) {
+	l := newSlog(false)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.LogAttrs(ctx, slog.LevelInfo, message,
+				slog.String("method", "GET"),
+				slog.Int("status", 200),
+				slog.Float64("latency_ms", 12.5),
+				slog.Int64("user_id", 42),
+				slog.Bool("cached", true),
+			)
+		}
+	})
+}
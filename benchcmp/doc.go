@@ -0,0 +1,28 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\benchcmp\doc.go
+ * @Description: go-logger 与 zap、zerolog、slog 的同场景基准对比（独立模块，不引入主模块依赖）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+// Package benchcmp 对比 go-logger 与 zap、zerolog、log/slog 在相同场景下的性能：
+//
+//	cd benchcmp
+//	go test -bench . -benchmem -count 5 | tee new.txt
+//	benchstat new.txt
+//
+// 场景（均写入 io.Discard、INFO 级别、带时间戳，每次调用产生一行完整输出）：
+//
+//	Msg       纯消息
+//	Fields5   5 个键值字段（字符串、整数、浮点、int64、布尔）
+//	Fields10  10 个键值字段
+//	Caller    纯消息 + 调用者信息
+//
+// 各库使用其推荐的结构化写法：go-logger 为 InfoKV（文本与 JSON 两种格式），zap 为强类型 Field
+// 与 SugaredLogger.Infow，zerolog 为链式事件，slog 为 LogAttrs 与 Info 键值对（JSONHandler）。
+// 输出格式不同（文本/JSON），结果对比的是各库默认推荐配置下的开销，而非同一字节序列的编码速度
+package benchcmp
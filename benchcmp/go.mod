@@ -0,0 +1,34 @@
+module github.com/kamalyes/go-logger/benchcmp
+
+go 1.24.0
+
+require (
+	github.com/kamalyes/go-logger v0.0.0
+	github.com/rs/zerolog v1.33.0
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/bytedance/sonic v1.11.6 // indirect
+	github.com/bytedance/sonic/loader v0.1.1 // indirect
+	github.com/cloudwego/base64x v0.1.4 // indirect
+	github.com/cloudwego/iasm v0.2.0 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/kamalyes/go-argus v0.1.0 // indirect
+	github.com/kamalyes/go-toolbox v0.15.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.2.7 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/arch v0.8.0 // indirect
+	golang.org/x/sys v0.37.0 // indirect
+	google.golang.org/grpc v1.77.0 // indirect
+	google.golang.org/protobuf v1.36.10 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// 始终对比当前工作区中的 go-logger
+replace github.com/kamalyes/go-logger => ../
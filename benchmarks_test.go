@@ -0,0 +1,430 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\benchmarks_test.go
+ * @Description: 日志器与输出器基准测试及内存分配回归保护
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+// newBenchLogger 创建写入 io.Discard 的基准测试日志器
+func newBenchLogger() *Logger {
+	return NewLogger().WithOutput(io.Discard).WithColorful(false)
+}
+
+// ============================================================================
+// Logger 基准测试
+// ============================================================================
+
+func BenchmarkLogger_Info(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled")
+	}
+}
+
+func BenchmarkLogger_Infof(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Infof("request handled: %s %d", "GET", 200)
+	}
+}
+
+func BenchmarkLogger_InfoMsg(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoMsg("request handled")
+	}
+}
+
+func BenchmarkNop_InfoKV(b *testing.B) {
+	l := Nop()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "method", "GET")
+	}
+}
+
+func BenchmarkDiscard_InfoKV(b *testing.B) {
+	l := Discard()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "method", "GET")
+	}
+}
+
+// BenchmarkFieldLogger_PreEncoded WithFields 子 Logger 复用预编码字段
+func BenchmarkFieldLogger_PreEncoded(b *testing.B) {
+	l := newBenchLogger().WithFields(map[string]any{
+		"request_id": "req-123", "tenant": "acme", "user_id": 42, "region": "eu-west-1", "version": "v2",
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "status", 200)
+	}
+}
+
+func BenchmarkLogger_Disabled(b *testing.B) {
+	l := newBenchLogger().WithLevel(ERROR)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Infof("request handled: %s %d", "GET", 200)
+	}
+}
+
+func BenchmarkLogger_AtInfo(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.AtInfo().WithField("method", "GET").WithField("path", "/api/users").Msg("request handled")
+	}
+}
+
+func BenchmarkLogger_AtDebugDisabled(b *testing.B) {
+	l := newBenchLogger().WithLevel(INFO)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.AtDebug().WithField("method", "GET").WithField("status", 200).Msg("request handled")
+	}
+}
+
+func BenchmarkLogger_ShowCaller(b *testing.B) {
+	l := newBenchLogger().WithShowCaller(true)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Info("request handled")
+	}
+}
+
+func BenchmarkLogger_LogBatch(b *testing.B) {
+	l := newBenchLogger()
+	entries := make([]Entry, 100)
+	for i := range entries {
+		entries[i] = Entry{Level: INFO, Message: "request handled", Fields: map[string]any{"status": 200}}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.LogBatch(entries)
+	}
+}
+
+func BenchmarkLogger_InfoKV(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "method", "GET", "status", 200, "path", "/api/users")
+	}
+}
+
+func BenchmarkLogger_InfoWithFields(b *testing.B) {
+	l := newBenchLogger()
+	fields := map[string]any{"method": "GET", "status": 200, "path": "/api/users"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoWithFields("request handled", fields)
+	}
+}
+
+func BenchmarkLogger_WithFields(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithFields(map[string]any{"method": "GET", "status": 200}).Info("request handled")
+	}
+}
+
+// BenchmarkRequestLogger 请求级 Logger 池复用字段 map 与预编码缓冲（对比 BenchmarkLogger_WithFields）
+func BenchmarkRequestLogger(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rl := l.AcquireRequestLogger("method", "GET", "status", 200)
+		rl.Info("request handled")
+		ReleaseRequestLogger(rl)
+	}
+}
+
+func BenchmarkLogger_WithError(b *testing.B) {
+	l := newBenchLogger()
+	err := errors.New("connection refused")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.WithError(err).Error("request failed")
+	}
+}
+
+func BenchmarkFieldLogger_InfoKV(b *testing.B) {
+	l := newBenchLogger().WithField("service", "api")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "method", "GET", "path", "/api/users")
+	}
+}
+
+func BenchmarkFieldLogger_InfoWithFields(b *testing.B) {
+	l := newBenchLogger().WithField("service", "api")
+	fields := map[string]any{"method": "GET", "path": "/api/users"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoWithFields("request handled", fields)
+	}
+}
+
+func BenchmarkLogEntry_AcquireRelease(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		entry := AcquireLogEntry()
+		entry.Level = INFO
+		entry.Message = "request handled"
+		entry.Fields["method"] = "GET"
+		ReleaseLogEntry(entry)
+	}
+}
+
+func BenchmarkLogger_WithStatic(b *testing.B) {
+	l := newBenchLogger().With("service", "api", "version", "1.0.0")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "status", 200)
+	}
+}
+
+func BenchmarkLogger_Async(b *testing.B) {
+	l := newBenchLogger().WithAsyncWrite(true)
+	defer l.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.InfoKV("request handled", "status", 200)
+	}
+	l.Flush()
+}
+
+// ============================================================================
+// 并发基准测试
+// ============================================================================
+
+func BenchmarkLogger_Parallel(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.InfoKV("request handled", "status", 200)
+		}
+	})
+}
+
+func BenchmarkLogger_AsyncParallel(b *testing.B) {
+	l := newBenchLogger().WithAsyncWrite(true)
+	defer l.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.InfoKV("request handled", "status", 200)
+		}
+	})
+	l.Flush()
+}
+
+// ============================================================================
+// 输出器基准测试（benchmark.sh 使用）
+// ============================================================================
+
+var benchLine = []byte("2026-10-15 00:00:00 ℹ️ [INFO] request handled {method: GET, status: 200}\n")
+
+func BenchmarkConsoleWriter_Sequential(b *testing.B) {
+	w := NewConsoleWriter(WithConsoleOutput(io.Discard))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Write(benchLine)
+	}
+}
+
+func BenchmarkConsoleWriter_Parallel(b *testing.B) {
+	w := NewConsoleWriter(WithConsoleOutput(io.Discard))
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			w.Write(benchLine)
+		}
+	})
+}
+
+func BenchmarkFileWriter_Sequential(b *testing.B) {
+	w := NewFileWriter(WithFileWriterPath(filepath.Join(b.TempDir(), "bench.log")))
+	defer w.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Write(benchLine)
+	}
+}
+
+func BenchmarkFileWriter_Parallel(b *testing.B) {
+	w := NewFileWriter(WithFileWriterPath(filepath.Join(b.TempDir(), "bench.log")))
+	defer w.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			w.Write(benchLine)
+		}
+	})
+}
+
+func BenchmarkFileWriter_Vectors(b *testing.B) {
+	w := NewFileWriter(WithFileWriterPath(filepath.Join(b.TempDir(), "bench.log")))
+	defer w.Close()
+	bufs := make([][]byte, 100)
+	for i := range bufs {
+		bufs[i] = benchLine
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WriteVectors(w, bufs)
+	}
+}
+
+func BenchmarkRotateWriter_Sequential(b *testing.B) {
+	w := NewRotateWriter(WithFilePath(filepath.Join(b.TempDir(), "bench.log")), WithMaxSize(64<<20))
+	defer w.Close()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Write(benchLine)
+	}
+}
+
+func BenchmarkStats_Original(b *testing.B) {
+	stats := newWriterStats()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stats.addBytes(int64(len(benchLine)))
+	}
+}
+
+func BenchmarkStats_Original_Parallel(b *testing.B) {
+	stats := newWriterStats()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			stats.addBytes(int64(len(benchLine)))
+		}
+	})
+}
+
+// BenchmarkJSONEngine_Marshal 对比 -tags jsoniter / -tags sonic 下的结构化编码开销
+func BenchmarkJSONEngine_Marshal(b *testing.B) {
+	entry := &LogEntry{
+		Level:     INFO,
+		Message:   "request handled",
+		Timestamp: 1,
+		Fields:    map[string]any{"method": "GET", "path": "/api/users", "status": 200, "latency_ms": 12.5},
+	}
+	engine := GetJSONEngine()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.Marshal(entry)
+	}
+}
+
+// ============================================================================
+// 内存分配回归保护
+// ============================================================================
+
+// TestAllocationGuards 确保热路径的分配次数不会退化
+func TestAllocationGuards(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not meaningful under -race")
+	}
+	l := newBenchLogger()
+	static := l.With("service", "api")
+	disabled := newBenchLogger().WithLevel(ERROR)
+	caller := newBenchLogger().WithShowCaller(true)
+	withField := l.WithField("service", "api")
+	fields := map[string]any{"method": "GET", "path": "/api/users"}
+	nop := Nop()
+	discard := Discard()
+
+	cases := []struct {
+		name      string
+		maxAllocs float64
+		fn        func()
+	}{
+		{"Info", 0, func() { l.Info("request handled") }},
+		{"InfoMsg", 0, func() { l.InfoMsg("request handled") }},
+		{"ShowCaller", 0, func() { caller.Info("request handled") }},
+		{"Disabled", 0, func() { disabled.Infof("request handled: %s", "GET") }},
+		{"Infof", 0, func() { l.Infof("request handled: %s", "GET") }},
+		{"InfoKV", 0, func() { l.InfoKV("request handled", "method", "GET", "path", "/api/users") }},
+		{"InfoWithFields", 0, func() { l.InfoWithFields("request handled", fields) }},
+		{"WithStatic", 0, func() { static.InfoKV("request handled", "method", "GET") }},
+		{"FieldLoggerKV", 0, func() { withField.InfoKV("request handled", "method", "GET") }},
+		{"FieldLoggerWithFields", 0, func() { withField.InfoWithFields("request handled", fields) }},
+		{"Nop", 0, func() { nop.InfoKV("request handled", "method", "GET") }},
+		{"Discard", 0, func() { discard.DebugKV("request handled", "method", "GET") }},
+		{"AtInfo", 0, func() { l.AtInfo().WithField("method", "GET").Msg("request handled") }},
+		{"AtDebugDisabled", 0, func() { disabled.AtDebug().WithField("method", "GET").Msg("request handled") }},
+		{"RequestLogger", 0, func() {
+			rl := l.AcquireRequestLogger("method", "GET")
+			rl.Set("path", "/api/users").Info("request handled")
+			ReleaseRequestLogger(rl)
+		}},
+		{"EveryNSuppressed", 0, func() { l.EveryN(1 << 30).Info("request handled") }},
+		{"LogEntry", 0, func() {
+			entry := AcquireLogEntry()
+			entry.Fields["method"] = "GET"
+			ReleaseLogEntry(entry)
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(100, tc.fn)
+			if allocs > tc.maxAllocs {
+				t.Errorf("%s allocs/op regressed: got %.1f, want <= %.1f", tc.name, allocs, tc.maxAllocs)
+			}
+		})
+	}
+}
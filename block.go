@@ -0,0 +1,125 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\block.go
+ * @Description: 多行块日志（堆栈、SQL、配置转储），文本模式输出带边框的缩进块，结构化模式为单条日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"strings"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// BlockKey 结构化管道中块内容的字段名
+const BlockKey = "block"
+
+// 块边框
+var (
+	blockOpen   = []byte("┌ ")
+	blockLine   = []byte("  │ ")
+	blockClose  = []byte("  └")
+	blockEscape = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// logBlock 输出块日志
+// 文本模式下标题占首行，正文每行缩进并加竖线，整块一次写出；
+// 启用结构化管道（如 JSON 格式化器）时作为单条日志，正文放入 block 字段由格式化器转义
+func (l *Logger) logBlock(level LogLevel, title, body string, fields map[string]any) {
+	if level < l.level {
+		return
+	}
+	body = strings.TrimRight(blockEscape.Replace(body), "\n")
+
+	if l.hasPipeline() {
+		l.dispatch(level, title, title, fields, []any{BlockKey, body}, 2)
+		return
+	}
+
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 2)
+	buf = append(buf, blockOpen...)
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(title)...)
+	buf = l.limitMessage(buf, msgStart)
+	if len(fields) > 0 || l.hasStaticFields() {
+		buf = l.appendFieldBlock(buf, level, nil, fields)
+	}
+	buf = append(buf, newline...)
+
+	for len(body) > 0 {
+		line := body
+		if idx := strings.IndexByte(body, '\n'); idx >= 0 {
+			line, body = body[:idx], body[idx+1:]
+		} else {
+			body = ""
+		}
+		buf = append(buf, blockLine...)
+		buf = append(buf, convert.S2B(line)...)
+		buf = append(buf, newline...)
+	}
+	buf = append(buf, blockClose...)
+	buf = append(buf, newline...)
+
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
+}
+
+// DebugBlock 调试级别块日志
+func (l *Logger) DebugBlock(title, body string) {
+	if l.level > DEBUG {
+		return
+	}
+	l.logBlock(DEBUG, title, body, nil)
+}
+
+// InfoBlock 信息级别块日志
+func (l *Logger) InfoBlock(title, body string) {
+	if l.level > INFO {
+		return
+	}
+	l.logBlock(INFO, title, body, nil)
+}
+
+// WarnBlock 警告级别块日志
+func (l *Logger) WarnBlock(title, body string) {
+	if l.level > WARN {
+		return
+	}
+	l.logBlock(WARN, title, body, nil)
+}
+
+// ErrorBlock 错误级别块日志
+func (l *Logger) ErrorBlock(title, body string) {
+	if l.level > ERROR {
+		return
+	}
+	l.logBlock(ERROR, title, body, nil)
+}
+
+func (f *fieldLogger) DebugBlock(title, body string) {
+	f.logger.logBlock(DEBUG, title, body, f.fields)
+}
+
+func (f *fieldLogger) InfoBlock(title, body string) {
+	f.logger.logBlock(INFO, title, body, f.fields)
+}
+
+func (f *fieldLogger) WarnBlock(title, body string) {
+	f.logger.logBlock(WARN, title, body, f.fields)
+}
+
+func (f *fieldLogger) ErrorBlock(title, body string) {
+	f.logger.logBlock(ERROR, title, body, f.fields)
+}
+
+var (
+	_ IBlockLogger = (*Logger)(nil)
+	_ IBlockLogger = (*fieldLogger)(nil)
+	_ IBlockLogger = (*EmptyLogger)(nil)
+)
@@ -0,0 +1,243 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\broker.go
+ * @Description: 消息代理发布循环（NATS、MQTT 适配器共用）：有界队列、断线重连、失败重试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultBrokerQueueSize = 8192
+	DefaultBrokerTimeout   = 5 * time.Second
+	DefaultBrokerRetries   = 3
+)
+
+// ErrBrokerClosed 适配器已关闭
+var ErrBrokerClosed = errors.New("logger: broker adapter closed")
+
+// brokerConn 已建立的代理连接
+type brokerConn interface {
+	publish(topic string, payload []byte, retry bool) error // 发布一条消息（可只写入缓冲区），retry 表示重发此前已尝试发布的消息
+	flush() error                                           // 将缓冲区写出并确认连接可用
+	close() error
+}
+
+// brokerMessage 待发布的消息
+type brokerMessage struct {
+	topic   string
+	payload []byte
+}
+
+// BrokerStats 消息代理发布统计
+type BrokerStats struct {
+	Published int64 // 发布成功的消息数
+	Failed    int64 // 重试耗尽的消息数
+	Dropped   int64 // 因队列满或已关闭丢弃的消息数
+}
+
+// brokerPublisher 发布循环（连接仅由后台协程访问）
+type brokerPublisher struct {
+	component string
+	dial      func() (brokerConn, error)
+	retries   int
+	onHealth  func(bool)
+
+	queue     chan brokerMessage
+	flushReq  chan chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	conn      brokerConn
+
+	published atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+}
+
+// newBrokerPublisher 创建发布循环并启动后台协程
+func newBrokerPublisher(component string, queueSize, retries int, dial func() (brokerConn, error), onHealth func(bool)) *brokerPublisher {
+	p := &brokerPublisher{
+		component: component,
+		dial:      dial,
+		retries:   retries,
+		onHealth:  onHealth,
+		queue:     make(chan brokerMessage, queueSize),
+		flushReq:  make(chan chan error),
+		done:      make(chan struct{}),
+	}
+	p.wg.Add(1)
+	go p.run()
+	return p
+}
+
+// enqueue 消息入队（不阻塞，队列满或已关闭时丢弃）
+func (p *brokerPublisher) enqueue(m brokerMessage) {
+	select {
+	case <-p.done:
+		p.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case p.queue <- m:
+	default:
+		p.dropped.Add(1)
+		reportOverflow(p.component, OverflowDropped, cap(p.queue))
+	}
+}
+
+// run 后台发布协程：队列空闲时刷新连接缓冲区
+func (p *brokerPublisher) run() {
+	defer p.wg.Done()
+	for {
+		select {
+		case m := <-p.queue:
+			p.publish(m)
+			if len(p.queue) == 0 {
+				p.flushConn()
+			}
+		case reply := <-p.flushReq:
+			p.drain()
+			reply <- p.flushConn()
+		case <-p.done:
+			p.drain()
+			p.flushConn()
+			if p.conn != nil {
+				p.conn.close()
+				p.conn = nil
+			}
+			return
+		}
+	}
+}
+
+// drain 发布队列中剩余的消息
+func (p *brokerPublisher) drain() {
+	for {
+		select {
+		case m := <-p.queue:
+			p.publish(m)
+		default:
+			return
+		}
+	}
+}
+
+// publish 发布一条消息（失败时重连重试）
+func (p *brokerPublisher) publish(m brokerMessage) {
+	var err error
+	retry := false
+	for attempt := 0; attempt <= p.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+		if p.conn == nil {
+			if p.conn, err = p.dial(); err != nil {
+				p.conn = nil
+				continue
+			}
+		}
+		err = p.conn.publish(m.topic, m.payload, retry)
+		retry = true
+		if err == nil {
+			p.published.Add(1)
+			return
+		}
+		p.reset()
+	}
+	p.failed.Add(1)
+	p.onHealth(false)
+	reportInternalError(p.component, fmt.Errorf("%s publish to %s: %w", p.component, m.topic, err))
+}
+
+// flushConn 刷新连接缓冲区
+func (p *brokerPublisher) flushConn() error {
+	if p.conn == nil {
+		return nil
+	}
+	if err := p.conn.flush(); err != nil {
+		p.reset()
+		p.onHealth(false)
+		reportInternalError(p.component, err)
+		return err
+	}
+	p.onHealth(true)
+	return nil
+}
+
+// reset 关闭当前连接（下次发布时重连）
+func (p *brokerPublisher) reset() {
+	if p.conn != nil {
+		p.conn.close()
+		p.conn = nil
+	}
+}
+
+// flush 发布队列中的消息并等待确认
+func (p *brokerPublisher) flush() error {
+	reply := make(chan error, 1)
+	select {
+	case p.flushReq <- reply:
+		return <-reply
+	case <-p.done:
+		return ErrBrokerClosed
+	}
+}
+
+// close 发布剩余消息后关闭连接
+func (p *brokerPublisher) close() {
+	p.closeOnce.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+// queueDepth 返回队列中待发送的条数与队列容量
+func (p *brokerPublisher) queueDepth() (depth, capacity int) {
+	return len(p.queue), cap(p.queue)
+}
+
+// stats 返回统计信息
+func (p *brokerPublisher) stats() BrokerStats {
+	return BrokerStats{
+		Published: p.published.Load(),
+		Failed:    p.failed.Load(),
+		Dropped:   p.dropped.Load(),
+	}
+}
+
+// brokerPayload 将日志编码为 JSON 消息（time、level、message 与字段）
+func brokerPayload(level LogLevel, msg string, fields map[string]any) []byte {
+	event := make(map[string]any, len(fields)+3)
+	for k, v := range fields {
+		event[k] = v
+	}
+	event["time"] = time.Now().Format(time.RFC3339Nano)
+	event["level"] = level.String()
+	event["message"] = msg
+	data, err := marshalJSON(event)
+	if err != nil {
+		return []byte(msg)
+	}
+	return data
+}
+
+// expandLevelTopic 替换主题模板中的 {level}（小写级别名）
+func expandLevelTopic(template string, level LogLevel) string {
+	if !strings.Contains(template, "{level}") {
+		return template
+	}
+	return strings.ReplaceAll(template, "{level}", strings.ToLower(level.String()))
+}
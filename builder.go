@@ -0,0 +1,147 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\builder.go
+ * @Description: Logger 构建器：在一处组装输出、格式化、时钟、上下文提取、采样、适配器、钩子与中间件
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "io"
+
+// LoggerBuilder Logger 构建器，可能失败的步骤（按名称声明的组件）在 Build 时统一返回错误：
+//
+//	log, err := logger.NewLoggerBuilder().
+//		WithLevel(logger.INFO).
+//		WithOutput(os.Stdout).
+//		WithSampler(10).
+//		WithMiddleware(logger.NewRedactMiddleware([]string{"password"})).
+//		WithComponents(cfg.Logging).
+//		Build()
+type LoggerBuilder struct {
+	logger     *Logger
+	hooks      []IHook
+	middleware []IMiddleware
+	components []ComponentsConfig
+	errHandler InternalErrorHandler
+}
+
+// NewLoggerBuilder 创建基于默认配置 Logger 的构建器
+func NewLoggerBuilder() *LoggerBuilder {
+	return &LoggerBuilder{logger: NewLogger()}
+}
+
+// WithLevel 设置日志级别
+func (b *LoggerBuilder) WithLevel(level LogLevel) *LoggerBuilder {
+	b.logger.WithLevel(level)
+	return b
+}
+
+// WithOutput 设置输出目标
+func (b *LoggerBuilder) WithOutput(output io.Writer) *LoggerBuilder {
+	b.logger.WithOutput(output)
+	return b
+}
+
+// WithOutputs 设置多个按级别阈值分发的输出目标
+func (b *LoggerBuilder) WithOutputs(outputs []OutputSpec) *LoggerBuilder {
+	b.logger.WithOutputs(outputs)
+	return b
+}
+
+// WithFormatter 设置格式化器
+func (b *LoggerBuilder) WithFormatter(formatter IFormatter) *LoggerBuilder {
+	b.logger.WithFormatter(formatter)
+	return b
+}
+
+// WithClock 设置时间源
+func (b *LoggerBuilder) WithClock(clock Clock) *LoggerBuilder {
+	b.logger.WithClock(clock)
+	return b
+}
+
+// WithContextExtractor 设置上下文提取器
+func (b *LoggerBuilder) WithContextExtractor(extractor ContextExtractor) *LoggerBuilder {
+	b.logger.WithContextExtractor(extractor)
+	return b
+}
+
+// WithContextFieldExtractor 设置上下文字段提取器
+func (b *LoggerBuilder) WithContextFieldExtractor(extractor ContextFieldExtractor) *LoggerBuilder {
+	b.logger.WithContextFieldExtractor(extractor)
+	return b
+}
+
+// WithSampler 添加采样中间件（见 NewSamplingMiddleware）
+func (b *LoggerBuilder) WithSampler(every int, opts ...SamplingOption) *LoggerBuilder {
+	b.middleware = append(b.middleware, NewSamplingMiddleware(every, opts...))
+	return b
+}
+
+// WithAdapters 将日志同时转发给适配器（见 Logger.WithAdapters）
+func (b *LoggerBuilder) WithAdapters(adapters ...IAdapter) *LoggerBuilder {
+	for _, adapter := range adapters {
+		if adapter != nil {
+			b.hooks = append(b.hooks, &adapterHook{adapter: adapter})
+		}
+	}
+	return b
+}
+
+// WithHook 追加钩子
+func (b *LoggerBuilder) WithHook(hooks ...IHook) *LoggerBuilder {
+	b.hooks = append(b.hooks, hooks...)
+	return b
+}
+
+// WithMiddleware 追加中间件（按优先级升序执行）
+func (b *LoggerBuilder) WithMiddleware(middleware ...IMiddleware) *LoggerBuilder {
+	b.middleware = append(b.middleware, middleware...)
+	return b
+}
+
+// WithComponents 追加按名称声明的钩子与中间件，在 Build 时实例化
+func (b *LoggerBuilder) WithComponents(cfg ComponentsConfig) *LoggerBuilder {
+	b.components = append(b.components, cfg)
+	return b
+}
+
+// WithErrorHandler 设置内部错误处理函数（Build 时生效，作用于整个进程，见 SetInternalErrorHandler）
+func (b *LoggerBuilder) WithErrorHandler(handler InternalErrorHandler) *LoggerBuilder {
+	b.errHandler = handler
+	return b
+}
+
+// Configure 对 Logger 执行构建器未覆盖的配置：
+//
+//	builder.Configure(func(l *logger.Logger) { l.WithLogID(logger.LogIDULID) })
+func (b *LoggerBuilder) Configure(fn func(l *Logger)) *LoggerBuilder {
+	fn(b.logger)
+	return b
+}
+
+// Build 实例化声明的组件并返回 Logger，任一组件创建失败时返回错误
+func (b *LoggerBuilder) Build() (*Logger, error) {
+	l := b.logger
+	if len(b.hooks) > 0 {
+		l.WithHooks(append(append([]IHook(nil), l.hooks...), b.hooks...))
+	}
+	if len(b.middleware) > 0 {
+		l.WithMiddleware(append(append([]IMiddleware(nil), l.middleware...), b.middleware...))
+	}
+	b.hooks, b.middleware = nil, nil
+	for _, cfg := range b.components {
+		if err := l.ApplyComponents(cfg); err != nil {
+			return nil, err
+		}
+	}
+	b.components = nil
+	if b.errHandler != nil {
+		SetInternalErrorHandler(b.errHandler)
+	}
+	return l, nil
+}
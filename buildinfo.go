@@ -0,0 +1,109 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\buildinfo.go
+ * @Description: 构建信息字段（模块版本、VCS 提交与是否有未提交改动）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// 构建信息字段名
+const (
+	BuildModuleKey   = "module"
+	BuildVersionKey  = "version"
+	BuildRevisionKey = "revision"
+	BuildDirtyKey    = "dirty"
+)
+
+// BuildInfo 构建信息
+type BuildInfo struct {
+	Module    string `json:"module"`
+	Version   string `json:"version"`
+	Revision  string `json:"revision"`
+	Dirty     bool   `json:"dirty"`
+	GoVersion string `json:"go_version"`
+}
+
+var (
+	buildInfo     BuildInfo
+	buildInfoOnce sync.Once
+)
+
+// ReadBuildInfo 读取主模块构建信息（结果缓存，非模块构建时各字段为空）
+func ReadBuildInfo() BuildInfo {
+	buildInfoOnce.Do(func() {
+		bi, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		buildInfo.Module = bi.Main.Path
+		buildInfo.Version = bi.Main.Version
+		buildInfo.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				buildInfo.Revision = s.Value
+			case "vcs.modified":
+				buildInfo.Dirty = s.Value == "true"
+			}
+		}
+	})
+	return buildInfo
+}
+
+// keysAndValues 转为键值对（省略空字段）
+func (b BuildInfo) keysAndValues() []any {
+	kv := make([]any, 0, 8)
+	if b.Module != "" {
+		kv = append(kv, BuildModuleKey, b.Module)
+	}
+	if b.Version != "" {
+		kv = append(kv, BuildVersionKey, b.Version)
+	}
+	if b.Revision != "" {
+		kv = append(kv, BuildRevisionKey, b.Revision, BuildDirtyKey, b.Dirty)
+	}
+	return kv
+}
+
+// BuildInfoMode 构建信息附加方式
+type BuildInfoMode int
+
+const (
+	BuildInfoEveryEntry BuildInfoMode = iota // 每条日志都附加
+	BuildInfoFirstEntry                      // 仅在启动后第一条日志前输出一次
+)
+
+// WithBuildInfo 附加构建信息字段
+func (l *Logger) WithBuildInfo(mode BuildInfoMode) *Logger {
+	kv := ReadBuildInfo().keysAndValues()
+	if len(kv) == 0 {
+		return l
+	}
+
+	if mode == BuildInfoFirstEntry {
+		l.buildInfoPending = &atomic.Bool{}
+		l.buildInfoPending.Store(true)
+		return l
+	}
+
+	l.staticKV = append(l.staticKV[:len(l.staticKV):len(l.staticKV)], kv...)
+	l.staticFields, _ = l.appendKVPairs(append([]byte(nil), l.staticFields...), kv, len(l.staticFields) > 0)
+	return l
+}
+
+// writeBuildInfo 第一条日志前输出构建信息（仅一次，子 Logger 共享）
+func (l *Logger) writeBuildInfo() {
+	if l.buildInfoPending.CompareAndSwap(true, false) {
+		l.writeNotice(INFO, []byte("build info"), ReadBuildInfo().keysAndValues())
+	}
+}
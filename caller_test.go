@@ -0,0 +1,79 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\caller_test.go
+ * @Description: 调用位置测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestCallerFrame 各类日志方法报告的调用位置均为测试文件本身
+func TestCallerFrame(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText).WithShowCaller(true)
+	f := l.WithField("k", "v")
+	ctx := context.Background()
+
+	calls := map[string]func(){
+		"InfoKV":               func() { l.InfoKV("m", "a", 1) },
+		"InfoContextKV":        func() { l.InfoContextKV(ctx, "m", "a", 1) },
+		"ErrorContextKV":       func() { l.ErrorContextKV(ctx, "m", "a", 1) },
+		"InfoKVReturn":         func() { _ = l.InfoKVReturn("m", "a", 1) },
+		"InfoCtxReturn":        func() { _ = l.InfoCtxReturn(ctx, "m %d", 1) },
+		"field.InfoKV":         func() { f.InfoKV("m", "a", 1) },
+		"field.InfoReturn":     func() { _ = f.InfoReturn("m %d", 1) },
+		"field.DebugKVReturn":  func() { _ = f.DebugKVReturn("m", "a", 1) },
+		"field.InfoKVReturn":   func() { _ = f.InfoKVReturn("m", "a", 1) },
+		"field.WarnKVReturn":   func() { _ = f.WarnKVReturn("m", "a", 1) },
+		"field.ErrorKVReturn":  func() { _ = f.ErrorKVReturn("m", "a", 1) },
+		"field.DebugCtxReturn": func() { _ = f.DebugCtxReturn(ctx, "m %d", 1) },
+		"field.InfoCtxReturn":  func() { _ = f.InfoCtxReturn(ctx, "m %d", 1) },
+		"field.WarnCtxReturn":  func() { _ = f.WarnCtxReturn(ctx, "m %d", 1) },
+		"field.ErrorCtxReturn": func() { _ = f.ErrorCtxReturn(ctx, "m %d", 1) },
+		"field.InfoContextKV":  func() { f.InfoContextKV(ctx, "m", "a", 1) },
+	}
+	for name, call := range calls {
+		buf.Reset()
+		call()
+		if !strings.Contains(buf.String(), "caller_test.go:") {
+			t.Errorf("%s: caller is not the test file: %s", name, strings.TrimSpace(buf.String()))
+		}
+	}
+}
+
+// TestGlobalCallerFrame 包级函数报告的调用位置为调用方
+func TestGlobalCallerFrame(t *testing.T) {
+	var buf bytes.Buffer
+	restore := ReplaceGlobal(NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText).WithShowCaller(true))
+	defer restore()
+	ctx := context.Background()
+
+	calls := map[string]func(){
+		"Info":          func() { Info("m %d", 1) },
+		"InfoKV":        func() { InfoKV("m", "a", 1) },
+		"InfoContextKV": func() { InfoContextKV(ctx, "m", "a", 1) },
+		"InfoReturn":    func() { _ = InfoReturn("m %d", 1) },
+		"InfoCtxReturn": func() { _ = InfoCtxReturn(ctx, "m %d", 1) },
+		"InfoKVReturn":  func() { _ = InfoKVReturn("m", "a", 1) },
+		"InfoLines":     func() { InfoLines("m") },
+		"LogKV":         func() { LogKV(INFO, "m", "a", 1) },
+		"InfoMsg":       func() { InfoMsg("m") },
+	}
+	for name, call := range calls {
+		buf.Reset()
+		call()
+		if !strings.Contains(buf.String(), "caller_test.go:") {
+			t.Errorf("%s: caller is not the test file: %s", name, strings.TrimSpace(buf.String()))
+		}
+	}
+}
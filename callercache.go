@@ -0,0 +1,120 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\callercache.go
+ * @Description: 调用者信息缓存（按程序计数器缓存解析结果，避免每次调用 FuncForPC 与路径裁剪）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kamalyes/go-toolbox/pkg/stringx"
+)
+
+// callerFrame 解析后的调用者信息
+type callerFrame struct {
+	file     string // 完整文件路径
+	line     int
+	function string // 完整函数名
+	header   []byte // 预格式化的 "[file.go:line:Func] "
+}
+
+// callerCache 调用者缓存：读路径无锁，未命中时复制并替换整个 map（调用点数量有限）
+type callerCache struct {
+	frames atomic.Pointer[map[uintptr]*callerFrame]
+	mu     sync.Mutex
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+var callers = func() *callerCache {
+	c := &callerCache{}
+	empty := make(map[uintptr]*callerFrame)
+	c.frames.Store(&empty)
+	return c
+}()
+
+// CallerCacheStats 调用者缓存统计
+type CallerCacheStats struct {
+	Entries int     `json:"entries"`  // 已缓存的调用点数
+	Hits    int64   `json:"hits"`     // 命中次数
+	Misses  int64   `json:"misses"`   // 未命中次数
+	HitRate float64 `json:"hit_rate"` // 命中率（0-1）
+}
+
+// GetCallerCacheStats 获取调用者缓存统计（命中次数需先调用 EnablePoolStats(true)）
+func GetCallerCacheStats() CallerCacheStats {
+	stats := CallerCacheStats{
+		Entries: len(*callers.frames.Load()),
+		Hits:    callers.hits.Load(),
+		Misses:  callers.misses.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// lookupCaller 获取调用栈上第 skip 层（相对 lookupCaller 的调用方）的调用者信息
+func lookupCaller(skip int) (*callerFrame, bool) {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return nil, false
+	}
+	pc := pcs[0]
+
+	if f, ok := (*callers.frames.Load())[pc]; ok {
+		if poolStatsEnabled.Load() {
+			callers.hits.Add(1)
+		}
+		return f, true
+	}
+	if poolStatsEnabled.Load() {
+		callers.misses.Add(1)
+	}
+	return callers.resolve(pc), true
+}
+
+// resolve 解析程序计数器并写入缓存
+func (c *callerCache) resolve(pc uintptr) *callerFrame {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	f := &callerFrame{file: frame.File, line: frame.Line, function: frame.Function}
+
+	funcName := frame.Function
+	if idx := strings.LastIndex(funcName, "."); idx != -1 {
+		funcName = funcName[idx+1:]
+	}
+	file := frame.File
+	if idx := strings.LastIndex(file, "/"); idx != -1 {
+		file = file[idx+1:]
+	}
+	f.header = append(f.header, '[')
+	f.header = append(f.header, file...)
+	f.header = append(f.header, ':')
+	f.header = stringx.FastAppendInt(f.header, frame.Line)
+	f.header = append(f.header, ':')
+	f.header = append(f.header, funcName...)
+	f.header = append(f.header, ']', ' ')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := *c.frames.Load()
+	if existing, ok := old[pc]; ok {
+		return existing
+	}
+	next := make(map[uintptr]*callerFrame, len(old)+1)
+	for k, v := range old {
+		next[k] = v
+	}
+	next[pc] = f
+	c.frames.Store(&next)
+	return f
+}
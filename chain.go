@@ -0,0 +1,175 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\chain.go
+ * @Description: 按级别链式记录日志，级别未开启时返回 nil 实现零开销
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// LevelLogger 链式日志记录器
+// 级别未开启时 AtXxx 返回 nil，nil 上的所有方法均为空操作，不分配也不格式化：
+//
+//	log.AtDebug().WithField("user", id).Msg("loaded")
+//
+// Msg/Msgf 调用后对象归还池中，不可再使用
+type LevelLogger struct {
+	logger *Logger
+	level  LogLevel
+	fields []byte // 已编码的 "k: v, k2: v2"
+	kv     []any  // 原始键值对（仅结构化管道启用时记录）
+}
+
+// levelLoggerPool 链式日志记录器池
+var levelLoggerPool = newStatPool("level_logger", func() any {
+	return &LevelLogger{fields: make([]byte, 0, 256)}
+})
+
+// AtLevel 返回指定级别的链式记录器，级别未开启时返回 nil
+func (l *Logger) AtLevel(level LogLevel) *LevelLogger {
+	if level < l.level {
+		return nil
+	}
+	e := levelLoggerPool.Get().(*LevelLogger)
+	e.logger = l
+	e.level = level
+	e.fields = e.fields[:0]
+	e.kv = e.kv[:0]
+	return e
+}
+
+// AtDebug 返回 DEBUG 级别的链式记录器
+func (l *Logger) AtDebug() *LevelLogger {
+	return l.AtLevel(DEBUG)
+}
+
+// AtInfo 返回 INFO 级别的链式记录器
+func (l *Logger) AtInfo() *LevelLogger {
+	return l.AtLevel(INFO)
+}
+
+// AtWarn 返回 WARN 级别的链式记录器
+func (l *Logger) AtWarn() *LevelLogger {
+	return l.AtLevel(WARN)
+}
+
+// AtError 返回 ERROR 级别的链式记录器
+func (l *Logger) AtError() *LevelLogger {
+	return l.AtLevel(ERROR)
+}
+
+// Enabled 级别是否开启（可用于跳过昂贵的参数计算）
+func (e *LevelLogger) Enabled() bool {
+	return e != nil
+}
+
+// WithField 追加字段
+func (e *LevelLogger) WithField(key string, value any) *LevelLogger {
+	if e == nil {
+		return nil
+	}
+	if e.logger.hasPipeline() {
+		e.kv = append(e.kv, key, value)
+		return e
+	}
+	if lf, ok := value.(LevelField); ok {
+		if !lf.allows(e.level) {
+			return e
+		}
+		value = lf.Value
+	}
+	e.fields, _ = e.logger.appendField(e.fields, len(e.fields) > 0, key, value)
+	return e
+}
+
+// WithFields 追加多个字段
+func (e *LevelLogger) WithFields(fields map[string]any) *LevelLogger {
+	if e == nil || len(fields) == 0 {
+		return e
+	}
+	if e.logger.hasPipeline() {
+		for k, v := range fields {
+			e.kv = append(e.kv, k, v)
+		}
+		return e
+	}
+	e.fields, _ = e.logger.appendFieldsMap(e.fields, fields, len(e.fields) > 0)
+	return e
+}
+
+// WithError 追加错误字段（err 为 nil 时忽略）
+func (e *LevelLogger) WithError(err error) *LevelLogger {
+	if e == nil || err == nil {
+		return e
+	}
+	return e.WithField("error", err.Error())
+}
+
+// Msg 写出日志并归还记录器
+func (e *LevelLogger) Msg(msg string) {
+	if e == nil {
+		return
+	}
+	e.logger.logEncoded(e.level, msg, e.fields, e.kv)
+	e.release()
+}
+
+// Msgf 格式化后写出日志并归还记录器
+func (e *LevelLogger) Msgf(format string, args ...any) {
+	if e == nil {
+		return
+	}
+	e.logger.logEncoded(e.level, fmt.Sprintf(format, args...), e.fields, e.kv)
+	e.release()
+}
+
+// release 归还到池中
+func (e *LevelLogger) release() {
+	e.logger = nil
+	clear(e.kv)
+	if cap(e.fields) > maxPooledBufferSize {
+		e.fields = make([]byte, 0, 256)
+	}
+	levelLoggerPool.Put(e)
+}
+
+// logEncoded 使用已编码字段记录日志（结构化管道启用时使用原始键值对）
+func (l *Logger) logEncoded(level LogLevel, msg string, encoded []byte, keysAndValues []any) {
+	if l.hasPipeline() {
+		l.dispatch(level, msg, msg, nil, keysAndValues, 2)
+		return
+	}
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 2)
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(msg)...)
+	buf = l.limitMessage(buf, msgStart)
+	if len(encoded) > 0 || l.hasStaticFields() {
+		start := len(buf)
+		buf = append(buf, kvBraceOpen...)
+		var wrote bool
+		buf, wrote = l.appendStaticFields(buf, level)
+		if wrote && len(encoded) > 0 {
+			buf = append(buf, kvDelimiter...)
+		}
+		buf = append(buf, encoded...)
+		if wrote || len(encoded) > 0 {
+			buf = append(buf, kvBraceClose...)
+		} else {
+			buf = buf[:start]
+		}
+	}
+	buf = append(buf, newline...)
+
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
+}
@@ -0,0 +1,115 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\checksum.go
+ * @Description: 轮转文件 SHA256 校验和文件（与 sha256sum 格式兼容），用于校验转存到冷存储的归档日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumExt 校验和文件扩展名（app.log.1.gz 对应 app.log.1.gz.sha256）
+const ChecksumExt = ".sha256"
+
+// ErrChecksumMismatch 文件内容与校验和文件不一致
+var ErrChecksumMismatch = errors.New("logger: checksum mismatch")
+
+// WithRotateChecksum 设置是否为轮转（及压缩）后的文件生成 SHA256 校验和文件
+func WithRotateChecksum(enabled bool) RotateWriterOption {
+	return func(w *RotateLogWriter) {
+		w.checksum = enabled
+	}
+}
+
+// FileChecksum 计算文件的 SHA256（十六进制）
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteChecksumFile 计算 path 的 SHA256 并写入 path+ChecksumExt，返回校验和
+// 文件内容为 "<sha256>  <文件名>"，可直接使用 sha256sum -c 校验
+func WriteChecksumFile(path string) (string, error) {
+	sum, err := FileChecksum(path)
+	if err != nil {
+		return "", err
+	}
+	if err := writeChecksumLine(path, sum); err != nil {
+		return "", err
+	}
+	return sum, nil
+}
+
+// writeChecksumLine 写入 path 的校验和文件（先写临时文件再重命名）
+func writeChecksumLine(path, sum string) error {
+	line := sum + "  " + filepath.Base(path) + "\n"
+	tmp := path + ChecksumExt + ".tmp"
+	if err := os.WriteFile(tmp, []byte(line), DefaultFilePermission); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path+ChecksumExt); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// moveChecksumFile 随轮转重命名校验和文件，并将其中记录的文件名更新为 newPath
+func moveChecksumFile(oldPath, newPath string) error {
+	data, err := os.ReadFile(oldPath + ChecksumExt)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return os.Remove(oldPath + ChecksumExt)
+	}
+	if err := writeChecksumLine(newPath, fields[0]); err != nil {
+		return err
+	}
+	return os.Remove(oldPath + ChecksumExt)
+}
+
+// VerifyChecksumFile 使用 path+ChecksumExt 校验 path，不一致时返回 ErrChecksumMismatch
+func VerifyChecksumFile(path string) error {
+	data, err := os.ReadFile(path + ChecksumExt)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return fmt.Errorf("invalid checksum file %s", path+ChecksumExt)
+	}
+	want := strings.ToLower(fields[0])
+
+	got, err := FileChecksum(path)
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("%w: %s (expected %s, got %s)", ErrChecksumMismatch, path, want, got)
+	}
+	return nil
+}
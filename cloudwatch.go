@@ -0,0 +1,407 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\cloudwatch.go
+ * @Description: AWS CloudWatch Logs 适配器（PutLogEvents，自动创建日志组与日志流，按接口大小限制拆分批次）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CloudWatch Logs 接口限制
+const (
+	CloudWatchMaxBatchEvents = 10000            // 每批最多事件数
+	CloudWatchMaxBatchBytes  = 1024 * 1024      // 每批最大字节数（消息字节数 + 每条 26 字节）
+	CloudWatchMaxEventBytes  = 256 * 1024       // 单条事件最大字节数
+	cloudWatchEventOverhead  = 26               // 每条事件的计费开销
+	cloudWatchMaxSpan        = 24 * time.Hour   // 一批事件的最大时间跨度
+	cloudWatchTargetPrefix   = "Logs_20140328." // X-Amz-Target 前缀
+)
+
+// ErrCloudWatchConfig CloudWatch 适配器缺少必要配置
+var ErrCloudWatchConfig = errors.New("logger: cloudwatch log group, stream and region are required")
+
+// CloudWatchAdapter AWS CloudWatch Logs 适配器：
+//
+//	cw := logger.NewCloudWatchAdapter(logger.WithCloudWatchGroup("/ecs/checkout"), logger.WithCloudWatchRetention(30))
+//	if err := cw.Initialize(); err != nil { ... }
+//	log := logger.NewLogger().WithAdapters(cw)
+//
+// 每条日志编码为一行 JSON（level、message 与字段）作为事件消息，便于 Logs Insights 查询；
+// 使用当前的 PutLogEvents 语义（不需要序列号，同一日志流允许并发写入），日志组或日志流不存在时自动创建后重试。
+// 区域、凭证、日志组与日志流默认读取 AWS_REGION、环境变量 / ECS 任务角色凭证、
+// AWS_LAMBDA_LOG_GROUP_NAME 与 AWS_LAMBDA_LOG_STREAM_NAME（日志流缺省为主机名）
+type CloudWatchAdapter struct {
+	*BaseAdapter
+	cfg           httpBatchConfig
+	region        string
+	endpoint      string
+	group         string
+	stream        string
+	credentials   AWSCredentialsProvider
+	createGroup   bool
+	retentionDays int
+	maxBatchBytes int
+	sender        *httpBatchSender
+}
+
+// CloudWatchOption CloudWatch 适配器配置选项
+type CloudWatchOption func(*CloudWatchAdapter)
+
+// WithCloudWatchRegion 设置区域
+func WithCloudWatchRegion(region string) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.region = region
+	}
+}
+
+// WithCloudWatchEndpoint 设置接口地址（VPC 终端节点或本地模拟服务，默认 https://logs.<region>.amazonaws.com）
+func WithCloudWatchEndpoint(endpoint string) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithCloudWatchGroup 设置日志组
+func WithCloudWatchGroup(group string) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.group = group
+	}
+}
+
+// WithCloudWatchStream 设置日志流
+func WithCloudWatchStream(stream string) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.stream = stream
+	}
+}
+
+// WithCloudWatchCredentials 设置凭证来源（默认 DefaultAWSCredentials）
+func WithCloudWatchCredentials(provider AWSCredentialsProvider) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if provider != nil {
+			a.credentials = provider
+		}
+	}
+}
+
+// WithCloudWatchCreateGroup 设置日志组不存在时是否创建（默认开启，日志流总是按需创建）
+func WithCloudWatchCreateGroup(enabled bool) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.createGroup = enabled
+	}
+}
+
+// WithCloudWatchRetention 设置创建日志组时的保留天数（0 表示永久保留）
+func WithCloudWatchRetention(days int) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.retentionDays = days
+	}
+}
+
+// WithCloudWatchMaxBatchBytes 设置每批最大字节数（默认且最大为 CloudWatchMaxBatchBytes）
+func WithCloudWatchMaxBatchBytes(size int) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if size > 0 {
+			a.maxBatchBytes = min(size, CloudWatchMaxBatchBytes)
+		}
+	}
+}
+
+// WithCloudWatchBatch 设置每批最大事件数与刷新间隔
+func WithCloudWatchBatch(size int, interval time.Duration) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if size > 0 {
+			a.cfg.batchSize = min(size, CloudWatchMaxBatchEvents)
+		}
+		if interval > 0 {
+			a.cfg.flushInterval = interval
+		}
+	}
+}
+
+// WithCloudWatchHTTPClient 设置 HTTP 客户端
+func WithCloudWatchHTTPClient(client *http.Client) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if client != nil {
+			a.cfg.client = client
+		}
+	}
+}
+
+// WithCloudWatchTLS 设置 TLS 配置（CA 证书、客户端证书等，见 TLSConfig.Build），应用到 HTTP 客户端的 Transport
+func WithCloudWatchTLS(conf *tls.Config) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		a.cfg.tls = conf
+	}
+}
+
+// WithCloudWatchQueueSize 设置发送队列长度（默认 DefaultHTTPBatchQueueSize）
+func WithCloudWatchQueueSize(size int) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if size > 0 {
+			a.cfg.queueSize = size
+		}
+	}
+}
+
+// WithCloudWatchRetries 设置每批失败重试次数（默认 DefaultHTTPBatchRetries）
+func WithCloudWatchRetries(retries int) CloudWatchOption {
+	return func(a *CloudWatchAdapter) {
+		if retries >= 0 {
+			a.cfg.retries = retries
+		}
+	}
+}
+
+// NewCloudWatchAdapter 创建 CloudWatch Logs 适配器并启动发送协程
+func NewCloudWatchAdapter(opts ...CloudWatchOption) *CloudWatchAdapter {
+	a := &CloudWatchAdapter{
+		cfg:           defaultHTTPBatchConfig(CloudWatchMaxBatchEvents),
+		region:        awsRegion(),
+		group:         os.Getenv("AWS_LAMBDA_LOG_GROUP_NAME"),
+		stream:        os.Getenv("AWS_LAMBDA_LOG_STREAM_NAME"),
+		credentials:   DefaultAWSCredentials(),
+		createGroup:   true,
+		maxBatchBytes: CloudWatchMaxBatchBytes,
+	}
+	if a.stream == "" {
+		a.stream, _ = os.Hostname()
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.cfg.resolveClient()
+	if a.endpoint == "" {
+		a.endpoint = "https://logs." + a.region + ".amazonaws.com"
+	}
+
+	a.BaseAdapter = NewBaseAdapter("cloudwatch", "1.0.0", a.emit)
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-amz-json-1.1")
+	header.Set("X-Amz-Target", cloudWatchTargetPrefix+"PutLogEvents")
+	a.sender = newHTTPBatchSender(a.cfg, httpBatchSpec{
+		component: "cloudwatch",
+		url:       a.endpoint + "/",
+		header:    header,
+		encode:    a.encode,
+		onHealth:  a.SetHealthy,
+		split:     a.split,
+		sign:      a.sign,
+		recover:   a.recover,
+		response:  a.response,
+	})
+	return a
+}
+
+// Initialize 校验配置并确保日志组与日志流存在
+func (a *CloudWatchAdapter) Initialize() error {
+	if a.group == "" || a.stream == "" || a.region == "" {
+		return ErrCloudWatchConfig
+	}
+	return a.ensureStream(context.Background())
+}
+
+// emit 日志入队（消息编码为一行 JSON，超过单条事件上限时截断）
+func (a *CloudWatchAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	event := make(map[string]any, len(fields)+2)
+	for k, v := range fields {
+		event[k] = v
+	}
+	event["level"] = level.String()
+	event["message"] = msg
+	data, err := marshalJSON(event)
+	if err != nil {
+		data = []byte(msg)
+	}
+	if limit := CloudWatchMaxEventBytes - cloudWatchEventOverhead; len(data) > limit {
+		// 回退到 UTF-8 字符边界，CloudWatch 拒绝含非法 UTF-8 的事件
+		for limit > 0 && !utf8.RuneStart(data[limit]) {
+			limit--
+		}
+		data = data[:limit]
+	}
+	a.sender.enqueue(httpBatchRecord{time: time.Now(), level: level, message: string(data)})
+}
+
+// split 按时间排序后按事件数、字节数与时间跨度限制拆分
+func (a *CloudWatchAdapter) split(batch []httpBatchRecord) [][]httpBatchRecord {
+	sorted := append([]httpBatchRecord(nil), batch...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].time.Before(sorted[j].time)
+	})
+
+	var parts [][]httpBatchRecord
+	start, size := 0, 0
+	for i, r := range sorted {
+		eventSize := len(r.message) + cloudWatchEventOverhead
+		if i > start && (i-start >= a.cfg.batchSize || size+eventSize > a.maxBatchBytes ||
+			r.time.Sub(sorted[start].time) > cloudWatchMaxSpan) {
+			parts = append(parts, sorted[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	return append(parts, sorted[start:])
+}
+
+// cloudWatchEvent PutLogEvents 事件
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// encode 编码 PutLogEvents 请求
+func (a *CloudWatchAdapter) encode(batch []httpBatchRecord) ([]byte, error) {
+	events := make([]cloudWatchEvent, len(batch))
+	for i, r := range batch {
+		events[i] = cloudWatchEvent{Timestamp: r.time.UnixMilli(), Message: r.message}
+	}
+	return json.Marshal(map[string]any{
+		"logGroupName":  a.group,
+		"logStreamName": a.stream,
+		"logEvents":     events,
+	})
+}
+
+// sign 使用当前凭证签名请求
+func (a *CloudWatchAdapter) sign(req *http.Request, body []byte) error {
+	creds, err := a.credentials(req.Context())
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	signAWSV4(req, hex.EncodeToString(sum[:]), "logs", a.region, creds, time.Now())
+	return nil
+}
+
+// recover 日志组或日志流不存在时创建后重试
+func (a *CloudWatchAdapter) recover(err *httpStatusError) bool {
+	if awsErrorType(err.body) != "ResourceNotFoundException" {
+		return false
+	}
+	if cerr := a.ensureStream(context.Background()); cerr != nil {
+		reportInternalError("cloudwatch", cerr)
+		return false
+	}
+	return true
+}
+
+// response 上报被拒绝的事件（时间过早、过晚或已过期）
+func (a *CloudWatchAdapter) response(body []byte) {
+	var resp struct {
+		Rejected map[string]int `json:"rejectedLogEventsInfo"`
+	}
+	if json.Unmarshal(body, &resp) == nil && len(resp.Rejected) > 0 {
+		reportInternalError("cloudwatch", fmt.Errorf("cloudwatch rejected log events: %v", resp.Rejected))
+	}
+}
+
+// ensureStream 创建日志组（可选）与日志流，已存在时忽略
+func (a *CloudWatchAdapter) ensureStream(ctx context.Context) error {
+	if a.createGroup {
+		err := a.call(ctx, "CreateLogGroup", map[string]any{"logGroupName": a.group})
+		if err == nil && a.retentionDays > 0 {
+			err = a.call(ctx, "PutRetentionPolicy", map[string]any{"logGroupName": a.group, "retentionInDays": a.retentionDays})
+		}
+		if err != nil && !isAWSAlreadyExists(err) {
+			return err
+		}
+	}
+	err := a.call(ctx, "CreateLogStream", map[string]any{"logGroupName": a.group, "logStreamName": a.stream})
+	if err != nil && !isAWSAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// call 调用 CloudWatch Logs 管理接口
+func (a *CloudWatchAdapter) call(ctx context.Context, action string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", cloudWatchTargetPrefix+action)
+	if err := a.sign(req, body); err != nil {
+		return err
+	}
+
+	resp, err := a.cfg.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("cloudwatch %s: %w", action, &httpStatusError{status: resp.StatusCode, body: strings.TrimSpace(string(msg))})
+	}
+	return nil
+}
+
+// isAWSAlreadyExists 是否为资源已存在错误
+func isAWSAlreadyExists(err error) bool {
+	var statusErr *httpStatusError
+	return errors.As(err, &statusErr) && awsErrorType(statusErr.body) == "ResourceAlreadyExistsException"
+}
+
+// awsErrorType 从 JSON 协议错误响应中提取错误类型（去掉命名空间前缀）
+func awsErrorType(body string) string {
+	var resp struct {
+		Type string `json:"__type"`
+	}
+	if json.Unmarshal([]byte(body), &resp) != nil {
+		return ""
+	}
+	if i := strings.LastIndexByte(resp.Type, '#'); i >= 0 {
+		return resp.Type[i+1:]
+	}
+	return resp.Type
+}
+
+// Flush 立即发送队列中的日志并等待结果
+func (a *CloudWatchAdapter) Flush() error {
+	return a.sender.flush()
+}
+
+// Close 发送剩余日志后关闭
+func (a *CloudWatchAdapter) Close() error {
+	a.sender.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *CloudWatchAdapter) QueueDepth() (depth, capacity int) {
+	return a.sender.queueDepth()
+}
+
+// Stats 返回发送统计
+func (a *CloudWatchAdapter) Stats() HTTPBatchStats {
+	return a.sender.stats()
+}
+
+var _ IAdapter = (*CloudWatchAdapter)(nil)
@@ -0,0 +1,87 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\cloudwatch_test.go
+ * @Description: CloudWatch Logs 适配器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestCloudWatchTruncatesOnRuneBoundary 超过单条事件上限的消息在 UTF-8 字符边界截断
+func TestCloudWatchTruncatesOnRuneBoundary(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []cloudWatchEvent
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(auth, "/us-east-1/logs/aws4_request") {
+			t.Errorf("unexpected Authorization %q", auth)
+		}
+		if r.Header.Get("X-Amz-Target") != cloudWatchTargetPrefix+"PutLogEvents" {
+			w.Write([]byte(`{}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			LogGroupName  string            `json:"logGroupName"`
+			LogStreamName string            `json:"logStreamName"`
+			LogEvents     []cloudWatchEvent `json:"logEvents"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("decode PutLogEvents: %v", err)
+		}
+		if req.LogGroupName != "/test/group" || req.LogStreamName != "stream-1" {
+			t.Errorf("group/stream = %q/%q", req.LogGroupName, req.LogStreamName)
+		}
+		mu.Lock()
+		events = append(events, req.LogEvents...)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	a := NewCloudWatchAdapter(
+		WithCloudWatchEndpoint(srv.URL),
+		WithCloudWatchRegion("us-east-1"),
+		WithCloudWatchGroup("/test/group"),
+		WithCloudWatchStream("stream-1"),
+		WithCloudWatchCredentials(StaticAWSCredentials("AKIDEXAMPLE", "secret", "")),
+	)
+	defer a.Close()
+	if err := a.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	// 3 字节的字符，使上限落在字符中间
+	a.Info(strings.Repeat("日", CloudWatchMaxEventBytes/3))
+	if err := a.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	msg := events[0].Message
+	if len(msg) > CloudWatchMaxEventBytes-cloudWatchEventOverhead {
+		t.Errorf("event is %d bytes, over the limit", len(msg))
+	}
+	if !utf8.ValidString(msg) {
+		t.Error("truncated event is not valid UTF-8")
+	}
+}
@@ -0,0 +1,317 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\cmd\logctl\main.go
+ * @Description: logctl 命令行工具：美化、过滤、格式转换、跟随日志文件，以及运行时修改日志级别
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/tailer"
+)
+
+const usage = `logctl - go-logger 命令行工具
+
+用法:
+  logctl pretty  [flags] [file...]   美化输出（JSON 或文本日志，未指定文件时读取 stdin）
+  logctl filter  [flags] [file...]   按级别、字段、关键字过滤
+  logctl convert [flags] [file...]   转换格式（-to json|text）
+  logctl tail    [flags] file        跟随日志文件（支持轮转）
+  logctl level   [flags] [LEVEL]     查看或修改运行中服务的日志级别
+  logctl collect [flags]             接收 unix 数据报适配器发送的日志
+
+执行 logctl <command> -h 查看各命令参数
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "pretty":
+		err = runPretty(args)
+	case "filter":
+		err = runFilter(args)
+	case "convert":
+		err = runConvert(args)
+	case "tail":
+		err = runTail(args)
+	case "level":
+		err = runLevel(args)
+	case "collect":
+		err = runCollect(args)
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logctl:", err)
+		os.Exit(1)
+	}
+}
+
+// criteriaFlags 过滤参数
+type criteriaFlags struct {
+	level  string
+	text   string
+	since  string
+	until  string
+	fields multiFlag
+}
+
+// multiFlag 可重复的字符串参数
+type multiFlag []string
+
+func (m *multiFlag) String() string     { return strings.Join(*m, ",") }
+func (m *multiFlag) Set(v string) error { *m = append(*m, v); return nil }
+
+// register 注册过滤参数
+func (c *criteriaFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.level, "level", "", "最低级别（debug/info/warn/error/fatal）")
+	fs.StringVar(&c.text, "text", "", "消息或字段值包含的关键字（不区分大小写）")
+	fs.StringVar(&c.since, "since", "", "起始时间（RFC3339 或相对时长，如 15m）")
+	fs.StringVar(&c.until, "until", "", "结束时间（RFC3339 或相对时长）")
+	fs.Var(&c.fields, "field", "字段匹配 key=value（可重复）")
+}
+
+// criteria 转换为查询条件
+func (c *criteriaFlags) criteria() (logger.QueryCriteria, error) {
+	var q logger.QueryCriteria
+	var err error
+	if c.level != "" {
+		if q.Level, err = logger.ParseLevel(c.level); err != nil {
+			return q, err
+		}
+	}
+	if q.Since, err = parseTimeFlag(c.since); err != nil {
+		return q, err
+	}
+	if q.Until, err = parseTimeFlag(c.until); err != nil {
+		return q, err
+	}
+	q.Text = c.text
+	for _, f := range c.fields {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			return q, fmt.Errorf("invalid -field %q, want key=value", f)
+		}
+		if q.Fields == nil {
+			q.Fields = make(map[string]string)
+		}
+		q.Fields[k] = v
+	}
+	return q, nil
+}
+
+// parseTimeFlag 解析 RFC3339 时间或相对当前的时长
+func parseTimeFlag(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// eachEntry 依次读取各文件（为空时读取 stdin）中的日志
+func eachEntry(files []string, fn func(logger.Entry) bool) error {
+	if len(files) == 0 {
+		return tailer.ReadEntries(os.Stdin, fn)
+	}
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		err = tailer.ReadEntries(f, fn)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPretty 美化输出
+func runPretty(args []string) error {
+	fs := flag.NewFlagSet("pretty", flag.ExitOnError)
+	color := fs.Bool("color", isTerminal(os.Stdout), "彩色输出")
+	fs.Parse(args)
+
+	out := newPrinter(os.Stdout, formatText, *color)
+	return eachEntry(fs.Args(), out.print)
+}
+
+// runFilter 过滤
+func runFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	var cf criteriaFlags
+	cf.register(fs)
+	format := fs.String("o", formatText, "输出格式（text|json）")
+	color := fs.Bool("color", isTerminal(os.Stdout), "彩色输出（text 格式）")
+	fs.Parse(args)
+
+	criteria, err := cf.criteria()
+	if err != nil {
+		return err
+	}
+	out := newPrinter(os.Stdout, *format, *color)
+	return eachEntry(fs.Args(), func(e logger.Entry) bool {
+		if criteria.Match(e) {
+			return out.print(e)
+		}
+		return true
+	})
+}
+
+// runConvert 格式转换
+func runConvert(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	to := fs.String("to", formatJSON, "目标格式（json|text）")
+	fs.Parse(args)
+
+	out := newPrinter(os.Stdout, *to, false)
+	return eachEntry(fs.Args(), out.print)
+}
+
+// runTail 跟随日志文件
+func runTail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	var cf criteriaFlags
+	cf.register(fs)
+	fromStart := fs.Bool("from-start", false, "从文件开头读取")
+	format := fs.String("o", formatText, "输出格式（text|json）")
+	color := fs.Bool("color", isTerminal(os.Stdout), "彩色输出（text 格式）")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("tail requires exactly one file")
+	}
+
+	criteria, err := cf.criteria()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var opts []tailer.Option
+	if *fromStart {
+		opts = append(opts, tailer.WithFromStart())
+	}
+	t := tailer.Follow(ctx, fs.Arg(0), opts...)
+	out := newPrinter(os.Stdout, *format, *color)
+	for e := range t.Entries() {
+		if criteria.Match(e) && !out.print(e) {
+			t.Stop()
+		}
+	}
+	return t.Err()
+}
+
+// runLevel 查看或修改日志级别（DebugHandler 的 /debug/logger/level 接口）
+func runLevel(args []string) error {
+	fs := flag.NewFlagSet("level", flag.ExitOnError)
+	addr := fs.String("url", "http://127.0.0.1:8080"+logger.DebugPath, "调试接口地址")
+	timeout := fs.Duration("timeout", 5*time.Second, "请求超时")
+	fs.Parse(args)
+
+	endpoint := strings.TrimRight(*addr, "/") + "/level"
+	client := &http.Client{Timeout: *timeout}
+
+	var resp *http.Response
+	var err error
+	if fs.NArg() == 0 {
+		resp, err = client.Get(endpoint)
+	} else {
+		level, perr := logger.ParseLevel(fs.Arg(0))
+		if perr != nil {
+			return perr
+		}
+		body, _ := json.Marshal(map[string]string{"level": level.String()})
+		req, rerr := http.NewRequest(http.MethodPut, endpoint, bytes.NewReader(body))
+		if rerr != nil {
+			return rerr
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+	}
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	var result struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	fmt.Println(result.Level)
+	return nil
+}
+
+// runCollect 接收 UnixgramAdapter 发送的日志并输出（只读根文件系统容器的边车采集示例）
+func runCollect(args []string) error {
+	fs := flag.NewFlagSet("collect", flag.ExitOnError)
+	socket := fs.String("socket", logger.DefaultUnixgramPath, "数据报套接字路径")
+	var cf criteriaFlags
+	cf.register(fs)
+	format := fs.String("o", formatJSON, "输出格式（text|json）")
+	color := fs.Bool("color", isTerminal(os.Stdout), "彩色输出（text 格式）")
+	fs.Parse(args)
+
+	criteria, err := cf.criteria()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	out := newPrinter(os.Stdout, *format, *color)
+	collector, err := logger.ListenUnixgram(*socket, func(datagram []byte) {
+		if e, ok := tailer.Parse(bytes.TrimSpace(datagram)); ok && criteria.Match(e) {
+			out.print(e)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return collector.Close()
+}
+
+// isTerminal 判断是否输出到终端
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
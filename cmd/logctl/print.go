@@ -0,0 +1,109 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\cmd\logctl\print.go
+ * @Description: 日志条目的文本与 JSON 输出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/ndjson"
+)
+
+// 输出格式
+const (
+	formatText = "text"
+	formatJSON = "json"
+)
+
+// printer 日志输出
+type printer struct {
+	w      *bufio.Writer
+	format string
+	color  bool
+}
+
+// newPrinter 创建输出（未知格式按文本输出）
+func newPrinter(w io.Writer, format string, color bool) *printer {
+	if format != formatJSON {
+		format = formatText
+	}
+	return &printer{w: bufio.NewWriter(w), format: format, color: color}
+}
+
+// print 输出一条日志，写入失败（如管道关闭）时返回 false
+func (p *printer) print(e logger.Entry) bool {
+	if p.format == formatJSON {
+		data, err := ndjson.Marshal(e)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "logctl:", err)
+			return true
+		}
+		p.w.Write(data)
+	} else {
+		p.printText(e)
+	}
+	// 逐条刷新，保证 tail 实时输出
+	return p.w.Flush() == nil
+}
+
+// printText 以 "时间 级别 [caller] 消息 k=v" 形式输出
+func (p *printer) printText(e logger.Entry) {
+	if !e.Time.IsZero() {
+		p.paint("\033[90m", e.Time.Format(time.DateTime))
+		p.w.WriteByte(' ')
+	}
+	p.paint(e.Level.Color(), fmt.Sprintf("%-5s", e.Level.String()))
+	p.w.WriteByte(' ')
+	if caller, ok := e.Fields[ndjson.CallerKey]; ok {
+		p.paint("\033[90m", fmt.Sprintf("[%v] ", caller))
+	}
+	p.w.WriteString(e.Message)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		if k != ndjson.CallerKey {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		p.w.WriteByte(' ')
+		p.paint("\033[36m", k+"=")
+		p.w.WriteString(formatValue(e.Fields[k]))
+	}
+	p.w.WriteByte('\n')
+}
+
+// paint 按需加颜色输出
+func (p *printer) paint(color, s string) {
+	if p.color && color != "" {
+		p.w.WriteString(color)
+		p.w.WriteString(s)
+		p.w.WriteString("\033[0m")
+		return
+	}
+	p.w.WriteString(s)
+}
+
+// formatValue 字段值包含空白时加引号
+func formatValue(v any) string {
+	s := fmt.Sprint(v)
+	if strings.ContainsAny(s, " \t\n\"") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
@@ -0,0 +1,518 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\components.go
+ * @Description: 按名称声明钩子与中间件（可写在 YAML/JSON 配置中），由注册的工厂函数实例化
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnknownComponent 未注册的钩子或中间件名称
+var ErrUnknownComponent = errors.New("logger: unknown component")
+
+// ComponentConfig 按名称声明的钩子或中间件及其参数
+type ComponentConfig struct {
+	Name   string          `json:"name" yaml:"name"`                         // 注册的工厂名称
+	Params ComponentParams `json:"params,omitempty" yaml:"params,omitempty"` // 工厂参数
+}
+
+// ComponentsConfig 钩子与中间件声明，可直接嵌入应用的配置结构：
+//
+//	logging:
+//	  hooks:
+//	    - name: webhook
+//	      params: {platform: slack, url: "${env:SLACK_WEBHOOK_URL}", levels: [error, fatal]}
+//	  middleware:
+//	    - name: redact
+//	      params: {keys: [password, token], action: mask}
+//	    - name: sampling
+//	      params: {every: 10}
+type ComponentsConfig struct {
+	Hooks      []ComponentConfig `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Middleware []ComponentConfig `json:"middleware,omitempty" yaml:"middleware,omitempty"`
+}
+
+// HookFactory 钩子工厂函数
+type HookFactory func(params ComponentParams) (IHook, error)
+
+// MiddlewareFactory 中间件工厂函数
+type MiddlewareFactory func(params ComponentParams) (IMiddleware, error)
+
+var (
+	componentMu         sync.RWMutex
+	hookFactories       = make(map[string]HookFactory)
+	middlewareFactories = make(map[string]MiddlewareFactory)
+)
+
+// RegisterHookFactory 注册钩子工厂（名称不区分大小写，重复注册时覆盖）
+func RegisterHookFactory(name string, factory HookFactory) {
+	componentMu.Lock()
+	defer componentMu.Unlock()
+	hookFactories[strings.ToLower(name)] = factory
+}
+
+// RegisterMiddlewareFactory 注册中间件工厂（名称不区分大小写，重复注册时覆盖）
+func RegisterMiddlewareFactory(name string, factory MiddlewareFactory) {
+	componentMu.Lock()
+	defer componentMu.Unlock()
+	middlewareFactories[strings.ToLower(name)] = factory
+}
+
+// HookFactories 返回已注册的钩子名称（已排序）
+func HookFactories() []string {
+	componentMu.RLock()
+	defer componentMu.RUnlock()
+	names := make([]string, 0, len(hookFactories))
+	for name := range hookFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MiddlewareFactories 返回已注册的中间件名称（已排序）
+func MiddlewareFactories() []string {
+	componentMu.RLock()
+	defer componentMu.RUnlock()
+	names := make([]string, 0, len(middlewareFactories))
+	for name := range middlewareFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewHookFromConfig 按声明创建钩子，参数中的 ${env:NAME} 等密钥引用在调用工厂前展开
+func NewHookFromConfig(cfg ComponentConfig) (IHook, error) {
+	componentMu.RLock()
+	factory, ok := hookFactories[strings.ToLower(cfg.Name)]
+	componentMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: hook %q", ErrUnknownComponent, cfg.Name)
+	}
+	params, err := cfg.Params.ResolveSecrets(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("hook %q: %w", cfg.Name, err)
+	}
+	hook, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("hook %q: %w", cfg.Name, err)
+	}
+	return hook, nil
+}
+
+// NewMiddlewareFromConfig 按声明创建中间件，参数中的密钥引用在调用工厂前展开
+func NewMiddlewareFromConfig(cfg ComponentConfig) (IMiddleware, error) {
+	componentMu.RLock()
+	factory, ok := middlewareFactories[strings.ToLower(cfg.Name)]
+	componentMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: middleware %q", ErrUnknownComponent, cfg.Name)
+	}
+	params, err := cfg.Params.ResolveSecrets(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("middleware %q: %w", cfg.Name, err)
+	}
+	mw, err := factory(params)
+	if err != nil {
+		return nil, fmt.Errorf("middleware %q: %w", cfg.Name, err)
+	}
+	return mw, nil
+}
+
+// ApplyComponents 按声明创建钩子与中间件并追加到 Logger，任一组件创建失败时不做任何修改
+func (l *Logger) ApplyComponents(cfg ComponentsConfig) error {
+	hooks := append([]IHook(nil), l.hooks...)
+	for _, c := range cfg.Hooks {
+		hook, err := NewHookFromConfig(c)
+		if err != nil {
+			return err
+		}
+		hooks = append(hooks, hook)
+	}
+	middleware := append([]IMiddleware(nil), l.middleware...)
+	for _, c := range cfg.Middleware {
+		mw, err := NewMiddlewareFromConfig(c)
+		if err != nil {
+			return err
+		}
+		middleware = append(middleware, mw)
+	}
+	l.WithHooks(hooks).WithMiddleware(middleware)
+	return nil
+}
+
+// ============================================================================
+// 组件参数
+// ============================================================================
+
+// ComponentParams 组件参数（YAML/JSON 解码后的原始值），读取方法在类型不符时返回错误
+type ComponentParams map[string]any
+
+// String 读取字符串参数，不存在时返回 def
+func (p ComponentParams) String(key, def string) (string, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return def, fmt.Errorf("param %q: want string, got %T", key, v)
+	}
+	return s, nil
+}
+
+// Int 读取整数参数，不存在时返回 def
+func (p ComponentParams) Int(key string, def int) (int, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	case uint64:
+		return int(n), nil
+	case float64:
+		if n == float64(int(n)) {
+			return int(n), nil
+		}
+	case string:
+		if i, err := strconv.Atoi(n); err == nil {
+			return i, nil
+		}
+	}
+	return def, fmt.Errorf("param %q: want integer, got %v", key, v)
+}
+
+// Bool 读取布尔参数，不存在时返回 def
+func (p ComponentParams) Bool(key string, def bool) (bool, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		if parsed, err := strconv.ParseBool(b); err == nil {
+			return parsed, nil
+		}
+	}
+	return def, fmt.Errorf("param %q: want bool, got %v", key, v)
+}
+
+// Duration 读取时长参数（"5s" 形式的字符串或秒数），不存在时返回 def
+func (p ComponentParams) Duration(key string, def time.Duration) (time.Duration, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return def, nil
+	}
+	if s, ok := v.(string); ok {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return def, fmt.Errorf("param %q: %w", key, err)
+		}
+		return d, nil
+	}
+	switch n := v.(type) {
+	case int:
+		return time.Duration(n) * time.Second, nil
+	case int64:
+		return time.Duration(n) * time.Second, nil
+	case float64:
+		return time.Duration(n * float64(time.Second)), nil
+	}
+	return def, fmt.Errorf("param %q: want duration, got %v", key, v)
+}
+
+// Strings 读取字符串列表参数（也接受单个字符串），不存在时返回 nil
+func (p ComponentParams) Strings(key string) ([]string, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	switch list := v.(type) {
+	case string:
+		return []string{list}, nil
+	case []string:
+		return list, nil
+	case []any:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("param %q: want string list, got element %T", key, item)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("param %q: want string list, got %T", key, v)
+}
+
+// componentSection 将嵌套的参数段（YAML/JSON 解码后的 map）转换为 ComponentParams
+func componentSection(v any) (ComponentParams, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case ComponentParams:
+		return m, true
+	}
+	return nil, false
+}
+
+// Level 读取级别参数，不存在时返回 def
+func (p ComponentParams) Level(key string, def LogLevel) (LogLevel, error) {
+	s, err := p.String(key, "")
+	if err != nil || s == "" {
+		return def, err
+	}
+	level, err := ParseLevel(s)
+	if err != nil {
+		return def, fmt.Errorf("param %q: %w", key, err)
+	}
+	return level, nil
+}
+
+// Levels 读取级别列表参数，不存在时返回 nil
+func (p ComponentParams) Levels(key string) ([]LogLevel, error) {
+	names, err := p.Strings(key)
+	if err != nil {
+		return nil, err
+	}
+	levels := make([]LogLevel, 0, len(names))
+	for _, name := range names {
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("param %q: %w", key, err)
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// ============================================================================
+// 内置组件
+// ============================================================================
+
+func init() {
+	RegisterHookFactory("webhook", newWebhookHookFromParams)
+	RegisterHookFactory("sentry", newSentryHookFromParams)
+	RegisterMiddlewareFactory("redact", newRedactMiddlewareFromParams)
+	RegisterMiddlewareFactory("sampling", newSamplingMiddlewareFromParams)
+	RegisterMiddlewareFactory("secret_scan", newSecretScanMiddlewareFromParams)
+}
+
+// newWebhookHookFromParams webhook 告警钩子：platform、url、secret、title、levels、batch_interval、max_groups、rate_limit、rate_window、transport、tls
+func newWebhookHookFromParams(p ComponentParams) (IHook, error) {
+	platform, err := p.String("platform", string(NotifySlack))
+	if err != nil {
+		return nil, err
+	}
+	url, err := p.String("url", "")
+	if err != nil {
+		return nil, err
+	}
+	var opts []NotifyHookOption
+	if secret, err := p.String("secret", ""); err != nil {
+		return nil, err
+	} else if secret != "" {
+		opts = append(opts, WithNotifySecret(secret))
+	}
+	if title, err := p.String("title", ""); err != nil {
+		return nil, err
+	} else if title != "" {
+		opts = append(opts, WithNotifyTitle(title))
+	}
+	if levels, err := p.Levels("levels"); err != nil {
+		return nil, err
+	} else if len(levels) > 0 {
+		opts = append(opts, WithNotifyLevels(levels...))
+	}
+	if interval, err := p.Duration("batch_interval", 0); err != nil {
+		return nil, err
+	} else if interval > 0 {
+		opts = append(opts, WithNotifyBatchInterval(interval))
+	}
+	if n, err := p.Int("max_groups", 0); err != nil {
+		return nil, err
+	} else if n > 0 {
+		opts = append(opts, WithNotifyMaxGroups(n))
+	}
+	limit, err := p.Int("rate_limit", 0)
+	if err != nil {
+		return nil, err
+	}
+	window, err := p.Duration("rate_window", time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 {
+		opts = append(opts, WithNotifyRateLimit(limit, window))
+	}
+	if client, err := httpClientFromParams(p); err != nil {
+		return nil, err
+	} else if client != nil {
+		opts = append(opts, WithNotifyHTTPClient(client))
+	}
+	return NewNotifyHook(NotifyPlatform(platform), url, opts...)
+}
+
+// newSentryHookFromParams Sentry 钩子：dsn、levels、release、environment、server_name、tag_keys、queue_size、transport、tls
+func newSentryHookFromParams(p ComponentParams) (IHook, error) {
+	dsn, err := p.String("dsn", "")
+	if err != nil {
+		return nil, err
+	}
+	var opts []SentryHookOption
+	if levels, err := p.Levels("levels"); err != nil {
+		return nil, err
+	} else if len(levels) > 0 {
+		opts = append(opts, WithSentryLevels(levels...))
+	}
+	for key, opt := range map[string]func(string) SentryHookOption{
+		"release":     WithSentryRelease,
+		"environment": WithSentryEnvironment,
+		"server_name": WithSentryServerName,
+	} {
+		if s, err := p.String(key, ""); err != nil {
+			return nil, err
+		} else if s != "" {
+			opts = append(opts, opt(s))
+		}
+	}
+	if keys, err := p.Strings("tag_keys"); err != nil {
+		return nil, err
+	} else if len(keys) > 0 {
+		opts = append(opts, WithSentryTagKeys(keys...))
+	}
+	if size, err := p.Int("queue_size", 0); err != nil {
+		return nil, err
+	} else if size > 0 {
+		opts = append(opts, WithSentryQueueSize(size))
+	}
+	if client, err := httpClientFromParams(p); err != nil {
+		return nil, err
+	} else if client != nil {
+		opts = append(opts, WithSentryHTTPClient(client))
+	}
+	return NewSentryHook(dsn, opts...)
+}
+
+// httpClientFromParams 按 transport 与 tls 参数段创建 HTTP 客户端（tls 段优先于 transport.tls），
+// 均未配置时返回 nil（使用组件默认客户端）
+func httpClientFromParams(p ComponentParams) (*http.Client, error) {
+	transport, err := p.HTTPTransport("transport")
+	if err != nil {
+		return nil, err
+	}
+	section, err := p.TLS("tls")
+	if err != nil {
+		return nil, err
+	}
+	if transport == nil && section == nil {
+		return nil, nil
+	}
+	if transport == nil {
+		transport = &HTTPTransportConfig{}
+	}
+	if section != nil {
+		transport.TLS = section
+	}
+	return transport.Client(5 * time.Second)
+}
+
+// redactActions 脱敏中间件 action 参数取值
+var redactActions = map[string]PolicyAction{
+	"mask": PolicyMask,
+	"hash": PolicyHash,
+	"drop": PolicyDrop,
+}
+
+// newRedactMiddlewareFromParams 按字段名脱敏中间件：keys、action（mask / hash / drop）、salt、priority
+func newRedactMiddlewareFromParams(p ComponentParams) (IMiddleware, error) {
+	keys, err := p.Strings("keys")
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New(`param "keys" is required`)
+	}
+	actionName, err := p.String("action", "mask")
+	if err != nil {
+		return nil, err
+	}
+	action, ok := redactActions[strings.ToLower(actionName)]
+	if !ok {
+		return nil, fmt.Errorf("param %q: unknown action %q", "action", actionName)
+	}
+	opts := []RedactOption{WithRedactAction(action)}
+	if salt, err := p.String("salt", ""); err != nil {
+		return nil, err
+	} else if salt != "" {
+		opts = append(opts, WithRedactSalt([]byte(salt)))
+	}
+	if _, ok := p["priority"]; ok {
+		priority, err := p.Int("priority", 0)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithRedactPriority(priority))
+	}
+	return NewRedactMiddleware(keys, opts...), nil
+}
+
+// newSamplingMiddlewareFromParams 采样中间件：every、below、priority
+func newSamplingMiddlewareFromParams(p ComponentParams) (IMiddleware, error) {
+	every, err := p.Int("every", 0)
+	if err != nil {
+		return nil, err
+	}
+	if every <= 0 {
+		return nil, errors.New(`param "every" must be positive`)
+	}
+	below, err := p.Level("below", WARN)
+	if err != nil {
+		return nil, err
+	}
+	priority, err := p.Int("priority", 0)
+	if err != nil {
+		return nil, err
+	}
+	return NewSamplingMiddleware(every, WithSamplingBelow(below), WithSamplingPriority(priority)), nil
+}
+
+// newSecretScanMiddlewareFromParams 密钥自检中间件：strict、priority
+func newSecretScanMiddlewareFromParams(p ComponentParams) (IMiddleware, error) {
+	strict, err := p.Bool("strict", false)
+	if err != nil {
+		return nil, err
+	}
+	opts := []SecretScanOption{WithSecretStrict(strict)}
+	if _, ok := p["priority"]; ok {
+		priority, err := p.Int("priority", 0)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithSecretPriority(priority))
+	}
+	return NewSecretScanMiddleware(opts...), nil
+}
@@ -0,0 +1,330 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\compress.go
+ * @Description: 日志压缩（轮转文件后台压缩、网络输出流式压缩），内置 gzip，zstd 等算法通过 RegisterCompressor 插入
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 压缩算法名称
+const (
+	// CompressionGzip 内置 gzip 实现
+	CompressionGzip = "gzip"
+	// CompressionZstd 预留给可插拔 zstd 编码器的名称，本包不内置实现（避免引入第三方依赖），
+	// 使用前须通过 RegisterCompressor 注册，否则 NewCompressWriter 与 NewRotateWriter 均拒绝该配置
+	CompressionZstd = "zstd"
+)
+
+// ErrCompressorNotRegistered 压缩算法未注册
+var ErrCompressorNotRegistered = errors.New("compressor not registered")
+
+// 压缩级别（CPU 开销由低到高），具体含义由压缩实现映射
+const (
+	CompressionFastest = 1
+	CompressionDefault = 0
+	CompressionBest    = 9
+)
+
+// CompressorFactory 创建流式压缩器，返回的 Writer 若实现 Flush() error 则支持流式刷新
+type CompressorFactory func(w io.Writer, level int) (io.WriteCloser, error)
+
+type compressorEntry struct {
+	ext     string
+	factory CompressorFactory
+}
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[string]compressorEntry{
+		CompressionGzip: {ext: ".gz", factory: newGzipCompressor},
+	}
+)
+
+// RegisterCompressor 注册压缩实现（如 zstd），ext 为压缩文件扩展名（如 ".zst"）
+// 须在创建使用该算法的输出器之前调用，例如使用 github.com/klauspost/compress/zstd：
+//
+//	logger.RegisterCompressor(logger.CompressionZstd, ".zst", func(w io.Writer, level int) (io.WriteCloser, error) {
+//		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+//	})
+func RegisterCompressor(name, ext string, factory CompressorFactory) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	compressors[name] = compressorEntry{ext: ext, factory: factory}
+}
+
+// getCompressor 获取已注册的压缩实现
+func getCompressor(name string) (compressorEntry, error) {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	entry, ok := compressors[name]
+	if !ok {
+		return compressorEntry{}, fmt.Errorf("%w: %q", ErrCompressorNotRegistered, name)
+	}
+	return entry, nil
+}
+
+// newGzipCompressor 创建 gzip 压缩器
+func newGzipCompressor(w io.Writer, level int) (io.WriteCloser, error) {
+	switch {
+	case level == CompressionDefault:
+		level = gzip.DefaultCompression
+	case level < gzip.BestSpeed:
+		level = gzip.BestSpeed
+	case level > gzip.BestCompression:
+		level = gzip.BestCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+// ============================================================================
+// CPU 预算：限制同时进行的后台压缩任务数
+// ============================================================================
+
+var compressionSlots atomic.Pointer[chan struct{}]
+
+func init() {
+	SetCompressionConcurrency(1)
+}
+
+// SetCompressionConcurrency 设置后台压缩的最大并发数（默认 1，即最多占用一个核心）
+func SetCompressionConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	slots := make(chan struct{}, n)
+	compressionSlots.Store(&slots)
+}
+
+// compressFile 将 src 压缩为 dst 并删除 src（先写临时文件再重命名，避免产生残缺的压缩文件）
+func compressFile(src, dst, codec string, level int, permission os.FileMode) error {
+	slots := *compressionSlots.Load()
+	slots <- struct{}{}
+	defer func() { <-slots }()
+
+	entry, err := getCompressor(codec)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, permission)
+	if err != nil {
+		return err
+	}
+
+	cw, err := entry.factory(out, level)
+	if err == nil {
+		if _, err = io.Copy(cw, in); err == nil {
+			err = cw.Close()
+		}
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// ============================================================================
+// 流式压缩输出器（适用于网络等带宽受限的输出）
+// ============================================================================
+
+// flusher 支持流式刷新的压缩器
+type flusher interface {
+	Flush() error
+}
+
+// CompressWriter 流式压缩输出器，压缩后写入底层输出
+// Flush 时刷新压缩块，对端可立即解压已写入的数据
+type CompressWriter struct {
+	baseWriter
+	underlying    io.Writer
+	codec         string
+	compressLevel int
+	flushInterval time.Duration
+	encoder       io.WriteCloser
+	lastFlush     time.Time
+}
+
+// CompressWriterOption 流式压缩输出器配置选项
+type CompressWriterOption func(*CompressWriter)
+
+// WithCompressUnderlying 设置底层输出（网络连接、IWriter 等）
+func WithCompressUnderlying(underlying io.Writer) CompressWriterOption {
+	return func(w *CompressWriter) {
+		w.underlying = underlying
+	}
+}
+
+// WithCompressCodec 设置压缩算法（默认 gzip，其他算法须先通过 RegisterCompressor 注册）
+func WithCompressCodec(codec string) CompressWriterOption {
+	return func(w *CompressWriter) {
+		w.codec = codec
+	}
+}
+
+// WithCompressLevel 设置压缩级别（CompressionFastest 开销最低）
+func WithCompressLevel(level int) CompressWriterOption {
+	return func(w *CompressWriter) {
+		w.compressLevel = level
+	}
+}
+
+// WithCompressFlushInterval 设置自动刷新间隔（写入时检查，0 表示仅在 Flush 时刷新）
+func WithCompressFlushInterval(interval time.Duration) CompressWriterOption {
+	return func(w *CompressWriter) {
+		w.flushInterval = interval
+	}
+}
+
+// WithCompressWriterLevel 设置日志级别
+func WithCompressWriterLevel(level LogLevel) CompressWriterOption {
+	return func(w *CompressWriter) {
+		w.level = level
+	}
+}
+
+// NewCompressWriter 创建流式压缩输出器
+func NewCompressWriter(opts ...CompressWriterOption) (*CompressWriter, error) {
+	w := &CompressWriter{
+		baseWriter: baseWriter{
+			level:   DEBUG,
+			healthy: true,
+			stats:   newWriterStats(),
+		},
+		codec:         CompressionGzip,
+		compressLevel: CompressionFastest,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.underlying == nil {
+		return nil, fmt.Errorf("compress writer requires an underlying writer")
+	}
+	entry, err := getCompressor(w.codec)
+	if err != nil {
+		return nil, err
+	}
+	if w.encoder, err = entry.factory(w.underlying, w.compressLevel); err != nil {
+		return nil, err
+	}
+	w.lastFlush = time.Now()
+	return w, nil
+}
+
+// Write 实现io.Writer接口（返回未压缩字节数）
+func (w *CompressWriter) Write(p []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.healthy {
+		return 0, fmt.Errorf("compress writer is not healthy")
+	}
+
+	n, err = w.encoder.Write(p)
+	if err == nil && w.flushInterval > 0 && time.Since(w.lastFlush) >= w.flushInterval {
+		err = w.flushLocked()
+	}
+	if err != nil {
+		w.stats.addError()
+		w.healthy = false
+		return n, err
+	}
+
+	w.stats.addBytes(int64(n))
+	return n, nil
+}
+
+// WriteLevel 按级别写入
+func (w *CompressWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
+	if level < w.level {
+		return len(data), nil
+	}
+	return w.Write(data)
+}
+
+// flushLocked 刷新压缩块与底层输出（调用方持有锁）
+func (w *CompressWriter) flushLocked() error {
+	if w.encoder == nil {
+		return nil
+	}
+	w.lastFlush = time.Now()
+	if f, ok := w.encoder.(flusher); ok {
+		if err := f.Flush(); err != nil {
+			return err
+		}
+	}
+	if f, ok := w.underlying.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Flush 刷新压缩块
+func (w *CompressWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.flushLocked()
+}
+
+// Close 写出压缩尾部并关闭底层输出（如实现 io.Closer）
+func (w *CompressWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.encoder == nil {
+		return nil
+	}
+	w.healthy = false
+	err := w.encoder.Close()
+	w.encoder = nil
+	if c, ok := w.underlying.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// IsHealthy 检查健康状态
+func (w *CompressWriter) IsHealthy() bool {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	return w.healthy
+}
+
+// GetStats 获取统计信息（字节数为压缩前大小）
+func (w *CompressWriter) GetStats() WriterStatsSnapshot {
+	return w.stats.getSnapshot()
+}
@@ -0,0 +1,109 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\compress_test.go
+ * @Description: 压缩算法注册测试（未注册算法被拒绝，注册后可用）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// unregisterCompressor 删除测试注册的压缩实现
+func unregisterCompressor(name string) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	delete(compressors, name)
+}
+
+// TestCompressWriterCodec 未注册的算法（包括未注册实现的 zstd）在构造时被拒绝
+func TestCompressWriterCodec(t *testing.T) {
+	tests := []struct {
+		name    string
+		codec   string
+		wantErr error
+	}{
+		{"gzip built in", CompressionGzip, nil},
+		{"zstd not built in", CompressionZstd, ErrCompressorNotRegistered},
+		{"unknown", "lz4", ErrCompressorNotRegistered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCompressWriter(WithCompressUnderlying(&buf), WithCompressCodec(tt.codec))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewCompressWriter(%q) error = %v, want %v", tt.codec, err, tt.wantErr)
+			}
+			if err == nil {
+				w.Close()
+			}
+		})
+	}
+}
+
+// TestRotateWriterUnregisteredCodec 轮转输出器配置了未注册算法时拒绝写入，而不是静默写出未压缩文件
+func TestRotateWriterUnregisteredCodec(t *testing.T) {
+	var reported []InternalError
+	SetInternalErrorHandler(func(e InternalError) { reported = append(reported, e) })
+	defer SetInternalErrorHandler(nil)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w := NewRotateWriter(WithFilePath(path), WithCompression(CompressionZstd, CompressionFastest))
+	defer w.Close()
+
+	if _, err := w.Write([]byte("line\n")); !errors.Is(err, ErrCompressorNotRegistered) {
+		t.Fatalf("Write error = %v, want ErrCompressorNotRegistered", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("log file should not be created, stat error = %v", err)
+	}
+	if len(reported) != 1 || reported[0].Component != "writer" {
+		t.Errorf("internal errors = %+v, want one writer error", reported)
+	}
+}
+
+// TestRegisterCompressor 注册后的算法可用于轮转文件压缩，扩展名取注册值
+func TestRegisterCompressor(t *testing.T) {
+	RegisterCompressor(CompressionZstd, ".zst", newGzipCompressor) // 以 gzip 代替真实 zstd 实现
+	defer unregisterCompressor(CompressionZstd)
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	w := NewRotateWriter(WithFilePath(path), WithMaxSize(8), WithCompression(CompressionZstd, CompressionFastest))
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	f, err := os.Open(path + ".1.zst")
+	if err != nil {
+		t.Fatalf("rotated file not compressed with registered codec: %v", err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(data) != "first\n" {
+		t.Errorf("rotated content = %q, want %q", data, "first\n")
+	}
+}
@@ -12,6 +12,7 @@ package logger
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/kamalyes/go-toolbox/pkg/convert"
 	"google.golang.org/grpc/metadata"
@@ -29,6 +30,31 @@ const (
 type compiledContextKey struct {
 	key      string
 	keyBytes []byte
+	ctxKey   any                                   // ctx.Value 使用的键（为 nil 时使用 key 字符串）
+	accessor func(ctx context.Context) (any, bool) // 自定义取值函数（优先于 ctxKey）
+}
+
+// ContextKey 类型化上下文提取规则，用于非字符串的上下文键（如包内未导出的结构体键类型）
+type ContextKey struct {
+	Name     string                                // 输出名
+	Key      any                                   // ctx.Value 使用的键（任意可比较值）
+	Accessor func(ctx context.Context) (any, bool) // 自定义取值函数（设置后忽略 Key）
+}
+
+// TypedContextKey 以任意可比较值作为上下文键：
+//
+//	type traceKey struct{}
+//	log.WithTypedContextKeys(logger.TypedContextKey("trace_id", traceKey{}))
+func TypedContextKey(name string, key any) ContextKey {
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		panic("logger: context key is not comparable")
+	}
+	return ContextKey{Name: name, Key: key}
+}
+
+// ContextAccessor 使用取值函数从上下文提取字段（如各业务包导出的 FromContext 函数）
+func ContextAccessor(name string, accessor func(ctx context.Context) (any, bool)) ContextKey {
+	return ContextKey{Name: name, Accessor: accessor}
 }
 
 var defaultContextKeys = []string{
@@ -64,9 +90,12 @@ func extractContextWithCompiledKeys(ctx context.Context, keys []compiledContextK
 		return ""
 	}
 
-	buf := contextPool.Get().([]byte)
-	buf = buf[:0]
-	defer contextPool.Put(buf)
+	bp := contextPool.Get().(*[]byte)
+	buf := (*bp)[:0]
+	defer func() {
+		*bp = buf[:0]
+		contextPool.Put(bp)
+	}()
 
 	buf = append(buf, '[')
 
@@ -78,6 +107,22 @@ func extractContextWithCompiledKeys(ctx context.Context, keys []compiledContextK
 	)
 
 	for _, key := range keys {
+		if key.ctxKey != nil || key.accessor != nil {
+			start := len(buf)
+			if wroteField {
+				buf = append(buf, ' ')
+			}
+			buf = append(buf, key.keyBytes...)
+			buf = append(buf, '=')
+			valueStart := len(buf)
+			if buf = appendTypedContextValue(buf, ctx, key); len(buf) == valueStart {
+				buf = buf[:start]
+				continue
+			}
+			wroteField = true
+			continue
+		}
+
 		value := ""
 		if raw := ctx.Value(key.key); raw != nil {
 			if text, ok := raw.(string); ok && text != "" {
@@ -118,9 +163,142 @@ func extractContextWithCompiledKeys(ctx context.Context, keys []compiledContextK
 	return string(buf)
 }
 
+// extractContextKVWithCompiledKeys 按提取规则从上下文中取值，返回字段名与值交替的键值对
+func extractContextKVWithCompiledKeys(ctx context.Context, keys []compiledContextKey) []any {
+	if ctx == nil || len(keys) == 0 {
+		return nil
+	}
+
+	var (
+		kv         []any
+		incomingMD metadata.MD
+		mdLoaded   bool
+		hasMD      bool
+	)
+	for _, key := range keys {
+		if key.accessor != nil {
+			if value, ok := key.accessor(ctx); ok && value != nil {
+				kv = append(kv, key.key, value)
+			}
+			continue
+		}
+		if key.ctxKey != nil {
+			if value := ctx.Value(key.ctxKey); value != nil {
+				kv = append(kv, key.key, value)
+			}
+			continue
+		}
+
+		if text, ok := ctx.Value(key.key).(string); ok && text != "" {
+			kv = append(kv, key.key, text)
+			continue
+		}
+		if !mdLoaded {
+			incomingMD, hasMD = metadata.FromIncomingContext(ctx)
+			mdLoaded = true
+		}
+		if hasMD {
+			if values := incomingMD.Get(key.key); len(values) > 0 && values[0] != "" {
+				kv = append(kv, key.key, values[0])
+			}
+		}
+	}
+	return kv
+}
+
 // WithContextKeys 配置 Logger 在记录 Context 日志时提取哪些 key
 func (l *Logger) WithContextKeys(keys ...string) *Logger {
 	l.contextKeys = compileContextKeys(keys)
 	l.contextExtractor = nil
 	return l
 }
+
+// WithTypedContextKeys 追加类型化上下文提取规则（在已配置的字符串 key 之后提取）
+func (l *Logger) WithTypedContextKeys(keys ...ContextKey) *Logger {
+	compiled := append([]compiledContextKey(nil), l.contextKeys...)
+	for _, key := range keys {
+		if key.Name == "" || (key.Key == nil && key.Accessor == nil) {
+			continue
+		}
+		compiled = append(compiled, compiledContextKey{
+			key:      key.Name,
+			keyBytes: []byte(key.Name),
+			ctxKey:   key.Key,
+			accessor: key.Accessor,
+		})
+	}
+	l.contextKeys = compiled
+	l.contextExtractor = nil
+	return l
+}
+
+// appendTypedContextValue 追加类型化规则取得的值（值不存在或为空时不追加）
+func appendTypedContextValue(buf []byte, ctx context.Context, key compiledContextKey) []byte {
+	var raw any
+	if key.accessor != nil {
+		value, ok := key.accessor(ctx)
+		if !ok {
+			return buf
+		}
+		raw = value
+	} else {
+		raw = ctx.Value(key.ctxKey)
+	}
+	if raw == nil {
+		return buf
+	}
+	return convert.AppendValue(buf, raw)
+}
+
+// ContextFieldExtractor 上下文字段提取器，返回附加到日志的键值对（与 ContextExtractor 的消息前缀互补）
+type ContextFieldExtractor func(ctx context.Context) []any
+
+// SetContextFieldExtractor 设置上下文字段提取器，所有 *Context 方法均会附加其返回的字段
+func (l *Logger) SetContextFieldExtractor(extractor ContextFieldExtractor) {
+	l.contextFieldExtractor = extractor
+}
+
+// GetContextFieldExtractor 获取当前的上下文字段提取器
+func (l *Logger) GetContextFieldExtractor() ContextFieldExtractor {
+	return l.contextFieldExtractor
+}
+
+// WithContextFieldExtractor 设置上下文字段提取器
+func (l *Logger) WithContextFieldExtractor(extractor ContextFieldExtractor) *Logger {
+	l.contextFieldExtractor = extractor
+	return l
+}
+
+// extractContextFields 从上下文中提取字段（字段提取器、baggage、pprof 标签依次追加），没有时返回 nil
+func (l *Logger) extractContextFields(ctx context.Context) []any {
+	if ctx == nil || ctx == l.context || (l.contextFieldExtractor == nil && l.baggage == nil && l.pprofLabels == nil) {
+		return nil
+	}
+	var kv []any
+	if l.contextFieldExtractor != nil {
+		kv = append(kv, l.contextFieldExtractor(ctx)...)
+	}
+	if l.baggage != nil {
+		kv = append(kv, l.baggage.Extract(ctx)...)
+	}
+	if l.pprofLabels != nil {
+		kv = l.pprofLabels.extract(ctx, kv)
+	}
+	return kv
+}
+
+// contextLogger 返回绑定 ctx 的子 Logger：上下文 key、字段提取器与 baggage 提取到的字段一次性转为静态字段，
+// 之后通过 With、WithField、Clone 派生的子 Logger 均继承这些字段；使用同一 ctx 调用 *Context 方法时不再重复提取
+// 自定义的 ContextExtractor 返回的是消息前缀，无法转为字段，仍在每次调用时提取
+func (l *Logger) contextLogger(ctx context.Context) *Logger {
+	var kv []any
+	if ctx != nil {
+		if l.contextExtractor == nil {
+			kv = extractContextKVWithCompiledKeys(ctx, l.contextKeys)
+		}
+		kv = append(kv, l.extractContextFields(ctx)...)
+	}
+	child := l.With(kv...)
+	child.context = ctx
+	return child
+}
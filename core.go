@@ -0,0 +1,419 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\core.go
+ * @Description: 最小日志接口 CoreLogger 与 Extend 包装器（由少量核心方法派生完整的 ILogger）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// CoreLogger 最小日志接口，自定义适配器只需实现这 3 个方法，再通过 Extend 得到完整的 ILogger
+type CoreLogger interface {
+	// Emit 输出一条日志，fields 已合并 WithField 字段与本次调用的键值对（可能为 nil），ctx 可能为 nil
+	Emit(ctx context.Context, level LogLevel, msg string, fields map[string]any)
+	SetLevel(level LogLevel)
+	GetLevel() LogLevel
+}
+
+// extendedLogger 由 CoreLogger 派生的完整 ILogger
+type extendedLogger struct {
+	core       CoreLogger
+	fields     map[string]any
+	ctx        context.Context
+	showCaller *bool // 派生的子 Logger 共享
+
+	consoleOnce  *sync.Once
+	consoleGroup **ConsoleGroup
+}
+
+// Extend 由 CoreLogger 派生完整的 ILogger：级别判断、格式化、键值对与字段合并、上下文、多行、块、控制台等方法均由包装器实现
+// 开启 SetShowCaller 后调用位置以 "caller" 字段（file:line）传给 Emit；FATAL 日志输出后若 core 实现了 Flush 则先刷新再退出进程
+func Extend(core CoreLogger) ILogger {
+	if l, ok := core.(ILogger); ok {
+		return l
+	}
+	showCaller := false
+	var group *ConsoleGroup
+	return &extendedLogger{
+		core:         core,
+		showCaller:   &showCaller,
+		consoleOnce:  &sync.Once{},
+		consoleGroup: &group,
+	}
+}
+
+// derive 创建共享 core 与配置的子 Logger
+func (x *extendedLogger) derive(fields map[string]any, ctx context.Context) *extendedLogger {
+	child := *x
+	child.fields = fields
+	child.ctx = ctx
+	return &child
+}
+
+// emit 合并字段后交给 core 输出（由各公开方法直接调用，以保证调用位置的层级一致）
+func (x *extendedLogger) emit(ctx context.Context, level LogLevel, msg string, fields map[string]any, keysAndValues []any) {
+	if level < x.core.GetLevel() {
+		return
+	}
+	if ctx == nil {
+		ctx = x.ctx
+	}
+
+	var merged map[string]any
+	if len(x.fields) > 0 || len(fields) > 0 || len(keysAndValues) > 0 || *x.showCaller {
+		merged = make(map[string]any, len(x.fields)+len(fields)+len(keysAndValues)/2+1)
+		for k, v := range x.fields {
+			merged[k] = v
+		}
+		for k, v := range gateFields(level, fields) {
+			merged[k] = v
+		}
+		mergeKV(merged, gateKV(level, keysAndValues))
+		if *x.showCaller {
+			if f, ok := lookupCaller(2); ok {
+				merged["caller"] = filepath.Base(f.file) + ":" + strconv.Itoa(f.line)
+			}
+		}
+	}
+	x.core.Emit(ctx, level, msg, merged)
+
+	if level == FATAL {
+		if flusher, ok := x.core.(interface{ Flush() error }); ok {
+			_ = flusher.Flush()
+		}
+		os.Exit(1)
+	}
+}
+
+// mergeKV 将键值对写入字段映射（规则同 collectKV，不做策略处理）
+func mergeKV(dst map[string]any, keysAndValues []any) {
+	for i := 0; i < len(keysAndValues); {
+		if f, ok := keysAndValues[i].(Field); ok {
+			dst[f.Key] = f
+			i++
+			continue
+		}
+		var (
+			key   string
+			value any
+		)
+		key, value, i = kvPair(keysAndValues, i)
+		dst[key] = value
+	}
+}
+
+// 基本日志方法
+func (x *extendedLogger) Debug(format string, args ...any) {
+	x.emit(nil, DEBUG, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Info(format string, args ...any) {
+	x.emit(nil, INFO, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Warn(format string, args ...any) {
+	x.emit(nil, WARN, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Error(format string, args ...any) {
+	x.emit(nil, ERROR, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Fatal(format string, args ...any) {
+	x.emit(nil, FATAL, fmt.Sprintf(format, args...), nil, nil)
+}
+
+// Printf 风格日志方法
+func (x *extendedLogger) Debugf(format string, args ...any) {
+	x.emit(nil, DEBUG, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Infof(format string, args ...any) {
+	x.emit(nil, INFO, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Warnf(format string, args ...any) {
+	x.emit(nil, WARN, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Errorf(format string, args ...any) {
+	x.emit(nil, ERROR, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Fatalf(format string, args ...any) {
+	x.emit(nil, FATAL, fmt.Sprintf(format, args...), nil, nil)
+}
+
+// 纯文本日志方法
+func (x *extendedLogger) DebugMsg(msg string) { x.emit(nil, DEBUG, msg, nil, nil) }
+func (x *extendedLogger) InfoMsg(msg string)  { x.emit(nil, INFO, msg, nil, nil) }
+func (x *extendedLogger) WarnMsg(msg string)  { x.emit(nil, WARN, msg, nil, nil) }
+func (x *extendedLogger) ErrorMsg(msg string) { x.emit(nil, ERROR, msg, nil, nil) }
+func (x *extendedLogger) FatalMsg(msg string) { x.emit(nil, FATAL, msg, nil, nil) }
+
+// returnError 构建 *Return 方法返回的 LoggedError（字段为 WithField 字段与本次键值对）
+func (x *extendedLogger) returnError(level LogLevel, format string, args, keysAndValues []any) *LoggedError {
+	fields := make(map[string]any, len(x.fields)+len(keysAndValues)/2)
+	for k, v := range x.fields {
+		fields[k] = v
+	}
+	mergeKV(fields, gateKV(level, keysAndValues))
+	return newLoggedError(level, returnMessage(format, args), format, fields, args, keysAndValues)
+}
+
+// 返回错误的日志方法
+func (x *extendedLogger) DebugReturn(format string, args ...any) error {
+	err := x.returnError(DEBUG, format, args, nil)
+	x.emit(nil, DEBUG, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) InfoReturn(format string, args ...any) error {
+	err := x.returnError(INFO, format, args, nil)
+	x.emit(nil, INFO, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) WarnReturn(format string, args ...any) error {
+	err := x.returnError(WARN, format, args, nil)
+	x.emit(nil, WARN, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) ErrorReturn(format string, args ...any) error {
+	err := x.returnError(ERROR, format, args, nil)
+	x.emit(nil, ERROR, err.Message, nil, nil)
+	return err
+}
+
+// 返回错误的上下文日志方法
+func (x *extendedLogger) DebugCtxReturn(ctx context.Context, format string, args ...any) error {
+	err := x.returnError(DEBUG, format, args, nil)
+	x.emit(ctx, DEBUG, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) InfoCtxReturn(ctx context.Context, format string, args ...any) error {
+	err := x.returnError(INFO, format, args, nil)
+	x.emit(ctx, INFO, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) WarnCtxReturn(ctx context.Context, format string, args ...any) error {
+	err := x.returnError(WARN, format, args, nil)
+	x.emit(ctx, WARN, err.Message, nil, nil)
+	return err
+}
+func (x *extendedLogger) ErrorCtxReturn(ctx context.Context, format string, args ...any) error {
+	err := x.returnError(ERROR, format, args, nil)
+	x.emit(ctx, ERROR, err.Message, nil, nil)
+	return err
+}
+
+// 返回错误的键值对日志方法
+func (x *extendedLogger) DebugKVReturn(msg string, keysAndValues ...any) error {
+	x.emit(nil, DEBUG, msg, nil, keysAndValues)
+	return x.returnError(DEBUG, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) InfoKVReturn(msg string, keysAndValues ...any) error {
+	x.emit(nil, INFO, msg, nil, keysAndValues)
+	return x.returnError(INFO, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) WarnKVReturn(msg string, keysAndValues ...any) error {
+	x.emit(nil, WARN, msg, nil, keysAndValues)
+	return x.returnError(WARN, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) ErrorKVReturn(msg string, keysAndValues ...any) error {
+	x.emit(nil, ERROR, msg, nil, keysAndValues)
+	return x.returnError(ERROR, msg, nil, keysAndValues)
+}
+
+// 带上下文的日志方法
+func (x *extendedLogger) DebugContext(ctx context.Context, format string, args ...any) {
+	x.emit(ctx, DEBUG, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) InfoContext(ctx context.Context, format string, args ...any) {
+	x.emit(ctx, INFO, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) WarnContext(ctx context.Context, format string, args ...any) {
+	x.emit(ctx, WARN, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) ErrorContext(ctx context.Context, format string, args ...any) {
+	x.emit(ctx, ERROR, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) FatalContext(ctx context.Context, format string, args ...any) {
+	x.emit(ctx, FATAL, fmt.Sprintf(format, args...), nil, nil)
+}
+
+// 结构化日志方法（键值对）
+func (x *extendedLogger) DebugKV(msg string, keysAndValues ...any) {
+	x.emit(nil, DEBUG, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) InfoKV(msg string, keysAndValues ...any) {
+	x.emit(nil, INFO, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) WarnKV(msg string, keysAndValues ...any) {
+	x.emit(nil, WARN, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) ErrorKV(msg string, keysAndValues ...any) {
+	x.emit(nil, ERROR, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) FatalKV(msg string, keysAndValues ...any) {
+	x.emit(nil, FATAL, msg, nil, keysAndValues)
+}
+
+// 结构化日志方法（字段映射）
+func (x *extendedLogger) DebugWithFields(msg string, fields map[string]any) {
+	x.emit(nil, DEBUG, msg, fields, nil)
+}
+func (x *extendedLogger) InfoWithFields(msg string, fields map[string]any) {
+	x.emit(nil, INFO, msg, fields, nil)
+}
+func (x *extendedLogger) WarnWithFields(msg string, fields map[string]any) {
+	x.emit(nil, WARN, msg, fields, nil)
+}
+func (x *extendedLogger) ErrorWithFields(msg string, fields map[string]any) {
+	x.emit(nil, ERROR, msg, fields, nil)
+}
+func (x *extendedLogger) FatalWithFields(msg string, fields map[string]any) {
+	x.emit(nil, FATAL, msg, fields, nil)
+}
+
+// 带上下文的结构化日志方法（键值对）
+func (x *extendedLogger) DebugContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	x.emit(ctx, DEBUG, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) InfoContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	x.emit(ctx, INFO, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) WarnContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	x.emit(ctx, WARN, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) ErrorContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	x.emit(ctx, ERROR, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) FatalContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	x.emit(ctx, FATAL, msg, nil, keysAndValues)
+}
+
+// 多行日志方法
+func (x *extendedLogger) InfoLines(lines ...string) {
+	for _, line := range lines {
+		x.emit(nil, INFO, line, nil, nil)
+	}
+}
+func (x *extendedLogger) ErrorLines(lines ...string) {
+	for _, line := range lines {
+		x.emit(nil, ERROR, line, nil, nil)
+	}
+}
+func (x *extendedLogger) WarnLines(lines ...string) {
+	for _, line := range lines {
+		x.emit(nil, WARN, line, nil, nil)
+	}
+}
+func (x *extendedLogger) DebugLines(lines ...string) {
+	for _, line := range lines {
+		x.emit(nil, DEBUG, line, nil, nil)
+	}
+}
+
+// 块日志方法（正文作为 block 字段）
+func (x *extendedLogger) DebugBlock(title, body string) {
+	x.emit(nil, DEBUG, title, nil, []any{BlockKey, body})
+}
+func (x *extendedLogger) InfoBlock(title, body string) {
+	x.emit(nil, INFO, title, nil, []any{BlockKey, body})
+}
+func (x *extendedLogger) WarnBlock(title, body string) {
+	x.emit(nil, WARN, title, nil, []any{BlockKey, body})
+}
+func (x *extendedLogger) ErrorBlock(title, body string) {
+	x.emit(nil, ERROR, title, nil, []any{BlockKey, body})
+}
+
+// 原始日志条目方法
+func (x *extendedLogger) Log(level LogLevel, msg string) {
+	x.emit(nil, level, msg, nil, nil)
+}
+func (x *extendedLogger) LogContext(ctx context.Context, level LogLevel, msg string) {
+	x.emit(ctx, level, msg, nil, nil)
+}
+func (x *extendedLogger) LogKV(level LogLevel, msg string, keysAndValues ...any) {
+	x.emit(nil, level, msg, nil, keysAndValues)
+}
+func (x *extendedLogger) LogWithFields(level LogLevel, msg string, fields map[string]any) {
+	x.emit(nil, level, msg, fields, nil)
+}
+
+// 配置方法
+func (x *extendedLogger) SetLevel(level LogLevel)            { x.core.SetLevel(level) }
+func (x *extendedLogger) GetLevel() LogLevel                 { return x.core.GetLevel() }
+func (x *extendedLogger) SetShowCaller(show bool)            { *x.showCaller = show }
+func (x *extendedLogger) IsShowCaller() bool                 { return *x.showCaller }
+func (x *extendedLogger) IsLevelEnabled(level LogLevel) bool { return level >= x.core.GetLevel() }
+
+// 结构化日志构建器
+func (x *extendedLogger) WithField(key string, value any) ILogger {
+	return x.WithFields(map[string]any{key: value})
+}
+
+func (x *extendedLogger) WithFields(fields map[string]any) ILogger {
+	if len(fields) == 0 {
+		return x
+	}
+	merged := make(map[string]any, len(x.fields)+len(fields))
+	for k, v := range x.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return x.derive(merged, x.ctx)
+}
+
+func (x *extendedLogger) WithError(err error) ILogger {
+	if err == nil {
+		return x
+	}
+	return x.WithField(ErrorKey, err.Error())
+}
+
+func (x *extendedLogger) WithContext(ctx context.Context) ILogger {
+	return x.derive(x.fields, ctx)
+}
+
+// 兼容标准 log 包的方法
+func (x *extendedLogger) Print(args ...any) {
+	x.emit(nil, INFO, fmt.Sprint(args...), nil, nil)
+}
+func (x *extendedLogger) Printf(format string, args ...any) {
+	x.emit(nil, INFO, fmt.Sprintf(format, args...), nil, nil)
+}
+func (x *extendedLogger) Println(args ...any) {
+	x.emit(nil, INFO, fmt.Sprint(args...), nil, nil)
+}
+
+// Console 风格日志功能
+func (x *extendedLogger) NewConsoleGroup() *ConsoleGroup {
+	return &ConsoleGroup{logger: x, collapsedLevels: make([]bool, 0, 16)}
+}
+
+// console 获取共享的控制台分组（延迟初始化）
+func (x *extendedLogger) console() *ConsoleGroup {
+	x.consoleOnce.Do(func() {
+		*x.consoleGroup = x.NewConsoleGroup()
+	})
+	return *x.consoleGroup
+}
+
+func (x *extendedLogger) ConsoleGroup(label string, args ...any) {
+	x.console().Group(label, args...)
+}
+func (x *extendedLogger) ConsoleGroupCollapsed(label string, args ...any) {
+	x.console().GroupCollapsed(label, args...)
+}
+func (x *extendedLogger) ConsoleGroupEnd()      { x.console().GroupEnd() }
+func (x *extendedLogger) ConsoleTable(data any) { x.console().Table(data) }
+func (x *extendedLogger) ConsoleTime(label string) *Timer {
+	return x.console().Time(label)
+}
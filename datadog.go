@@ -0,0 +1,251 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\datadog.go
+ * @Description: Datadog 日志接入（HTTP intake v2）适配器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultDatadogSite      = "datadoghq.com"
+	DefaultDatadogSource    = "go"
+	DefaultDatadogBatchSize = 500 // 接口上限为每批 1000 条、5MB
+)
+
+// DatadogAdapter Datadog 日志接入适配器（POST https://http-intake.logs.<site>/api/v2/logs，DD-API-KEY 认证）
+//
+//	dd := logger.NewDatadogAdapter(logger.WithDatadogAPIKey(key), logger.WithDatadogService("checkout"), logger.WithDatadogTags("env:prod"))
+//	log := logger.NewLogger().WithAdapters(dd)
+//
+// 字段作为属性与保留属性（ddsource、ddtags、hostname、service、status、message、timestamp）平级发送，
+// 与保留属性同名的字段会被忽略；API key 与 site 默认读取环境变量 DD_API_KEY、DD_SITE
+type DatadogAdapter struct {
+	*BaseAdapter
+	cfg      httpBatchConfig
+	url      string
+	apiKey   string
+	service  string
+	source   string
+	hostname string
+	tags     []string
+	sender   *httpBatchSender
+}
+
+// DatadogOption Datadog 适配器配置选项
+type DatadogOption func(*DatadogAdapter)
+
+// WithDatadogAPIKey 设置 API key
+func WithDatadogAPIKey(apiKey string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.apiKey = apiKey
+	}
+}
+
+// WithDatadogSite 设置站点（如 datadoghq.eu、us5.datadoghq.com）
+func WithDatadogSite(site string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		if site != "" {
+			a.url = datadogIntakeURL(site)
+		}
+	}
+}
+
+// WithDatadogURL 设置完整的接入地址（代理或测试环境）
+func WithDatadogURL(url string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.url = url
+	}
+}
+
+// WithDatadogService 设置 service
+func WithDatadogService(service string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.service = service
+	}
+}
+
+// WithDatadogSource 设置 ddsource（默认 DefaultDatadogSource）
+func WithDatadogSource(source string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.source = source
+	}
+}
+
+// WithDatadogHostname 设置 hostname（默认主机名）
+func WithDatadogHostname(hostname string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.hostname = hostname
+	}
+}
+
+// WithDatadogTags 追加 ddtags（如 "env:prod"）
+func WithDatadogTags(tags ...string) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.tags = append(a.tags, tags...)
+	}
+}
+
+// WithDatadogBatch 设置每批最大日志数与刷新间隔
+func WithDatadogBatch(size int, interval time.Duration) DatadogOption {
+	return func(a *DatadogAdapter) {
+		if size > 0 {
+			a.cfg.batchSize = min(size, 1000)
+		}
+		if interval > 0 {
+			a.cfg.flushInterval = interval
+		}
+	}
+}
+
+// WithDatadogHTTPClient 设置 HTTP 客户端
+func WithDatadogHTTPClient(client *http.Client) DatadogOption {
+	return func(a *DatadogAdapter) {
+		if client != nil {
+			a.cfg.client = client
+		}
+	}
+}
+
+// WithDatadogTLS 设置 TLS 配置（CA 证书、客户端证书等，见 TLSConfig.Build），应用到 HTTP 客户端的 Transport
+func WithDatadogTLS(conf *tls.Config) DatadogOption {
+	return func(a *DatadogAdapter) {
+		a.cfg.tls = conf
+	}
+}
+
+// WithDatadogQueueSize 设置发送队列长度（默认 DefaultHTTPBatchQueueSize）
+func WithDatadogQueueSize(size int) DatadogOption {
+	return func(a *DatadogAdapter) {
+		if size > 0 {
+			a.cfg.queueSize = size
+		}
+	}
+}
+
+// WithDatadogRetries 设置每批失败重试次数（默认 DefaultHTTPBatchRetries）
+func WithDatadogRetries(retries int) DatadogOption {
+	return func(a *DatadogAdapter) {
+		if retries >= 0 {
+			a.cfg.retries = retries
+		}
+	}
+}
+
+// NewDatadogAdapter 创建 Datadog 日志适配器并启动发送协程
+func NewDatadogAdapter(opts ...DatadogOption) *DatadogAdapter {
+	site := os.Getenv("DD_SITE")
+	if site == "" {
+		site = DefaultDatadogSite
+	}
+	a := &DatadogAdapter{
+		cfg:    defaultHTTPBatchConfig(DefaultDatadogBatchSize),
+		url:    datadogIntakeURL(site),
+		apiKey: os.Getenv("DD_API_KEY"),
+		source: DefaultDatadogSource,
+	}
+	a.hostname, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.cfg.resolveClient()
+
+	a.BaseAdapter = NewBaseAdapter("datadog", "1.0.0", a.emit)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("DD-API-KEY", a.apiKey)
+	a.sender = newHTTPBatchSender(a.cfg, httpBatchSpec{
+		component: OverflowComponentDatadog,
+		url:       a.url,
+		header:    header,
+		encode:    a.encode,
+		onHealth:  a.SetHealthy,
+	})
+	return a
+}
+
+// datadogIntakeURL 返回站点的日志接入地址
+func datadogIntakeURL(site string) string {
+	return "https://http-intake.logs." + site + "/api/v2/logs"
+}
+
+// emit 日志入队
+func (a *DatadogAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	a.sender.enqueue(httpBatchRecord{time: time.Now(), level: level, message: msg, fields: fields})
+}
+
+// encode 编码为 JSON 数组
+func (a *DatadogAdapter) encode(batch []httpBatchRecord) ([]byte, error) {
+	tags := strings.Join(a.tags, ",")
+	items := make([]map[string]any, len(batch))
+	for i, r := range batch {
+		item := make(map[string]any, len(r.fields)+7)
+		for k, v := range r.fields {
+			item[k] = v
+		}
+		item["ddsource"] = a.source
+		item["hostname"] = a.hostname
+		item["message"] = r.message
+		item["status"] = datadogStatus(r.level)
+		item["timestamp"] = r.time.UnixMilli()
+		if a.service != "" {
+			item["service"] = a.service
+		}
+		if tags != "" {
+			item["ddtags"] = tags
+		}
+		items[i] = item
+	}
+	return marshalJSON(items)
+}
+
+// datadogStatus 将日志级别映射为 Datadog status（扩展级别映射为 info）
+func datadogStatus(level LogLevel) string {
+	switch level {
+	case TRACE, DEBUG:
+		return "debug"
+	case WARN:
+		return "warn"
+	case ERROR:
+		return "error"
+	case FATAL:
+		return "critical"
+	}
+	return "info"
+}
+
+// Flush 立即发送队列中的日志并等待结果
+func (a *DatadogAdapter) Flush() error {
+	return a.sender.flush()
+}
+
+// Close 发送剩余日志后关闭
+func (a *DatadogAdapter) Close() error {
+	a.sender.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *DatadogAdapter) QueueDepth() (depth, capacity int) {
+	return a.sender.queueDepth()
+}
+
+// Stats 返回发送统计
+func (a *DatadogAdapter) Stats() HTTPBatchStats {
+	return a.sender.stats()
+}
+
+var _ IAdapter = (*DatadogAdapter)(nil)
@@ -0,0 +1,140 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\datadog_test.go
+ * @Description: Datadog 日志适配器测试（级别映射、批量编码与发送重试）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestDatadogStatus 基础级别一一映射，扩展级别（业务、安全、性能等）不得映射为 critical
+func TestDatadogStatus(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		want  string
+	}{
+		{TRACE, "debug"},
+		{DEBUG, "debug"},
+		{INFO, "info"},
+		{WARN, "warn"},
+		{ERROR, "error"},
+		{FATAL, "critical"},
+		{SYSTEM, "info"},
+		{BUSINESS, "info"},
+		{WORKFLOW, "info"},
+		{SECURITY, "info"},
+		{AUDIT, "info"},
+		{PERFORMANCE, "info"},
+		{PROFILING, "info"},
+	}
+	for _, tt := range tests {
+		if got := datadogStatus(tt.level); got != tt.want {
+			t.Errorf("datadogStatus(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestDatadogSend 批次编码为 JSON 数组并携带 DD-API-KEY；保留属性覆盖同名字段，5xx 重试，其余 4xx 不重试
+func TestDatadogSend(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []int // 依次返回的状态码（用尽后返回最后一个）
+		wantPosts int
+		wantSent  int64
+		wantFail  int64
+	}{
+		{"accepted", []int{http.StatusAccepted}, 1, 2, 0},
+		{"retry on 503", []int{http.StatusServiceUnavailable, http.StatusAccepted}, 2, 2, 0},
+		{"no retry on 400", []int{http.StatusBadRequest}, 1, 0, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				posts int
+				items []map[string]any
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				if key := r.Header.Get("DD-API-KEY"); key != "k1" {
+					t.Errorf("DD-API-KEY = %q, want k1", key)
+				}
+				body, _ := io.ReadAll(r.Body)
+				items = nil
+				if err := json.Unmarshal(body, &items); err != nil {
+					t.Errorf("body is not a JSON array: %s", body)
+				}
+				w.WriteHeader(tt.statuses[min(posts, len(tt.statuses)-1)])
+				posts++
+			}))
+			defer server.Close()
+
+			var reported []InternalError
+			SetInternalErrorHandler(func(e InternalError) { reported = append(reported, e) })
+			defer SetInternalErrorHandler(nil)
+
+			a := NewDatadogAdapter(
+				WithDatadogURL(server.URL),
+				WithDatadogAPIKey("k1"),
+				WithDatadogService("checkout"),
+				WithDatadogHostname("web-1"),
+				WithDatadogTags("env:test", "team:pay"),
+				WithDatadogRetries(1),
+			)
+			a.InfoKV("paid", "order", "o-1", "service", "ignored")
+			a.Error("declined")
+			a.Flush()
+			a.Close()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if posts != tt.wantPosts {
+				t.Errorf("posts = %d, want %d", posts, tt.wantPosts)
+			}
+			if stats := a.Stats(); stats.Sent != tt.wantSent || stats.Failed != tt.wantFail {
+				t.Errorf("stats = %+v, want sent %d failed %d", stats, tt.wantSent, tt.wantFail)
+			}
+			if (tt.wantFail > 0) != (len(reported) > 0) {
+				t.Errorf("internal errors = %v, want reported only on failure", reported)
+			}
+			if len(items) != 2 {
+				t.Fatalf("got %d items, want 2", len(items))
+			}
+			first := items[0]
+			want := map[string]any{
+				"message":  "paid",
+				"status":   "info",
+				"service":  "checkout",
+				"hostname": "web-1",
+				"ddsource": DefaultDatadogSource,
+				"ddtags":   "env:test,team:pay",
+				"order":    "o-1",
+			}
+			for k, v := range want {
+				if first[k] != v {
+					t.Errorf("item[%q] = %v, want %v", k, first[k], v)
+				}
+			}
+			if _, ok := first["timestamp"].(float64); !ok {
+				t.Errorf("timestamp = %#v, want epoch milliseconds", first["timestamp"])
+			}
+			if items[1]["status"] != "error" {
+				t.Errorf("second status = %v, want error", items[1]["status"])
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\debug.go
+ * @Description: /debug/logger 调试接口（日志级别、对象池统计、错误聚合、键值指标、健康检查）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebugPath 调试接口默认路径
+const DebugPath = "/debug/logger"
+
+// DebugHandler 调试接口
+//
+//	GET /debug/logger                      概要（级别、对象池统计、错误指纹数）
+//	GET /debug/logger/errors               错误聚合列表（按次数降序）
+//	GET /debug/logger/errors?fingerprint=x 单个指纹详情
+//	GET /debug/logger/targets              定向调试目标列表
+//	POST /debug/logger/targets             添加目标 {"key":"user_id","value":"42","ttl":"10m"}
+//	DELETE /debug/logger/targets?key=&value= 移除目标（不带参数时清空）
+//	GET /debug/logger/level                当前级别
+//	PUT /debug/logger/level                修改级别 {"level":"debug"}
+//	GET /debug/logger/query                日志查询 ?since=&until=（RFC3339）&level=&text=&field=k:v&limit=
+//	GET /debug/logger/metrics              键值指标统计 ?message=（可选，按消息过滤）
+//	GET /debug/logger/health               日志管道健康报告（需 WithDebugHealth，有适配器 down 时返回 503）
+//	GET /debug/logger/ui                   网页（需 WithDebugUI，参数同 query；POST level= 修改级别）
+type DebugHandler struct {
+	logger   *Logger
+	tracker  *ErrorTracker
+	targets  *DebugTargets
+	sources  []QuerySource
+	metrics  *KVMetrics
+	adapters []IAdapter
+	manager  *LoggerManager
+	ui       bool
+}
+
+// DebugHandlerOption 调试接口配置选项
+type DebugHandlerOption func(*DebugHandler)
+
+// WithDebugErrorTracker 设置错误聚合来源
+func WithDebugErrorTracker(tracker *ErrorTracker) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.tracker = tracker
+	}
+}
+
+// WithDebugTargetsAPI 开放定向调试目标的管理接口
+func WithDebugTargetsAPI(targets *DebugTargets) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.targets = targets
+	}
+}
+
+// WithDebugQuerySources 开放日志查询接口
+func WithDebugQuerySources(sources ...QuerySource) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.sources = append(h.sources, sources...)
+	}
+}
+
+// WithDebugKVMetrics 开放键值指标统计接口
+func WithDebugKVMetrics(metrics *KVMetrics) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.metrics = metrics
+	}
+}
+
+// WithDebugHealth 开放健康检查接口，概要中附带整体健康状态
+func WithDebugHealth(manager *LoggerManager) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.manager = manager
+	}
+}
+
+// NewDebugHandler 创建调试接口
+func NewDebugHandler(logger *Logger, opts ...DebugHandlerOption) *DebugHandler {
+	h := &DebugHandler{logger: logger}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Register 注册到 mux 的 /debug/logger 路径下
+func (h *DebugHandler) Register(mux *http.ServeMux) {
+	mux.Handle(DebugPath, h)
+	mux.Handle(DebugPath+"/", h)
+}
+
+// ServeHTTP 实现 http.Handler 接口
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sub := strings.Trim(strings.TrimPrefix(r.URL.Path, DebugPath), "/")
+	switch sub {
+	case "targets":
+		h.serveTargets(w, r)
+		return
+	case "level":
+		h.serveLevel(w, r)
+		return
+	case "ui":
+		h.serveUI(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch sub {
+	case "":
+		h.serveSummary(w)
+	case "errors":
+		h.serveErrors(w, r)
+	case "query":
+		h.serveQuery(w, r)
+	case "metrics":
+		h.serveMetrics(w, r)
+	case "health":
+		h.serveHealth(w)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSummary 概要
+func (h *DebugHandler) serveSummary(w http.ResponseWriter) {
+	summary := map[string]any{
+		"pools":        GetPoolStats(),
+		"caller_cache": GetCallerCacheStats(),
+	}
+	if h.logger != nil {
+		summary["level"] = h.logger.GetLevel()
+	}
+	if h.tracker != nil {
+		summary["error_fingerprints"] = len(h.tracker.Snapshot())
+	}
+	if h.targets != nil {
+		summary["debug_targets"] = len(h.targets.List())
+	}
+	if h.metrics != nil {
+		summary["kv_metrics"] = len(h.metrics.Snapshot())
+	}
+	if len(h.adapters) > 0 {
+		summary["adapters"] = h.adapterStatuses()
+	}
+	if h.manager != nil {
+		summary["health"] = h.manager.Health().Status
+	}
+	writeDebugJSON(w, summary)
+}
+
+// serveHealth 健康报告
+func (h *DebugHandler) serveHealth(w http.ResponseWriter) {
+	if h.manager == nil {
+		http.Error(w, "health check not configured", http.StatusNotFound)
+		return
+	}
+	writeHealthReport(w, h.manager.Health())
+}
+
+// serveErrors 错误聚合
+func (h *DebugHandler) serveErrors(w http.ResponseWriter, r *http.Request) {
+	if h.tracker == nil {
+		http.Error(w, "error tracker not configured", http.StatusNotFound)
+		return
+	}
+	if fp := r.URL.Query().Get("fingerprint"); fp != "" {
+		occ, ok := h.tracker.Get(fp)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeDebugJSON(w, occ)
+		return
+	}
+	writeDebugJSON(w, h.tracker.Snapshot())
+}
+
+// serveMetrics 键值指标统计
+func (h *DebugHandler) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	if h.metrics == nil {
+		http.Error(w, "kv metrics not configured", http.StatusNotFound)
+		return
+	}
+	stats := h.metrics.Snapshot()
+	if msg := r.URL.Query().Get("message"); msg != "" {
+		filtered := stats[:0]
+		for _, s := range stats {
+			if s.Message == msg {
+				filtered = append(filtered, s)
+			}
+		}
+		stats = filtered
+	}
+	writeDebugJSON(w, stats)
+}
+
+// serveTargets 定向调试目标管理
+func (h *DebugHandler) serveTargets(w http.ResponseWriter, r *http.Request) {
+	if h.targets == nil {
+		http.Error(w, "debug targets not configured", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeDebugJSON(w, h.targets.List())
+	case http.MethodPost:
+		var req struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+			TTL   string `json:"ttl"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ttl, err := time.ParseDuration(req.TTL)
+		if err != nil || ttl <= 0 || req.Key == "" || req.Value == "" {
+			http.Error(w, "key, value and a positive ttl are required", http.StatusBadRequest)
+			return
+		}
+		h.targets.Add(req.Key, req.Value, ttl)
+		writeDebugJSON(w, h.targets.List())
+	case http.MethodDelete:
+		query := r.URL.Query()
+		if key := query.Get("key"); key != "" {
+			h.targets.Remove(key, query.Get("value"))
+		} else {
+			h.targets.Clear()
+		}
+		writeDebugJSON(w, h.targets.List())
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveLevel 查看与修改日志级别
+func (h *DebugHandler) serveLevel(w http.ResponseWriter, r *http.Request) {
+	if h.logger == nil {
+		http.Error(w, "logger not configured", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		var req struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		level, err := ParseLevel(req.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.SetLevel(level)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	writeDebugJSON(w, map[string]any{"level": h.logger.GetLevel()})
+}
+
+// serveQuery 日志查询
+func (h *DebugHandler) serveQuery(w http.ResponseWriter, r *http.Request) {
+	if len(h.sources) == 0 {
+		http.Error(w, "query sources not configured", http.StatusNotFound)
+		return
+	}
+
+	criteria, err := parseQueryCriteria(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, err := Query(criteria, h.sources...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeDebugJSON(w, entries)
+}
+
+// parseQueryCriteria 从 URL 参数解析查询条件
+func parseQueryCriteria(values url.Values) (QueryCriteria, error) {
+	var c QueryCriteria
+	var err error
+	if v := values.Get("since"); v != "" {
+		if c.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return c, err
+		}
+	}
+	if v := values.Get("until"); v != "" {
+		if c.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return c, err
+		}
+	}
+	if v := values.Get("level"); v != "" {
+		if c.Level, err = ParseLevel(v); err != nil {
+			return c, err
+		}
+	}
+	if v := values.Get("limit"); v != "" {
+		if c.Limit, err = strconv.Atoi(v); err != nil {
+			return c, err
+		}
+	}
+	c.Text = values.Get("text")
+	for _, field := range values["field"] {
+		k, v, ok := strings.Cut(field, ":")
+		if !ok {
+			return c, fmt.Errorf("invalid field matcher %q, want key:value", field)
+		}
+		if c.Fields == nil {
+			c.Fields = make(map[string]string)
+		}
+		c.Fields[k] = v
+	}
+	return c, nil
+}
+
+// writeDebugJSON 输出格式化 JSON
+func writeDebugJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\debuglevel.go
+ * @Description: 按请求提升日志级别：校验 X-Debug-Level 请求头（签名或内网来源）后通过 context 仅对该请求生效
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebugLevelHeader 按请求提升日志级别的默认请求头
+const DebugLevelHeader = "X-Debug-Level"
+
+// contextLevelKey context 中请求级日志级别的 key
+type contextLevelKey struct{}
+
+// ContextWithLevel 设置该 context 的日志级别，FromContext 返回的 Logger 使用更详细的一方
+func ContextWithLevel(ctx context.Context, level LogLevel) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, contextLevelKey{}, level)
+}
+
+// LevelFromContext 返回 ContextWithLevel 设置的日志级别
+func LevelFromContext(ctx context.Context) (LogLevel, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(contextLevelKey{}).(LogLevel)
+	return level, ok
+}
+
+// PrivateNetworks 回环、RFC 1918 私有网段与 IPv6 ULA，可用于 WithDebugLevelNetworks
+var PrivateNetworks = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("fc00::/7"),
+}
+
+// DebugLevelGuard 校验 X-Debug-Level 请求头，通过后仅对该请求提升日志级别：
+//
+//	guard := logger.NewDebugLevelGuard(
+//		logger.WithDebugLevelSecret(secret),
+//		logger.WithDebugLevelNetworks(logger.PrivateNetworks...),
+//	)
+//	mux.Handle("/", guard.Middleware(handler))
+//	// 处理函数中 log.FromContext(r.Context()).Debug(...)
+//
+// 请求头取值为级别名（如 "debug"），或由 SignDebugLevel 生成的 "级别:过期时间:签名"：
+// 签名有效且未过期时接受（可来自任意网络），未签名的取值仅接受来自可信网段的请求；
+// 两者均未配置时拒绝所有请求头。只接受允许列表中的级别（默认 TRACE、DEBUG）
+type DebugLevelGuard struct {
+	header   string
+	secret   []byte
+	networks []netip.Prefix
+	allowed  []LogLevel
+}
+
+// DebugLevelOption 请求级别校验配置选项
+type DebugLevelOption func(*DebugLevelGuard)
+
+// WithDebugLevelHeader 设置请求头名（默认 DebugLevelHeader）
+func WithDebugLevelHeader(name string) DebugLevelOption {
+	return func(g *DebugLevelGuard) {
+		if name != "" {
+			g.header = name
+		}
+	}
+}
+
+// WithDebugLevelSecret 设置签名密钥，接受 SignDebugLevel 生成的签名取值
+func WithDebugLevelSecret(secret []byte) DebugLevelOption {
+	return func(g *DebugLevelGuard) {
+		g.secret = secret
+	}
+}
+
+// WithDebugLevelNetworks 设置可信网段，来自这些网段的请求可使用未签名的级别名
+// 来源地址取自 http.Request.RemoteAddr，位于反向代理之后时需先由代理中间件改写
+func WithDebugLevelNetworks(networks ...netip.Prefix) DebugLevelOption {
+	return func(g *DebugLevelGuard) {
+		g.networks = append(g.networks, networks...)
+	}
+}
+
+// WithDebugLevelAllowed 设置允许提升到的级别（默认 TRACE、DEBUG）
+func WithDebugLevelAllowed(levels ...LogLevel) DebugLevelOption {
+	return func(g *DebugLevelGuard) {
+		g.allowed = levels
+	}
+}
+
+// NewDebugLevelGuard 创建请求级别校验器
+func NewDebugLevelGuard(opts ...DebugLevelOption) *DebugLevelGuard {
+	g := &DebugLevelGuard{
+		header:  DebugLevelHeader,
+		allowed: []LogLevel{TRACE, DEBUG},
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// SignDebugLevel 生成签名的请求头取值 "级别:过期时间(unix 秒):签名"，签名为 HMAC-SHA256(secret, "级别:过期时间")
+func SignDebugLevel(secret []byte, level LogLevel, expires time.Time) string {
+	payload := strings.ToLower(level.String()) + ":" + strconv.FormatInt(expires.Unix(), 10)
+	return payload + ":" + debugLevelSignature(secret, payload)
+}
+
+// debugLevelSignature 计算签名
+func debugLevelSignature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验请求头取值，remote 为请求来源地址（"ip:port" 或 "ip"），通过时返回级别
+func (g *DebugLevelGuard) Verify(value, remote string) (LogLevel, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	name := value
+	if parts := strings.Split(value, ":"); len(parts) == 3 {
+		if !g.verifySignature(parts[0], parts[1], parts[2]) {
+			return 0, false
+		}
+		name = parts[0]
+	} else if !g.trusted(remote) {
+		return 0, false
+	}
+
+	level, err := ParseLevel(name)
+	if err != nil || !slices.Contains(g.allowed, level) {
+		return 0, false
+	}
+	return level, true
+}
+
+// verifySignature 校验签名与过期时间
+func (g *DebugLevelGuard) verifySignature(name, expires, signature string) bool {
+	if len(g.secret) == 0 {
+		return false
+	}
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	want := debugLevelSignature(g.secret, name+":"+expires)
+	return hmac.Equal([]byte(want), []byte(strings.ToLower(signature)))
+}
+
+// trusted 来源地址是否位于可信网段
+func (g *DebugLevelGuard) trusted(remote string) bool {
+	if len(g.networks) == 0 {
+		return false
+	}
+	var addr netip.Addr
+	if ap, err := netip.ParseAddrPort(remote); err == nil {
+		addr = ap.Addr()
+	} else if a, err := netip.ParseAddr(remote); err == nil {
+		addr = a
+	} else {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range g.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Level 校验请求的级别请求头，通过时返回级别
+func (g *DebugLevelGuard) Level(r *http.Request) (LogLevel, bool) {
+	return g.Verify(r.Header.Get(g.header), r.RemoteAddr)
+}
+
+// Middleware HTTP 中间件：请求头校验通过时在 context 中设置该请求的日志级别
+func (g *DebugLevelGuard) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if level, ok := g.Level(r); ok {
+			r = r.WithContext(ContextWithLevel(r.Context(), level))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
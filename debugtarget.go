@@ -0,0 +1,236 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\debugtarget.go
+ * @Description: 定向调试：命中指定键值（用户、租户、请求头）的请求临时以 DEBUG 级别输出，到期自动失效
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// forceDebugKey context 中标记强制 DEBUG 的 key
+type forceDebugKey struct{}
+
+// DebugTarget 定向调试目标
+type DebugTarget struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DebugTargets 定向调试目标注册表（并发安全）
+// Key 既作为 context.Value 的字符串 key、gRPC metadata key，也作为 HTTP 请求头名
+type DebugTargets struct {
+	mu      sync.RWMutex
+	targets map[string]map[string]time.Time
+	count   atomic.Int32
+}
+
+// NewDebugTargets 创建定向调试目标注册表
+func NewDebugTargets() *DebugTargets {
+	return &DebugTargets{targets: make(map[string]map[string]time.Time)}
+}
+
+// Add 注册目标，ttl 到期后自动失效
+func (t *DebugTargets) Add(key, value string, ttl time.Duration) {
+	if key == "" || value == "" || ttl <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	values, ok := t.targets[key]
+	if !ok {
+		values = make(map[string]time.Time)
+		t.targets[key] = values
+	}
+	values[value] = time.Now().Add(ttl)
+	t.recountLocked()
+}
+
+// Remove 移除目标
+func (t *DebugTargets) Remove(key, value string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if values, ok := t.targets[key]; ok {
+		delete(values, value)
+		if len(values) == 0 {
+			delete(t.targets, key)
+		}
+	}
+	t.recountLocked()
+}
+
+// Clear 清空所有目标
+func (t *DebugTargets) Clear() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.targets = make(map[string]map[string]time.Time)
+	t.recountLocked()
+}
+
+// List 返回未过期的目标（按 key、value 排序），同时清理已过期目标
+func (t *DebugTargets) List() []DebugTarget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	result := make([]DebugTarget, 0)
+	for key, values := range t.targets {
+		for value, expiresAt := range values {
+			if now.After(expiresAt) {
+				delete(values, value)
+				continue
+			}
+			result = append(result, DebugTarget{Key: key, Value: value, ExpiresAt: expiresAt})
+		}
+		if len(values) == 0 {
+			delete(t.targets, key)
+		}
+	}
+	t.recountLocked()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Key != result[j].Key {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
+
+// recountLocked 更新目标数（调用方持有写锁）
+func (t *DebugTargets) recountLocked() {
+	n := 0
+	for _, values := range t.targets {
+		n += len(values)
+	}
+	t.count.Store(int32(n))
+}
+
+// Empty 是否没有任何目标
+func (t *DebugTargets) Empty() bool {
+	return t.count.Load() == 0
+}
+
+// activeLocked 检查键值是否为未过期的目标（调用方持有读锁）
+func (t *DebugTargets) activeLocked(key, value string, now time.Time) bool {
+	if value == "" {
+		return false
+	}
+	expiresAt, ok := t.targets[key][value]
+	return ok && now.Before(expiresAt)
+}
+
+// Match 检查 context 是否命中目标：依次检查强制标记、context.Value(key) 与 gRPC incoming metadata
+func (t *DebugTargets) Match(ctx context.Context) bool {
+	if isForceDebug(ctx) {
+		return true
+	}
+	if ctx == nil || t.Empty() {
+		return false
+	}
+
+	md, hasMD := metadata.FromIncomingContext(ctx)
+	now := time.Now()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for key := range t.targets {
+		if value, ok := ctx.Value(key).(string); ok && t.activeLocked(key, value, now) {
+			return true
+		}
+		if hasMD {
+			for _, value := range md.Get(key) {
+				if t.activeLocked(key, value, now) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MatchHeader 检查 HTTP 请求头是否命中目标
+func (t *DebugTargets) MatchHeader(header http.Header) bool {
+	if t.Empty() {
+		return false
+	}
+	now := time.Now()
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for key := range t.targets {
+		for _, value := range header.Values(key) {
+			if t.activeLocked(key, value, now) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Middleware HTTP 中间件：请求头命中目标时在 context 中标记强制 DEBUG
+func (t *DebugTargets) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if t.MatchHeader(r.Header) {
+			r = r.WithContext(ContextWithForceDebug(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ContextWithForceDebug 标记 context 强制以 DEBUG 级别输出
+func ContextWithForceDebug(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, forceDebugKey{}, true)
+}
+
+// isForceDebug context 是否被标记为强制 DEBUG
+func isForceDebug(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	forced, _ := ctx.Value(forceDebugKey{}).(bool)
+	return forced
+}
+
+// WithDebugTargets 设置定向调试目标，配合 FromContext 使用
+func (l *Logger) WithDebugTargets(targets *DebugTargets) *Logger {
+	l.debugTargets = targets
+	return l
+}
+
+// debugLogger 命中定向调试目标时返回 DEBUG 级别的子 Logger，
+// context 设置了更详细的级别（ContextWithLevel）时使用该级别
+func (l *Logger) debugLogger(ctx context.Context) *Logger {
+	level := l.level
+	if lv, ok := LevelFromContext(ctx); ok && lv < level {
+		level = lv
+	}
+	if level > DEBUG && (isForceDebug(ctx) || (l.debugTargets != nil && l.debugTargets.Match(ctx))) {
+		level = DEBUG
+	}
+	if level == l.level {
+		return l
+	}
+	child := l.Clone().(*Logger)
+	child.level = level
+	return child
+}
@@ -0,0 +1,191 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\debugui.go
+ * @Description: /debug/logger/ui 内置网页：最近日志、级别控制与适配器健康状态（无外部资源依赖）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"sort"
+)
+
+// DefaultDebugUILimit 网页默认展示的最近日志条数
+const DefaultDebugUILimit = 200
+
+// WithDebugUI 开放 /debug/logger/ui 网页（最近日志来自 WithDebugQuerySources，如 query.Ring）
+func WithDebugUI() DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.ui = true
+	}
+}
+
+// WithDebugAdapters 在概要与网页中展示适配器健康状态
+func WithDebugAdapters(adapters ...IAdapter) DebugHandlerOption {
+	return func(h *DebugHandler) {
+		h.adapters = append(h.adapters, adapters...)
+	}
+}
+
+// debugAdapterStatus 适配器状态
+type debugAdapterStatus struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Level   LogLevel `json:"level"`
+	Healthy bool     `json:"healthy"`
+}
+
+// adapterStatuses 返回适配器状态（按名称排序）
+func (h *DebugHandler) adapterStatuses() []debugAdapterStatus {
+	statuses := make([]debugAdapterStatus, 0, len(h.adapters))
+	for _, a := range h.adapters {
+		statuses = append(statuses, debugAdapterStatus{
+			Name:    a.GetAdapterName(),
+			Version: a.GetAdapterVersion(),
+			Level:   a.GetLevel(),
+			Healthy: a.IsHealthy(),
+		})
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// debugUIPage 网页数据
+type debugUIPage struct {
+	Path     string
+	Level    string
+	Levels   []string
+	Filter   debugUIFilter
+	Entries  []Entry
+	HasQuery bool
+	Adapters []debugAdapterStatus
+	Error    string
+}
+
+// debugUIFilter 日志过滤参数（原样回填到表单）
+type debugUIFilter struct {
+	Level string
+	Text  string
+	Limit int
+}
+
+// serveUI 渲染网页；POST 表单 level=xxx 修改级别后重定向回页面
+func (h *DebugHandler) serveUI(w http.ResponseWriter, r *http.Request) {
+	if !h.ui {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if h.logger == nil {
+			http.Error(w, "logger not configured", http.StatusNotFound)
+			return
+		}
+		level, err := ParseLevel(r.PostFormValue("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.logger.SetLevel(level)
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	page := debugUIPage{
+		Path:     DebugPath,
+		HasQuery: len(h.sources) > 0,
+		Adapters: h.adapterStatuses(),
+	}
+	for _, level := range LevelsAtLeast(TRACE) {
+		page.Levels = append(page.Levels, level.String())
+	}
+	if h.logger != nil {
+		page.Level = h.logger.GetLevel().String()
+	}
+
+	values := r.URL.Query()
+	criteria, err := parseQueryCriteria(values)
+	if err != nil {
+		page.Error = err.Error()
+	}
+	if criteria.Limit <= 0 {
+		criteria.Limit = DefaultDebugUILimit
+	}
+	page.Filter = debugUIFilter{Level: values.Get("level"), Text: criteria.Text, Limit: criteria.Limit}
+	if page.HasQuery && err == nil {
+		entries, err := Query(criteria, h.sources...)
+		if err != nil {
+			page.Error = err.Error()
+		}
+		// 最新的日志在前
+		slices.Reverse(entries)
+		page.Entries = entries
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := debugUITemplate.Execute(w, page); err != nil {
+		reportInternalError("debug_ui", err)
+	}
+}
+
+// debugUITemplate 网页模板
+var debugUITemplate = template.Must(template.New("ui").Funcs(template.FuncMap{
+	"time": func(e Entry) string { return e.Time.Format("2006-01-02 15:04:05.000") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>go-logger</title>
+<style>
+body { font: 13px/1.4 -apple-system, "Segoe UI", sans-serif; margin: 16px; color: #222; }
+h2 { font-size: 15px; margin: 20px 0 8px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: 3px 8px; border-bottom: 1px solid #eee; vertical-align: top; }
+td.fields { font-family: ui-monospace, monospace; color: #555; word-break: break-all; }
+.TRACE, .DEBUG { color: #888; } .WARN { color: #b8860b; } .ERROR, .FATAL { color: #c00; font-weight: bold; }
+.ok { color: #080; } .bad { color: #c00; font-weight: bold; } .err { color: #c00; }
+form { margin: 0 0 8px; } input, select, button { font: inherit; }
+</style>
+</head>
+<body>
+<h2>Level</h2>
+{{if .Level}}<form method="post">
+<select name="level">{{range .Levels}}<option{{if eq . $.Level}} selected{{end}}>{{.}}</option>{{end}}</select>
+<button type="submit">Apply</button> current: <b class="{{.Level}}">{{.Level}}</b>
+</form>{{else}}<p>logger not configured</p>{{end}}
+
+{{if .Adapters}}<h2>Adapters</h2>
+<table>
+<tr><th>Name</th><th>Version</th><th>Level</th><th>Health</th></tr>
+{{range .Adapters}}<tr><td>{{.Name}}</td><td>{{.Version}}</td><td>{{.Level}}</td><td>{{if .Healthy}}<span class="ok">healthy</span>{{else}}<span class="bad">unhealthy</span>{{end}}</td></tr>
+{{end}}</table>{{end}}
+
+<h2>Recent entries</h2>
+{{if .HasQuery}}<form method="get">
+level <select name="level"><option value="">ALL</option>{{range .Levels}}<option{{if eq . $.Filter.Level}} selected{{end}}>{{.}}</option>{{end}}</select>
+text <input name="text" value="{{.Filter.Text}}">
+limit <input name="limit" size="5" value="{{.Filter.Limit}}">
+<button type="submit">Filter</button> <a href="{{.Path}}/query">json</a>
+</form>
+{{if .Error}}<p class="err">{{.Error}}</p>{{end}}
+<table>
+<tr><th>Time</th><th>Level</th><th>Message</th><th>Fields</th></tr>
+{{range .Entries}}<tr><td>{{time .}}</td><td class="{{.Level}}">{{.Level}}</td><td>{{.Message}}</td><td class="fields">{{range $k, $v := .Fields}}{{$k}}={{$v}} {{end}}</td></tr>
+{{else}}<tr><td colspan="4">no entries</td></tr>
+{{end}}</table>
+{{else}}<p>query sources not configured</p>{{end}}
+</body>
+</html>
+`))
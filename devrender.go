@@ -0,0 +1,155 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\devrender.go
+ * @Description: 开发环境渲染器：终端中以对齐的人类可读格式输出结构化日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// DevRenderMode 开发渲染模式
+type DevRenderMode int
+
+const (
+	DevRenderAuto   DevRenderMode = iota // 配置了 JSON 格式化器且输出为终端时使用（默认）
+	DevRenderAlways                      // 始终使用
+	DevRenderNever                       // 从不使用
+)
+
+// 开发渲染的颜色
+const (
+	devDim   = "\033[2m"
+	devReset = "\033[0m"
+)
+
+// DevFormatter 开发环境格式化器："时间 | LEVEL | 消息 | k=v"，字段按键排序并以暗色显示
+type DevFormatter struct {
+	color      bool
+	timeLayout string
+	sanitize   SanitizeMode
+}
+
+// NewDevFormatter 创建开发环境格式化器
+func NewDevFormatter(color bool) *DevFormatter {
+	return &DevFormatter{color: color, timeLayout: "15:04:05.000"}
+}
+
+// Format 实现 IFormatter 接口
+func (f *DevFormatter) Format(entry *LogEntry) ([]byte, error) {
+	buf := make([]byte, 0, 128)
+	buf = f.dim(buf, time.Unix(0, entry.Timestamp).Format(f.timeLayout))
+	buf = append(buf, " | "...)
+	if f.color {
+		buf = append(buf, entry.Level.Color()...)
+	}
+	buf = append(buf, fmt.Sprintf("%-5s", entry.Level.String())...)
+	if f.color {
+		buf = append(buf, devReset...)
+	}
+	buf = append(buf, " | "...)
+	if entry.Caller != nil {
+		buf = f.dim(buf, fmt.Sprintf("%s:%d ", shortFile(entry.Caller.File), entry.Caller.Line))
+	}
+	start := len(buf)
+	buf = append(buf, entry.Message...)
+	buf = sanitizeTail(buf, start, f.sanitize, NewlineKeep)
+
+	if len(entry.Fields) > 0 {
+		keys := make([]string, 0, len(entry.Fields))
+		for k := range entry.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf = append(buf, " | "...)
+		for i, k := range keys {
+			if i > 0 {
+				buf = append(buf, ' ')
+			}
+			buf = f.dim(buf, k+"="+string(sanitizeTail([]byte(devValue(entry.Fields[k])), 0, f.sanitize, NewlineKeep)))
+		}
+	}
+	return append(buf, '\n'), nil
+}
+
+// GetName 实现 IFormatter 接口
+func (f *DevFormatter) GetName() string {
+	return "dev"
+}
+
+// WithSanitize 设置消息与字段值中控制字符的处理方式（默认 SanitizeEscape）
+func (f *DevFormatter) WithSanitize(mode SanitizeMode) *DevFormatter {
+	f.sanitize = mode
+	return f
+}
+
+// dim 以暗色追加文本
+func (f *DevFormatter) dim(buf []byte, s string) []byte {
+	if !f.color {
+		return append(buf, s...)
+	}
+	buf = append(buf, devDim...)
+	buf = append(buf, s...)
+	return append(buf, devReset...)
+}
+
+// devValue 字段值包含空白时加引号
+func devValue(v any) string {
+	s := string(convert.AppendValue(nil, v))
+	if strings.ContainsAny(s, " \t\n\"") {
+		return fmt.Sprintf("%q", s)
+	}
+	return s
+}
+
+// shortFile 只保留文件名
+func shortFile(path string) string {
+	if idx := strings.LastIndexAny(path, `/\`); idx >= 0 {
+		return path[idx+1:]
+	}
+	return path
+}
+
+// WithDevRenderer 设置开发渲染模式（默认 DevRenderAuto）
+func (l *Logger) WithDevRenderer(mode DevRenderMode) *Logger {
+	l.devRender = mode
+	l.updateRenderer()
+	return l
+}
+
+// updateRenderer 根据渲染模式、格式化器与输出目标决定是否使用开发渲染
+func (l *Logger) updateRenderer() {
+	l.renderer = nil
+	switch l.devRender {
+	case DevRenderAlways:
+		l.renderer = NewDevFormatter(isTerminal(l.output)).WithSanitize(l.sanitize)
+	case DevRenderAuto:
+		if l.formatter != nil && strings.Contains(strings.ToLower(l.formatter.GetName()), string(FormatJSON)) && isTerminal(l.output) {
+			l.renderer = NewDevFormatter(true).WithSanitize(l.sanitize)
+		}
+	}
+}
+
+// isTerminal 输出目标是否为终端
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
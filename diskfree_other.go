@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !windows
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\diskfree_other.go
+ * @Description: 不支持查询磁盘剩余空间的平台
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+// diskFree 当前平台不支持
+func diskFree(string) (uint64, error) {
+	return 0, ErrDiskFreeUnsupported
+}
@@ -0,0 +1,24 @@
+//go:build linux || darwin
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\diskfree_unix.go
+ * @Description: 基于 statfs 查询磁盘剩余空间
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "golang.org/x/sys/unix"
+
+// diskFree 返回 path 所在卷对非特权用户可用的剩余字节数
+func diskFree(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
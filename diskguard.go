@@ -0,0 +1,327 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\diskguard.go
+ * @Description: 磁盘空间守护输出器：剩余空间低于阈值时清理最旧的轮转文件、上报内部错误或切换为丢弃模式
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultDiskGuardInterval = 10 * time.Second  // 默认检查间隔
+	DefaultDiskGuardMinFree  = 512 * 1024 * 1024 // 默认最低剩余空间 512MB
+)
+
+// ErrDiskSpaceLow 磁盘剩余空间低于阈值
+var ErrDiskSpaceLow = errors.New("logger: disk space low")
+
+// ErrDiskFreeUnsupported 当前平台不支持查询磁盘剩余空间
+var ErrDiskFreeUnsupported = errors.New("logger: disk free space query not supported on this platform")
+
+// DiskGuardEvent 磁盘空间检查结果（剩余空间不足或恢复时回调）
+type DiskGuardEvent struct {
+	Path     string // 检查的路径
+	Free     uint64 // 剩余可用字节数（清理之后）
+	MinFree  uint64 // 阈值
+	Removed  int    // 本次清理删除的文件数
+	Dropping bool   // 是否处于丢弃模式
+}
+
+// DiskGuardWriter 磁盘空间守护输出器（包装文件类输出器）
+// 后台按间隔检查 path 所在卷的剩余空间，低于阈值时依次：删除匹配 cleanup 的最旧文件、上报内部错误（ErrDiskSpaceLow）、
+// 开启 drop 时丢弃后续写入直到空间恢复，避免写满磁盘影响宿主机
+type DiskGuardWriter struct {
+	baseWriter
+	underlying IWriter
+	path       string
+	minFree    uint64
+	interval   time.Duration
+	cleanup    string
+	drop       bool
+	onLow      func(DiskGuardEvent)
+
+	dropping atomic.Bool
+	dropped  atomic.Int64
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// DiskGuardOption 磁盘空间守护输出器配置选项
+type DiskGuardOption func(*DiskGuardWriter)
+
+// WithDiskGuardUnderlying 设置底层输出器
+func WithDiskGuardUnderlying(underlying IWriter) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.underlying = underlying
+	}
+}
+
+// WithDiskGuardPath 设置检查的路径（日志文件或其所在目录）
+func WithDiskGuardPath(path string) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.path = path
+	}
+}
+
+// WithDiskGuardMinFree 设置最低剩余空间（字节，默认 DefaultDiskGuardMinFree）
+func WithDiskGuardMinFree(bytes uint64) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		if bytes > 0 {
+			w.minFree = bytes
+		}
+	}
+}
+
+// WithDiskGuardInterval 设置检查间隔（默认 DefaultDiskGuardInterval）
+func WithDiskGuardInterval(interval time.Duration) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// WithDiskGuardCleanup 设置空间不足时可删除的文件（glob 模式，如 "/var/log/app.log.*"），按修改时间从旧到新删除
+// 模式不应匹配正在写入的文件
+func WithDiskGuardCleanup(pattern string) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.cleanup = pattern
+	}
+}
+
+// WithDiskGuardDrop 清理后仍不足时是否丢弃写入（默认开启）
+func WithDiskGuardDrop(drop bool) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.drop = drop
+	}
+}
+
+// WithDiskGuardOnLow 设置空间不足与恢复时的回调（在检查协程中调用）
+func WithDiskGuardOnLow(fn func(DiskGuardEvent)) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.onLow = fn
+	}
+}
+
+// WithDiskGuardLevel 设置日志级别
+func WithDiskGuardLevel(level LogLevel) DiskGuardOption {
+	return func(w *DiskGuardWriter) {
+		w.level = level
+	}
+}
+
+// NewDiskGuardWriter 创建磁盘空间守护输出器，创建时立即检查一次
+//
+//	w := logger.NewDiskGuardWriter(
+//		logger.WithDiskGuardUnderlying(rotate),
+//		logger.WithDiskGuardPath("/var/log/app.log"),
+//		logger.WithDiskGuardMinFree(1<<30),
+//		logger.WithDiskGuardCleanup("/var/log/app.log.*"),
+//	)
+func NewDiskGuardWriter(opts ...DiskGuardOption) *DiskGuardWriter {
+	w := &DiskGuardWriter{
+		baseWriter: baseWriter{
+			level:   DEBUG,
+			healthy: true,
+			stats:   newWriterStats(),
+		},
+		minFree:  DefaultDiskGuardMinFree,
+		interval: DefaultDiskGuardInterval,
+		drop:     true,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.Check()
+	go w.run()
+	return w
+}
+
+// run 后台定期检查
+func (w *DiskGuardWriter) run() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Check()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// Check 立即检查一次剩余空间，返回检查结果
+func (w *DiskGuardWriter) Check() (DiskGuardEvent, error) {
+	event := DiskGuardEvent{Path: w.path, MinFree: w.minFree}
+	free, err := diskFree(w.checkPath())
+	if err != nil {
+		reportInternalError("disk_guard", err)
+		return event, err
+	}
+
+	if free < w.minFree && w.cleanup != "" {
+		event.Removed, free = w.removeOldest(free)
+	}
+	event.Free = free
+
+	wasDropping := w.dropping.Load()
+	if free >= w.minFree {
+		if wasDropping {
+			w.dropping.Store(false)
+			if w.onLow != nil {
+				w.onLow(event)
+			}
+		}
+		return event, nil
+	}
+
+	event.Dropping = w.drop
+	w.dropping.Store(w.drop)
+	reportInternalError("disk_guard", fmt.Errorf("%w: %s has %d bytes free, below %d", ErrDiskSpaceLow, w.path, free, w.minFree))
+	if w.onLow != nil {
+		w.onLow(event)
+	}
+	return event, nil
+}
+
+// checkPath 返回查询剩余空间使用的路径（文件尚未创建时使用其所在目录）
+func (w *DiskGuardWriter) checkPath() string {
+	if w.path == "" {
+		return "."
+	}
+	if _, err := os.Stat(w.path); err != nil {
+		return filepath.Dir(w.path)
+	}
+	return w.path
+}
+
+// removeOldest 按修改时间从旧到新删除匹配的文件，直到空间恢复或没有可删除的文件
+func (w *DiskGuardWriter) removeOldest(free uint64) (int, uint64) {
+	matches, err := filepath.Glob(w.cleanup)
+	if err != nil {
+		reportInternalError("disk_guard", err)
+		return 0, free
+	}
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	candidates := make([]candidate, 0, len(matches))
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			candidates = append(candidates, candidate{path: path, modTime: info.ModTime()})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.Before(candidates[j].modTime)
+	})
+
+	removed := 0
+	for _, c := range candidates {
+		if free >= w.minFree {
+			break
+		}
+		if err := os.Remove(c.path); err != nil {
+			reportInternalError("disk_guard", err)
+			continue
+		}
+		removed++
+		if f, err := diskFree(w.checkPath()); err == nil {
+			free = f
+		}
+	}
+	return removed, free
+}
+
+// Write 实现 io.Writer 接口（丢弃模式下视为写入成功）
+func (w *DiskGuardWriter) Write(p []byte) (n int, err error) {
+	if w.underlying == nil {
+		return 0, fmt.Errorf("disk guard writer has no underlying writer")
+	}
+	if w.dropping.Load() {
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+	n, err = w.underlying.Write(p)
+	if err != nil {
+		w.stats.addError()
+		return n, err
+	}
+	w.stats.addBytes(int64(n))
+	return n, nil
+}
+
+// WriteLevel 按级别写入
+func (w *DiskGuardWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
+	if level < w.level {
+		return len(data), nil
+	}
+	if w.dropping.Load() {
+		w.dropped.Add(1)
+		return len(data), nil
+	}
+	if w.underlying == nil {
+		return 0, fmt.Errorf("disk guard writer has no underlying writer")
+	}
+	n, err = w.underlying.WriteLevel(level, data)
+	if err != nil {
+		w.stats.addError()
+		return n, err
+	}
+	w.stats.addBytes(int64(n))
+	return n, nil
+}
+
+// Flush 刷新底层输出器
+func (w *DiskGuardWriter) Flush() error {
+	if w.underlying == nil {
+		return nil
+	}
+	return w.underlying.Flush()
+}
+
+// Close 停止检查并关闭底层输出器
+func (w *DiskGuardWriter) Close() error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	if w.underlying == nil {
+		return nil
+	}
+	return w.underlying.Close()
+}
+
+// IsHealthy 检查健康状态（丢弃模式下视为不健康）
+func (w *DiskGuardWriter) IsHealthy() bool {
+	return !w.dropping.Load() && w.underlying != nil && w.underlying.IsHealthy()
+}
+
+// GetStats 获取统计信息
+func (w *DiskGuardWriter) GetStats() WriterStatsSnapshot {
+	return w.stats.getSnapshot()
+}
+
+// Dropping 是否处于丢弃模式
+func (w *DiskGuardWriter) Dropping() bool {
+	return w.dropping.Load()
+}
+
+// Dropped 返回丢弃模式下丢弃的写入次数
+func (w *DiskGuardWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
@@ -12,6 +12,7 @@ package logger
 
 import (
 	"context"
+	"io"
 	"sync"
 )
 
@@ -32,6 +33,16 @@ func NewEmptyLogger() *EmptyLogger {
 	}
 }
 
+// Nop 返回不做任何工作的 ILogger，适合作为库中 ILogger 参数的默认值
+func Nop() ILogger {
+	return NewEmptyLogger()
+}
+
+// Discard 返回完整格式化但写入 io.Discard 的 Logger（DEBUG 级别、无颜色），可作为基准测试的基线
+func Discard() *Logger {
+	return NewLogger().WithOutput(io.Discard).WithColorful(false).WithLevel(DEBUG)
+}
+
 // NewEmptyLoggerWithLevel 创建一个指定级别的空日志实例
 func NewEmptyLoggerWithLevel(level LogLevel) *EmptyLogger {
 	logger := NewEmptyLogger()
@@ -108,6 +119,12 @@ func (e *EmptyLogger) ErrorLines(lines ...string) {}
 func (e *EmptyLogger) WarnLines(lines ...string)  {}
 func (e *EmptyLogger) DebugLines(lines ...string) {}
 
+// 块日志方法 - 空实现
+func (e *EmptyLogger) DebugBlock(title, body string) {}
+func (e *EmptyLogger) InfoBlock(title, body string)  {}
+func (e *EmptyLogger) WarnBlock(title, body string)  {}
+func (e *EmptyLogger) ErrorBlock(title, body string) {}
+
 // 带上下文的日志方法
 func (e *EmptyLogger) DebugContext(ctx context.Context, format string, args ...interface{}) {}
 func (e *EmptyLogger) InfoContext(ctx context.Context, format string, args ...interface{})  {}
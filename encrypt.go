@@ -0,0 +1,264 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\encrypt.go
+ * @Description: 日志文件 AES-GCM 加密输出与解密读取
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// 加密记录格式：[4 字节大端长度 N][8 字节写入流标识][8 字节大端序号][12 字节 nonce][密文 + 16 字节 GCM 标签]，
+// N 为长度前缀之后的全部字节数。流标识与序号作为 GCM 的附加认证数据（AAD）：每次打开文件生成新的流标识，
+// 序号从 1 开始逐条递增，记录被删除、重排或在流之间移动都会在解密时发现（多进程共享写入同一文件时各进程各自成流）
+const (
+	encryptedLengthSize    = 4
+	encryptedStreamSize    = 8
+	encryptedSeqSize       = 8
+	encryptedAADSize       = encryptedStreamSize + encryptedSeqSize
+	maxEncryptedRecordSize = 16 * 1024 * 1024 // 单条加密记录上限，防止损坏的长度前缀导致超大分配
+)
+
+var (
+	ErrInvalidEncryptionKey    = errors.New("encryption key must be 16, 24 or 32 bytes")
+	ErrEncryptedRecordSize     = errors.New("encrypted record size invalid")
+	ErrEncryptedRecordSequence = errors.New("encrypted record out of sequence")
+)
+
+// KeyProvider 加密密钥提供者（可从环境变量、KMS 等获取）
+type KeyProvider func() ([]byte, error)
+
+// StaticKey 固定密钥
+func StaticKey(key []byte) KeyProvider {
+	key = append([]byte(nil), key...)
+	return func() ([]byte, error) {
+		return key, nil
+	}
+}
+
+// KeyFromEnv 从环境变量读取密钥，支持 hex 或 base64 编码
+func KeyFromEnv(name string) KeyProvider {
+	return func() ([]byte, error) {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			return nil, fmt.Errorf("environment variable %s is empty", name)
+		}
+		if key, err := hex.DecodeString(value); err == nil {
+			return key, nil
+		}
+		if key, err := base64.StdEncoding.DecodeString(value); err == nil {
+			return key, nil
+		}
+		return nil, fmt.Errorf("environment variable %s is neither hex nor base64", name)
+	}
+}
+
+// newGCM 根据密钥长度创建 AES-128/192/256-GCM
+func newGCM(key []byte) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidEncryptionKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// recordEncryptor 按记录加密（调用方持有写锁）
+type recordEncryptor struct {
+	aead   cipher.AEAD
+	stream [encryptedStreamSize]byte
+	seq    uint64
+}
+
+// newRecordEncryptor 从密钥提供者创建加密器
+func newRecordEncryptor(provider KeyProvider) (*recordEncryptor, error) {
+	key, err := provider()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load encryption key: %w", err)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	e := &recordEncryptor{aead: aead}
+	if err := e.newStream(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// newStream 开始新的写入流（每次打开文件时调用），序号从 1 重新计数
+func (e *recordEncryptor) newStream() error {
+	if _, err := rand.Read(e.stream[:]); err != nil {
+		return err
+	}
+	e.seq = 0
+	return nil
+}
+
+// seal 将明文加密为一条带长度前缀的记录并追加到 dst
+func (e *recordEncryptor) seal(dst, plaintext []byte) ([]byte, error) {
+	nonceSize := e.aead.NonceSize()
+	recordSize := encryptedAADSize + nonceSize + len(plaintext) + e.aead.Overhead()
+	if recordSize > maxEncryptedRecordSize {
+		return dst, ErrEncryptedRecordSize
+	}
+
+	start := len(dst)
+	dst = binary.BigEndian.AppendUint32(dst, uint32(recordSize))
+	aadStart := len(dst)
+	dst = append(dst, e.stream[:]...)
+	dst = binary.BigEndian.AppendUint64(dst, e.seq+1)
+	nonceStart := len(dst)
+	dst = append(dst, make([]byte, nonceSize)...)
+	if _, err := rand.Read(dst[nonceStart:]); err != nil {
+		return dst[:start], err
+	}
+	dst = e.aead.Seal(dst, dst[nonceStart:], plaintext, dst[aadStart:nonceStart])
+	e.seq++
+	return dst, nil
+}
+
+// DecryptReader 解密读取器，将加密日志文件还原为明文流
+// 校验每个写入流的序号从 1 开始连续递增，发现缺失或重排的记录时返回 ErrEncryptedRecordSequence
+type DecryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	streams map[[encryptedStreamSize]byte]uint64 // 各写入流最后一条记录的序号
+	record  []byte
+	pending []byte
+}
+
+// NewDecryptReader 创建解密读取器
+func NewDecryptReader(r io.Reader, key []byte) (*DecryptReader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &DecryptReader{
+		r:       bufio.NewReader(r),
+		aead:    aead,
+		streams: make(map[[encryptedStreamSize]byte]uint64),
+	}, nil
+}
+
+// Read 实现 io.Reader 接口
+func (d *DecryptReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// next 读取并解密下一条记录
+func (d *DecryptReader) next() error {
+	var header [encryptedLengthSize]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("truncated record header: %w", err)
+		}
+		return err
+	}
+
+	size := int(binary.BigEndian.Uint32(header[:]))
+	nonceSize := d.aead.NonceSize()
+	if size < encryptedAADSize+nonceSize+d.aead.Overhead() || size > maxEncryptedRecordSize {
+		return ErrEncryptedRecordSize
+	}
+
+	if cap(d.record) < size {
+		d.record = make([]byte, size)
+	}
+	record := d.record[:size]
+	if _, err := io.ReadFull(d.r, record); err != nil {
+		return fmt.Errorf("truncated record: %w", err)
+	}
+
+	aad, nonce, sealed := record[:encryptedAADSize], record[encryptedAADSize:encryptedAADSize+nonceSize], record[encryptedAADSize+nonceSize:]
+	plaintext, err := d.aead.Open(sealed[:0], nonce, sealed, aad)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt record: %w", err)
+	}
+
+	// 认证通过后再校验序号，流标识与序号无法在不破坏标签的情况下被改写
+	stream := [encryptedStreamSize]byte(aad[:encryptedStreamSize])
+	seq := binary.BigEndian.Uint64(aad[encryptedStreamSize:])
+	if last := d.streams[stream]; seq != last+1 {
+		return fmt.Errorf("%w: stream %x record %d after %d", ErrEncryptedRecordSequence, stream, seq, last)
+	}
+	d.streams[stream] = seq
+	d.pending = plaintext
+	return nil
+}
+
+// DecryptLogFile 解密日志文件并写入 w
+func DecryptLogFile(path string, key []byte, w io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := NewDecryptReader(file, key)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// ============================================================================
+// FileLogWriter 集成
+// ============================================================================
+
+// WithFileEncryption 启用 AES-GCM 加密写入，每次写入加密为一条带长度前缀的记录
+// 密钥在首次打开文件时获取，获取失败时写入返回错误；加密文件可用 NewDecryptReader 读取
+func WithFileEncryption(provider KeyProvider) FileWriterOption {
+	return func(w *FileLogWriter) {
+		w.keyProvider = provider
+	}
+}
+
+// writeEncrypted 加密并写入缓冲区，返回写入的明文字节数（调用方持有锁）
+func (w *FileLogWriter) writeEncrypted(p []byte) (int, error) {
+	var err error
+	w.sealBuf, err = w.encryptor.seal(w.sealBuf[:0], p)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := w.writeRecord(w.sealBuf); err != nil {
+		return 0, err
+	}
+	if w.index != nil {
+		w.index.advance(len(w.sealBuf))
+	}
+	if cap(w.sealBuf) > maxPooledBufferSize {
+		w.sealBuf = nil
+	}
+	return len(p), nil
+}
@@ -0,0 +1,135 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\encrypt_test.go
+ * @Description: AES-GCM 加密写入与解密读取测试（往返、错误密钥、记录删除与重排）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestFileEncryptionRoundTrip 加密写入的文件（含重新打开后追加的部分）可完整解密，且不含明文
+func TestFileEncryptionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var want strings.Builder
+	for run := 0; run < 2; run++ {
+		w := NewFileWriter(WithFileWriterPath(path), WithFileEncryption(StaticKey(testEncryptionKey)))
+		for _, line := range []string{"secret line one\n", "secret line two\n"} {
+			if _, err := w.Write([]byte(line)); err != nil {
+				t.Fatal(err)
+			}
+			want.WriteString(line)
+		}
+		if _, err := w.(IVectorWriter).WriteVectors([][]byte{[]byte("a\n"), []byte("b\n")}); err != nil {
+			t.Fatal(err)
+		}
+		want.WriteString("a\nb\n")
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("secret")) {
+		t.Fatal("encrypted file contains plaintext")
+	}
+	var out bytes.Buffer
+	if err := DecryptLogFile(path, testEncryptionKey, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != want.String() {
+		t.Errorf("decrypted %q, want %q", out.String(), want.String())
+	}
+
+	wrong := append([]byte(nil), testEncryptionKey...)
+	wrong[0] ^= 1
+	if err := DecryptLogFile(path, wrong, io.Discard); err == nil {
+		t.Error("decrypted with the wrong key")
+	}
+}
+
+// sealRecords 加密多条记录，返回各条记录的字节
+func sealRecords(t *testing.T, e *recordEncryptor, lines ...string) [][]byte {
+	t.Helper()
+	records := make([][]byte, 0, len(lines))
+	for _, line := range lines {
+		record, err := e.seal(nil, []byte(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// decryptAll 解密拼接后的记录
+func decryptAll(records ...[]byte) (string, error) {
+	r, err := NewDecryptReader(bytes.NewReader(bytes.Join(records, nil)), testEncryptionKey)
+	if err != nil {
+		return "", err
+	}
+	out, err := io.ReadAll(r)
+	return string(out), err
+}
+
+// TestDecryptRecordSequence 记录被删除、重排、改写序号或密文时解密失败
+func TestDecryptRecordSequence(t *testing.T) {
+	e, err := newRecordEncryptor(StaticKey(testEncryptionKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := sealRecords(t, e, "one\n", "two\n", "three\n")
+
+	if got, err := decryptAll(r...); err != nil || got != "one\ntwo\nthree\n" {
+		t.Fatalf("intact = %q, %v", got, err)
+	}
+	for name, records := range map[string][][]byte{
+		"deleted":   {r[0], r[2]},
+		"reordered": {r[0], r[2], r[1]},
+		"replayed":  {r[0], r[1], r[1], r[2]},
+		"head cut":  {r[1], r[2]},
+	} {
+		if _, err := decryptAll(records...); !errors.Is(err, ErrEncryptedRecordSequence) {
+			t.Errorf("%s: err = %v, want ErrEncryptedRecordSequence", name, err)
+		}
+	}
+
+	// 改写序号（把第三条伪装成第二条）或密文都会使认证失败
+	forged := append([]byte(nil), r[2]...)
+	binary.BigEndian.PutUint64(forged[encryptedLengthSize+encryptedStreamSize:], 2)
+	if _, err := decryptAll(r[0], forged); err == nil || errors.Is(err, ErrEncryptedRecordSequence) {
+		t.Errorf("forged sequence: err = %v, want authentication failure", err)
+	}
+	flipped := append([]byte(nil), r[1]...)
+	flipped[len(flipped)-1] ^= 1
+	if _, err := decryptAll(r[0], flipped); err == nil {
+		t.Error("tampered ciphertext decrypted")
+	}
+
+	// 新的写入流（重新打开文件或另一个进程）序号重新从 1 开始
+	if err := e.newStream(); err != nil {
+		t.Fatal(err)
+	}
+	next := sealRecords(t, e, "four\n")
+	if got, err := decryptAll(append(r, next...)...); err != nil || got != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("two streams = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,127 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\errorscope.go
+ * @Description: 首错误捕获范围：范围内只写出第一条错误日志，其余计数，结束时输出汇总
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// ErrorScope 首错误捕获范围（适用于重试循环等会反复记录相同失败的场景）
+// 范围内第一条 captureLevel 及以上级别的日志正常写出，之后的同类日志只计数不写出（FATAL 除外），
+// Close 时若有被抑制的日志则写出一行汇总
+type ErrorScope struct {
+	logger       *Logger
+	name         string
+	start        time.Time
+	captureLevel LogLevel
+
+	mu         sync.Mutex
+	errors     int
+	suppressed int
+	closed     bool
+}
+
+// ErrorScopeOption 首错误捕获范围配置选项
+type ErrorScopeOption func(*ErrorScope)
+
+// WithErrorScopeLevel 设置捕获的最低级别（默认 ERROR）
+func WithErrorScopeLevel(level LogLevel) ErrorScopeOption {
+	return func(s *ErrorScope) {
+		s.captureLevel = level
+	}
+}
+
+// BeginErrorScope 开始一个首错误捕获范围，通过 scope.Logger() 获取绑定该范围的子 Logger：
+//
+//	scope := log.BeginErrorScope("sync-orders")
+//	defer scope.Close()
+//	for attempt := 0; attempt < 100; attempt++ {
+//		if err := sync(); err != nil {
+//			scope.Logger().ErrorKV("sync failed", "attempt", attempt, "error", err)
+//		}
+//	}
+func (l *Logger) BeginErrorScope(name string, opts ...ErrorScopeOption) *ErrorScope {
+	s := &ErrorScope{
+		name:         name,
+		start:        time.Now(),
+		captureLevel: ERROR,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	child := l.Clone().(*Logger)
+	child.errScope = s
+	s.logger = child
+	return s
+}
+
+// Logger 返回绑定该范围的 Logger
+func (s *ErrorScope) Logger() *Logger {
+	return s.logger
+}
+
+// suppress 记录一条日志，返回 true 时由调用方丢弃
+func (s *ErrorScope) suppress(level LogLevel) bool {
+	if level < s.captureLevel || level >= FATAL {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.errors++
+	if s.errors == 1 {
+		return false
+	}
+	s.suppressed++
+	return true
+}
+
+// Close 结束范围：有被抑制的日志时写出汇总行（scope、errors、suppressed、duration），返回被抑制的条数
+// 结束后该 Logger 的日志直接写出
+func (s *ErrorScope) Close() int {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return 0
+	}
+	s.closed = true
+	errors, suppressed := s.errors, s.suppressed
+	s.mu.Unlock()
+
+	if suppressed > 0 {
+		s.logger.writeNotice(s.captureLevel, []byte("error scope closed, repeated errors suppressed"), []any{
+			"scope", s.name,
+			"errors", errors,
+			"suppressed", suppressed,
+			"duration", time.Since(s.start),
+		})
+	}
+	return suppressed
+}
+
+// Errors 返回范围内记录的错误条数（含第一条）
+func (s *ErrorScope) Errors() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.errors
+}
+
+// Suppressed 返回被抑制的条数
+func (s *ErrorScope) Suppressed() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed
+}
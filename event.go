@@ -0,0 +1,146 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\event.go
+ * @Description: 业务/埋点事件：带 kind=event 标记的独立条目类型，支持校验钩子与按事件名路由
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 事件条目标记
+const (
+	EventKindKey = "kind"  // 条目类型字段名
+	EventKind    = "event" // 事件条目的类型取值
+)
+
+// EventValidator 事件校验函数（如按事件名校验属性 schema），返回错误时丢弃该事件
+type EventValidator func(name string, props map[string]any) error
+
+// eventRoute 事件路由：匹配 pattern 的事件写入 target
+type eventRoute struct {
+	pattern string
+	target  ILogger
+}
+
+// eventRegistry 事件校验器与路由（Logger 及其派生 Logger 共享）
+type eventRegistry struct {
+	mu         sync.RWMutex
+	validators []EventValidator
+	routes     []eventRoute
+}
+
+// ensureEvents 获取事件注册表，不存在时创建
+func (l *Logger) ensureEvents() *eventRegistry {
+	if l.events == nil {
+		l.events = &eventRegistry{}
+	}
+	return l.events
+}
+
+// WithEventValidator 追加事件校验器，校验失败的事件被丢弃并上报内部错误
+func (l *Logger) WithEventValidator(validator EventValidator) *Logger {
+	if validator == nil {
+		return l
+	}
+	events := l.ensureEvents()
+	events.mu.Lock()
+	events.validators = append(events.validators, validator)
+	events.mu.Unlock()
+	return l
+}
+
+// WithEventRoute 将匹配 pattern 的事件写入 target（如投递到分析 Topic 的 Kafka 适配器），不再写入自身输出
+// pattern 为事件名，"order.*" 匹配 order. 开头的事件，"*" 匹配所有事件；多条路由匹配时均写入
+func (l *Logger) WithEventRoute(pattern string, target ILogger) *Logger {
+	if pattern == "" || target == nil {
+		return l
+	}
+	events := l.ensureEvents()
+	events.mu.Lock()
+	events.routes = append(events.routes, eventRoute{pattern: pattern, target: target})
+	events.mu.Unlock()
+	return l
+}
+
+// Event 记录业务/埋点事件：以 name 为消息、props 为字段输出一条 INFO 日志，并附带 kind=event
+// 事件先经过校验器，再按路由写入对应目标；未匹配任何路由时写入自身输出
+func (l *Logger) Event(name string, props map[string]any) {
+	if l.level > INFO {
+		return
+	}
+
+	events := l.events
+	if events == nil {
+		l.logWithFields(INFO, name, props, EventKindKey, EventKind)
+		return
+	}
+
+	events.mu.RLock()
+	validators, routes := events.validators, events.routes
+	events.mu.RUnlock()
+
+	for _, validate := range validators {
+		if err := validate(name, props); err != nil {
+			reportInternalError("event", fmt.Errorf("event %q rejected: %w", name, err))
+			return
+		}
+	}
+
+	var fields map[string]any
+	for _, route := range routes {
+		if !matchEvent(route.pattern, name) {
+			continue
+		}
+		if fields == nil {
+			fields = l.eventFields(props)
+		}
+		route.target.LogWithFields(INFO, name, fields)
+	}
+	if fields == nil {
+		l.logWithFields(INFO, name, props, EventKindKey, EventKind)
+	}
+}
+
+// eventFields 合并静态字段、事件属性与类型标记，供路由目标使用
+func (l *Logger) eventFields(props map[string]any) map[string]any {
+	fields := make(map[string]any, len(l.staticKV)/2+len(props)+1)
+	l.collectKV(fields, l.staticKV)
+	l.collectLevelFields(fields, INFO)
+	for k, v := range gateFields(INFO, props) {
+		if resolved, ok := l.resolveField(k, v); ok {
+			fields[k] = resolved
+		}
+	}
+	fields[EventKindKey] = EventKind
+	return fields
+}
+
+// matchEvent 事件名匹配（"*" 匹配所有，"a.*" 匹配 a. 开头的事件）
+func matchEvent(pattern, name string) bool {
+	if pattern == "*" || pattern == name {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(name, prefix)
+	}
+	return false
+}
+
+// AddEventValidator 为根 Logger 及其派生的租户、命名、包 Logger 追加事件校验器
+func (m *LoggerManager) AddEventValidator(validator EventValidator) {
+	m.root.WithEventValidator(validator)
+}
+
+// RouteEvents 将根 Logger 及其派生 Logger 中匹配 pattern 的事件写入 target
+func (m *LoggerManager) RouteEvents(pattern string, target ILogger) {
+	m.root.WithEventRoute(pattern, target)
+}
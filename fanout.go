@@ -0,0 +1,42 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\fanout.go
+ * @Description: 将 Logger 的日志同时转发给适配器（zap、zerolog 等第三方后端）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+// adapterHook 将日志条目转发给适配器的钩子
+type adapterHook struct {
+	adapter IAdapter
+}
+
+// Fire 实现 IHook 接口（适配器自身的级别过滤仍然生效）
+func (h *adapterHook) Fire(entry *LogEntry) error {
+	if !h.adapter.IsLevelEnabled(entry.Level) {
+		return nil
+	}
+	h.adapter.LogWithFields(entry.Level, entry.Message, entry.Fields)
+	return nil
+}
+
+// Levels 实现 IHook 接口
+func (h *adapterHook) Levels() []LogLevel {
+	return nil
+}
+
+// WithAdapters 将日志同时转发给适配器（以钩子形式追加，字段为经过中间件与字段策略处理后的结果）
+// 适配器的生命周期由调用方管理，可配合 CloseOnShutdown 在退出时刷新并关闭
+func (l *Logger) WithAdapters(adapters ...IAdapter) *Logger {
+	hooks := append([]IHook(nil), l.hooks...)
+	for _, adapter := range adapters {
+		if adapter != nil {
+			hooks = append(hooks, &adapterHook{adapter: adapter})
+		}
+	}
+	return l.WithHooks(hooks)
+}
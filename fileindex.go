@@ -0,0 +1,187 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\fileindex.go
+ * @Description: 文件输出的偏移量旁路索引（按时间桶与级别记录首条日志的字节偏移）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// FileIndexSuffix 索引文件后缀（索引文件位于日志文件旁：app.log.idx）
+	FileIndexSuffix = ".idx"
+
+	// fileIndexAnyLevel 索引文件中未知级别的占位符
+	fileIndexAnyLevel = "-"
+)
+
+// FileIndexEntry 索引条目：某时间桶内（某级别）首条日志在文件中的字节偏移
+type FileIndexEntry struct {
+	Time    time.Time // 时间桶起点
+	Level   LogLevel  // 日志级别（Leveled 为 false 时无意义）
+	Leveled bool      // 是否记录了级别（通过 WriteLevel 写入时为 true）
+	Offset  int64     // 字节偏移
+}
+
+// FileIndex 文件索引
+type FileIndex []FileIndexEntry
+
+// fileIndex 索引写入状态（由 FileLogWriter 的锁保护）
+type fileIndex struct {
+	bucket  time.Duration
+	file    *os.File
+	offset  int64 // 日志文件当前写入偏移（含缓冲中未落盘的部分）
+	current int64 // 当前时间桶（Unix 秒）
+	seen    map[LogLevel]bool
+	seenAny bool
+	line    []byte
+}
+
+// WithFileIndex 为文件输出开启偏移量索引，bucket 为时间桶粒度（<= 0 时为 1 分钟）
+// 每个时间桶内首次出现某级别的日志时记录其偏移，便于在大文件中按时间与级别快速定位
+// 级别仅在通过 WriteLevel 写入时可知（如 Logger.WithOutputs），直接 Write 的日志记录为未知级别
+func WithFileIndex(bucket time.Duration) FileWriterOption {
+	return func(w *FileLogWriter) {
+		if bucket <= 0 {
+			bucket = time.Minute
+		}
+		w.index = &fileIndex{bucket: bucket, seen: make(map[LogLevel]bool)}
+	}
+}
+
+// open 以日志文件当前大小为起始偏移打开索引文件
+func (x *fileIndex) open(logPath string, logFile *os.File, perm os.FileMode) error {
+	info, err := logFile.Stat()
+	if err != nil {
+		return err
+	}
+	x.offset = info.Size()
+
+	if x.file == nil {
+		f, err := os.OpenFile(logPath+FileIndexSuffix, os.O_WRONLY|os.O_CREATE|os.O_APPEND, perm)
+		if err != nil {
+			return fmt.Errorf("failed to open index file: %w", err)
+		}
+		x.file = f
+	}
+	return nil
+}
+
+// mark 在写入前记录偏移（同一时间桶内每个级别只记录一次）
+func (x *fileIndex) mark(now time.Time, level LogLevel, leveled bool) {
+	bucket := now.Truncate(x.bucket).Unix()
+	if bucket != x.current {
+		x.current = bucket
+		clear(x.seen)
+		x.seenAny = false
+	}
+
+	if leveled {
+		if x.seen[level] {
+			return
+		}
+		x.seen[level] = true
+	} else {
+		if x.seenAny {
+			return
+		}
+		x.seenAny = true
+	}
+
+	x.line = strconv.AppendInt(x.line[:0], bucket, 10)
+	x.line = append(x.line, ' ')
+	if leveled {
+		x.line = append(x.line, level.String()...)
+	} else {
+		x.line = append(x.line, fileIndexAnyLevel...)
+	}
+	x.line = append(x.line, ' ')
+	x.line = strconv.AppendInt(x.line, x.offset, 10)
+	x.line = append(x.line, '\n')
+	if _, err := x.file.Write(x.line); err != nil {
+		reportInternalError("file_index", err)
+	}
+}
+
+// advance 累加已写入的字节数
+func (x *fileIndex) advance(n int) {
+	x.offset += int64(n)
+}
+
+// close 关闭索引文件
+func (x *fileIndex) close() error {
+	if x.file == nil {
+		return nil
+	}
+	err := x.file.Close()
+	x.file = nil
+	return err
+}
+
+// ReadFileIndex 读取日志文件的索引（logPath 为日志文件路径）
+func ReadFileIndex(logPath string) (FileIndex, error) {
+	f, err := os.Open(logPath + FileIndexSuffix)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var index FileIndex
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		sec, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		offset, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		entry := FileIndexEntry{Time: time.Unix(sec, 0), Offset: offset}
+		if fields[1] != fileIndexAnyLevel {
+			level, err := ParseLevel(fields[1])
+			if err != nil {
+				continue
+			}
+			entry.Level, entry.Leveled = level, true
+		}
+		index = append(index, entry)
+	}
+	return index, scanner.Err()
+}
+
+// Seek 返回 since 所在时间桶起首条不低于 minLevel 的日志偏移，没有匹配时返回 -1
+// 未记录级别的条目视为可能包含任意级别
+func (idx FileIndex) Seek(since time.Time, minLevel LogLevel) int64 {
+	// since 所在时间桶为不晚于 since 的最后一个桶
+	var start time.Time
+	for _, e := range idx {
+		if !e.Time.After(since) && e.Time.After(start) {
+			start = e.Time
+		}
+	}
+
+	for _, e := range idx {
+		if e.Time.Before(start) || (e.Leveled && e.Level < minLevel) {
+			continue
+		}
+		return e.Offset
+	}
+	return -1
+}
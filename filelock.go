@@ -0,0 +1,54 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filelock.go
+ * @Description: 多进程共享日志文件：每条记录一次 O_APPEND 写入，可选 flock 咨询锁，避免行交错
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+// FileShareMode 日志文件的多进程共享方式
+type FileShareMode int
+
+const (
+	// FileShareNone 独占写入（默认）：经缓冲区批量写出，多个进程写同一文件时行可能被截断交错
+	FileShareNone FileShareMode = iota
+	// FileShareAppend 每条记录以一次 O_APPEND 系统调用写出（不经缓冲），本地文件系统上记录之间不会交错
+	FileShareAppend
+	// FileShareLock 在 FileShareAppend 基础上每次写入前获取 flock 排他锁（Unix），
+	// 可与同样遵守 flock 的进程（如 logrotate 的 copytruncate 脚本）协作；其他平台等同 FileShareAppend
+	FileShareLock
+)
+
+// WithFileShareMode 设置多进程共享方式，适用于多个进程（或 fork 出的子进程）追加写同一个日志文件
+// 共享模式下不经过缓冲区，每条日志一次系统调用；偏移量索引（WithFileIndex）只记录本进程写入的位置，不建议同时开启
+func WithFileShareMode(mode FileShareMode) FileWriterOption {
+	return func(w *FileLogWriter) {
+		w.shareMode = mode
+	}
+}
+
+// writeRecord 写出一条完整记录：共享模式下直接写入文件，否则写入缓冲区
+func (w *FileLogWriter) writeRecord(p []byte) (int, error) {
+	if w.shareMode == FileShareNone {
+		return w.buffer.Write(p)
+	}
+	if w.shareMode == FileShareLock {
+		if err := lockFile(w.file); err != nil {
+			return 0, err
+		}
+		defer unlockFile(w.file)
+	}
+	return w.file.Write(p)
+}
+
+// writerFunc 函数式 io.Writer
+type writerFunc func(p []byte) (int, error)
+
+// Write 实现 io.Writer 接口
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
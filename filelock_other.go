@@ -0,0 +1,25 @@
+//go:build !unix
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filelock_other.go
+ * @Description: 不支持 flock 的平台（仅依赖 O_APPEND 追加写入）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "os"
+
+// lockFile 当前平台不加锁
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile 当前平台不加锁
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filelock_test.go
+ * @Description: 多进程共享日志文件测试（多个独立文件句柄并发追加时记录不交错）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// shareRecord 第 writer 个输出器写出的第 seq 条记录（长度不一，跨越多个页面时更容易暴露交错）
+func shareRecord(writer, seq int) string {
+	return fmt.Sprintf("w%d-%d %s\n", writer, seq, strings.Repeat(string(rune('a'+writer)), 100+seq*37%9000))
+}
+
+// TestFileShareModeConcurrentWriters 每个输出器各自打开文件（模拟多个进程），并发写入后每行完整且不经缓冲立即可见
+func TestFileShareModeConcurrentWriters(t *testing.T) {
+	const writers, records = 4, 200
+	tests := []struct {
+		name    string
+		mode    FileShareMode
+		vectors bool
+	}{
+		{"append write", FileShareAppend, false},
+		{"append vectors", FileShareAppend, true},
+		{"lock write", FileShareLock, false},
+		{"lock vectors", FileShareLock, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "shared.log")
+			ws := make([]*FileLogWriter, writers)
+			for i := range ws {
+				ws[i] = NewFileWriter(WithFileWriterPath(path), WithFileShareMode(tt.mode)).(*FileLogWriter)
+				defer ws[i].Close()
+			}
+
+			var wg sync.WaitGroup
+			for i, w := range ws {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for seq := 0; seq < records; seq++ {
+						record := shareRecord(i, seq)
+						var err error
+						if tt.vectors {
+							// 一条记录拆成多个缓冲区，共享模式下合并为一次写入
+							half := len(record) / 2
+							_, err = w.WriteVectors([][]byte{[]byte(record[:half]), []byte(record[half:])})
+						} else {
+							_, err = w.Write([]byte(record))
+						}
+						if err != nil {
+							t.Errorf("writer %d: %v", i, err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// 未关闭输出器即读取：共享模式不经缓冲，所有记录应已落到文件
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+			if len(lines) != writers*records {
+				t.Fatalf("got %d lines, want %d", len(lines), writers*records)
+			}
+			next := make([]int, writers)
+			for n, line := range lines {
+				var writer, seq int
+				if _, err := fmt.Sscanf(line, "w%d-%d ", &writer, &seq); err != nil || writer >= writers {
+					t.Fatalf("line %d is corrupted: %.60q", n, line)
+				}
+				if line+"\n" != shareRecord(writer, seq) {
+					t.Fatalf("line %d interleaved: %.60q", n, line)
+				}
+				if seq != next[writer] {
+					t.Fatalf("writer %d: got record %d, want %d", writer, seq, next[writer])
+				}
+				next[writer]++
+			}
+		})
+	}
+}
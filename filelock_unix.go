@@ -0,0 +1,54 @@
+//go:build unix
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filelock_unix.go
+ * @Description: 基于 flock 的文件咨询锁
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// lockFile 获取文件排他锁（阻塞等待）
+func lockFile(f *os.File) error {
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var lockErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		for {
+			lockErr = unix.Flock(int(fd), unix.LOCK_EX)
+			if lockErr != unix.EINTR {
+				return
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	return lockErr
+}
+
+// unlockFile 释放文件锁
+func unlockFile(f *os.File) error {
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var unlockErr error
+	if err := rawConn.Control(func(fd uintptr) {
+		unlockErr = unix.Flock(int(fd), unix.LOCK_UN)
+	}); err != nil {
+		return err
+	}
+	return unlockErr
+}
@@ -0,0 +1,86 @@
+//go:build unix
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filelock_unix_test.go
+ * @Description: flock 咨询锁竞争测试（FileShareLock 等待其他持锁者释放，FileShareAppend 不受影响）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFileShareLockContention 其他文件句柄（如另一个进程或 logrotate 脚本）持有 flock 时，
+// FileShareLock 的写入阻塞到锁释放，FileShareAppend 不参与加锁直接写入
+func TestFileShareLockContention(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      FileShareMode
+		wantBlock bool
+	}{
+		{"lock waits for holder", FileShareLock, true},
+		{"append ignores lock", FileShareAppend, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "shared.log")
+			holder, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer holder.Close()
+			if err := lockFile(holder); err != nil {
+				t.Fatalf("lockFile: %v", err)
+			}
+
+			w := NewFileWriter(WithFileWriterPath(path), WithFileShareMode(tt.mode))
+			defer w.Close()
+			done := make(chan error, 1)
+			go func() {
+				_, err := w.Write([]byte("child\n"))
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				if tt.wantBlock {
+					t.Fatalf("write finished while another handle held the lock (err=%v)", err)
+				}
+				if err != nil {
+					t.Fatalf("Write: %v", err)
+				}
+			case <-time.After(200 * time.Millisecond):
+				if !tt.wantBlock {
+					t.Fatal("write blocked although the writer does not take the lock")
+				}
+				// 持锁者写完自己的记录后释放，等待中的写入随后完成
+				holder.WriteString("holder\n")
+				if err := unlockFile(holder); err != nil {
+					t.Fatalf("unlockFile: %v", err)
+				}
+				select {
+				case err := <-done:
+					if err != nil {
+						t.Fatalf("Write: %v", err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatal("write still blocked after the lock was released")
+				}
+				data, _ := os.ReadFile(path)
+				if string(data) != "holder\nchild\n" {
+					t.Errorf("file = %q, want holder record before child record", data)
+				}
+			}
+		})
+	}
+}
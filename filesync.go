@@ -0,0 +1,114 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\filesync.go
+ * @Description: 文件输出器落盘策略（每次写入 fsync 或按间隔 fsync），用于需要在崩溃后保留的审计类日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fileSync 文件落盘策略
+type fileSync struct {
+	everyWrite bool          // 每次写入后刷新缓冲并 fsync
+	interval   time.Duration // 后台 fsync 间隔（<= 0 表示关闭）
+	dirty      atomic.Bool   // 上次 fsync 后是否有新写入
+	stop       chan struct{}
+	stopOnce   sync.Once
+}
+
+// WithFileSyncEveryWrite 每次写入后刷新缓冲并 fsync（最强持久性，吞吐量显著下降）
+func WithFileSyncEveryWrite(enabled bool) FileWriterOption {
+	return func(w *FileLogWriter) {
+		w.durability.everyWrite = enabled
+	}
+}
+
+// WithFileSyncInterval 后台按间隔刷新缓冲并 fsync（崩溃时最多丢失一个间隔内的日志）
+func WithFileSyncInterval(interval time.Duration) FileWriterOption {
+	return func(w *FileLogWriter) {
+		w.durability.interval = interval
+	}
+}
+
+// WithRotateSyncEveryWrite 同 WithFileSyncEveryWrite，用于轮转文件输出器
+func WithRotateSyncEveryWrite(enabled bool) RotateWriterOption {
+	return func(w *RotateLogWriter) {
+		w.durability.everyWrite = enabled
+	}
+}
+
+// WithRotateSyncInterval 同 WithFileSyncInterval，用于轮转文件输出器
+func WithRotateSyncInterval(interval time.Duration) RotateWriterOption {
+	return func(w *RotateLogWriter) {
+		w.durability.interval = interval
+	}
+}
+
+// start 启动后台 fsync 协程（未设置间隔时不启动）
+func (s *fileSync) start(flush func() error) {
+	if s.interval <= 0 {
+		return
+	}
+	s.stop = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if s.dirty.Swap(false) {
+					if err := flush(); err != nil {
+						reportInternalError("writer", err)
+					}
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// close 停止后台 fsync 协程
+func (s *fileSync) close() {
+	if s.stop != nil {
+		s.stopOnce.Do(func() { close(s.stop) })
+	}
+}
+
+// written 记录一次写入，返回是否需要立即 fsync
+func (s *fileSync) written() bool {
+	if s.everyWrite {
+		return true
+	}
+	if s.interval > 0 {
+		s.dirty.Store(true)
+	}
+	return false
+}
+
+// syncFile 刷新缓冲并 fsync，记录 fsync 耗时
+func syncFile(stats *writerStats, buf *bufio.Writer, f *os.File) error {
+	if buf != nil {
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+	}
+	if f == nil {
+		return nil
+	}
+	start := time.Now()
+	err := f.Sync()
+	stats.addSync(time.Since(start))
+	return err
+}
@@ -0,0 +1,87 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\fingerprint.go
+ * @Description: 日志消息指纹（基于模板，忽略数字、ID、引号内容等可变部分），用于聚合同一日志语句
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/hex"
+	"hash/fnv"
+	"strings"
+)
+
+// FingerprintKey 指纹字段名
+const FingerprintKey = "fingerprint"
+
+// Fingerprint 计算消息模板指纹（16 位十六进制）
+// 传入格式串时直接按模板聚合；传入已格式化的消息时忽略其中的数字、ID、引号内容等可变部分，
+// 因此 "order 1 failed" 与 "order 2 failed" 的指纹相同
+func Fingerprint(template string) string {
+	h := fnv.New64a()
+	h.Write(normalizeMessage(make([]byte, 0, len(template)), template))
+	var sum [8]byte
+	return hex.EncodeToString(h.Sum(sum[:0]))
+}
+
+// WithFingerprint 设置是否为每条日志附加 fingerprint 字段（格式化日志按格式串计算）
+// 开启后日志经过结构化管道输出
+func (l *Logger) WithFingerprint(enable bool) *Logger {
+	l.fingerprint = enable
+	return l
+}
+
+// entryFingerprint 获取条目指纹（优先使用已附加的 fingerprint 字段）
+func entryFingerprint(entry *LogEntry) string {
+	if fp, ok := entry.Fields[FingerprintKey].(string); ok {
+		return fp
+	}
+	return Fingerprint(entry.Message)
+}
+
+// normalizeMessage 将消息中的可变部分替换为占位符：
+// 含数字的单词（数字、十六进制 ID、UUID、IP 等）替换为 "#"，引号内容替换为 "*"
+func normalizeMessage(dst []byte, msg string) []byte {
+	for i := 0; i < len(msg); {
+		c := msg[i]
+
+		if c == '"' || c == '\'' || c == '`' {
+			if end := strings.IndexByte(msg[i+1:], c); end >= 0 {
+				dst = append(dst, c, '*', c)
+				i += end + 2
+				continue
+			}
+		}
+
+		if !isWordByte(c) {
+			dst = append(dst, c)
+			i++
+			continue
+		}
+
+		start, hasDigit := i, false
+		for i < len(msg) && isWordByte(msg[i]) {
+			if msg[i] >= '0' && msg[i] <= '9' {
+				hasDigit = true
+			}
+			i++
+		}
+		if hasDigit {
+			dst = append(dst, '#')
+		} else {
+			dst = append(dst, msg[start:i]...)
+		}
+	}
+	return dst
+}
+
+// isWordByte 单词字符（字母、数字、-、_、.、:，使 UUID/IP/版本号等作为整体处理）
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == ':' || c >= 0x80
+}
@@ -0,0 +1,206 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\flatten.go
+ * @Description: 结构体字段展开（深度、键连接符、嵌入结构体、标签优先级与零值省略可配置）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DefaultFlattenDepth 结构体默认展开深度
+const DefaultFlattenDepth = 3
+
+// structFlattener 结构体展开配置
+type structFlattener struct {
+	maxDepth       int    // 最多展开的结构体层数，超出的部分作为整体输出
+	separator      string // 键连接符
+	prefixEmbedded bool   // 嵌入结构体的字段是否以类型名为前缀（默认与 encoding/json 一致直接提升）
+	tag            string // 优先使用的结构体标签名，为空时使用字段名
+	omitZero       bool   // 是否省略零值字段
+}
+
+// FlattenOption 结构体展开选项
+type FlattenOption func(*structFlattener)
+
+// WithFlattenDepth 设置展开深度（默认 3），超出深度的结构体作为整体输出
+func WithFlattenDepth(depth int) FlattenOption {
+	return func(f *structFlattener) {
+		if depth > 0 {
+			f.maxDepth = depth
+		}
+	}
+}
+
+// WithFlattenSeparator 设置键连接符（默认 "."，如 "user.address.city"；可改为 "_"）
+func WithFlattenSeparator(separator string) FlattenOption {
+	return func(f *structFlattener) {
+		f.separator = separator
+	}
+}
+
+// WithFlattenEmbeddedPrefix 嵌入结构体的字段以类型名为前缀（默认与 encoding/json 一致直接提升到外层）
+func WithFlattenEmbeddedPrefix(prefix bool) FlattenOption {
+	return func(f *structFlattener) {
+		f.prefixEmbedded = prefix
+	}
+}
+
+// WithFlattenTag 设置键名使用的结构体标签（默认 "json"，标签为 "-" 的字段跳过）；传入空串时始终使用字段名
+func WithFlattenTag(tag string) FlattenOption {
+	return func(f *structFlattener) {
+		f.tag = tag
+	}
+}
+
+// WithFlattenOmitZero 省略零值字段（标签中带 omitempty 的字段始终省略零值）
+func WithFlattenOmitZero(omit bool) FlattenOption {
+	return func(f *structFlattener) {
+		f.omitZero = omit
+	}
+}
+
+// WithStructFlatten 将键值对与字段映射中的结构体（及其指针）值展开为多个字段：
+//
+//	log.WithStructFlatten(logger.WithFlattenSeparator("_")).InfoKV("login", "user", u)
+//	// login {user_id: 1, user_name: alice, user_address_city: Paris}
+//
+// 字段的 log 标签（见 LogTagKey）在展开时同样生效；实现了 fmt.Stringer、error、json.Marshaler、encoding.TextMarshaler 的类型以及 time.Time 不展开
+func (l *Logger) WithStructFlatten(opts ...FlattenOption) *Logger {
+	f := &structFlattener{
+		maxDepth:  DefaultFlattenDepth,
+		separator: ".",
+		tag:       "json",
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	l.flattener = f
+	return l
+}
+
+// flattenField 值为可展开的结构体时按配置展开为叶子字段依次交给 fn，返回是否已展开
+func (l *Logger) flattenField(key string, value any, fn func(key string, value any)) bool {
+	rv, ok := flattenable(value)
+	if !ok {
+		return false
+	}
+	l.flattener.walk(key, rv, 1, fn)
+	return true
+}
+
+var (
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+	fieldType         = reflect.TypeOf(Field{})
+	levelFieldType    = reflect.TypeOf(LevelField{})
+	fullValueType     = reflect.TypeOf(FullValue{})
+	fieldSummaryType  = reflect.TypeOf(FieldSummary{})
+)
+
+// flattenable 返回解引用后的结构体值；非结构体、nil 指针或自带输出格式的类型返回 false
+func flattenable(value any) (reflect.Value, bool) {
+	if value == nil {
+		return reflect.Value{}, false
+	}
+	return nestedStruct(reflect.ValueOf(value))
+}
+
+// nestedStruct 同 flattenable，作用于反射值（未导出字段也可判断）
+func nestedStruct(rv reflect.Value) (reflect.Value, bool) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() || selfFormatting(rv.Type()) {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct || selfFormatting(rv.Type()) || selfFormatting(reflect.PointerTo(rv.Type())) {
+		return reflect.Value{}, false
+	}
+	switch rv.Type() {
+	case fieldType, levelFieldType, fullValueType, fieldSummaryType:
+		return reflect.Value{}, false
+	}
+	return rv, true
+}
+
+// selfFormatting 类型是否自带输出格式
+func selfFormatting(t reflect.Type) bool {
+	return t == timeType || t.Implements(stringerType) || t.Implements(errorType) ||
+		t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+// walk 展开结构体 rv，depth 为当前层数（直接提升的嵌入结构体不增加层数）
+func (f *structFlattener) walk(prefix string, rv reflect.Value, depth int, fn func(key string, value any)) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		fv := rv.Field(i)
+		nested, isStruct := nestedStruct(fv)
+		if !sf.IsExported() && !(sf.Anonymous && isStruct) {
+			continue
+		}
+		name, omitEmpty, skip := f.fieldName(sf)
+		tagAction := logTagAction(sf)
+		if skip || tagAction == PolicyDrop || ((omitEmpty || f.omitZero) && fv.IsZero()) {
+			continue
+		}
+
+		embedded := isStruct && sf.Anonymous && !f.prefixEmbedded && name == sf.Name
+		key := prefix
+		if !embedded {
+			key = prefix + f.separator + name
+		}
+		if tagAction == PolicyMask {
+			if fv.CanInterface() {
+				fn(key, maskValue(fv.Interface()))
+			}
+			continue
+		}
+		if isStruct && (embedded || depth < f.maxDepth) {
+			next := depth + 1
+			if embedded {
+				next = depth
+			}
+			f.walk(key, nested, next, fn)
+			continue
+		}
+		if fv.CanInterface() {
+			fn(key, fv.Interface())
+		}
+	}
+}
+
+// fieldName 返回字段的键名、标签是否带 omitempty 以及是否跳过
+func (f *structFlattener) fieldName(sf reflect.StructField) (string, bool, bool) {
+	if f.tag == "" {
+		return sf.Name, false, false
+	}
+	tag, ok := sf.Tag.Lookup(f.tag)
+	if !ok {
+		return sf.Name, false, false
+	}
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	omitEmpty := strings.Contains(","+opts+",", ",omitempty,")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,390 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\fluent.go
+ * @Description: Fluentd / Fluent Bit forward 协议适配器（TCP 上的 MessagePack，Forward 模式批量发送，支持 ack 确认）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultFluentAddress       = "127.0.0.1:24224"
+	DefaultFluentTag           = "app"
+	DefaultFluentBatchSize     = 256
+	DefaultFluentFlushInterval = time.Second
+	DefaultFluentQueueSize     = 8192
+	DefaultFluentTimeout       = 5 * time.Second
+	DefaultFluentRetries       = 3
+)
+
+// ErrFluentClosed 适配器已关闭
+var ErrFluentClosed = errors.New("logger: fluent adapter closed")
+
+// ErrFluentAckMismatch 服务端返回的 ack 与发送的 chunk 不一致
+var ErrFluentAckMismatch = errors.New("logger: fluent ack mismatch")
+
+// fluentRecord 待发送的事件
+type fluentRecord struct {
+	time   time.Time
+	record map[string]any
+}
+
+// FluentAdapter Fluentd / Fluent Bit forward 协议适配器：
+//
+//	fluent := logger.NewFluentAdapter(logger.WithFluentAddress("fluentd:24224"), logger.WithFluentTag("app.web"), logger.WithFluentAck(true))
+//	log := logger.NewLogger().WithAdapters(fluent)
+//	logger.CloseOnShutdown(fluent)
+//
+// 日志写入有界队列后由后台协程按批（Forward 模式）发送，断线时自动重连并重试；
+// 开启 ack 时每批携带 chunk 标识并等待服务端确认，确认前不视为发送成功（at-least-once）
+type FluentAdapter struct {
+	*BaseAdapter
+	network       string
+	address       string
+	tag           string
+	ack           bool
+	timeout       time.Duration
+	tls           *tls.Config
+	batchSize     int
+	flushInterval time.Duration
+	retries       int
+	messageKey    string
+	levelKey      string
+
+	queue     chan fluentRecord
+	flushReq  chan chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+	conn      net.Conn // 仅由发送协程访问
+	reader    *bufio.Reader
+
+	sent    atomic.Int64
+	failed  atomic.Int64
+	dropped atomic.Int64
+}
+
+// FluentOption Fluent 适配器配置选项
+type FluentOption func(*FluentAdapter)
+
+// WithFluentAddress 设置服务端地址（默认 DefaultFluentAddress）
+func WithFluentAddress(address string) FluentOption {
+	return func(a *FluentAdapter) {
+		a.address = address
+	}
+}
+
+// WithFluentNetwork 设置网络类型（默认 tcp，可为 unix）
+func WithFluentNetwork(network string) FluentOption {
+	return func(a *FluentAdapter) {
+		a.network = network
+	}
+}
+
+// WithFluentTag 设置事件 tag（默认 DefaultFluentTag）
+func WithFluentTag(tag string) FluentOption {
+	return func(a *FluentAdapter) {
+		a.tag = tag
+	}
+}
+
+// WithFluentAck 设置是否等待服务端 ack 确认（需服务端开启 require_ack_response）
+func WithFluentAck(enabled bool) FluentOption {
+	return func(a *FluentAdapter) {
+		a.ack = enabled
+	}
+}
+
+// WithFluentTimeout 设置连接、写入与等待 ack 的超时（默认 DefaultFluentTimeout）
+func WithFluentTimeout(timeout time.Duration) FluentOption {
+	return func(a *FluentAdapter) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithFluentTLS 通过 TLS 连接（Fluentd / Fluent Bit 的 in_forward 开启 transport tls 时使用）
+func WithFluentTLS(conf *tls.Config) FluentOption {
+	return func(a *FluentAdapter) {
+		a.tls = conf
+	}
+}
+
+// WithFluentBatch 设置每批最大事件数与刷新间隔
+func WithFluentBatch(size int, interval time.Duration) FluentOption {
+	return func(a *FluentAdapter) {
+		if size > 0 {
+			a.batchSize = size
+		}
+		if interval > 0 {
+			a.flushInterval = interval
+		}
+	}
+}
+
+// WithFluentQueueSize 设置发送队列长度（默认 DefaultFluentQueueSize，满时丢弃并上报溢出事件）
+func WithFluentQueueSize(size int) FluentOption {
+	return func(a *FluentAdapter) {
+		if size > 0 {
+			a.queue = make(chan fluentRecord, size)
+		}
+	}
+}
+
+// WithFluentRetries 设置每批失败重试次数（默认 DefaultFluentRetries）
+func WithFluentRetries(retries int) FluentOption {
+	return func(a *FluentAdapter) {
+		if retries >= 0 {
+			a.retries = retries
+		}
+	}
+}
+
+// WithFluentKeys 设置事件中消息与级别的键名（默认 message、level）
+func WithFluentKeys(messageKey, levelKey string) FluentOption {
+	return func(a *FluentAdapter) {
+		if messageKey != "" {
+			a.messageKey = messageKey
+		}
+		if levelKey != "" {
+			a.levelKey = levelKey
+		}
+	}
+}
+
+// NewFluentAdapter 创建 Fluent forward 适配器并启动发送协程（连接在首次发送时建立）
+func NewFluentAdapter(opts ...FluentOption) *FluentAdapter {
+	a := &FluentAdapter{
+		network:       "tcp",
+		address:       DefaultFluentAddress,
+		tag:           DefaultFluentTag,
+		timeout:       DefaultFluentTimeout,
+		batchSize:     DefaultFluentBatchSize,
+		flushInterval: DefaultFluentFlushInterval,
+		retries:       DefaultFluentRetries,
+		messageKey:    "message",
+		levelKey:      "level",
+		queue:         make(chan fluentRecord, DefaultFluentQueueSize),
+		flushReq:      make(chan chan error),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.BaseAdapter = NewBaseAdapter("fluent", "1.0.0", a.emit)
+	a.wg.Add(1)
+	go a.run()
+	return a
+}
+
+// emit 事件入队（不阻塞，队列满或已关闭时丢弃）
+func (a *FluentAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	record := make(map[string]any, len(fields)+2)
+	for k, v := range fields {
+		record[k] = v
+	}
+	record[a.levelKey] = level.String()
+	record[a.messageKey] = msg
+
+	select {
+	case <-a.done:
+		a.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case a.queue <- fluentRecord{time: time.Now(), record: record}:
+	default:
+		a.dropped.Add(1)
+		reportOverflow(OverflowComponentFluent, OverflowDropped, cap(a.queue))
+	}
+}
+
+// run 后台发送协程
+func (a *FluentAdapter) run() {
+	defer a.wg.Done()
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]fluentRecord, 0, a.batchSize)
+	for {
+		select {
+		case r := <-a.queue:
+			batch = append(batch, r)
+			if len(batch) >= a.batchSize {
+				a.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				a.send(batch)
+				batch = batch[:0]
+			}
+		case reply := <-a.flushReq:
+			batch = a.drain(batch)
+			reply <- a.send(batch)
+			batch = batch[:0]
+		case <-a.done:
+			batch = a.drain(batch)
+			a.send(batch)
+			if a.conn != nil {
+				a.conn.Close()
+				a.conn = nil
+			}
+			return
+		}
+	}
+}
+
+// drain 取出队列中剩余事件，按批发送已满的部分，返回未满的批次
+func (a *FluentAdapter) drain(batch []fluentRecord) []fluentRecord {
+	for {
+		select {
+		case r := <-a.queue:
+			batch = append(batch, r)
+			if len(batch) >= a.batchSize {
+				a.send(batch)
+				batch = batch[:0]
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// send 发送一批事件（失败时重连重试，最终失败计入 failed 并上报内部错误）
+func (a *FluentAdapter) send(batch []fluentRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	var chunk string
+	if a.ack {
+		chunk = newFluentChunkID()
+	}
+	msg := a.encode(batch, chunk)
+
+	var err error
+	for attempt := 0; attempt <= a.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * 100 * time.Millisecond)
+		}
+		if err = a.write(msg, chunk); err == nil {
+			a.sent.Add(int64(len(batch)))
+			a.SetHealthy(true)
+			return nil
+		}
+		if a.conn != nil {
+			a.conn.Close()
+			a.conn = nil
+		}
+	}
+	a.failed.Add(int64(len(batch)))
+	a.SetHealthy(false)
+	err = fmt.Errorf("fluent forward to %s: %w", a.address, err)
+	reportInternalError("fluent", err)
+	return err
+}
+
+// write 写入一条消息并按需等待 ack
+func (a *FluentAdapter) write(msg []byte, chunk string) error {
+	if a.conn == nil {
+		conn, err := dialTLS(a.network, a.address, a.timeout, a.tls)
+		if err != nil {
+			return err
+		}
+		a.conn = conn
+		a.reader = bufio.NewReader(conn)
+	}
+
+	a.conn.SetWriteDeadline(time.Now().Add(a.timeout))
+	if _, err := a.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	a.conn.SetReadDeadline(time.Now().Add(a.timeout))
+	resp, err := msgpackDecode(a.reader)
+	if err != nil {
+		return err
+	}
+	if m, ok := resp.(map[string]any); !ok || m["ack"] != chunk {
+		return ErrFluentAckMismatch
+	}
+	return nil
+}
+
+// encode 编码为 Forward 模式消息：[tag, [[time, record], ...], option]
+func (a *FluentAdapter) encode(batch []fluentRecord, chunk string) []byte {
+	b := make([]byte, 0, 128*len(batch))
+	b = msgpackAppendArrayHeader(b, 3)
+	b = msgpackAppendString(b, a.tag)
+	b = msgpackAppendArrayHeader(b, len(batch))
+	for _, r := range batch {
+		b = msgpackAppendArrayHeader(b, 2)
+		b = msgpackAppendEventTime(b, r.time)
+		b = msgpackAppendMap(b, r.record)
+	}
+	option := map[string]any{"size": len(batch)}
+	if chunk != "" {
+		option["chunk"] = chunk
+	}
+	return msgpackAppendMap(b, option)
+}
+
+// newFluentChunkID 生成 chunk 标识（128 位随机数的 base64）
+func newFluentChunkID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// Flush 立即发送队列中的事件并等待结果
+func (a *FluentAdapter) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case a.flushReq <- reply:
+		return <-reply
+	case <-a.done:
+		return ErrFluentClosed
+	}
+}
+
+// Close 发送剩余事件后关闭连接
+func (a *FluentAdapter) Close() error {
+	a.closeOnce.Do(func() { close(a.done) })
+	a.wg.Wait()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *FluentAdapter) QueueDepth() (depth, capacity int) {
+	return len(a.queue), cap(a.queue)
+}
+
+// Stats 返回发送成功、发送失败与因队列满或关闭丢弃的事件数
+func (a *FluentAdapter) Stats() (sent, failed, dropped int64) {
+	return a.sent.Load(), a.failed.Load(), a.dropped.Load()
+}
+
+var _ IAdapter = (*FluentAdapter)(nil)
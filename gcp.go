@@ -0,0 +1,151 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\gcp.go
+ * @Description: Google Cloud Logging 结构化日志格式化器（Cloud Run / GKE 标准输出采集识别的 severity、trace、sourceLocation 等字段）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cloud Logging 结构化日志特殊字段
+const (
+	GCPTraceKey          = "logging.googleapis.com/trace"
+	GCPSpanIDKey         = "logging.googleapis.com/spanId"
+	GCPTraceSampledKey   = "logging.googleapis.com/trace_sampled"
+	GCPSourceLocationKey = "logging.googleapis.com/sourceLocation"
+	GCPLabelsKey         = "logging.googleapis.com/labels"
+)
+
+// GCPFormatter Cloud Logging 结构化 JSON 格式化器：
+//
+//	log := logger.NewLogger().WithFormatter(logger.NewGCPFormatter(logger.WithGCPProject("my-project")))
+//
+// 级别映射为 severity（TRACE/DEBUG→DEBUG、WARN→WARNING、FATAL→CRITICAL），调用者信息映射为 sourceLocation；
+// 字段中的 trace_id / span_id（可通过 WithGCPTraceKeys 修改）映射为 trace（projects/<项目>/traces/<id>）与 spanId，其余字段保留在顶层
+type GCPFormatter struct {
+	projectID string
+	traceKey  string
+	spanKey   string
+	labels    map[string]string
+}
+
+// GCPFormatterOption Cloud Logging 格式化器配置选项
+type GCPFormatterOption func(*GCPFormatter)
+
+// WithGCPProject 设置项目 ID（默认读取 GOOGLE_CLOUD_PROJECT，为空时 trace 字段输出原始 trace id）
+func WithGCPProject(projectID string) GCPFormatterOption {
+	return func(f *GCPFormatter) {
+		f.projectID = projectID
+	}
+}
+
+// WithGCPTraceKeys 设置 trace id 与 span id 所在的字段名（默认 trace_id、span_id）
+func WithGCPTraceKeys(traceKey, spanKey string) GCPFormatterOption {
+	return func(f *GCPFormatter) {
+		f.traceKey = traceKey
+		f.spanKey = spanKey
+	}
+}
+
+// WithGCPLabels 设置附加到每条日志的 labels
+func WithGCPLabels(labels map[string]string) GCPFormatterOption {
+	return func(f *GCPFormatter) {
+		f.labels = labels
+	}
+}
+
+// NewGCPFormatter 创建 Cloud Logging 格式化器
+func NewGCPFormatter(opts ...GCPFormatterOption) *GCPFormatter {
+	f := &GCPFormatter{
+		projectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		traceKey:  ContextKeyTraceID,
+		spanKey:   "span_id",
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Format 实现 IFormatter 接口
+func (f *GCPFormatter) Format(entry *LogEntry) ([]byte, error) {
+	out := make(map[string]any, len(entry.Fields)+6)
+	for k, v := range entry.Fields {
+		out[k] = v
+	}
+	out["severity"] = GCPSeverity(entry.Level)
+	out["message"] = entry.Message
+	out["time"] = time.Unix(0, entry.Timestamp).UTC().Format(time.RFC3339Nano)
+
+	if traceID, ok := entry.Fields[f.traceKey].(string); ok && traceID != "" {
+		delete(out, f.traceKey)
+		out[GCPTraceKey] = f.tracePath(traceID)
+	}
+	if spanID, ok := entry.Fields[f.spanKey].(string); ok && spanID != "" {
+		delete(out, f.spanKey)
+		out[GCPSpanIDKey] = spanID
+	}
+	if entry.Caller != nil {
+		out[GCPSourceLocationKey] = map[string]string{
+			"file":     entry.Caller.File,
+			"line":     strconv.Itoa(entry.Caller.Line),
+			"function": entry.Caller.Function,
+		}
+	}
+	if len(f.labels) > 0 {
+		out[GCPLabelsKey] = f.labels
+	}
+
+	data, err := marshalJSON(out)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// tracePath 返回 trace 字段的完整资源名
+func (f *GCPFormatter) tracePath(traceID string) string {
+	if f.projectID == "" || strings.HasPrefix(traceID, "projects/") {
+		return traceID
+	}
+	return "projects/" + f.projectID + "/traces/" + traceID
+}
+
+// GetName 实现 IFormatter 接口
+func (f *GCPFormatter) GetName() string {
+	return "gcp"
+}
+
+// GCPSeverity 将日志级别映射为 Cloud Logging severity（扩展级别映射为 INFO）
+func GCPSeverity(level LogLevel) string {
+	switch level {
+	case TRACE, DEBUG:
+		return "DEBUG"
+	case WARN:
+		return "WARNING"
+	case ERROR:
+		return "ERROR"
+	case FATAL:
+		return "CRITICAL"
+	}
+	return "INFO"
+}
+
+// ParseCloudTraceContext 解析 X-Cloud-Trace-Context 请求头（"TRACE_ID/SPAN_ID;o=OPTIONS"），
+// 返回的 span id 为十进制（与请求头一致）
+func ParseCloudTraceContext(header string) (traceID, spanID string, sampled bool) {
+	header, options, _ := strings.Cut(header, ";")
+	traceID, spanID, _ = strings.Cut(header, "/")
+	sampled = strings.TrimSpace(options) == "o=1"
+	return strings.TrimSpace(traceID), strings.TrimSpace(spanID), sampled
+}
@@ -0,0 +1,41 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\gcp_test.go
+ * @Description: Google Cloud Logging 格式化器测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "testing"
+
+// TestGCPSeverity 基础级别一一映射，扩展级别（业务、安全、性能等）不得映射为 CRITICAL
+func TestGCPSeverity(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		want  string
+	}{
+		{TRACE, "DEBUG"},
+		{DEBUG, "DEBUG"},
+		{INFO, "INFO"},
+		{WARN, "WARNING"},
+		{ERROR, "ERROR"},
+		{FATAL, "CRITICAL"},
+		{SYSTEM, "INFO"},
+		{BUSINESS, "INFO"},
+		{TRANSACTION, "INFO"},
+		{SECURITY, "INFO"},
+		{AUDIT, "INFO"},
+		{THREAT, "INFO"},
+		{PERFORMANCE, "INFO"},
+		{METRIC, "INFO"},
+	}
+	for _, tt := range tests {
+		if got := GCPSeverity(tt.level); got != tt.want {
+			t.Errorf("GCPSeverity(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,521 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\global.go
+ * @Description: 全局 Logger 的原子替换与包级日志函数
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// globalPtr 全局 Logger（原子读写，替换时无数据竞争）
+var globalPtr atomic.Pointer[Logger]
+
+func init() {
+	globalPtr.Store(NewLogger())
+}
+
+// globalLogger 获取当前全局 Logger
+func globalLogger() *Logger {
+	return globalPtr.Load()
+}
+
+// ReplaceGlobal 替换全局 Logger（包级日志函数、GetGlobalLogger 等均使用新的 Logger），返回恢复原 Logger 的函数：
+//
+//	restore := logger.ReplaceGlobal(testLogger)
+//	defer restore()
+//
+// l 为 nil 时不替换。已创建的 DefaultManager 仍以原 Logger 为根
+func ReplaceGlobal(l *Logger) (restore func()) {
+	if l == nil {
+		return func() {}
+	}
+	prev := globalPtr.Swap(l)
+	return func() {
+		globalPtr.Store(prev)
+	}
+}
+
+// SetGlobalOutput 设置全局 Logger 的输出（写时复制，见 updateGlobal）
+func SetGlobalOutput(output io.Writer) {
+	updateGlobal(func(l *Logger) {
+		l.WithOutput(output)
+	})
+}
+
+// SetGlobalFormat 设置全局 Logger 的输出格式（写时复制，见 updateGlobal）
+func SetGlobalFormat(format FormatType) {
+	updateGlobal(func(l *Logger) {
+		l.WithFormat(format)
+	})
+}
+
+// updateGlobal 所有 SetGlobalXxx 的统一实现（写时复制）：复制当前全局 Logger，在副本上应用修改后原子发布，
+// 不与正在写日志的协程竞争；并发修改时基于最新的全局 Logger 重试。
+// 此前通过 GetGlobalLogger 取得的 Logger 及其子 Logger 保持原有设置；副本改用新的异步写入器时，
+// 原写入器写出剩余日志后停止，仍持有旧 Logger 的调用方改为同步写入原输出
+func updateGlobal(apply func(*Logger)) {
+	for {
+		cur := globalLogger()
+		next := cur.Clone().(*Logger)
+		next.stats = cur.stats
+		apply(next)
+		if next.async == cur.async {
+			// 沿用同一写入器时一并接管其所有权，全局 Logger 的 Close 仍能停止写入协程
+			next.ownsAsync = cur.ownsAsync
+		}
+		if globalPtr.CompareAndSwap(cur, next) {
+			if cur.ownsAsync && cur.async != nil && cur.async != next.async {
+				cur.async.Close()
+			}
+			return
+		}
+	}
+}
+
+// Debug 使用全局 Logger 记录调试日志
+func Debug(format string, args ...any) {
+	l := globalLogger()
+	if l.level > DEBUG {
+		return
+	}
+	l.ultraLogf(DEBUG, format, args...)
+}
+
+// Info 使用全局 Logger 记录信息日志
+func Info(format string, args ...any) {
+	l := globalLogger()
+	if l.level > INFO {
+		return
+	}
+	l.ultraLogf(INFO, format, args...)
+}
+
+// Warn 使用全局 Logger 记录警告日志
+func Warn(format string, args ...any) {
+	l := globalLogger()
+	if l.level > WARN {
+		return
+	}
+	l.ultraLogf(WARN, format, args...)
+}
+
+// Error 使用全局 Logger 记录错误日志
+func Error(format string, args ...any) {
+	l := globalLogger()
+	if l.level > ERROR {
+		return
+	}
+	l.ultraLogf(ERROR, format, args...)
+}
+
+// Fatal 使用全局 Logger 记录致命错误日志
+func Fatal(format string, args ...any) {
+	globalLogger().ultraLogf(FATAL, format, args...)
+}
+
+// DebugKV 使用全局 Logger 记录键值对调试日志
+func DebugKV(msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(DEBUG, msg, keysAndValues...)
+}
+
+// InfoKV 使用全局 Logger 记录键值对信息日志
+func InfoKV(msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(INFO, msg, keysAndValues...)
+}
+
+// WarnKV 使用全局 Logger 记录键值对警告日志
+func WarnKV(msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(WARN, msg, keysAndValues...)
+}
+
+// ErrorKV 使用全局 Logger 记录键值对错误日志
+func ErrorKV(msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(ERROR, msg, keysAndValues...)
+}
+
+// WithField 基于全局 Logger 添加字段
+func WithField(key string, value any) ILogger {
+	return globalLogger().WithField(key, value)
+}
+
+// WithFields 基于全局 Logger 添加多个字段
+func WithFields(fields map[string]any) ILogger {
+	return globalLogger().WithFields(fields)
+}
+
+// Debugf 使用全局 Logger 记录调试日志（同 Debug）
+func Debugf(format string, args ...any) {
+	l := globalLogger()
+	if l.level > DEBUG {
+		return
+	}
+	l.ultraLogf(DEBUG, format, args...)
+}
+
+// Infof 使用全局 Logger 记录信息日志（同 Info）
+func Infof(format string, args ...any) {
+	l := globalLogger()
+	if l.level > INFO {
+		return
+	}
+	l.ultraLogf(INFO, format, args...)
+}
+
+// Warnf 使用全局 Logger 记录警告日志（同 Warn）
+func Warnf(format string, args ...any) {
+	l := globalLogger()
+	if l.level > WARN {
+		return
+	}
+	l.ultraLogf(WARN, format, args...)
+}
+
+// Errorf 使用全局 Logger 记录错误日志（同 Error）
+func Errorf(format string, args ...any) {
+	l := globalLogger()
+	if l.level > ERROR {
+		return
+	}
+	l.ultraLogf(ERROR, format, args...)
+}
+
+// Fatalf 使用全局 Logger 记录致命错误日志（同 Fatal）
+func Fatalf(format string, args ...any) {
+	globalLogger().ultraLogf(FATAL, format, args...)
+}
+
+// FatalKV 使用全局 Logger 记录键值对致命错误日志
+func FatalKV(msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(FATAL, msg, keysAndValues...)
+}
+
+// DebugMsg 使用全局 Logger 记录纯文本调试日志
+func DebugMsg(msg string) {
+	l := globalLogger()
+	if l.level > DEBUG {
+		return
+	}
+	l.ultraLog(DEBUG, msg)
+}
+
+// InfoMsg 使用全局 Logger 记录纯文本信息日志
+func InfoMsg(msg string) {
+	l := globalLogger()
+	if l.level > INFO {
+		return
+	}
+	l.ultraLog(INFO, msg)
+}
+
+// WarnMsg 使用全局 Logger 记录纯文本警告日志
+func WarnMsg(msg string) {
+	l := globalLogger()
+	if l.level > WARN {
+		return
+	}
+	l.ultraLog(WARN, msg)
+}
+
+// ErrorMsg 使用全局 Logger 记录纯文本错误日志
+func ErrorMsg(msg string) {
+	l := globalLogger()
+	if l.level > ERROR {
+		return
+	}
+	l.ultraLog(ERROR, msg)
+}
+
+// DebugWithFields 使用全局 Logger 记录带字段映射的调试日志
+func DebugWithFields(msg string, fields map[string]any) {
+	globalLogger().logWithFields(DEBUG, msg, fields)
+}
+
+// InfoWithFields 使用全局 Logger 记录带字段映射的信息日志
+func InfoWithFields(msg string, fields map[string]any) {
+	globalLogger().logWithFields(INFO, msg, fields)
+}
+
+// WarnWithFields 使用全局 Logger 记录带字段映射的警告日志
+func WarnWithFields(msg string, fields map[string]any) {
+	globalLogger().logWithFields(WARN, msg, fields)
+}
+
+// ErrorWithFields 使用全局 Logger 记录带字段映射的错误日志
+func ErrorWithFields(msg string, fields map[string]any) {
+	globalLogger().logWithFields(ERROR, msg, fields)
+}
+
+// DebugContext 使用全局 Logger 记录带上下文的调试日志
+func DebugContext(ctx context.Context, format string, args ...any) {
+	globalLogger().DebugContext(ctx, format, args...)
+}
+
+// InfoContext 使用全局 Logger 记录带上下文的信息日志
+func InfoContext(ctx context.Context, format string, args ...any) {
+	globalLogger().InfoContext(ctx, format, args...)
+}
+
+// WarnContext 使用全局 Logger 记录带上下文的警告日志
+func WarnContext(ctx context.Context, format string, args ...any) {
+	globalLogger().WarnContext(ctx, format, args...)
+}
+
+// ErrorContext 使用全局 Logger 记录带上下文的错误日志
+func ErrorContext(ctx context.Context, format string, args ...any) {
+	globalLogger().ErrorContext(ctx, format, args...)
+}
+
+// DebugContextKV 使用全局 Logger 记录带上下文的键值对调试日志
+func DebugContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	globalLogger().logWithContextKV(ctx, DEBUG, msg, keysAndValues...)
+}
+
+// InfoContextKV 使用全局 Logger 记录带上下文的键值对信息日志
+func InfoContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	globalLogger().logWithContextKV(ctx, INFO, msg, keysAndValues...)
+}
+
+// WarnContextKV 使用全局 Logger 记录带上下文的键值对警告日志
+func WarnContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	globalLogger().logWithContextKV(ctx, WARN, msg, keysAndValues...)
+}
+
+// ErrorContextKV 使用全局 Logger 记录带上下文的键值对错误日志
+func ErrorContextKV(ctx context.Context, msg string, keysAndValues ...any) {
+	globalLogger().logWithContextKV(ctx, ERROR, msg, keysAndValues...)
+}
+
+// DebugLines 使用全局 Logger 逐行记录调试日志
+func DebugLines(lines ...string) {
+	globalLogger().DebugLines(lines...)
+}
+
+// InfoLines 使用全局 Logger 逐行记录信息日志
+func InfoLines(lines ...string) {
+	globalLogger().InfoLines(lines...)
+}
+
+// WarnLines 使用全局 Logger 逐行记录警告日志
+func WarnLines(lines ...string) {
+	globalLogger().WarnLines(lines...)
+}
+
+// ErrorLines 使用全局 Logger 逐行记录错误日志
+func ErrorLines(lines ...string) {
+	globalLogger().ErrorLines(lines...)
+}
+
+// DebugReturn 使用全局 Logger 记录调试日志并返回格式化的错误
+func DebugReturn(format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(DEBUG, format, args, nil)
+	l.logSkip(0, DEBUG, err.Message, nil)
+	return err
+}
+
+// InfoReturn 使用全局 Logger 记录信息日志并返回格式化的错误
+func InfoReturn(format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(INFO, format, args, nil)
+	l.logSkip(0, INFO, err.Message, nil)
+	return err
+}
+
+// WarnReturn 使用全局 Logger 记录警告日志并返回格式化的错误
+func WarnReturn(format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(WARN, format, args, nil)
+	l.logSkip(0, WARN, err.Message, nil)
+	return err
+}
+
+// ErrorReturn 使用全局 Logger 记录错误日志并返回格式化的错误
+func ErrorReturn(format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(ERROR, format, args, nil)
+	l.logSkip(0, ERROR, err.Message, nil)
+	return err
+}
+
+// DebugCtxReturn 使用全局 Logger 记录带上下文的调试日志并返回格式化的错误
+func DebugCtxReturn(ctx context.Context, format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(DEBUG, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, DEBUG, err.Message)
+	return err
+}
+
+// InfoCtxReturn 使用全局 Logger 记录带上下文的信息日志并返回格式化的错误
+func InfoCtxReturn(ctx context.Context, format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(INFO, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, INFO, err.Message)
+	return err
+}
+
+// WarnCtxReturn 使用全局 Logger 记录带上下文的警告日志并返回格式化的错误
+func WarnCtxReturn(ctx context.Context, format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(WARN, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, WARN, err.Message)
+	return err
+}
+
+// ErrorCtxReturn 使用全局 Logger 记录带上下文的错误日志并返回格式化的错误
+func ErrorCtxReturn(ctx context.Context, format string, args ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(ERROR, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, ERROR, err.Message)
+	return err
+}
+
+// DebugKVReturn 使用全局 Logger 记录带键值对的调试日志并返回错误
+func DebugKVReturn(msg string, keysAndValues ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(DEBUG, msg, nil, keysAndValues)
+	l.logSkip(0, DEBUG, msg, keysAndValues)
+	return err
+}
+
+// InfoKVReturn 使用全局 Logger 记录带键值对的信息日志并返回错误
+func InfoKVReturn(msg string, keysAndValues ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(INFO, msg, nil, keysAndValues)
+	l.logSkip(0, INFO, msg, keysAndValues)
+	return err
+}
+
+// WarnKVReturn 使用全局 Logger 记录带键值对的警告日志并返回错误
+func WarnKVReturn(msg string, keysAndValues ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(WARN, msg, nil, keysAndValues)
+	l.logSkip(0, WARN, msg, keysAndValues)
+	return err
+}
+
+// ErrorKVReturn 使用全局 Logger 记录带键值对的错误日志并返回错误
+func ErrorKVReturn(msg string, keysAndValues ...any) error {
+	l := globalLogger()
+	err := l.newReturnError(ERROR, msg, nil, keysAndValues)
+	l.logSkip(0, ERROR, msg, keysAndValues)
+	return err
+}
+
+// Log 使用全局 Logger 记录指定级别的日志
+func Log(level LogLevel, msg string) {
+	l := globalLogger()
+	if level < l.level {
+		return
+	}
+	l.ultraLog(level, msg)
+}
+
+// LogKV 使用全局 Logger 记录指定级别的键值对日志
+func LogKV(level LogLevel, msg string, keysAndValues ...any) {
+	globalLogger().logWithKV(level, msg, keysAndValues...)
+}
+
+// LogWithCallerSkip 使用全局 Logger 记录键值对日志，调用位置再向上跳过 skip 层
+func LogWithCallerSkip(skip int, level LogLevel, msg string, keysAndValues ...any) {
+	globalLogger().logSkip(skip, level, msg, keysAndValues)
+}
+
+// FatalContext 使用全局 Logger 记录带上下文的致命错误日志
+func FatalContext(ctx context.Context, format string, args ...any) {
+	globalLogger().FatalContext(ctx, format, args...)
+}
+
+// LogContext 使用全局 Logger 记录指定级别的带上下文日志
+func LogContext(ctx context.Context, level LogLevel, msg string) {
+	globalLogger().LogContext(ctx, level, msg)
+}
+
+// LogWithFields 使用全局 Logger 记录指定级别的带字段映射日志
+func LogWithFields(level LogLevel, msg string, fields map[string]any) {
+	globalLogger().logWithFields(level, msg, fields)
+}
+
+// With 基于全局 Logger 创建携带静态字段的子 Logger
+func With(keysAndValues ...any) *Logger {
+	return globalLogger().With(keysAndValues...)
+}
+
+// WithError 基于全局 Logger 添加错误字段
+func WithError(err error) ILogger {
+	return globalLogger().WithError(err)
+}
+
+// WithContext 基于全局 Logger 创建绑定上下文的子 Logger
+func WithContext(ctx context.Context) ILogger {
+	return globalLogger().WithContext(ctx)
+}
+
+// NewConsoleGroup 基于全局 Logger 创建控制台分组
+func NewConsoleGroup() *ConsoleGroup {
+	return globalLogger().NewConsoleGroup()
+}
+
+// ConsoleGroupStart 使用全局 Logger 开始日志分组（ConsoleGroup 为类型名）
+func ConsoleGroupStart(label string, args ...any) {
+	globalLogger().ConsoleGroup(label, args...)
+}
+
+// ConsoleGroupCollapsed 使用全局 Logger 开始折叠分组
+func ConsoleGroupCollapsed(label string, args ...any) {
+	globalLogger().ConsoleGroupCollapsed(label, args...)
+}
+
+// ConsoleGroupEnd 结束全局 Logger 的当前分组
+func ConsoleGroupEnd() {
+	globalLogger().ConsoleGroupEnd()
+}
+
+// ConsoleShowTable 使用全局 Logger 显示表格（ConsoleTable 为类型名）
+func ConsoleShowTable(data any) {
+	globalLogger().ConsoleTable(data)
+}
+
+// ConsoleTime 使用全局 Logger 开始计时
+func ConsoleTime(label string) *Timer {
+	return globalLogger().ConsoleTime(label)
+}
+
+// Named 通过默认管理器获取命名 Logger
+func Named(name string) *Logger {
+	return DefaultManager().Named(name)
+}
+
+// Once 全局 Logger 的 Once 守卫：同一调用位置只输出第一次
+func Once() ILogger {
+	return globalLogger().onceAt(guardSite())
+}
+
+// EveryN 全局 Logger 的 EveryN 守卫：同一调用位置每 n 次输出一次
+func EveryN(n int) ILogger {
+	return globalLogger().everyNAt(guardSite(), n)
+}
+
+// Every 全局 Logger 的 Every 守卫：同一调用位置每个时间间隔内最多输出一次
+func Every(interval time.Duration) ILogger {
+	return globalLogger().everyAt(guardSite(), interval)
+}
+
+// Go 使用全局 Logger 启动带 panic 恢复的协程
+func Go(fn func()) {
+	globalLogger().Go(fn)
+}
+
+// SafeGo 使用全局 Logger 启动带 panic 恢复的协程，panic 日志附加 ctx 中的上下文字段
+func SafeGo(ctx context.Context, fn func(ctx context.Context)) {
+	globalLogger().SafeGo(ctx, fn)
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/kamalyes/go-toolbox v0.15.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/sys v0.37.0
 	google.golang.org/grpc v1.77.0
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -13,7 +14,6 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kamalyes/go-argus v0.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.37.0 // indirect
 	google.golang.org/protobuf v1.36.10 // indirect
 )
 
@@ -0,0 +1,100 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\guard.go
+ * @Description: 按调用位置限频的日志守卫（Once / EveryN / Every），用于热循环中安全打日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 各调用位置的守卫状态（调用位置数量有限，不做回收）
+var (
+	onceSites  sync.Map // uintptr -> struct{}
+	countSites sync.Map // uintptr -> *atomic.Uint64
+	timeSites  sync.Map // uintptr -> *atomic.Int64
+)
+
+// nopGuard 守卫拒绝时返回的空日志器
+var nopGuard ILogger = NewEmptyLogger()
+
+// guardSite 返回守卫方法（Once 等）调用方的程序计数器，作为调用位置的键
+func guardSite() uintptr {
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	return pcs[0]
+}
+
+// Once 同一调用位置只输出第一次：
+//
+//	for _, item := range items {
+//		log.Once().Warn("deprecated field used: %s", item.Name)
+//	}
+//
+// 守卫在调用 Once 时判定，允许时返回 Logger 自身，否则返回空日志器
+func (l *Logger) Once() ILogger {
+	return l.onceAt(guardSite())
+}
+
+// EveryN 同一调用位置每 n 次输出一次（第 1、n+1、2n+1... 次），n <= 1 时每次都输出
+func (l *Logger) EveryN(n int) ILogger {
+	return l.everyNAt(guardSite(), n)
+}
+
+// Every 同一调用位置每个时间间隔内最多输出一次（第一次总是输出）
+func (l *Logger) Every(interval time.Duration) ILogger {
+	return l.everyAt(guardSite(), interval)
+}
+
+// onceAt Once 的实现，site 为调用位置
+func (l *Logger) onceAt(site uintptr) ILogger {
+	if _, loaded := onceSites.LoadOrStore(site, struct{}{}); loaded {
+		return nopGuard
+	}
+	return l
+}
+
+// everyNAt EveryN 的实现
+func (l *Logger) everyNAt(site uintptr, n int) ILogger {
+	if n <= 1 {
+		return l
+	}
+	counter, ok := countSites.Load(site)
+	if !ok {
+		counter, _ = countSites.LoadOrStore(site, new(atomic.Uint64))
+	}
+	if (counter.(*atomic.Uint64).Add(1)-1)%uint64(n) != 0 {
+		return nopGuard
+	}
+	return l
+}
+
+// everyAt Every 的实现
+func (l *Logger) everyAt(site uintptr, interval time.Duration) ILogger {
+	if interval <= 0 {
+		return l
+	}
+	last, ok := timeSites.Load(site)
+	if !ok {
+		last, _ = timeSites.LoadOrStore(site, new(atomic.Int64))
+	}
+	stamp := last.(*atomic.Int64)
+	now := time.Now().UnixNano()
+	prev := stamp.Load()
+	if prev != 0 && now-prev < int64(interval) {
+		return nopGuard
+	}
+	if !stamp.CompareAndSwap(prev, now) {
+		return nopGuard
+	}
+	return l
+}
@@ -0,0 +1,224 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\health.go
+ * @Description: 日志管道健康检查（各适配器状态、最近错误、队列深度），可接入就绪探针与常见健康检查框架
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HealthStatus 健康状态
+type HealthStatus string
+
+const (
+	HealthOK       HealthStatus = "ok"       // 正常
+	HealthDegraded HealthStatus = "degraded" // 可用，但近期出错或队列接近满
+	HealthDown     HealthStatus = "down"     // 适配器不可用（IsHealthy 为 false）
+)
+
+// 健康判定阈值
+const (
+	HealthErrorWindow    = time.Minute // 该时间内出现过内部错误时视为 degraded
+	HealthQueueThreshold = 0.9         // 队列占用超过该比例时视为 degraded
+)
+
+// severity 状态严重程度（用于取最差状态）
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthDown:
+		return 2
+	case HealthDegraded:
+		return 1
+	}
+	return 0
+}
+
+// QueueReporter 可选接口：带发送队列的适配器返回队列中待发送的条数与队列容量
+type QueueReporter interface {
+	QueueDepth() (depth, capacity int)
+}
+
+// AdapterHealth 单个适配器的健康状态
+type AdapterHealth struct {
+	Name          string       `json:"name"`
+	Version       string       `json:"version"`
+	Status        HealthStatus `json:"status"`
+	Healthy       bool         `json:"healthy"`
+	LastError     string       `json:"last_error,omitempty"`
+	LastErrorTime *time.Time   `json:"last_error_time,omitempty"`
+	QueueDepth    int          `json:"queue_depth"`
+	QueueCapacity int          `json:"queue_capacity,omitempty"` // 0 表示没有发送队列
+}
+
+// HealthReport 日志管道健康报告，Status 为各适配器中最差的状态
+type HealthReport struct {
+	Status   HealthStatus    `json:"status"`
+	Time     time.Time       `json:"time"`
+	Adapters []AdapterHealth `json:"adapters"`
+}
+
+// Err 有适配器处于 down 状态时返回错误（degraded 不视为失败），用于 func() error 形式的检查
+func (r HealthReport) Err() error {
+	var down []string
+	for _, a := range r.Adapters {
+		if a.Status != HealthDown {
+			continue
+		}
+		msg := a.Name
+		if a.LastError != "" {
+			msg += ": " + a.LastError
+		}
+		down = append(down, msg)
+	}
+	if len(down) == 0 {
+		return nil
+	}
+	return errors.New("logger: adapters down: " + strings.Join(down, "; "))
+}
+
+// adapterHealth 检查单个适配器
+func adapterHealth(adapter IAdapter, now time.Time) AdapterHealth {
+	h := AdapterHealth{
+		Name:    adapter.GetAdapterName(),
+		Version: adapter.GetAdapterVersion(),
+		Status:  HealthOK,
+		Healthy: adapter.IsHealthy(),
+	}
+	if e, ok := LastInternalError(h.Name); ok {
+		at := e.Time
+		h.LastError = e.Err.Error()
+		h.LastErrorTime = &at
+		if now.Sub(at) < HealthErrorWindow {
+			h.Status = HealthDegraded
+		}
+	}
+	if q, ok := unwrapAdapter(adapter).(QueueReporter); ok {
+		h.QueueDepth, h.QueueCapacity = q.QueueDepth()
+		if h.QueueCapacity > 0 && float64(h.QueueDepth) >= HealthQueueThreshold*float64(h.QueueCapacity) {
+			h.Status = HealthDegraded
+		}
+	}
+	if !h.Healthy {
+		h.Status = HealthDown
+	}
+	return h
+}
+
+// unwrapAdapter 去掉 Wrap 的包装，取得实际的适配器
+func unwrapAdapter(adapter IAdapter) IAdapter {
+	for {
+		w, ok := adapter.(interface{ Unwrap() IAdapter })
+		if !ok {
+			return adapter
+		}
+		adapter = w.Unwrap()
+	}
+}
+
+// MonitorAdapters 将未通过 WithAdapters 挂到管理器内 Logger 上的适配器（如单独使用或挂在其他 Logger 上的）纳入健康检查
+func (m *LoggerManager) MonitorAdapters(adapters ...IAdapter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, adapter := range adapters {
+		if adapter != nil {
+			m.monitored = append(m.monitored, adapter)
+		}
+	}
+}
+
+// adapters 收集根、租户、命名与包 Logger 上的适配器及 MonitorAdapters 登记的适配器（去重，保持发现顺序）
+func (m *LoggerManager) adapters() []IAdapter {
+	m.mu.RLock()
+	loggers := make([]*Logger, 0, 1+len(m.tenants)+len(m.named)+len(m.packages))
+	loggers = append(loggers, m.root)
+	for _, group := range []map[string]*Logger{m.tenants, m.named, m.packages} {
+		names := make([]string, 0, len(group))
+		for name := range group {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			loggers = append(loggers, group[name])
+		}
+	}
+	monitored := append([]IAdapter(nil), m.monitored...)
+	m.mu.RUnlock()
+
+	seen := make(map[IAdapter]struct{})
+	var result []IAdapter
+	add := func(adapter IAdapter) {
+		if _, ok := seen[adapter]; ok {
+			return
+		}
+		seen[adapter] = struct{}{}
+		result = append(result, adapter)
+	}
+	for _, l := range loggers {
+		for _, hook := range l.hooks {
+			if h, ok := hook.(*adapterHook); ok {
+				add(h.adapter)
+			}
+		}
+	}
+	for _, adapter := range monitored {
+		add(adapter)
+	}
+	return result
+}
+
+// Health 返回日志管道健康报告：
+//
+//	readiness.AddCheck("logger", func() error { return manager.Health().Err() })
+//	mux.Handle("/readyz/logger", manager.HealthHandler())
+func (m *LoggerManager) Health() HealthReport {
+	now := time.Now()
+	report := HealthReport{Status: HealthOK, Time: now, Adapters: []AdapterHealth{}}
+	for _, adapter := range m.adapters() {
+		h := adapterHealth(adapter, now)
+		if h.Status.severity() > report.Status.severity() {
+			report.Status = h.Status
+		}
+		report.Adapters = append(report.Adapters, h)
+	}
+	return report
+}
+
+// CheckHealth 以 func(context.Context) error 形式返回健康检查结果（有适配器 down 时返回错误）
+func (m *LoggerManager) CheckHealth(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return m.Health().Err()
+}
+
+// HealthHandler 返回健康检查接口：输出 JSON 报告，有适配器 down 时状态码为 503，可直接作为就绪探针
+func (m *LoggerManager) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealthReport(w, m.Health())
+	})
+}
+
+// writeHealthReport 输出健康报告
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == HealthDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+}
@@ -0,0 +1,154 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\hooksender.go
+ * @Description: 钩子异步 HTTP 发送器（有界队列，队列满时丢弃，关闭时发送完剩余请求）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultHookQueueSize 默认发送队列长度
+const defaultHookQueueSize = 256
+
+// hookRequest 待发送的请求
+type hookRequest struct {
+	url    string
+	header http.Header
+	body   []byte
+}
+
+// hookSender 钩子异步发送器
+type hookSender struct {
+	client    *http.Client
+	queue     chan hookRequest
+	pending   sync.WaitGroup
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// newHookSender 创建发送器并启动后台协程
+func newHookSender(client *http.Client, queueSize int) *hookSender {
+	if client == nil {
+		client = newHTTPClient(5 * time.Second)
+	}
+	if queueSize <= 0 {
+		queueSize = defaultHookQueueSize
+	}
+	s := &hookSender{
+		client: client,
+		queue:  make(chan hookRequest, queueSize),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// enqueue 请求入队（不阻塞）
+func (s *hookSender) enqueue(req hookRequest) error {
+	select {
+	case <-s.done:
+		return ErrHookClosed
+	default:
+	}
+
+	s.pending.Add(1)
+	select {
+	case s.queue <- req:
+		return nil
+	default:
+		s.pending.Done()
+		s.dropped.Add(1)
+		reportOverflow(OverflowComponentHook, OverflowDropped, cap(s.queue))
+		return fmt.Errorf("hook queue full")
+	}
+}
+
+// run 后台发送协程（关闭后发送完队列中剩余请求再退出）
+func (s *hookSender) run() {
+	for {
+		select {
+		case req := <-s.queue:
+			s.deliver(req)
+		case <-s.done:
+			for {
+				select {
+				case req := <-s.queue:
+					s.deliver(req)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver 发送并记录结果
+func (s *hookSender) deliver(req hookRequest) {
+	if err := s.send(req); err != nil {
+		s.failed.Add(1)
+		reportInternalError("hook", err)
+	}
+	s.pending.Done()
+}
+
+// send 发送单个请求
+func (s *hookSender) send(r hookRequest) error {
+	req, err := http.NewRequest(http.MethodPost, r.url, bytes.NewReader(r.body))
+	if err != nil {
+		return err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s responded with status %d", r.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// flush 等待已入队请求发送完成
+func (s *hookSender) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close 停止接收新请求并等待已入队请求发送完成
+func (s *hookSender) close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.done) })
+	return s.flush(ctx)
+}
+
+// stats 返回发送失败与因队列满丢弃的请求数
+func (s *hookSender) stats() (failed, dropped int64) {
+	return s.failed.Load(), s.dropped.Load()
+}
@@ -0,0 +1,347 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\httpbatch.go
+ * @Description: HTTP 批量发送器（Datadog、Splunk HEC 等日志接入 API 共用）：有界队列、按批发送、429 按 Retry-After 退避
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultHTTPBatchFlushInterval = 2 * time.Second
+	DefaultHTTPBatchQueueSize     = 8192
+	DefaultHTTPBatchRetries       = 5
+	DefaultHTTPBatchMaxBackoff    = 30 * time.Second
+)
+
+// ErrHTTPBatchClosed 发送器已关闭
+var ErrHTTPBatchClosed = errors.New("logger: http batch sender closed")
+
+// httpBatchRecord 待发送的日志
+type httpBatchRecord struct {
+	time    time.Time
+	level   LogLevel
+	message string
+	fields  map[string]any
+}
+
+// httpBatchConfig 批量发送配置（由各适配器的选项设置）
+type httpBatchConfig struct {
+	client        *http.Client
+	tls           *tls.Config // 设置后应用到 client 的 Transport（见 resolveClient）
+	batchSize     int
+	flushInterval time.Duration
+	queueSize     int
+	retries       int
+	maxBackoff    time.Duration
+}
+
+// defaultHTTPBatchConfig 返回默认配置
+func defaultHTTPBatchConfig(batchSize int) httpBatchConfig {
+	return httpBatchConfig{
+		client:        newHTTPClient(10 * time.Second),
+		batchSize:     batchSize,
+		flushInterval: DefaultHTTPBatchFlushInterval,
+		queueSize:     DefaultHTTPBatchQueueSize,
+		retries:       DefaultHTTPBatchRetries,
+		maxBackoff:    DefaultHTTPBatchMaxBackoff,
+	}
+}
+
+// resolveClient 将 TLS 配置应用到客户端（在全部选项设置完成后调用，与 WithXxxHTTPClient 的先后顺序无关）
+func (c *httpBatchConfig) resolveClient() {
+	c.client = httpClientWithTLS(c.client, c.tls)
+}
+
+// HTTPBatchStats HTTP 批量发送统计
+type HTTPBatchStats struct {
+	Sent      int64 // 发送成功的日志数
+	Failed    int64 // 重试耗尽或被拒绝的日志数
+	Dropped   int64 // 因队列满或已关闭丢弃的日志数
+	Throttled int64 // 收到 429 的次数
+}
+
+// httpBatchSpec 各接入 API 的差异部分
+type httpBatchSpec struct {
+	component string                                      // 组件名（溢出事件与内部错误）
+	url       string                                      // 接入地址
+	header    http.Header                                 // 固定请求头
+	encode    func([]httpBatchRecord) ([]byte, error)     // 编码一批日志
+	onHealth  func(bool)                                  // 发送结果回调（更新适配器健康状态）
+	split     func([]httpBatchRecord) [][]httpBatchRecord // 可选：按接口限制拆分批次
+	sign      func(req *http.Request, body []byte) error  // 可选：请求签名
+	recover   func(err *httpStatusError) bool             // 可选：处理错误（如创建缺失的资源），返回 true 时立即重试
+	response  func(body []byte)                           // 可选：处理成功响应的内容
+}
+
+// httpBatchSender HTTP 批量发送器
+type httpBatchSender struct {
+	httpBatchSpec
+	cfg httpBatchConfig
+
+	queue     chan httpBatchRecord
+	flushReq  chan chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	sent      atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	throttled atomic.Int64
+}
+
+// newHTTPBatchSender 创建发送器并启动后台协程
+func newHTTPBatchSender(cfg httpBatchConfig, spec httpBatchSpec) *httpBatchSender {
+	s := &httpBatchSender{
+		httpBatchSpec: spec,
+		cfg:           cfg,
+		queue:         make(chan httpBatchRecord, cfg.queueSize),
+		flushReq:      make(chan chan error),
+		done:          make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// enqueue 日志入队（不阻塞，队列满或已关闭时丢弃）
+func (s *httpBatchSender) enqueue(r httpBatchRecord) {
+	select {
+	case <-s.done:
+		s.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case s.queue <- r:
+	default:
+		s.dropped.Add(1)
+		reportOverflow(s.component, OverflowDropped, cap(s.queue))
+	}
+}
+
+// run 后台发送协程
+func (s *httpBatchSender) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]httpBatchRecord, 0, s.cfg.batchSize)
+	for {
+		select {
+		case r := <-s.queue:
+			batch = append(batch, r)
+			if len(batch) >= s.cfg.batchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		case reply := <-s.flushReq:
+			batch = s.drain(batch)
+			reply <- s.send(batch)
+			batch = batch[:0]
+		case <-s.done:
+			batch = s.drain(batch)
+			s.send(batch)
+			return
+		}
+	}
+}
+
+// drain 取出队列中剩余日志，按批发送已满的部分，返回未满的批次
+func (s *httpBatchSender) drain(batch []httpBatchRecord) []httpBatchRecord {
+	for {
+		select {
+		case r := <-s.queue:
+			batch = append(batch, r)
+			if len(batch) >= s.cfg.batchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// httpStatusError 非 2xx 响应
+type httpStatusError struct {
+	status     int
+	body       string
+	retryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.status, e.body)
+}
+
+// retryable 408、429 与 5xx 可重试
+func (e *httpStatusError) retryable() bool {
+	return e.status == http.StatusRequestTimeout || e.status == http.StatusTooManyRequests || e.status >= 500
+}
+
+// send 发送一批日志（按需拆分后逐个发送，返回第一个错误）
+func (s *httpBatchSender) send(batch []httpBatchRecord) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if s.split == nil {
+		return s.sendOne(batch)
+	}
+	var firstErr error
+	for _, part := range s.split(batch) {
+		if err := s.sendOne(part); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sendOne 发送一个批次：网络错误与 5xx 按指数退避重试，429 优先使用 Retry-After，其余 4xx 不重试
+func (s *httpBatchSender) sendOne(batch []httpBatchRecord) error {
+	body, err := s.encode(batch)
+	if err == nil {
+		recovered := false
+		for attempt := 0; ; attempt++ {
+			if err = s.post(body); err == nil {
+				s.sent.Add(int64(len(batch)))
+				s.onHealth(true)
+				return nil
+			}
+			var statusErr *httpStatusError
+			isStatus := errors.As(err, &statusErr)
+			if isStatus && !recovered && s.recover != nil && s.recover(statusErr) {
+				recovered = true
+				attempt--
+				continue
+			}
+			if (isStatus && !statusErr.retryable()) || attempt >= s.cfg.retries {
+				break
+			}
+			time.Sleep(s.backoff(attempt, statusErr))
+		}
+	}
+	s.failed.Add(int64(len(batch)))
+	s.onHealth(false)
+	err = fmt.Errorf("%s post %d records: %w", s.component, len(batch), err)
+	reportInternalError(s.component, err)
+	return err
+}
+
+// backoff 计算重试等待时间
+func (s *httpBatchSender) backoff(attempt int, statusErr *httpStatusError) time.Duration {
+	wait := time.Duration(500<<attempt) * time.Millisecond
+	if statusErr != nil && statusErr.retryAfter > 0 {
+		wait = statusErr.retryAfter
+	}
+	return min(wait, s.cfg.maxBackoff)
+}
+
+// post 发送一次请求
+func (s *httpBatchSender) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	if s.sign != nil {
+		if err := s.sign(req, body); err != nil {
+			return err
+		}
+	}
+
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		if s.response != nil {
+			if data, err := io.ReadAll(resp.Body); err == nil {
+				s.response(data)
+			}
+			return nil
+		}
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	statusErr := &httpStatusError{status: resp.StatusCode, body: strings.TrimSpace(string(msg))}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		s.throttled.Add(1)
+		statusErr.retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	return statusErr
+}
+
+// parseRetryAfter 解析 Retry-After（秒数或 HTTP 日期）
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// flush 立即发送队列中的日志并等待结果
+func (s *httpBatchSender) flush() error {
+	reply := make(chan error, 1)
+	select {
+	case s.flushReq <- reply:
+		return <-reply
+	case <-s.done:
+		return ErrHTTPBatchClosed
+	}
+}
+
+// close 发送剩余日志后停止
+func (s *httpBatchSender) close() {
+	s.closeOnce.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// queueDepth 返回队列中待发送的条数与队列容量
+func (s *httpBatchSender) queueDepth() (depth, capacity int) {
+	return len(s.queue), cap(s.queue)
+}
+
+// stats 返回统计信息
+func (s *httpBatchSender) stats() HTTPBatchStats {
+	return HTTPBatchStats{
+		Sent:      s.sent.Load(),
+		Failed:    s.failed.Load(),
+		Dropped:   s.dropped.Load(),
+		Throttled: s.throttled.Load(),
+	}
+}
@@ -0,0 +1,193 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\httptransport.go
+ * @Description: HTTP 类适配器共用的传输配置（出站代理、连接池、超时、keep-alive），可设为包级默认值
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+)
+
+// HTTPTransportConfig HTTP 传输配置段，可嵌入应用的配置结构：
+//
+//	transport:
+//	  proxy_url: http://proxy.corp.example:3128
+//	  max_idle_conns_per_host: 4
+//	  idle_conn_timeout: 90s
+//	  tls: {ca_file: /etc/ssl/corp-ca.pem}
+//
+// 通过 Client 创建客户端后传给 WithDatadogHTTPClient 等选项，或通过 SetDefaultHTTPTransport 设为所有
+// HTTP 类适配器与钩子（Datadog、Splunk、CloudWatch、webhook 告警、Sentry、事件通知、S3 归档）的默认传输；零值字段使用 http.DefaultTransport 的设置
+type HTTPTransportConfig struct {
+	ProxyURL              string        `json:"proxy_url,omitempty" yaml:"proxy_url,omitempty"`                             // 出站代理（http、https、socks5），为空时读取 HTTP_PROXY / HTTPS_PROXY / NO_PROXY
+	DisableProxy          bool          `json:"disable_proxy,omitempty" yaml:"disable_proxy,omitempty"`                     // 不使用任何代理（忽略环境变量）
+	MaxIdleConns          int           `json:"max_idle_conns,omitempty" yaml:"max_idle_conns,omitempty"`                   // 空闲连接总数上限
+	MaxIdleConnsPerHost   int           `json:"max_idle_conns_per_host,omitempty" yaml:"max_idle_conns_per_host,omitempty"` // 每个主机的空闲连接上限
+	MaxConnsPerHost       int           `json:"max_conns_per_host,omitempty" yaml:"max_conns_per_host,omitempty"`           // 每个主机的连接上限（0 为不限）
+	IdleConnTimeout       time.Duration `json:"idle_conn_timeout,omitempty" yaml:"idle_conn_timeout,omitempty"`             // 空闲连接保留时间
+	DialTimeout           time.Duration `json:"dial_timeout,omitempty" yaml:"dial_timeout,omitempty"`                       // 建立连接超时
+	KeepAlive             time.Duration `json:"keep_alive,omitempty" yaml:"keep_alive,omitempty"`                           // TCP keep-alive 间隔（负数关闭）
+	TLSHandshakeTimeout   time.Duration `json:"tls_handshake_timeout,omitempty" yaml:"tls_handshake_timeout,omitempty"`     // TLS 握手超时
+	ResponseHeaderTimeout time.Duration `json:"response_header_timeout,omitempty" yaml:"response_header_timeout,omitempty"` // 等待响应头超时
+	DisableKeepAlives     bool          `json:"disable_keep_alives,omitempty" yaml:"disable_keep_alives,omitempty"`         // 每个请求使用新连接
+	TLS                   *TLSConfig    `json:"tls,omitempty" yaml:"tls,omitempty"`                                         // TLS 配置
+}
+
+// Transport 按配置创建 *http.Transport，nil 接收者返回 http.DefaultTransport 的副本
+func (c *HTTPTransportConfig) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c == nil {
+		return transport, nil
+	}
+	switch {
+	case c.DisableProxy:
+		transport.Proxy = nil
+	case c.ProxyURL != "":
+		proxy, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("logger: proxy_url: %w", err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("logger: proxy_url: unsupported scheme %q", proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if c.DialTimeout != 0 || c.KeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if c.DialTimeout > 0 {
+			dialer.Timeout = c.DialTimeout
+		}
+		if c.KeepAlive != 0 {
+			dialer.KeepAlive = c.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	if c.MaxIdleConns > 0 {
+		transport.MaxIdleConns = c.MaxIdleConns
+	}
+	if c.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = c.MaxIdleConnsPerHost
+	}
+	if c.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = c.MaxConnsPerHost
+	}
+	if c.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = c.IdleConnTimeout
+	}
+	if c.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = c.TLSHandshakeTimeout
+	}
+	if c.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = c.ResponseHeaderTimeout
+	}
+	transport.DisableKeepAlives = c.DisableKeepAlives
+
+	conf, err := c.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	if conf != nil {
+		transport.TLSClientConfig = conf
+	}
+	return transport, nil
+}
+
+// Client 按配置创建 HTTP 客户端，timeout 为单个请求的总超时（0 为不限）
+func (c *HTTPTransportConfig) Client(timeout time.Duration) (*http.Client, error) {
+	transport, err := c.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+// defaultHTTPTransport 包级默认传输（nil 时使用 http.DefaultTransport）
+var defaultHTTPTransport atomic.Pointer[http.Transport]
+
+// SetDefaultHTTPTransport 设置 HTTP 类适配器与钩子未指定客户端时使用的默认传输（需在创建适配器之前调用），
+// 多个适配器共享同一连接池；cfg 为 nil 时恢复为 http.DefaultTransport
+func SetDefaultHTTPTransport(cfg *HTTPTransportConfig) error {
+	if cfg == nil {
+		defaultHTTPTransport.Store(nil)
+		return nil
+	}
+	transport, err := cfg.Transport()
+	if err != nil {
+		return err
+	}
+	defaultHTTPTransport.Store(transport)
+	return nil
+}
+
+// newHTTPClient 创建使用包级默认传输的客户端
+func newHTTPClient(timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if transport := defaultHTTPTransport.Load(); transport != nil {
+		client.Transport = transport
+	}
+	return client
+}
+
+// HTTPTransport 读取传输配置段（键名与 HTTPTransportConfig 的 json 标签一致），不存在时返回 nil
+func (p ComponentParams) HTTPTransport(key string) (*HTTPTransportConfig, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	section, ok := componentSection(v)
+	if !ok {
+		return nil, fmt.Errorf("param %q: want transport section, got %T", key, v)
+	}
+	c := &HTTPTransportConfig{}
+	var err error
+	wrap := func(e error) error { return fmt.Errorf("param %q: %w", key, e) }
+	if c.ProxyURL, err = section.String("proxy_url", ""); err != nil {
+		return nil, wrap(err)
+	}
+	for name, dst := range map[string]*bool{
+		"disable_proxy":       &c.DisableProxy,
+		"disable_keep_alives": &c.DisableKeepAlives,
+	} {
+		if *dst, err = section.Bool(name, false); err != nil {
+			return nil, wrap(err)
+		}
+	}
+	for name, dst := range map[string]*int{
+		"max_idle_conns":          &c.MaxIdleConns,
+		"max_idle_conns_per_host": &c.MaxIdleConnsPerHost,
+		"max_conns_per_host":      &c.MaxConnsPerHost,
+	} {
+		if *dst, err = section.Int(name, 0); err != nil {
+			return nil, wrap(err)
+		}
+	}
+	for name, dst := range map[string]*time.Duration{
+		"idle_conn_timeout":       &c.IdleConnTimeout,
+		"dial_timeout":            &c.DialTimeout,
+		"keep_alive":              &c.KeepAlive,
+		"tls_handshake_timeout":   &c.TLSHandshakeTimeout,
+		"response_header_timeout": &c.ResponseHeaderTimeout,
+	} {
+		if *dst, err = section.Duration(name, 0); err != nil {
+			return nil, wrap(err)
+		}
+	}
+	if c.TLS, err = section.TLS("tls"); err != nil {
+		return nil, wrap(err)
+	}
+	return c, nil
+}
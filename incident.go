@@ -0,0 +1,304 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\incident.go
+ * @Description: 事件告警钩子（PagerDuty Events API v2 / Opsgenie），按规则触发并以消息指纹去重
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// IncidentProvider 事件平台
+type IncidentProvider string
+
+const (
+	IncidentPagerDuty IncidentProvider = "pagerduty"
+	IncidentOpsgenie  IncidentProvider = "opsgenie"
+)
+
+// 默认接口地址
+const (
+	pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL  = "https://api.opsgenie.com/v2/alerts"
+)
+
+// IncidentRule 触发规则：级别达到 MinLevel 且 Match 返回 true（为 nil 时视为匹配）时触发
+// Severity 为 PagerDuty 的 critical/error/warning/info；Priority 为 Opsgenie 的 P1-P5
+type IncidentRule struct {
+	Name     string
+	MinLevel LogLevel
+	Match    func(*LogEntry) bool
+	Severity string
+	Priority string
+}
+
+// DefaultIncidentRules 默认规则：FATAL 为最高级别，ERROR 为普通级别
+func DefaultIncidentRules() []IncidentRule {
+	return []IncidentRule{
+		{Name: "fatal", MinLevel: FATAL, Severity: "critical", Priority: "P1"},
+		{Name: "error", MinLevel: ERROR, Severity: "error", Priority: "P3"},
+	}
+}
+
+// IncidentHook 事件告警钩子
+// 按规则顺序匹配第一条命中的规则，dedup key 由规则名与消息指纹生成，
+// 同一 dedup key 在冷却期内只触发一次
+type IncidentHook struct {
+	provider   IncidentProvider
+	key        string
+	endpoint   string
+	source     string
+	component  string
+	rules      []IncidentRule
+	cooldown   time.Duration
+	client     *http.Client
+	queueSize  int
+	sender     *hookSender
+	mu         sync.Mutex
+	lastFired  map[string]time.Time
+	suppressed int64
+}
+
+// IncidentHookOption 事件告警钩子配置选项
+type IncidentHookOption func(*IncidentHook)
+
+// WithIncidentRules 设置触发规则（替换默认规则）
+func WithIncidentRules(rules ...IncidentRule) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.rules = rules
+	}
+}
+
+// WithIncidentSource 设置来源（默认主机名）
+func WithIncidentSource(source string) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.source = source
+	}
+}
+
+// WithIncidentComponent 设置组件名（服务名）
+func WithIncidentComponent(component string) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.component = component
+	}
+}
+
+// WithIncidentEndpoint 设置接口地址（如 Opsgenie 欧洲区 https://api.eu.opsgenie.com/v2/alerts）
+func WithIncidentEndpoint(endpoint string) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.endpoint = endpoint
+	}
+}
+
+// WithIncidentCooldown 设置同一 dedup key 的冷却时间（默认 5 分钟）
+func WithIncidentCooldown(cooldown time.Duration) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.cooldown = cooldown
+	}
+}
+
+// WithIncidentHTTPClient 设置 HTTP 客户端
+func WithIncidentHTTPClient(client *http.Client) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.client = client
+	}
+}
+
+// WithIncidentQueueSize 设置发送队列长度（默认 256）
+func WithIncidentQueueSize(size int) IncidentHookOption {
+	return func(h *IncidentHook) {
+		h.queueSize = size
+	}
+}
+
+// NewIncidentHook 创建事件告警钩子，key 为 PagerDuty 的 routing key 或 Opsgenie 的 API key
+func NewIncidentHook(provider IncidentProvider, key string, opts ...IncidentHookOption) (*IncidentHook, error) {
+	h := &IncidentHook{
+		provider:  provider,
+		key:       key,
+		rules:     DefaultIncidentRules(),
+		cooldown:  5 * time.Minute,
+		lastFired: make(map[string]time.Time),
+	}
+	switch provider {
+	case IncidentPagerDuty:
+		h.endpoint = pagerDutyEventsURL
+	case IncidentOpsgenie:
+		h.endpoint = opsgenieAlertsURL
+	default:
+		return nil, fmt.Errorf("unsupported incident provider: %s", provider)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("incident %s key is empty", provider)
+	}
+	h.source, _ = os.Hostname()
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.sender = newHookSender(h.client, h.queueSize)
+	return h, nil
+}
+
+// Fire 实现 IHook 接口
+func (h *IncidentHook) Fire(entry *LogEntry) error {
+	rule, ok := h.match(entry)
+	if !ok {
+		return nil
+	}
+
+	dedupKey := rule.Name + "-" + entryFingerprint(entry)
+	if !h.allow(dedupKey) {
+		return nil
+	}
+
+	var (
+		body   []byte
+		err    error
+		header = http.Header{}
+	)
+	header.Set("Content-Type", "application/json")
+	if h.provider == IncidentOpsgenie {
+		header.Set("Authorization", "GenieKey "+h.key)
+		body, err = marshalJSON(h.opsgeniePayload(entry, rule, dedupKey))
+	} else {
+		body, err = marshalJSON(h.pagerDutyPayload(entry, rule, dedupKey))
+	}
+	if err != nil {
+		return err
+	}
+	return h.sender.enqueue(hookRequest{url: h.endpoint, header: header, body: body})
+}
+
+// Levels 实现 IHook 接口（由规则决定是否触发，对所有级别开放）
+func (h *IncidentHook) Levels() []LogLevel {
+	return nil
+}
+
+// match 返回第一条命中的规则
+func (h *IncidentHook) match(entry *LogEntry) (IncidentRule, bool) {
+	for _, rule := range h.rules {
+		if entry.Level >= rule.MinLevel && (rule.Match == nil || rule.Match(entry)) {
+			return rule, true
+		}
+	}
+	return IncidentRule{}, false
+}
+
+// allow 冷却期检查
+func (h *IncidentHook) allow(dedupKey string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := h.lastFired[dedupKey]; ok && now.Sub(last) < h.cooldown {
+		h.suppressed++
+		return false
+	}
+	h.lastFired[dedupKey] = now
+
+	// 清理过期记录，避免指纹过多时无限增长
+	if len(h.lastFired) > 1024 {
+		for k, t := range h.lastFired {
+			if now.Sub(t) >= h.cooldown {
+				delete(h.lastFired, k)
+			}
+		}
+	}
+	return true
+}
+
+// pagerDutyPayload 构建 PagerDuty Events API v2 请求体
+func (h *IncidentHook) pagerDutyPayload(entry *LogEntry, rule IncidentRule, dedupKey string) map[string]any {
+	payload := map[string]any{
+		"summary":   truncateString(entry.Message, 1024),
+		"source":    h.source,
+		"severity":  rule.Severity,
+		"timestamp": time.Unix(0, entry.Timestamp).UTC().Format(time.RFC3339Nano),
+	}
+	if h.component != "" {
+		payload["component"] = h.component
+	}
+	if len(entry.Fields) > 0 {
+		payload["custom_details"] = stringifyFields(entry.Fields)
+	}
+	return map[string]any{
+		"routing_key":  h.key,
+		"event_action": "trigger",
+		"dedup_key":    dedupKey,
+		"payload":      payload,
+	}
+}
+
+// opsgeniePayload 构建 Opsgenie 告警请求体
+func (h *IncidentHook) opsgeniePayload(entry *LogEntry, rule IncidentRule, dedupKey string) map[string]any {
+	payload := map[string]any{
+		"message":     truncateString(entry.Message, 130),
+		"alias":       dedupKey,
+		"description": entry.Message,
+		"priority":    rule.Priority,
+		"source":      h.source,
+		"tags":        []string{entry.Level.String(), rule.Name},
+	}
+	if h.component != "" {
+		payload["entity"] = h.component
+	}
+	if len(entry.Fields) > 0 {
+		payload["details"] = stringifyFields(entry.Fields)
+	}
+	return payload
+}
+
+// stringifyFields 将字段值统一转为字符串
+func stringifyFields(fields map[string]any) map[string]string {
+	result := make(map[string]string, len(fields))
+	for k, v := range fields {
+		result[k] = string(convert.AppendValue(nil, v))
+	}
+	return result
+}
+
+// truncateString 按 UTF-8 边界截断字符串，结果（含省略号）不超过 limit 字节
+func truncateString(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// Flush 等待已入队告警发送完成
+func (h *IncidentHook) Flush(ctx context.Context) error {
+	return h.sender.flush(ctx)
+}
+
+// Close 停止接收新告警并等待已入队告警发送完成
+func (h *IncidentHook) Close(ctx context.Context) error {
+	return h.sender.close(ctx)
+}
+
+// Stats 返回发送失败、因队列满丢弃和因冷却被抑制的告警数
+func (h *IncidentHook) Stats() (failed, dropped, suppressed int64) {
+	failed, dropped = h.sender.stats()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return failed, dropped, h.suppressed
+}
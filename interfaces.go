@@ -123,6 +123,19 @@ type ILogger interface {
 	ConsoleTime(label string) *Timer                         // 开始计时
 }
 
+// IBlockLogger 可选接口：块日志方法（标题 + 多行正文，结构化模式下为单条日志）
+// Logger、WithField 等返回的 ILogger 与 EmptyLogger 均实现该接口，持有 ILogger 时通过类型断言使用：
+//
+//	if bl, ok := log.(logger.IBlockLogger); ok {
+//		bl.InfoBlock("config", dump)
+//	}
+type IBlockLogger interface {
+	DebugBlock(title, body string)
+	InfoBlock(title, body string)
+	WarnBlock(title, body string)
+	ErrorBlock(title, body string)
+}
+
 // IAdapter 日志适配器接口
 type IAdapter interface {
 	ILogger
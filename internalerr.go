@@ -0,0 +1,115 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\internalerr.go
+ * @Description: 内部错误上报（钩子、中间件、输出、压缩等自身故障），默认写到标准错误
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// InternalError go-logger 自身运行中产生的错误
+type InternalError struct {
+	Time      time.Time
+	Component string // 出错组件：hook、pipeline、output、audit、compress、writer 等
+	Err       error
+}
+
+// Error 实现 error 接口
+func (e InternalError) Error() string {
+	return e.Component + ": " + e.Err.Error()
+}
+
+// Unwrap 返回原始错误
+func (e InternalError) Unwrap() error {
+	return e.Err
+}
+
+// InternalErrorHandler 内部错误处理函数
+type InternalErrorHandler func(InternalError)
+
+// internalErrorHandler 当前处理函数（nil 表示使用默认处理）
+var internalErrorHandler atomic.Pointer[InternalErrorHandler]
+
+// SetInternalErrorHandler 设置内部错误处理函数，传入 nil 恢复默认（写到标准错误，每个组件每秒最多一条）
+// 处理函数可能在日志调用方或后台协程中被调用，不应再通过同一个 Logger 同步写日志
+func SetInternalErrorHandler(handler InternalErrorHandler) {
+	if handler == nil {
+		internalErrorHandler.Store(nil)
+		return
+	}
+	internalErrorHandler.Store(&handler)
+}
+
+// reportInternalError 上报内部错误
+func reportInternalError(component string, err error) {
+	if err == nil {
+		return
+	}
+	e := InternalError{Time: time.Now(), Component: component, Err: err}
+	lastInternalErrors.Store(component, e)
+	if h := internalErrorHandler.Load(); h != nil {
+		(*h)(e)
+		return
+	}
+	lastResort.report(e)
+}
+
+// lastInternalErrors 各组件最近一次内部错误（组件名 → InternalError），供健康检查使用
+var lastInternalErrors sync.Map
+
+// LastInternalError 返回组件最近一次内部错误（组件名与适配器名一致，如 datadog、nats、fluent）
+func LastInternalError(component string) (InternalError, bool) {
+	v, ok := lastInternalErrors.Load(component)
+	if !ok {
+		return InternalError{}, false
+	}
+	return v.(InternalError), true
+}
+
+// lastResortWriter 默认内部错误输出（按组件限频，被限频的条数在下一条中注明）
+type lastResortWriter struct {
+	mu         sync.Mutex
+	output     io.Writer
+	interval   time.Duration
+	last       map[string]time.Time
+	suppressed map[string]int
+}
+
+var lastResort = &lastResortWriter{
+	output:     os.Stderr,
+	interval:   time.Second,
+	last:       make(map[string]time.Time),
+	suppressed: make(map[string]int),
+}
+
+// report 写出内部错误
+func (w *lastResortWriter) report(e InternalError) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if last, ok := w.last[e.Component]; ok && e.Time.Sub(last) < w.interval {
+		w.suppressed[e.Component]++
+		return
+	}
+	w.last[e.Component] = e.Time
+
+	if n := w.suppressed[e.Component]; n > 0 {
+		fmt.Fprintf(w.output, "go-logger: %s [%s] %v (%d similar errors suppressed)\n",
+			e.Time.Format(time.RFC3339), e.Component, e.Err, n)
+		w.suppressed[e.Component] = 0
+		return
+	}
+	fmt.Fprintf(w.output, "go-logger: %s [%s] %v\n", e.Time.Format(time.RFC3339), e.Component, e.Err)
+}
@@ -0,0 +1,58 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\jsonengine.go
+ * @Description: 可替换的 JSON 编码引擎（默认 encoding/json，可通过构建标签启用 jsoniter / sonic）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/json"
+	"sync/atomic"
+)
+
+// JSONEngine JSON 编解码引擎，实现需与 encoding/json 的行为兼容（结构体标签、map 键排序等）
+type JSONEngine interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdJSONEngine 基于 encoding/json 的默认引擎
+type stdJSONEngine struct{}
+
+func (stdJSONEngine) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (stdJSONEngine) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// StdJSONEngine 标准库 encoding/json 引擎
+var StdJSONEngine JSONEngine = stdJSONEngine{}
+
+var jsonEngine atomic.Pointer[JSONEngine]
+
+func init() {
+	engine := StdJSONEngine
+	jsonEngine.Store(&engine)
+}
+
+// SetJSONEngine 设置结构化路径（钩子载荷、NDJSON 编码等）使用的 JSON 引擎，nil 表示恢复 encoding/json
+// 以 -tags jsoniter 或 -tags sonic 构建时在 init 中自动切换为对应引擎（需先 go get 对应依赖）
+// 审计日志的签名依赖逐字节稳定的编码，始终使用 encoding/json
+func SetJSONEngine(engine JSONEngine) {
+	if engine == nil {
+		engine = StdJSONEngine
+	}
+	jsonEngine.Store(&engine)
+}
+
+// GetJSONEngine 获取当前的 JSON 引擎
+func GetJSONEngine() JSONEngine {
+	return *jsonEngine.Load()
+}
+
+// marshalJSON 使用当前引擎编码
+func marshalJSON(v any) ([]byte, error) {
+	return GetJSONEngine().Marshal(v)
+}
@@ -0,0 +1,22 @@
+//go:build jsoniter && !sonic
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\jsonengine_jsoniter.go
+ * @Description: jsoniter JSON 引擎（go get github.com/json-iterator/go 后以 -tags jsoniter 构建启用）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import jsoniter "github.com/json-iterator/go"
+
+// JsoniterEngine 与 encoding/json 兼容配置的 jsoniter 引擎
+var JsoniterEngine JSONEngine = jsoniter.ConfigCompatibleWithStandardLibrary
+
+func init() {
+	SetJSONEngine(JsoniterEngine)
+}
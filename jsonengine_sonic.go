@@ -0,0 +1,22 @@
+//go:build sonic
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\jsonengine_sonic.go
+ * @Description: sonic JSON 引擎（go get github.com/bytedance/sonic 后以 -tags sonic 构建启用，仅支持 amd64/arm64）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "github.com/bytedance/sonic"
+
+// SonicEngine 与 encoding/json 兼容配置（map 键排序、HTML 转义）的 sonic 引擎
+var SonicEngine JSONEngine = sonic.ConfigStd
+
+func init() {
+	SetJSONEngine(SonicEngine)
+}
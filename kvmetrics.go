@@ -0,0 +1,283 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\kvmetrics.go
+ * @Description: 键值指标聚合（按消息统计约定字段的数值，如 duration_ms、bytes），无需指标库即可获得基础耗时统计
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BytesKey 字节数约定字段名
+const BytesKey = "bytes"
+
+// kvSample 数值样本
+type kvSample struct {
+	at    time.Time
+	value float64
+}
+
+// kvSeries 某条消息某个字段的样本环
+type kvSeries struct {
+	message  string
+	key      string
+	samples  []kvSample
+	next     int
+	lastSeen time.Time
+}
+
+// KVMetricStats 滚动窗口内的统计
+type KVMetricStats struct {
+	Message  string    `json:"message"`
+	Key      string    `json:"key"`
+	Count    int       `json:"count"`
+	Sum      float64   `json:"sum"`
+	Min      float64   `json:"min"`
+	Max      float64   `json:"max"`
+	Mean     float64   `json:"mean"`
+	P50      float64   `json:"p50"`
+	P95      float64   `json:"p95"`
+	P99      float64   `json:"p99"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// KVMetrics 键值指标聚合钩子
+// 监听日志字段中约定键的数值，按（消息, 键）维护最近若干个样本，统计时只计入滚动窗口内的样本
+type KVMetrics struct {
+	mu         sync.Mutex
+	keys       map[string]struct{}
+	window     time.Duration
+	maxSamples int
+	maxSeries  int
+	series     map[[2]string]*kvSeries
+}
+
+// KVMetricsOption 键值指标聚合配置选项
+type KVMetricsOption func(*KVMetrics)
+
+// WithKVMetricKeys 设置统计的字段名（默认 duration_ms、bytes）
+func WithKVMetricKeys(keys ...string) KVMetricsOption {
+	return func(m *KVMetrics) {
+		m.keys = make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			m.keys[key] = struct{}{}
+		}
+	}
+}
+
+// WithKVMetricWindow 设置滚动窗口（默认 5 分钟）
+func WithKVMetricWindow(window time.Duration) KVMetricsOption {
+	return func(m *KVMetrics) {
+		if window > 0 {
+			m.window = window
+		}
+	}
+}
+
+// WithKVMetricMaxSamples 设置每个统计序列保留的样本数（默认 1024）
+func WithKVMetricMaxSamples(n int) KVMetricsOption {
+	return func(m *KVMetrics) {
+		if n > 0 {
+			m.maxSamples = n
+		}
+	}
+}
+
+// WithKVMetricMaxSeries 设置最大统计序列数（默认 500），超过时淘汰最久未出现的序列
+func WithKVMetricMaxSeries(n int) KVMetricsOption {
+	return func(m *KVMetrics) {
+		if n > 0 {
+			m.maxSeries = n
+		}
+	}
+}
+
+// NewKVMetrics 创建键值指标聚合钩子，通过 Logger.WithHooks 启用
+func NewKVMetrics(opts ...KVMetricsOption) *KVMetrics {
+	m := &KVMetrics{
+		keys:       map[string]struct{}{DurationMsKey: {}, BytesKey: {}},
+		window:     5 * time.Minute,
+		maxSamples: 1024,
+		maxSeries:  500,
+		series:     make(map[[2]string]*kvSeries),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Fire 实现 IHook 接口
+func (m *KVMetrics) Fire(entry *LogEntry) error {
+	if len(entry.Fields) == 0 {
+		return nil
+	}
+	now := time.Unix(0, entry.Timestamp)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key := range m.keys {
+		raw, ok := entry.Fields[key]
+		if !ok {
+			continue
+		}
+		value, ok := kvNumber(raw)
+		if !ok {
+			continue
+		}
+		m.observeLocked(entry.Message, key, now, value)
+	}
+	return nil
+}
+
+// Levels 实现 IHook 接口（所有级别）
+func (m *KVMetrics) Levels() []LogLevel {
+	return nil
+}
+
+// observeLocked 记录样本（调用方持有锁）
+func (m *KVMetrics) observeLocked(message, key string, at time.Time, value float64) {
+	id := [2]string{message, key}
+	s, ok := m.series[id]
+	if !ok {
+		if len(m.series) >= m.maxSeries {
+			m.evictLocked()
+		}
+		s = &kvSeries{message: message, key: key, samples: make([]kvSample, 0, 16)}
+		m.series[id] = s
+	}
+	sample := kvSample{at: at, value: value}
+	if len(s.samples) < m.maxSamples {
+		s.samples = append(s.samples, sample)
+	} else {
+		s.samples[s.next] = sample
+		s.next = (s.next + 1) % m.maxSamples
+	}
+	s.lastSeen = at
+}
+
+// evictLocked 淘汰最久未出现的序列（调用方持有锁）
+func (m *KVMetrics) evictLocked() {
+	var oldest [2]string
+	var oldestSeen time.Time
+	first := true
+	for id, s := range m.series {
+		if first || s.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen, first = id, s.lastSeen, false
+		}
+	}
+	delete(m.series, oldest)
+}
+
+// Snapshot 返回滚动窗口内有样本的统计（按消息、字段名排序）
+func (m *KVMetrics) Snapshot() []KVMetricStats {
+	cutoff := time.Now().Add(-m.window)
+
+	m.mu.Lock()
+	result := make([]KVMetricStats, 0, len(m.series))
+	var values []float64
+	for _, s := range m.series {
+		values = values[:0]
+		for _, sample := range s.samples {
+			if !sample.at.Before(cutoff) {
+				values = append(values, sample.value)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		result = append(result, kvStats(s.message, s.key, s.lastSeen, values))
+	}
+	m.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Message != result[j].Message {
+			return result[i].Message < result[j].Message
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Reset 清空统计
+func (m *KVMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.series = make(map[[2]string]*kvSeries)
+}
+
+// kvStats 计算统计值（会对 values 排序）
+func kvStats(message, key string, lastSeen time.Time, values []float64) KVMetricStats {
+	sort.Float64s(values)
+	stats := KVMetricStats{
+		Message:  message,
+		Key:      key,
+		Count:    len(values),
+		Min:      values[0],
+		Max:      values[len(values)-1],
+		P50:      kvPercentile(values, 0.50),
+		P95:      kvPercentile(values, 0.95),
+		P99:      kvPercentile(values, 0.99),
+		LastSeen: lastSeen,
+	}
+	for _, v := range values {
+		stats.Sum += v
+	}
+	stats.Mean = stats.Sum / float64(len(values))
+	return stats
+}
+
+// kvPercentile 最近秩法分位数（values 已排序）
+func kvPercentile(values []float64, q float64) float64 {
+	rank := int(math.Ceil(q*float64(len(values)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return values[rank]
+}
+
+// kvNumber 将字段值转换为浮点数（支持整数、浮点数、json.Number 与 time.Duration（毫秒））
+func kvNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case time.Duration:
+		return float64(n) / float64(time.Millisecond), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -12,6 +12,8 @@ package logger
 
 import (
 	"fmt"
+	"iter"
+	"slices"
 	"sort"
 	"strings"
 )
@@ -257,6 +259,24 @@ func (l LogLevel) IsEnabled(target LogLevel) bool {
 	return target.Priority() >= l.Priority()
 }
 
+// Enabled 级别 l 是否达到最低级别 min（按优先级比较，与 IsEnabled 的接收者方向相反）：
+//
+//	if entry.Level.Enabled(logger.WARN) { ... }
+func (l LogLevel) Enabled(min LogLevel) bool {
+	return l.Priority() >= min.Priority()
+}
+
+// Compare 按优先级比较两个级别，l 较低时返回 -1，相同返回 0，较高返回 1（可用于 slices.SortFunc）
+func (l LogLevel) Compare(other LogLevel) int {
+	switch lp, op := l.Priority(), other.Priority(); {
+	case lp < op:
+		return -1
+	case lp > op:
+		return 1
+	}
+	return 0
+}
+
 // IsBasic 检查是否为基础级别
 func (l LogLevel) IsBasic() bool {
 	return l.Category() == "basic"
@@ -305,6 +325,40 @@ func GetAllLevels() []LogLevel {
 	return []LogLevel{DEBUG, INFO, WARN, ERROR, FATAL}
 }
 
+// sortedLevels 按优先级排序的全部可记录级别（不含 OFF）
+var sortedLevels = func() []LogLevel {
+	levels := make([]LogLevel, 0, len(levelInfoMap))
+	for level := range levelInfoMap {
+		if level != OFF {
+			levels = append(levels, level)
+		}
+	}
+	slices.SortFunc(levels, LogLevel.Compare)
+	return levels
+}()
+
+// AllLevels 按优先级从低到高遍历全部可记录级别（包括扩展级别，不含 OFF）：
+//
+//	for level := range logger.AllLevels() {
+//		fmt.Println(level, level.Category())
+//	}
+func AllLevels() iter.Seq[LogLevel] {
+	return slices.Values(sortedLevels)
+}
+
+// LevelsAtLeast 返回不低于 min 的基础级别（TRACE 至 FATAL），便于钩子实现 Levels：
+//
+//	func (h *AlertHook) Levels() []logger.LogLevel { return logger.LevelsAtLeast(logger.ERROR) }
+func LevelsAtLeast(min LogLevel) []LogLevel {
+	var levels []LogLevel
+	for _, level := range []LogLevel{TRACE, DEBUG, INFO, WARN, ERROR, FATAL} {
+		if level.Enabled(min) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
 // GetAllExtendedLevels 获取所有级别（包括扩展级别）
 func GetAllExtendedLevels() []LogLevel {
 	var levels []LogLevel
@@ -0,0 +1,183 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\levelfield.go
+ * @Description: 按日志级别条件附加的字段（如完整请求体只在 DEBUG 日志中输出）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+)
+
+// LevelField 仅在日志级别不高于 MaxLevel 时附加的字段，可作为键值对中的独立元素或字段值使用：
+//
+//	log.InfoKV("request", "path", path, logger.DebugField("body", body)) // INFO 日志不含 body
+//	log.DebugKV("request", "path", path, logger.DebugField("body", body)) // DEBUG 日志包含 body
+type LevelField struct {
+	Key      string
+	Value    any
+	MaxLevel LogLevel
+}
+
+// DebugField 仅在 DEBUG（及 TRACE）日志中附加的字段
+func DebugField(key string, value any) LevelField {
+	return LevelField{Key: key, Value: value, MaxLevel: DEBUG}
+}
+
+// FieldUpTo 仅在级别不高于 level 的日志中附加的字段
+func FieldUpTo(level LogLevel, key string, value any) LevelField {
+	return LevelField{Key: key, Value: value, MaxLevel: level}
+}
+
+// String 实现 fmt.Stringer
+func (f LevelField) String() string {
+	return fmt.Sprint(f.Value)
+}
+
+// allows 字段是否附加到 level 级别的日志
+func (f LevelField) allows(level LogLevel) bool {
+	return level <= f.MaxLevel
+}
+
+// WithDebugField 返回子 Logger，其 DEBUG（及 TRACE）日志附加该字段，更高级别的日志不附加
+func (l *Logger) WithDebugField(key string, value any) *Logger {
+	return l.WithLevelFields(DebugField(key, value))
+}
+
+// WithLevelFields 返回子 Logger，按各字段的 MaxLevel 有条件地附加到每条日志
+func (l *Logger) WithLevelFields(fields ...LevelField) *Logger {
+	child := l.Clone().(*Logger)
+	child.levelFields = append(l.levelFields[:len(l.levelFields):len(l.levelFields)], fields...)
+	return child
+}
+
+// splitLevelFields 从键值对中分离出 LevelField（With 使用），没有时原样返回
+func splitLevelFields(keysAndValues []any) ([]any, []LevelField) {
+	if !hasLevelField(keysAndValues) {
+		return keysAndValues, nil
+	}
+	var leveled []LevelField
+	kv := make([]any, 0, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i++ {
+		switch v := keysAndValues[i].(type) {
+		case LevelField:
+			leveled = append(leveled, v)
+		case Field:
+			kv = append(kv, v)
+		default:
+			if _, ok := v.(string); !ok || i+1 >= len(keysAndValues) {
+				kv = append(kv, v)
+				break
+			}
+			if lf, ok := keysAndValues[i+1].(LevelField); ok {
+				leveled = append(leveled, LevelField{Key: fieldKey(v), Value: lf.Value, MaxLevel: lf.MaxLevel})
+			} else {
+				kv = append(kv, v, keysAndValues[i+1])
+			}
+			i++
+		}
+	}
+	return kv, leveled
+}
+
+// hasLevelField 键值对中是否含有 LevelField
+func hasLevelField(keysAndValues []any) bool {
+	for _, v := range keysAndValues {
+		if _, ok := v.(LevelField); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// gateKV 按日志级别处理键值对中的 LevelField：允许的展开为普通键值对，不允许的移除；没有时原样返回
+func gateKV(level LogLevel, keysAndValues []any) []any {
+	if !hasLevelField(keysAndValues) {
+		return keysAndValues
+	}
+	kv := make([]any, 0, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i++ {
+		switch v := keysAndValues[i].(type) {
+		case LevelField:
+			if v.allows(level) {
+				kv = append(kv, v.Key, v.Value)
+			}
+		case Field:
+			kv = append(kv, v)
+		default:
+			if _, ok := v.(string); !ok || i+1 >= len(keysAndValues) {
+				kv = append(kv, v)
+				break
+			}
+			if lf, ok := keysAndValues[i+1].(LevelField); ok {
+				if lf.allows(level) {
+					kv = append(kv, v, lf.Value)
+				}
+			} else {
+				kv = append(kv, v, keysAndValues[i+1])
+			}
+			i++
+		}
+	}
+	return kv
+}
+
+// hasLevelFieldValue 字段映射中是否含有 LevelField 值
+func hasLevelFieldValue(fields map[string]any) bool {
+	for _, v := range fields {
+		if _, ok := v.(LevelField); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// gateFields 按日志级别处理字段映射中的 LevelField 值，没有时原样返回
+func gateFields(level LogLevel, fields map[string]any) map[string]any {
+	if !hasLevelFieldValue(fields) {
+		return fields
+	}
+
+	result := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if lf, ok := v.(LevelField); ok {
+			if lf.allows(level) {
+				result[k] = lf.Value
+			}
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// appendLevelFields 追加允许在 level 级别输出的条件字段，sep 表示之前已有字段
+func (l *Logger) appendLevelFields(buf []byte, level LogLevel, sep bool) ([]byte, bool) {
+	var wrote bool
+	for _, f := range l.levelFields {
+		if !f.allows(level) {
+			continue
+		}
+		var ok bool
+		buf, ok = l.appendField(buf, sep || wrote, f.Key, f.Value)
+		wrote = wrote || ok
+	}
+	return buf, wrote
+}
+
+// collectLevelFields 将允许在 level 级别输出的条件字段写入字段映射
+func (l *Logger) collectLevelFields(dst map[string]any, level LogLevel) {
+	for _, f := range l.levelFields {
+		if !f.allows(level) {
+			continue
+		}
+		if resolved, ok := l.resolveField(f.Key, f.Value); ok {
+			dst[f.Key] = resolved
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\loggederror.go
+ * @Description: *Return 系列方法返回的结构化错误（包装原始错误，携带字段与指纹）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "fmt"
+
+// LoggedError *Return 系列方法返回的错误：消息与日志一致，携带本次日志的字段与消息指纹，
+// 并包装参数中的第一个 error，可直接用于 errors.Is / errors.As：
+//
+//	if err := db.Query(); err != nil {
+//		return log.ErrorReturn("query users: %v", err) // errors.Is(返回值, sql.ErrNoRows) 仍然成立
+//	}
+type LoggedError struct {
+	Level       LogLevel       // 日志级别
+	Message     string         // 日志消息
+	Fields      map[string]any // 键值对、上下文与 WithField 字段（已按字段策略处理）
+	Fingerprint string         // 消息模板指纹，见 Fingerprint
+	Err         error          // 被包装的原始错误（参数中没有 error 时为 nil）
+}
+
+// Error 实现 error 接口
+func (e *LoggedError) Error() string {
+	return e.Message
+}
+
+// Unwrap 返回被包装的原始错误
+func (e *LoggedError) Unwrap() error {
+	return e.Err
+}
+
+// newLoggedError 构建 LoggedError，template 为计算指纹的消息模板
+func newLoggedError(level LogLevel, msg, template string, fields map[string]any, args, keysAndValues []any) *LoggedError {
+	e := &LoggedError{
+		Level:       level,
+		Message:     msg,
+		Fields:      fields,
+		Fingerprint: Fingerprint(template),
+		Err:         firstError(args),
+	}
+	if e.Err == nil {
+		e.Err = firstError(keysAndValues)
+	}
+	return e
+}
+
+// returnMessage 格式化 *Return 方法的消息：没有参数时原样使用 format，避免消息中的 % 被误解析；
+// 有参数时按 fmt.Errorf 规则格式化（支持 %w）
+func returnMessage(format string, args []any) string {
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Errorf(format, args...).Error()
+}
+
+// firstError 返回参数中的第一个 error
+func firstError(args []any) error {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newReturnError 构建 Logger 的 *Return 方法返回的错误，键值对按字段策略收集为字段
+func (l *Logger) newReturnError(level LogLevel, format string, args, keysAndValues []any) *LoggedError {
+	var fields map[string]any
+	if objFields := singleObjectFields(keysAndValues); objFields != nil {
+		fields = make(map[string]any, len(objFields))
+		for k, v := range objFields {
+			l.collectField(fields, k, v)
+		}
+	} else if len(keysAndValues) > 0 {
+		fields = make(map[string]any, len(keysAndValues)/2)
+		l.collectKV(fields, gateKV(level, keysAndValues))
+	}
+	return newLoggedError(level, returnMessage(format, args), format, fields, args, keysAndValues)
+}
+
+// newReturnError 同 Logger.newReturnError，附带 WithField 设置的字段
+func (f *fieldLogger) newReturnError(level LogLevel, format string, args, keysAndValues []any) *LoggedError {
+	e := f.logger.newReturnError(level, format, args, keysAndValues)
+	fields := make(map[string]any, len(f.fields)+len(e.Fields))
+	for k, v := range gateFields(level, f.fields) {
+		f.logger.collectField(fields, k, v)
+	}
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	e.Fields = fields
+	return e
+}
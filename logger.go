@@ -13,10 +13,7 @@ package logger
 import (
 	"context"
 	"fmt"
-	"os"
-	"runtime"
-	"strings"
-	"sync"
+	"slices"
 	"time"
 
 	"github.com/kamalyes/go-toolbox/pkg/convert"
@@ -33,26 +30,22 @@ const (
 	estimatedContextSize = 100  // 预估的上下文信息大小（TraceID 等）
 )
 
-// 字节池 - 用于日志消息构建
-var bytePool = sync.Pool{
-	New: func() any {
-		return make([]byte, 0, maxLogMessageSize)
-	},
-}
+// 字节池 - 用于日志消息构建（存放切片指针，避免 Put 时装箱产生分配）
+var bytePool = newStatPool("line", func() any {
+	buf := make([]byte, 0, maxLogMessageSize)
+	return &buf
+})
 
 // 上下文信息池 - 用于构建上下文字符串
-var contextPool = sync.Pool{
-	New: func() any {
-		return make([]byte, 0, estimatedContextSize)
-	},
-}
+var contextPool = newStatPool("context", func() any {
+	buf := make([]byte, 0, estimatedContextSize)
+	return &buf
+})
 
 // fieldMap 池 - 用于 fieldLogger 的 map 复用
-var fieldMapPool = sync.Pool{
-	New: func() any {
-		return make(map[string]any, 8) // 预分配常见大小
-	},
-}
+var fieldMapPool = newStatPool("fields", func() any {
+	return make(map[string]any, 8) // 预分配常见大小
+})
 
 // 预计算的常量字节切片
 var (
@@ -75,7 +68,6 @@ var (
 	kvDelimiter  = []byte(", ")
 	kvBraceOpen  = []byte(" {")
 	kvBraceClose = []byte("}")
-	kvMissing    = []byte("<missing>")
 )
 
 var (
@@ -107,16 +99,17 @@ type ContextExtractor func(ctx context.Context) string
 // Logger 结构体和初始化
 // ============================================================================
 
-// defaultLogger 默认日志记录器
-var defaultLogger *Logger
-
-func init() {
-	defaultLogger = NewLogger()
-}
-
-// New 创建新的日志记录器（简化版本）
-func New() *Logger {
-	return NewLogger()
+// New 创建新的日志记录器，可传入函数式选项：
+//
+//	log := logger.New(logger.WithLevel(logger.DEBUG), logger.WithJSON(), logger.WithOutput(w))
+func New(opts ...Option) *Logger {
+	l := NewLogger()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(l)
+		}
+	}
+	return l
 }
 
 // ultraLog 极致优化的日志方法（使用字节池和零拷贝）
@@ -124,13 +117,37 @@ func (l *Logger) ultraLog(level LogLevel, msg string) {
 	if level < l.level {
 		return
 	}
+	if l.hasPipeline() {
+		l.dispatch(level, msg, msg, nil, nil, 3)
+		return
+	}
+
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 3)
+
+	// 添加消息
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(msg)...)
+	buf = l.limitMessage(buf, msgStart)
+	if l.hasStaticFields() {
+		buf = l.appendFieldBlock(buf, level, nil, nil)
+	}
+	buf = append(buf, newline...)
+
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
+}
 
-	buf := bytePool.Get().([]byte)
-	buf = buf[:0]
-	defer bytePool.Put(buf)
+// appendHeader 追加时间戳、前缀、级别前缀和调用者信息
+// skip 为相对于 appendHeader 调用方的调用栈层数
+func (l *Logger) appendHeader(buf []byte, level LogLevel, skip int) []byte {
+	return l.appendHeaderAt(buf, level, l.now(), skip+1)
+}
 
+// appendHeaderAt 同 appendHeader，使用指定的时间戳
+func (l *Logger) appendHeaderAt(buf []byte, level LogLevel, t time.Time, skip int) []byte {
 	// 添加时间戳
-	buf = stringx.FastFormatTime(buf, time.Now())
+	buf = stringx.FastFormatTime(buf, t)
 
 	// 添加前缀（如果有）
 	if l.prefix != "" {
@@ -143,38 +160,72 @@ func (l *Logger) ultraLog(level LogLevel, msg string) {
 
 	// 添加调用者信息（如果需要）
 	if l.showCaller {
-		if pc, file, line, ok := runtime.Caller(3); ok {
-			funcName := runtime.FuncForPC(pc).Name()
-			if idx := strings.LastIndex(funcName, "."); idx != -1 {
-				funcName = funcName[idx+1:]
-			}
-			if idx := strings.LastIndex(file, "/"); idx != -1 {
-				file = file[idx+1:]
-			}
-			buf = append(buf, '[')
-			buf = append(buf, convert.S2B(file)...)
-			buf = append(buf, ':')
-			buf = stringx.FastAppendInt(buf, line)
-			buf = append(buf, ':')
-			buf = append(buf, convert.S2B(funcName)...)
-			buf = append(buf, ']', ' ')
+		if f, ok := lookupCaller(skip + 1 + l.callerSkip); ok {
+			buf = append(buf, f.header...)
 		}
 	}
 
-	// 添加消息
-	buf = append(buf, convert.S2B(msg)...)
-	buf = append(buf, newline...)
+	return buf
+}
 
-	// 写入输出
-	l.mu.Lock()
-	l.output.Write(buf)
-	l.mu.Unlock()
+// writeLine 将构建完成的整行写入输出
+func (l *Logger) writeLine(level LogLevel, line []byte) {
+	l.writeOutput(level, line)
 
 	if level == FATAL {
-		os.Exit(1)
+		l.exitFatal()
 	}
 }
 
+// writeOutput 写入输出（首错误范围内重复的错误与超出配额的日志丢弃，绑定请求缓冲时低级别日志先进入缓冲）
+func (l *Logger) writeOutput(level LogLevel, line []byte) {
+	if l.buildInfoPending != nil {
+		l.writeBuildInfo()
+	}
+	if l.errScope != nil && l.errScope.suppress(level) {
+		return
+	}
+	if l.quota != nil && !l.admitQuota(line) {
+		return
+	}
+	if l.reqBuffer != nil && l.reqBuffer.hold(level, line) {
+		return
+	}
+	l.writeRaw(level, line)
+}
+
+// writeRaw 直接写入输出（开启异步时优先进入异步队列，按级别分发的输出只写入阈值满足的目标）
+func (l *Logger) writeRaw(level LogLevel, line []byte) {
+	if l.async == nil || !l.async.write(level, line) {
+		var err error
+		l.mu.Lock()
+		if router, ok := l.output.(*outputRouter); ok {
+			_, err = router.WriteLevel(level, line)
+		} else {
+			_, err = l.output.Write(line)
+		}
+		l.mu.Unlock()
+		if err != nil {
+			reportInternalError("output", err)
+		}
+	}
+}
+
+// writeNotice 直接写出一行由 go-logger 自身生成的日志（不含调用者信息，保留静态字段，不经过配额与请求缓冲）
+func (l *Logger) writeNotice(level LogLevel, msg []byte, keysAndValues []any) {
+	bp := bytePool.Get().(*[]byte)
+	buf := stringx.FastFormatTime((*bp)[:0], l.now())
+	if l.prefix != "" {
+		buf = append(buf, convert.S2B(l.prefix)...)
+	}
+	buf = append(buf, mathx.IF(l.colorful, levelPrefixesColor[level], levelPrefixes[level])...)
+	buf = append(buf, msg...)
+	buf = l.appendFieldBlock(buf, level, keysAndValues, nil)
+	buf = append(buf, newline...)
+	l.writeRaw(level, buf)
+	putLineBuf(bp, buf)
+}
+
 // ultraLogf 极致优化的格式化日志方法
 func (l *Logger) ultraLogf(level LogLevel, format string, args ...any) {
 	if level < l.level {
@@ -187,14 +238,24 @@ func (l *Logger) ultraLogf(level LogLevel, format string, args ...any) {
 		return
 	}
 
-	// 有参数时才进行格式化
-	msg := fmt.Sprintf(format, args...)
-	l.ultraLog(level, msg)
-}
+	if l.hasPipeline() {
+		l.dispatch(level, fmt.Sprintf(format, args...), format, nil, nil, 2)
+		return
+	}
+
+	// 有参数时直接格式化进缓冲区，省去中间字符串
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 2)
+	msgStart := len(buf)
+	buf = fmt.Appendf(buf, format, args...)
+	buf = l.limitMessage(buf, msgStart)
+	if l.hasStaticFields() {
+		buf = l.appendFieldBlock(buf, level, nil, nil)
+	}
+	buf = append(buf, newline...)
 
-// log 记录日志 - 使用 ultraLogf 提升性能
-func (l *Logger) log(level LogLevel, format string, args ...any) {
-	l.ultraLogf(level, format, args...)
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
 }
 
 // Debug 调试日志
@@ -269,10 +330,26 @@ func (l *Logger) Fatalf(format string, args ...any) {
 
 // WithField 添加字段信息（结构化日志）
 func (l *Logger) WithField(key string, value any) ILogger {
-	return &fieldLogger{
-		logger: l,
-		fields: map[string]any{key: value},
+	return newFieldLogger(l, map[string]any{key: value})
+}
+
+// With 创建携带静态字段的子 Logger
+// 字段在创建时只编码一次，后续每条日志直接复用编码后的字节，适合请求级上下文
+func (l *Logger) With(keysAndValues ...any) *Logger {
+	child := l.Clone().(*Logger)
+	keysAndValues, leveled := splitLevelFields(keysAndValues)
+	if len(leveled) > 0 {
+		child.levelFields = append(l.levelFields[:len(l.levelFields):len(l.levelFields)], leveled...)
+	}
+	if len(keysAndValues) == 0 {
+		return child
 	}
+
+	child.staticKV = append(l.staticKV[:len(l.staticKV):len(l.staticKV)], keysAndValues...)
+	static := make([]byte, 0, len(l.staticFields)+len(keysAndValues)*16)
+	static = append(static, l.staticFields...)
+	child.staticFields, _ = l.appendKVPairs(static, keysAndValues, len(static) > 0)
+	return child
 }
 
 // WithFields 添加多个字段信息（结构化日志）
@@ -281,10 +358,7 @@ func (l *Logger) WithFields(fields map[string]any) ILogger {
 		return l
 	}
 
-	return &fieldLogger{
-		logger: l,
-		fields: fields,
-	}
+	return newFieldLogger(l, fields)
 }
 
 // WithError 添加错误信息
@@ -376,11 +450,14 @@ func (l *Logger) GetContextExtractor() ContextExtractor {
 	return l.contextExtractor
 }
 
-// extractContextInfo 从上下文中提取信息
+// extractContextInfo 从上下文中提取信息（ctx 已由 WithContext 绑定时，其 key 已转为静态字段）
 func (l *Logger) extractContextInfo(ctx context.Context) string {
 	if ctx == nil {
 		return ""
 	}
+	if l.contextExtractor == nil && ctx == l.context {
+		return ""
+	}
 	if l.contextExtractor != nil {
 		return l.contextExtractor(ctx)
 	}
@@ -396,6 +473,10 @@ func (l *Logger) DebugContext(ctx context.Context, format string, args ...any) {
 	if contextInfo != "" {
 		format = contextInfo + format
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(DEBUG, fmt.Sprintf(format, args...), kv...)
+		return
+	}
 	l.ultraLogf(DEBUG, format, args...)
 }
 
@@ -407,6 +488,10 @@ func (l *Logger) InfoContext(ctx context.Context, format string, args ...any) {
 	if contextInfo != "" {
 		format = contextInfo + format
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(INFO, fmt.Sprintf(format, args...), kv...)
+		return
+	}
 	l.ultraLogf(INFO, format, args...)
 }
 
@@ -418,6 +503,10 @@ func (l *Logger) WarnContext(ctx context.Context, format string, args ...any) {
 	if contextInfo != "" {
 		format = contextInfo + format
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(WARN, fmt.Sprintf(format, args...), kv...)
+		return
+	}
 	l.ultraLogf(WARN, format, args...)
 }
 
@@ -425,18 +514,28 @@ func (l *Logger) ErrorContext(ctx context.Context, format string, args ...any) {
 	if l.level > ERROR {
 		return
 	}
+	l = l.spanScope(ctx, ERROR)
 	contextInfo := l.extractContextInfo(ctx)
 	if contextInfo != "" {
 		format = contextInfo + format
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(ERROR, fmt.Sprintf(format, args...), kv...)
+		return
+	}
 	l.ultraLogf(ERROR, format, args...)
 }
 
 func (l *Logger) FatalContext(ctx context.Context, format string, args ...any) {
+	l = l.spanScope(ctx, FATAL)
 	contextInfo := l.extractContextInfo(ctx)
 	if contextInfo != "" {
 		format = contextInfo + format
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(FATAL, fmt.Sprintf(format, args...), kv...)
+		return
+	}
 	l.ultraLogf(FATAL, format, args...)
 }
 
@@ -444,85 +543,236 @@ func (l *Logger) FatalContext(ctx context.Context, format string, args ...any) {
 // 键值对和结构化日志辅助方法
 // ============================================================================
 
-// logWithKV 极简键值对实现 - 零分配优化
+// logWithKV 极简键值对实现 - 键值对直接编码进缓冲区
 func (l *Logger) logWithKV(level LogLevel, msg string, keysAndValues ...any) {
+	l.logSkip(1, level, msg, keysAndValues)
+}
+
+// logSkip 记录键值对日志，调用位置为调用 logSkip 的函数再向上跳过 skip 层（0 表示该函数的调用方）
+// 单个对象参数（分级字段与条件字段除外）解析为字段映射
+func (l *Logger) logSkip(skip int, level LogLevel, msg string, keysAndValues []any) {
 	if level < l.level {
 		return
 	}
 
-	if len(keysAndValues) == 0 {
-		l.ultraLog(level, msg)
+	fields := singleObjectFields(keysAndValues)
+	if fields != nil {
+		keysAndValues = nil
+	}
+	if l.hasPipeline() {
+		l.dispatch(level, msg, msg, fields, keysAndValues, skip+2)
 		return
 	}
 
-	// 检查是否是单个对象参数
-	if len(keysAndValues) == 1 {
-		if objFields := convert.ParseObjectToMap(keysAndValues[0]); objFields != nil {
-			l.logWithFields(level, msg, objFields)
-			return
-		}
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, skip+2)
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(msg)...)
+	buf = l.limitMessage(buf, msgStart)
+	if len(fields) > 0 || len(keysAndValues) > 0 || l.hasStaticFields() {
+		buf = l.appendFieldBlock(buf, level, keysAndValues, fields)
 	}
+	buf = append(buf, newline...)
 
-	// 快速构建带键值对的消息
-	buf := bytePool.Get().([]byte)
-	buf = buf[:0]
-	defer bytePool.Put(buf)
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
+}
 
-	buf = append(buf, convert.S2B(msg)...)
-	buf = append(buf, kvBraceOpen...)
+// singleObjectFields 键值对只有一个对象参数时解析为字段映射（分级字段与条件字段除外），否则返回 nil
+func singleObjectFields(keysAndValues []any) map[string]any {
+	if len(keysAndValues) != 1 {
+		return nil
+	}
+	switch keysAndValues[0].(type) {
+	case Field, LevelField:
+		return nil
+	}
+	return parseObjectFields(keysAndValues[0])
+}
 
-	for i := 0; i < len(keysAndValues); i += 2 {
-		if i > 0 {
-			buf = append(buf, kvDelimiter...)
-		}
+// logContextSkip 同 logSkip，附加上下文信息（消息前缀与上下文字段）
+func (l *Logger) logContextSkip(ctx context.Context, skip int, level LogLevel, msg string) {
+	if level < l.level {
+		return
+	}
+	if contextInfo := l.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	l.logSkip(skip+1, level, msg, l.extractContextFields(ctx))
+}
 
-		// 键
-		buf = convert.AppendValue(buf, keysAndValues[i])
-		buf = append(buf, kvSeparator...)
+// LogWithCallerSkip 记录键值对日志，调用位置再向上跳过 skip 层（0 表示调用 LogWithCallerSkip 的位置），
+// 供封装日志调用的辅助函数报告真实调用者：
+//
+//	func audit(msg string, kv ...any) { log.LogWithCallerSkip(1, logger.INFO, msg, kv...) }
+func (l *Logger) LogWithCallerSkip(skip int, level LogLevel, msg string, keysAndValues ...any) {
+	l.logSkip(skip, level, msg, keysAndValues)
+}
 
-		// 值
-		if i+1 < len(keysAndValues) {
-			buf = convert.AppendValue(buf, keysAndValues[i+1])
-		} else {
-			buf = append(buf, kvMissing...)
-		}
+// AddCallerSkip 返回调用位置额外跳过 n 层的子 Logger，适合在自定义门面中创建一次后复用：
+//
+//	facade := log.AddCallerSkip(1)
+//	func Info(msg string) { facade.InfoMsg(msg) }
+func (l *Logger) AddCallerSkip(n int) *Logger {
+	child := l.Clone().(*Logger)
+	child.callerSkip = max(l.callerSkip+n, 0)
+	return child
+}
+
+// logWithFields 使用字段映射记录日志，可追加本次调用的键值对（排在字段映射之后）
+func (l *Logger) logWithFields(level LogLevel, msg string, fields map[string]any, keysAndValues ...any) {
+	if level < l.level {
+		return
+	}
+	if l.hasPipeline() {
+		l.dispatch(level, msg, msg, fields, keysAndValues, 2)
+		return
 	}
 
-	buf = append(buf, kvBraceClose...)
-	l.ultraLog(level, string(buf))
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 2)
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(msg)...)
+	buf = l.limitMessage(buf, msgStart)
+	if len(fields) > 0 || len(keysAndValues) > 0 || l.hasStaticFields() {
+		buf = l.appendFieldBlock(buf, level, keysAndValues, fields)
+	}
+	buf = append(buf, newline...)
+
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
 }
 
-// logWithFields 使用字段映射记录日志
-func (l *Logger) logWithFields(level LogLevel, msg string, fields map[string]any) {
+// logWithEncodedFields 同 logWithFields，快速路径下直接复用 fieldLogger 预编码的字段
+func (l *Logger) logWithEncodedFields(level LogLevel, msg string, f *fieldLogger, keysAndValues ...any) {
 	if level < l.level {
 		return
 	}
-
-	if len(fields) == 0 {
-		l.ultraLog(level, msg)
+	if l.hasPipeline() {
+		l.dispatch(level, msg, msg, f.fields, keysAndValues, 2)
 		return
 	}
 
-	buf := bytePool.Get().([]byte)
-	buf = buf[:0]
-	defer bytePool.Put(buf)
-
+	bp := bytePool.Get().(*[]byte)
+	buf := l.appendHeader((*bp)[:0], level, 2)
+	msgStart := len(buf)
 	buf = append(buf, convert.S2B(msg)...)
-	buf = append(buf, kvBraceOpen...)
+	buf = l.limitMessage(buf, msgStart)
+	if !f.preEncoded {
+		buf = l.appendFieldBlock(buf, level, keysAndValues, f.fields)
+	} else if len(f.encoded) > 0 || len(keysAndValues) > 0 || l.hasStaticFields() {
+		buf = l.appendEncodedFieldBlock(buf, level, f.encoded, keysAndValues)
+	}
+	buf = append(buf, newline...)
 
-	first := true
-	for k, v := range fields {
-		if !first {
+	l.writeLine(level, buf)
+	putLineBuf(bp, buf)
+}
+
+// appendEncodedFieldBlock 同 appendFieldBlock，字段映射部分使用预编码的 encoded
+func (l *Logger) appendEncodedFieldBlock(buf []byte, level LogLevel, encoded []byte, keysAndValues []any) []byte {
+	keysAndValues = gateKV(level, keysAndValues)
+
+	start := len(buf)
+	buf = append(buf, kvBraceOpen...)
+	buf, wrote := l.appendStaticFields(buf, level)
+	if len(encoded) > 0 {
+		if wrote {
 			buf = append(buf, kvDelimiter...)
 		}
-		buf = append(buf, convert.S2B(k)...)
-		buf = append(buf, kvSeparator...)
-		buf = convert.AppendValue(buf, v)
-		first = false
+		buf = append(buf, encoded...)
+		wrote = true
 	}
+	buf, wrote = l.appendKVPairs(buf, keysAndValues, wrote)
+	if !wrote {
+		return buf[:start]
+	}
+	return append(buf, kvBraceClose...)
+}
+
+// appendFieldBlock 追加 " {static, fields, k: v, ...}" 字段块，依次为静态字段、字段映射、键值对
+// 条件字段按 level 取舍；所有字段均被丢弃时不输出字段块
+func (l *Logger) appendFieldBlock(buf []byte, level LogLevel, keysAndValues []any, fields map[string]any) []byte {
+	keysAndValues = gateKV(level, keysAndValues)
+	fields = gateFields(level, fields)
 
-	buf = append(buf, kvBraceClose...)
-	l.ultraLog(level, string(buf))
+	start := len(buf)
+	buf = append(buf, kvBraceOpen...)
+	buf, wrote := l.appendStaticFields(buf, level)
+	buf, wrote = l.appendFieldsMap(buf, fields, wrote)
+	buf, wrote = l.appendKVPairs(buf, keysAndValues, wrote)
+	if !wrote {
+		return buf[:start]
+	}
+	return append(buf, kvBraceClose...)
+}
+
+// appendKVPairs 以 "k: v, k2: v2" 形式追加键值对，不经过 fmt 和 map
+// 键位置上的 Field 独占一个元素，格式错误的元素以 BadKey 输出；sep 表示之前已有字段，返回值表示是否已写入字段
+func (l *Logger) appendKVPairs(buf []byte, keysAndValues []any, sep bool) ([]byte, bool) {
+	var wrote bool
+	for i := 0; i < len(keysAndValues); {
+		if f, ok := keysAndValues[i].(Field); ok {
+			buf, wrote = l.appendField(buf, sep, f.Key, f)
+			sep = sep || wrote
+			i++
+			continue
+		}
+
+		var (
+			key   string
+			value any
+		)
+		key, value, i = kvPair(keysAndValues, i)
+		if l.flattener != nil && l.flattenField(key, value, func(k string, v any) {
+			buf, wrote = l.appendField(buf, sep, k, v)
+			sep = sep || wrote
+		}) {
+			continue
+		}
+		buf, wrote = l.appendField(buf, sep, key, value)
+		sep = sep || wrote
+	}
+	return buf, sep
+}
+
+// appendFieldsMap 以 "k: v, k2: v2" 形式按字段名排序追加字段映射，参数含义同 appendKVPairs
+func (l *Logger) appendFieldsMap(buf []byte, fields map[string]any, sep bool) ([]byte, bool) {
+	var (
+		wrote   bool
+		keysBuf [16]string
+	)
+	for _, k := range sortedFieldKeys(keysBuf[:0], fields) {
+		v := fields[k]
+		if l.flattener != nil && l.flattenField(k, v, func(k string, v any) {
+			buf, wrote = l.appendField(buf, sep, k, v)
+			sep = sep || wrote
+		}) {
+			continue
+		}
+		buf, wrote = l.appendField(buf, sep, k, v)
+		sep = sep || wrote
+	}
+	return buf, sep
+}
+
+// sortedFieldKeys 将字段名按字典序追加到 dst 并返回，保证同一字段映射的输出逐字节一致
+func sortedFieldKeys(dst []string, fields map[string]any) []string {
+	for k := range fields {
+		dst = append(dst, k)
+	}
+	if len(dst) > 1 {
+		slices.Sort(dst)
+	}
+	return dst
+}
+
+// fieldKey 将键值对中的键转换为字符串
+func fieldKey(key any) string {
+	if s, ok := key.(string); ok {
+		return s
+	}
+	return string(convert.AppendValue(nil, key))
 }
 
 // logWithContextKV 带上下文的键值对日志
@@ -530,14 +780,19 @@ func (l *Logger) logWithContextKV(ctx context.Context, level LogLevel, msg strin
 	if level < l.level {
 		return
 	}
+	l = l.spanScope(ctx, level)
 
 	// 先从context提取信息
 	contextInfo := l.extractContextInfo(ctx)
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
 
-	l.logWithKV(level, msg, keysAndValues...)
+	// 跳过 logWithContextKV 与 XxxContextKV 两层，报告真实调用位置
+	l.logSkip(1, level, msg, keysAndValues)
 }
 
 // ============================================================================
@@ -654,10 +909,15 @@ func (l *Logger) LogContext(ctx context.Context, level LogLevel, msg string) {
 	if level < l.level {
 		return
 	}
+	l = l.spanScope(ctx, level)
 	contextInfo := l.extractContextInfo(ctx)
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
+	if kv := l.extractContextFields(ctx); len(kv) > 0 {
+		l.logWithKV(level, msg, kv...)
+		return
+	}
 	l.ultraLog(level, msg)
 }
 
@@ -675,15 +935,9 @@ func (l *Logger) LogWithFields(level LogLevel, msg string, fields map[string]any
 	l.logWithFields(level, msg, fields)
 }
 
-// WithContext 带上下文的logger（当前实现返回自身）
+// WithContext 返回绑定上下文的子 Logger，上下文中提取到的字段由后续派生的子 Logger 继承
 func (l *Logger) WithContext(ctx context.Context) ILogger {
-	// 创建一个新的logger实例并设置context
-	newLogger := l.Clone()
-	if loggerPtr, ok := newLogger.(*Logger); ok {
-		loggerPtr.context = ctx
-		return loggerPtr
-	}
-	return newLogger
+	return l.contextLogger(ctx)
 }
 
 // 兼容标准log包的方法
@@ -705,74 +959,86 @@ func (l *Logger) Println(args ...any) {
 
 // DebugReturn 记录调试日志并返回格式化的错误
 func (l *Logger) DebugReturn(format string, args ...any) error {
-	l.log(DEBUG, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(DEBUG, format, args, nil)
+	l.logSkip(0, DEBUG, err.Message, nil)
+	return err
 }
 
 // InfoReturn 记录信息日志并返回格式化的错误
 func (l *Logger) InfoReturn(format string, args ...any) error {
-	l.log(INFO, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(INFO, format, args, nil)
+	l.logSkip(0, INFO, err.Message, nil)
+	return err
 }
 
 // WarnReturn 记录警告日志并返回格式化的错误
 func (l *Logger) WarnReturn(format string, args ...any) error {
-	l.log(WARN, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(WARN, format, args, nil)
+	l.logSkip(0, WARN, err.Message, nil)
+	return err
 }
 
 // ErrorReturn 记录错误日志并返回格式化的错误
 func (l *Logger) ErrorReturn(format string, args ...any) error {
-	l.log(ERROR, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(ERROR, format, args, nil)
+	l.logSkip(0, ERROR, err.Message, nil)
+	return err
 }
 
 // DebugCtxReturn 记录带上下文的调试日志并返回格式化的错误
 func (l *Logger) DebugCtxReturn(ctx context.Context, format string, args ...any) error {
-	l.DebugContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(DEBUG, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, DEBUG, err.Message)
+	return err
 }
 
 // InfoCtxReturn 记录带上下文的信息日志并返回格式化的错误
 func (l *Logger) InfoCtxReturn(ctx context.Context, format string, args ...any) error {
-	l.InfoContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(INFO, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, INFO, err.Message)
+	return err
 }
 
 // WarnCtxReturn 记录带上下文的警告日志并返回格式化的错误
 func (l *Logger) WarnCtxReturn(ctx context.Context, format string, args ...any) error {
-	l.WarnContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(WARN, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, WARN, err.Message)
+	return err
 }
 
 // ErrorCtxReturn 记录带上下文的错误日志并返回格式化的错误
 func (l *Logger) ErrorCtxReturn(ctx context.Context, format string, args ...any) error {
-	l.ErrorContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	err := l.newReturnError(ERROR, format, args, l.extractContextFields(ctx))
+	l.logContextSkip(ctx, 0, ERROR, err.Message)
+	return err
 }
 
 // DebugKVReturn 记录带键值对的调试日志并返回错误
 func (l *Logger) DebugKVReturn(msg string, keysAndValues ...any) error {
-	l.DebugKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := l.newReturnError(DEBUG, msg, nil, keysAndValues)
+	l.logSkip(0, DEBUG, msg, keysAndValues)
+	return err
 }
 
 // InfoKVReturn 记录带键值对的信息日志并返回错误
 func (l *Logger) InfoKVReturn(msg string, keysAndValues ...any) error {
-	l.InfoKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := l.newReturnError(INFO, msg, nil, keysAndValues)
+	l.logSkip(0, INFO, msg, keysAndValues)
+	return err
 }
 
 // WarnKVReturn 记录带键值对的警告日志并返回错误
 func (l *Logger) WarnKVReturn(msg string, keysAndValues ...any) error {
-	l.WarnKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := l.newReturnError(WARN, msg, nil, keysAndValues)
+	l.logSkip(0, WARN, msg, keysAndValues)
+	return err
 }
 
 // ErrorKVReturn 记录带键值对的错误日志并返回错误
 func (l *Logger) ErrorKVReturn(msg string, keysAndValues ...any) error {
-	l.ErrorKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := l.newReturnError(ERROR, msg, nil, keysAndValues)
+	l.logSkip(0, ERROR, msg, keysAndValues)
+	return err
 }
 
 // ============================================================================
@@ -849,6 +1115,21 @@ func (l *Logger) SetShowCaller(show bool) {
 type fieldLogger struct {
 	logger *Logger
 	fields map[string]any
+
+	// 快速路径下预编码的字段（创建时按当时的字段策略编码一次，后续每条日志直接复用）
+	encoded    []byte
+	preEncoded bool
+}
+
+// newFieldLogger 创建字段日志器并预编码字段；启用结构化管道或字段中含有 LevelField 时不预编码
+func newFieldLogger(l *Logger, fields map[string]any) *fieldLogger {
+	f := &fieldLogger{logger: l, fields: fields}
+	if l.hasPipeline() || hasLevelFieldValue(fields) {
+		return f
+	}
+	f.encoded, _ = l.appendFieldsMap(nil, fields, false)
+	f.preEncoded = true
+	return f
 }
 
 // 实现所有 ILogger 接口方法，将字段附加到日志消息
@@ -862,7 +1143,7 @@ func (f *fieldLogger) Debug(format string, args ...any) {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(format, args...)
 	}
-	f.logger.logWithFields(DEBUG, msg, f.fields)
+	f.logger.logWithEncodedFields(DEBUG, msg, f)
 }
 
 // Info 信息日志
@@ -874,7 +1155,7 @@ func (f *fieldLogger) Info(format string, args ...any) {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(format, args...)
 	}
-	f.logger.logWithFields(INFO, msg, f.fields)
+	f.logger.logWithEncodedFields(INFO, msg, f)
 }
 
 // Warn 警告日志
@@ -886,7 +1167,7 @@ func (f *fieldLogger) Warn(format string, args ...any) {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(format, args...)
 	}
-	f.logger.logWithFields(WARN, msg, f.fields)
+	f.logger.logWithEncodedFields(WARN, msg, f)
 }
 
 // Error 错误日志
@@ -898,7 +1179,7 @@ func (f *fieldLogger) Error(format string, args ...any) {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(format, args...)
 	}
-	f.logger.logWithFields(ERROR, msg, f.fields)
+	f.logger.logWithEncodedFields(ERROR, msg, f)
 }
 
 // Fatal 致命错误日志
@@ -907,7 +1188,7 @@ func (f *fieldLogger) Fatal(format string, args ...any) {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(format, args...)
 	}
-	f.logger.logWithFields(FATAL, msg, f.fields)
+	f.logger.logWithEncodedFields(FATAL, msg, f)
 }
 
 // Printf风格方法
@@ -936,32 +1217,32 @@ func (f *fieldLogger) DebugMsg(msg string) {
 	if f.logger.level > DEBUG {
 		return
 	}
-	f.logger.logWithFields(DEBUG, msg, f.fields)
+	f.logger.logWithEncodedFields(DEBUG, msg, f)
 }
 
 func (f *fieldLogger) InfoMsg(msg string) {
 	if !f.logger.IsLevelEnabled(INFO) {
 		return
 	}
-	f.logger.logWithFields(INFO, msg, f.fields)
+	f.logger.logWithEncodedFields(INFO, msg, f)
 }
 
 func (f *fieldLogger) WarnMsg(msg string) {
 	if !f.logger.IsLevelEnabled(WARN) {
 		return
 	}
-	f.logger.logWithFields(WARN, msg, f.fields)
+	f.logger.logWithEncodedFields(WARN, msg, f)
 }
 
 func (f *fieldLogger) ErrorMsg(msg string) {
 	if !f.logger.IsLevelEnabled(ERROR) {
 		return
 	}
-	f.logger.logWithFields(ERROR, msg, f.fields)
+	f.logger.logWithEncodedFields(ERROR, msg, f)
 }
 
 func (f *fieldLogger) FatalMsg(msg string) {
-	f.logger.logWithFields(FATAL, msg, f.fields)
+	f.logger.logWithEncodedFields(FATAL, msg, f)
 }
 
 // 多行日志方法
@@ -970,7 +1251,7 @@ func (f *fieldLogger) InfoLines(lines ...string) {
 		return
 	}
 	for _, line := range lines {
-		f.logger.logWithFields(INFO, line, f.fields)
+		f.logger.logWithEncodedFields(INFO, line, f)
 	}
 }
 
@@ -979,7 +1260,7 @@ func (f *fieldLogger) ErrorLines(lines ...string) {
 		return
 	}
 	for _, line := range lines {
-		f.logger.logWithFields(ERROR, line, f.fields)
+		f.logger.logWithEncodedFields(ERROR, line, f)
 	}
 }
 
@@ -988,7 +1269,7 @@ func (f *fieldLogger) WarnLines(lines ...string) {
 		return
 	}
 	for _, line := range lines {
-		f.logger.logWithFields(WARN, line, f.fields)
+		f.logger.logWithEncodedFields(WARN, line, f)
 	}
 }
 
@@ -997,7 +1278,7 @@ func (f *fieldLogger) DebugLines(lines ...string) {
 		return
 	}
 	for _, line := range lines {
-		f.logger.logWithFields(DEBUG, line, f.fields)
+		f.logger.logWithEncodedFields(DEBUG, line, f)
 	}
 }
 
@@ -1011,7 +1292,7 @@ func (f *fieldLogger) DebugContext(ctx context.Context, format string, args ...a
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(DEBUG, msg, f.fields)
+	f.logger.logWithEncodedFields(DEBUG, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 func (f *fieldLogger) InfoContext(ctx context.Context, format string, args ...any) {
@@ -1023,7 +1304,7 @@ func (f *fieldLogger) InfoContext(ctx context.Context, format string, args ...an
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(INFO, msg, f.fields)
+	f.logger.logWithEncodedFields(INFO, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 func (f *fieldLogger) WarnContext(ctx context.Context, format string, args ...any) {
@@ -1035,7 +1316,7 @@ func (f *fieldLogger) WarnContext(ctx context.Context, format string, args ...an
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(WARN, msg, f.fields)
+	f.logger.logWithEncodedFields(WARN, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 func (f *fieldLogger) ErrorContext(ctx context.Context, format string, args ...any) {
@@ -1047,7 +1328,7 @@ func (f *fieldLogger) ErrorContext(ctx context.Context, format string, args ...a
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(ERROR, msg, f.fields)
+	f.logger.spanScope(ctx, ERROR).logWithEncodedFields(ERROR, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 func (f *fieldLogger) FatalContext(ctx context.Context, format string, args ...any) {
@@ -1056,7 +1337,7 @@ func (f *fieldLogger) FatalContext(ctx context.Context, format string, args ...a
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(FATAL, msg, f.fields)
+	f.logger.spanScope(ctx, FATAL).logWithEncodedFields(FATAL, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 // 键值对日志方法
@@ -1064,37 +1345,32 @@ func (f *fieldLogger) DebugKV(msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(DEBUG) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(DEBUG, msg, allFields...)
+	f.logger.logWithEncodedFields(DEBUG, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) InfoKV(msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(INFO) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(INFO, msg, allFields...)
+	f.logger.logWithEncodedFields(INFO, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) WarnKV(msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(WARN) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(WARN, msg, allFields...)
+	f.logger.logWithEncodedFields(WARN, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) ErrorKV(msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(ERROR) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(ERROR, msg, allFields...)
+	f.logger.logWithEncodedFields(ERROR, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) FatalKV(msg string, keysAndValues ...any) {
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(FATAL, msg, allFields...)
+	f.logger.logWithEncodedFields(FATAL, msg, f, keysAndValues...)
 }
 
 // 带上下文的键值对日志方法
@@ -1102,37 +1378,62 @@ func (f *fieldLogger) DebugContextKV(ctx context.Context, msg string, keysAndVal
 	if !f.logger.IsLevelEnabled(DEBUG) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithContextKV(ctx, DEBUG, msg, allFields...)
+	if contextInfo := f.logger.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	if kv := f.logger.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
+	f.logger.logWithEncodedFields(DEBUG, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) InfoContextKV(ctx context.Context, msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(INFO) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithContextKV(ctx, INFO, msg, allFields...)
+	if contextInfo := f.logger.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	if kv := f.logger.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
+	f.logger.logWithEncodedFields(INFO, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) WarnContextKV(ctx context.Context, msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(WARN) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithContextKV(ctx, WARN, msg, allFields...)
+	if contextInfo := f.logger.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	if kv := f.logger.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
+	f.logger.logWithEncodedFields(WARN, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) ErrorContextKV(ctx context.Context, msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(ERROR) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithContextKV(ctx, ERROR, msg, allFields...)
+	if contextInfo := f.logger.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	if kv := f.logger.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
+	f.logger.logWithEncodedFields(ERROR, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) FatalContextKV(ctx context.Context, msg string, keysAndValues ...any) {
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithContextKV(ctx, FATAL, msg, allFields...)
+	if contextInfo := f.logger.extractContextInfo(ctx); contextInfo != "" {
+		msg = contextInfo + msg
+	}
+	if kv := f.logger.extractContextFields(ctx); len(kv) > 0 {
+		keysAndValues = append(kv, keysAndValues...)
+	}
+	f.logger.logWithEncodedFields(FATAL, msg, f, keysAndValues...)
 }
 
 // 字段映射方法
@@ -1142,6 +1443,7 @@ func (f *fieldLogger) DebugWithFields(msg string, fields map[string]any) {
 	}
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(DEBUG, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 func (f *fieldLogger) InfoWithFields(msg string, fields map[string]any) {
@@ -1150,6 +1452,7 @@ func (f *fieldLogger) InfoWithFields(msg string, fields map[string]any) {
 	}
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(INFO, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 func (f *fieldLogger) WarnWithFields(msg string, fields map[string]any) {
@@ -1158,6 +1461,7 @@ func (f *fieldLogger) WarnWithFields(msg string, fields map[string]any) {
 	}
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(WARN, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 func (f *fieldLogger) ErrorWithFields(msg string, fields map[string]any) {
@@ -1166,11 +1470,13 @@ func (f *fieldLogger) ErrorWithFields(msg string, fields map[string]any) {
 	}
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(ERROR, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 func (f *fieldLogger) FatalWithFields(msg string, fields map[string]any) {
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(FATAL, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 // 原始日志条目方法
@@ -1178,7 +1484,7 @@ func (f *fieldLogger) Log(level LogLevel, msg string) {
 	if !f.logger.IsLevelEnabled(level) {
 		return
 	}
-	f.logger.logWithFields(level, msg, f.fields)
+	f.logger.logWithEncodedFields(level, msg, f)
 }
 
 func (f *fieldLogger) LogContext(ctx context.Context, level LogLevel, msg string) {
@@ -1189,15 +1495,14 @@ func (f *fieldLogger) LogContext(ctx context.Context, level LogLevel, msg string
 	if contextInfo != "" {
 		msg = contextInfo + msg
 	}
-	f.logger.logWithFields(level, msg, f.fields)
+	f.logger.spanScope(ctx, level).logWithEncodedFields(level, msg, f, f.logger.extractContextFields(ctx)...)
 }
 
 func (f *fieldLogger) LogKV(level LogLevel, msg string, keysAndValues ...any) {
 	if !f.logger.IsLevelEnabled(level) {
 		return
 	}
-	allFields := f.mergeKV(keysAndValues...)
-	f.logger.logWithKV(level, msg, allFields...)
+	f.logger.logWithEncodedFields(level, msg, f, keysAndValues...)
 }
 
 func (f *fieldLogger) LogWithFields(level LogLevel, msg string, fields map[string]any) {
@@ -1206,6 +1511,7 @@ func (f *fieldLogger) LogWithFields(level LogLevel, msg string, fields map[strin
 	}
 	mergedFields := f.mergeFieldsMap(fields)
 	f.logger.logWithFields(level, msg, mergedFields)
+	f.releaseMerged(fields, mergedFields)
 }
 
 // 配置方法
@@ -1229,12 +1535,9 @@ func (f *fieldLogger) IsLevelEnabled(level LogLevel) bool {
 	return f.logger.IsLevelEnabled(level)
 }
 
-// 结构化日志构建器 - 使用 clear() 优化（Go 1.21+）
+// 结构化日志构建器 - 新 map 会被子日志器长期持有，按精确容量分配而不占用对象池
 func (f *fieldLogger) WithField(key string, value any) ILogger {
-	// 从对象池获取 map
-	newFields := fieldMapPool.Get().(map[string]any)
-
-	clear(newFields)
+	newFields := make(map[string]any, len(f.fields)+1)
 
 	// 复制现有字段
 	for k, v := range f.fields {
@@ -1242,7 +1545,7 @@ func (f *fieldLogger) WithField(key string, value any) ILogger {
 	}
 	newFields[key] = value
 
-	return &fieldLogger{logger: f.logger, fields: newFields}
+	return newFieldLogger(f.logger, newFields)
 }
 
 func (f *fieldLogger) WithFields(fields map[string]any) ILogger {
@@ -1250,10 +1553,7 @@ func (f *fieldLogger) WithFields(fields map[string]any) ILogger {
 		return f
 	}
 
-	// 从对象池获取 map
-	newFields := fieldMapPool.Get().(map[string]any)
-
-	clear(newFields)
+	newFields := make(map[string]any, len(f.fields)+len(fields))
 
 	// 复制现有字段
 	for k, v := range f.fields {
@@ -1264,7 +1564,7 @@ func (f *fieldLogger) WithFields(fields map[string]any) ILogger {
 		newFields[k] = v
 	}
 
-	return &fieldLogger{logger: f.logger, fields: newFields}
+	return newFieldLogger(f.logger, newFields)
 }
 
 func (f *fieldLogger) WithError(err error) ILogger {
@@ -1272,7 +1572,7 @@ func (f *fieldLogger) WithError(err error) ILogger {
 }
 
 func (f *fieldLogger) WithContext(ctx context.Context) ILogger {
-	return f
+	return &fieldLogger{logger: f.logger.contextLogger(ctx), fields: f.fields, encoded: f.encoded, preEncoded: f.preEncoded}
 }
 
 // Clone 克隆当前Logger
@@ -1281,7 +1581,7 @@ func (f *fieldLogger) Clone() ILogger {
 	for k, v := range f.fields {
 		newFields[k] = v
 	}
-	return &fieldLogger{logger: f.logger, fields: newFields}
+	return newFieldLogger(f.logger, newFields)
 }
 
 // 兼容标准log包的方法
@@ -1289,14 +1589,14 @@ func (f *fieldLogger) Print(args ...any) {
 	if !f.logger.IsLevelEnabled(INFO) {
 		return
 	}
-	f.logger.logWithFields(INFO, fmt.Sprint(args...), f.fields)
+	f.logger.logWithEncodedFields(INFO, fmt.Sprint(args...), f)
 }
 
 func (f *fieldLogger) Printf(format string, args ...any) {
 	if !f.logger.IsLevelEnabled(INFO) {
 		return
 	}
-	f.logger.logWithFields(INFO, fmt.Sprintf(format, args...), f.fields)
+	f.logger.logWithEncodedFields(INFO, fmt.Sprintf(format, args...), f)
 }
 
 func (f *fieldLogger) Println(args ...any) {
@@ -1304,70 +1604,102 @@ func (f *fieldLogger) Println(args ...any) {
 		return
 	}
 	msg := fmt.Sprintln(args...)
-	f.logger.logWithFields(INFO, msg[:len(msg)-1], f.fields)
+	f.logger.logWithEncodedFields(INFO, msg[:len(msg)-1], f)
 }
 
 // 返回错误的日志方法
 func (f *fieldLogger) DebugReturn(format string, args ...any) error {
-	f.Debug(format, args...)
-	return fmt.Errorf(format, args...)
+	err := f.newReturnError(DEBUG, format, args, nil)
+	f.logger.logWithEncodedFields(DEBUG, err.Message, f)
+	return err
 }
 
 func (f *fieldLogger) InfoReturn(format string, args ...any) error {
-	f.Info(format, args...)
-	return fmt.Errorf(format, args...)
+	err := f.newReturnError(INFO, format, args, nil)
+	f.logger.logWithEncodedFields(INFO, err.Message, f)
+	return err
 }
 
 func (f *fieldLogger) WarnReturn(format string, args ...any) error {
-	f.Warn(format, args...)
-	return fmt.Errorf(format, args...)
+	err := f.newReturnError(WARN, format, args, nil)
+	f.logger.logWithEncodedFields(WARN, err.Message, f)
+	return err
 }
 
 func (f *fieldLogger) ErrorReturn(format string, args ...any) error {
-	f.Error(format, args...)
-	return fmt.Errorf(format, args...)
+	err := f.newReturnError(ERROR, format, args, nil)
+	f.logger.logWithEncodedFields(ERROR, err.Message, f)
+	return err
 }
 
 // 返回错误的上下文日志方法
 func (f *fieldLogger) DebugCtxReturn(ctx context.Context, format string, args ...any) error {
-	f.DebugContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	kv := f.logger.extractContextFields(ctx)
+	err := f.newReturnError(DEBUG, format, args, kv)
+	if f.logger.IsLevelEnabled(DEBUG) {
+		f.logger.spanScope(ctx, DEBUG).logWithEncodedFields(DEBUG, f.logger.extractContextInfo(ctx)+err.Message, f, kv...)
+	}
+	return err
 }
 
 func (f *fieldLogger) InfoCtxReturn(ctx context.Context, format string, args ...any) error {
-	f.InfoContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	kv := f.logger.extractContextFields(ctx)
+	err := f.newReturnError(INFO, format, args, kv)
+	if f.logger.IsLevelEnabled(INFO) {
+		f.logger.spanScope(ctx, INFO).logWithEncodedFields(INFO, f.logger.extractContextInfo(ctx)+err.Message, f, kv...)
+	}
+	return err
 }
 
 func (f *fieldLogger) WarnCtxReturn(ctx context.Context, format string, args ...any) error {
-	f.WarnContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	kv := f.logger.extractContextFields(ctx)
+	err := f.newReturnError(WARN, format, args, kv)
+	if f.logger.IsLevelEnabled(WARN) {
+		f.logger.spanScope(ctx, WARN).logWithEncodedFields(WARN, f.logger.extractContextInfo(ctx)+err.Message, f, kv...)
+	}
+	return err
 }
 
 func (f *fieldLogger) ErrorCtxReturn(ctx context.Context, format string, args ...any) error {
-	f.ErrorContext(ctx, format, args...)
-	return fmt.Errorf(format, args...)
+	kv := f.logger.extractContextFields(ctx)
+	err := f.newReturnError(ERROR, format, args, kv)
+	if f.logger.IsLevelEnabled(ERROR) {
+		f.logger.spanScope(ctx, ERROR).logWithEncodedFields(ERROR, f.logger.extractContextInfo(ctx)+err.Message, f, kv...)
+	}
+	return err
 }
 
 // 返回错误的键值对日志方法
 func (f *fieldLogger) DebugKVReturn(msg string, keysAndValues ...any) error {
-	f.DebugKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := f.newReturnError(DEBUG, msg, nil, keysAndValues)
+	if f.logger.IsLevelEnabled(DEBUG) {
+		f.logger.logWithEncodedFields(DEBUG, msg, f, keysAndValues...)
+	}
+	return err
 }
 
 func (f *fieldLogger) InfoKVReturn(msg string, keysAndValues ...any) error {
-	f.InfoKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := f.newReturnError(INFO, msg, nil, keysAndValues)
+	if f.logger.IsLevelEnabled(INFO) {
+		f.logger.logWithEncodedFields(INFO, msg, f, keysAndValues...)
+	}
+	return err
 }
 
 func (f *fieldLogger) WarnKVReturn(msg string, keysAndValues ...any) error {
-	f.WarnKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := f.newReturnError(WARN, msg, nil, keysAndValues)
+	if f.logger.IsLevelEnabled(WARN) {
+		f.logger.logWithEncodedFields(WARN, msg, f, keysAndValues...)
+	}
+	return err
 }
 
 func (f *fieldLogger) ErrorKVReturn(msg string, keysAndValues ...any) error {
-	f.ErrorKV(msg, keysAndValues...)
-	return fmt.Errorf("%s", msg)
+	err := f.newReturnError(ERROR, msg, nil, keysAndValues)
+	if f.logger.IsLevelEnabled(ERROR) {
+		f.logger.logWithEncodedFields(ERROR, msg, f, keysAndValues...)
+	}
+	return err
 }
 
 // Console 相关方法
@@ -1395,23 +1727,8 @@ func (f *fieldLogger) NewConsoleGroup() *ConsoleGroup {
 	return f.logger.getOrCreateConsoleGroup()
 }
 
-// 辅助方法：合并字段和键值对
-func (f *fieldLogger) mergeKV(keysAndValues ...any) []any {
-	totalLen := len(f.fields)*2 + len(keysAndValues)
-	result := make([]any, 0, totalLen)
-
-	// 添加现有字段
-	for k, v := range f.fields {
-		result = append(result, k, v)
-	}
-
-	// 添加传入的键值对
-	result = append(result, keysAndValues...)
-
-	return result
-}
-
 // 辅助方法：合并字段映射 - 使用对象池优化
+// 传入字段非空时返回池中的 map，写出后需调用 releaseMerged 归还
 func (f *fieldLogger) mergeFieldsMap(fields map[string]any) map[string]any {
 	if len(fields) == 0 {
 		return f.fields
@@ -1419,7 +1736,6 @@ func (f *fieldLogger) mergeFieldsMap(fields map[string]any) map[string]any {
 
 	// 从对象池获取 map
 	merged := fieldMapPool.Get().(map[string]any)
-	clear(merged)
 
 	// 添加现有字段
 	for k, v := range f.fields {
@@ -1434,6 +1750,13 @@ func (f *fieldLogger) mergeFieldsMap(fields map[string]any) map[string]any {
 	return merged
 }
 
+// releaseMerged 归还 mergeFieldsMap 从对象池取得的 map
+func (f *fieldLogger) releaseMerged(fields, merged map[string]any) {
+	if len(fields) > 0 {
+		putFieldMap(merged)
+	}
+}
+
 // ============================================================================
 // 特殊场景日志方法（specialty）
 // ============================================================================
@@ -1659,8 +1982,13 @@ func (l *Logger) Health(service string, status bool, details string) {
 	l.ultraLog(level, fmt.Sprintf("%s [HEALTH] %s: %s%s", emoji, service, statusStr, detailStr))
 }
 
-// Audit 审计日志（AUDIT 级别）
+// Audit 审计日志（AUDIT 级别），配置了审计通道时同时写入审计链
 func (l *Logger) Audit(action, user, resource, result string) {
+	if l.auditLogger != nil {
+		if err := l.auditLogger.Log(action, user, resource, result, nil); err != nil {
+			reportInternalError("audit", err)
+		}
+	}
 	if AUDIT < l.level {
 		return
 	}
@@ -0,0 +1,194 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\logger_test.go
+ * @Description: Logger 派生与并发写入测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serialWriter 记录写入内容，并检测是否有并发的 Write 调用
+type serialWriter struct {
+	inflight   atomic.Int32
+	overlapped atomic.Bool
+	delay      time.Duration
+	mu         sync.Mutex
+	buf        bytes.Buffer
+}
+
+func (w *serialWriter) Write(p []byte) (int, error) {
+	if w.inflight.Add(1) > 1 {
+		w.overlapped.Store(true)
+	}
+	defer w.inflight.Add(-1)
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *serialWriter) lines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return strings.Split(strings.TrimSpace(w.buf.String()), "\n")
+}
+
+// TestWithConcurrentParentChild 父 Logger 写入期间并发派生子 Logger：子 Logger 不得继承处于加锁状态的写锁，
+// 且与父 Logger 写同一输出时串行
+func TestWithConcurrentParentChild(t *testing.T) {
+	out := &serialWriter{delay: time.Millisecond}
+	l := NewLogger().WithOutput(out).WithColorful(false).WithFormat(FormatText)
+
+	const rounds = 20
+	var wg sync.WaitGroup
+	wg.Add(5)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			l.Info("parent")
+		}
+	}()
+	for g := 0; g < 4; g++ {
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				l.With("goroutine", g).Info("child")
+			}
+		}(g)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("child loggers deadlocked on an inherited write lock")
+	}
+
+	if out.overlapped.Load() {
+		t.Error("parent and child writes to the same output were not serialized")
+	}
+	if got := len(out.lines()); got != 5*rounds {
+		t.Errorf("got %d lines, want %d", got, 5*rounds)
+	}
+}
+
+// TestCloneIsolation 子 Logger 的静态字段与级别修改不影响父 Logger
+func TestCloneIsolation(t *testing.T) {
+	var buf bytes.Buffer
+	parent := NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText).With("service", "api")
+	child := parent.With("request_id", "r1")
+	child.SetLevel(ERROR)
+
+	parent.Info("from parent")
+	child.Info("suppressed")
+	child.Error("from child")
+
+	out := buf.String()
+	if strings.Contains(out, "suppressed") {
+		t.Error("child level was not applied")
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), out)
+	}
+	if strings.Contains(lines[0], "request_id") {
+		t.Errorf("parent picked up child field: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "service") || !strings.Contains(lines[1], "request_id") {
+		t.Errorf("child line missing fields: %s", lines[1])
+	}
+	if parent.GetLevel() != DEBUG {
+		t.Errorf("parent level changed to %v", parent.GetLevel())
+	}
+}
+
+// TestSetGlobalOutputConcurrent 修改全局输出、格式、级别与调用者设置时不与正在写日志的协程产生数据竞争（需 -race）
+func TestSetGlobalOutputConcurrent(t *testing.T) {
+	restore := ReplaceGlobal(NewLogger().WithOutput(io.Discard).WithColorful(false))
+	defer restore()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					Info("concurrent")
+				}
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		SetGlobalOutput(io.Discard)
+		SetGlobalFormat(FormatText)
+		SetGlobalLevel(INFO)
+		SetGlobalShowCaller(i%2 == 0)
+	}
+	close(stop)
+	wg.Wait()
+
+	var buf bytes.Buffer
+	SetGlobalOutput(&buf)
+	SetGlobalFormat(FormatText)
+	Info("after switch")
+	if !strings.Contains(buf.String(), "after switch") {
+		t.Errorf("global output not switched, got %q", buf.String())
+	}
+}
+
+// TestSetGlobalSnapshot 全局设置发布新的 Logger：已取得的 Logger 保持原设置，异步写入的旧写入器写出剩余日志后停止
+func TestSetGlobalSnapshot(t *testing.T) {
+	oldOut, newOut := &lockedBuffer{}, &lockedBuffer{}
+	restore := ReplaceGlobal(NewLogger().WithOutput(oldOut).WithColorful(false).WithFormat(FormatText).WithAsyncWrite(true))
+	defer restore()
+
+	stale := GetGlobalLogger()
+	stale.InfoMsg("queued before switch")
+	SetGlobalLevel(WARN)
+	SetGlobalOutput(newOut)
+	defer GetGlobalLogger().Close()
+
+	if stale.GetLevel() != DEBUG {
+		t.Errorf("stale logger level changed to %v", stale.GetLevel())
+	}
+	if GetGlobalLogger().GetLevel() != WARN {
+		t.Errorf("global level = %v, want WARN", GetGlobalLogger().GetLevel())
+	}
+	if !stale.async.closed.Load() {
+		t.Error("previous async writer was not stopped")
+	}
+
+	stale.InfoMsg("stale write")
+	Info("filtered")
+	Warn("global write")
+	GetGlobalLogger().Flush()
+
+	if got := oldOut.lines(); got != 2 {
+		t.Errorf("old output has %d lines, want 2 (queued + stale)", got)
+	}
+	if got := newOut.lines(); got != 1 {
+		t.Errorf("new output has %d lines, want 1", got)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\logid.go
+ * @Description: 每条日志自动生成唯一 log_id（ULID / UUIDv7），便于在问题报告中引用并在多个输出间关联
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"time"
+)
+
+// LogIDKey 日志 ID 字段名
+const LogIDKey = "log_id"
+
+// LogIDGenerator 日志 ID 生成函数
+type LogIDGenerator func() string
+
+// LogIDFormat 内置日志 ID 格式
+type LogIDFormat int
+
+const (
+	LogIDULID   LogIDFormat = iota // ULID（26 字符，字典序即时间序）
+	LogIDUUIDv7                    // UUIDv7（RFC 9562，时间有序）
+)
+
+// NewULID 生成 ULID：48 位毫秒时间戳 + 80 位随机数，Crockford Base32 编码
+func NewULID() string {
+	var b [16]byte
+	rand.Read(b[6:])
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+
+	// 128 位按 5 位一组编码为 26 个字符（首字符只用 3 位）
+	const encoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = encoding[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// NewUUIDv7 生成 UUIDv7：48 位毫秒时间戳 + 版本号 + 74 位随机数
+func NewUUIDv7() string {
+	var b [16]byte
+	rand.Read(b[6:])
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(b[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:6], uint32(ms))
+	b[6] = (b[6] & 0x0f) | 0x70 // 版本 7
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 9562 变体
+
+	const hexDigits = "0123456789abcdef"
+	var out [36]byte
+	j := 0
+	for i, v := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			out[j] = '-'
+			j++
+		}
+		out[j] = hexDigits[v>>4]
+		out[j+1] = hexDigits[v&0x0f]
+		j += 2
+	}
+	return string(out[:])
+}
+
+// WithLogID 为每条日志附加内置格式的 log_id 字段
+func (l *Logger) WithLogID(format LogIDFormat) *Logger {
+	if format == LogIDUUIDv7 {
+		return l.WithLogIDGenerator(NewUUIDv7)
+	}
+	return l.WithLogIDGenerator(NewULID)
+}
+
+// WithLogIDGenerator 使用自定义生成函数为每条日志附加 log_id 字段（nil 表示关闭）
+func (l *Logger) WithLogIDGenerator(gen LogIDGenerator) *Logger {
+	l.logID = gen
+	return l
+}
+
+// hasStaticFields 是否有每条日志都可能输出的字段（静态字段、条件字段、log_id 或协程 ID）
+func (l *Logger) hasStaticFields() bool {
+	return len(l.staticFields) > 0 || len(l.levelFields) > 0 || l.logID != nil || l.goroutineID
+}
+
+// appendStaticFields 追加 log_id、协程 ID、静态字段与 level 级别允许的条件字段，返回是否写入了字段
+func (l *Logger) appendStaticFields(buf []byte, level LogLevel) ([]byte, bool) {
+	wrote := false
+	if l.logID != nil {
+		buf = append(buf, LogIDKey...)
+		buf = append(buf, kvSeparator...)
+		buf = append(buf, l.logID()...)
+		wrote = true
+	}
+	if l.goroutineID {
+		if wrote {
+			buf = append(buf, kvDelimiter...)
+		}
+		buf = append(buf, GoroutineIDKey...)
+		buf = append(buf, kvSeparator...)
+		buf = strconv.AppendUint(buf, GoroutineID(), 10)
+		wrote = true
+	}
+	if len(l.staticFields) > 0 {
+		if wrote {
+			buf = append(buf, kvDelimiter...)
+		}
+		buf = append(buf, l.staticFields...)
+		wrote = true
+	}
+	if len(l.levelFields) == 0 {
+		return buf, wrote
+	}
+	buf, ok := l.appendLevelFields(buf, level, wrote)
+	return buf, wrote || ok
+}
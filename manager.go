@@ -0,0 +1,251 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\manager.go
+ * @Description: Logger 管理器（多租户隔离：按租户打标签、独立级别、独立输出与配额）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"sort"
+	"sync"
+)
+
+// 标签字段名
+const (
+	TenantKey     = "tenant_id" // 租户 Logger
+	LoggerNameKey = "logger"    // 命名 Logger
+)
+
+// tenantConfig 租户配置
+type tenantConfig struct {
+	level    *LogLevel
+	output   io.Writer
+	hooks    []IHook
+	quota    Quota
+	hasQuota bool
+}
+
+// TenantOption 租户配置选项
+type TenantOption func(*tenantConfig)
+
+// WithTenantLevel 设置租户日志级别（默认继承根 Logger）
+func WithTenantLevel(level LogLevel) TenantOption {
+	return func(c *tenantConfig) {
+		c.level = &level
+	}
+}
+
+// WithTenantOutput 将租户日志写入独立输出（如租户专属的文件写入器），不再写入根 Logger 的输出
+func WithTenantOutput(output io.Writer) TenantOption {
+	return func(c *tenantConfig) {
+		c.output = output
+	}
+}
+
+// WithTenantHooks 为租户追加钩子
+func WithTenantHooks(hooks ...IHook) TenantOption {
+	return func(c *tenantConfig) {
+		c.hooks = append(c.hooks, hooks...)
+	}
+}
+
+// WithTenantQuota 设置租户配额
+func WithTenantQuota(quota Quota) TenantOption {
+	return func(c *tenantConfig) {
+		c.quota = quota
+		c.hasQuota = true
+	}
+}
+
+// LoggerManager Logger 管理器
+// 租户、命名与包 Logger 在首次获取时基于根 Logger 创建并缓存，重新配置租户后下次获取时重建
+type LoggerManager struct {
+	root *Logger
+
+	mu      sync.RWMutex
+	configs map[string][]TenantOption
+	tenants map[string]*Logger
+	named   map[string]*Logger
+
+	packages      map[string]*Logger
+	packageLevels map[string]LogLevel
+
+	monitored []IAdapter // MonitorAdapters 登记的适配器
+}
+
+// NewLoggerManager 创建 Logger 管理器，root 为 nil 时使用全局 Logger
+func NewLoggerManager(root *Logger) *LoggerManager {
+	if root == nil {
+		root = globalLogger()
+	}
+	// 预先创建事件注册表与订阅者注册表，使之后派生的 Logger 与根 Logger 共享事件路由与订阅
+	root.ensureEvents()
+	root.ensureSubscribers()
+	return &LoggerManager{
+		root:    root,
+		configs: make(map[string][]TenantOption),
+		tenants: make(map[string]*Logger),
+		named:   make(map[string]*Logger),
+
+		packages:      make(map[string]*Logger),
+		packageLevels: make(map[string]LogLevel),
+	}
+}
+
+var (
+	defaultManager     *LoggerManager
+	defaultManagerOnce sync.Once
+)
+
+// DefaultManager 获取基于全局 Logger 的默认管理器
+func DefaultManager() *LoggerManager {
+	defaultManagerOnce.Do(func() {
+		defaultManager = NewLoggerManager(globalLogger())
+	})
+	return defaultManager
+}
+
+// ForTenant 通过默认管理器获取租户 Logger
+func ForTenant(id string) *Logger {
+	return DefaultManager().ForTenant(id)
+}
+
+// Root 返回根 Logger
+func (m *LoggerManager) Root() *Logger {
+	return m.root
+}
+
+// ConfigureTenant 设置租户配置（替换已有配置）
+func (m *LoggerManager) ConfigureTenant(id string, opts ...TenantOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.configs[id] = opts
+	delete(m.tenants, id)
+}
+
+// ForTenant 获取租户 Logger：日志带 tenant_id 标签，并按租户配置设置级别、输出、钩子与配额
+func (m *LoggerManager) ForTenant(id string) *Logger {
+	m.mu.RLock()
+	tl, ok := m.tenants[id]
+	m.mu.RUnlock()
+	if ok {
+		return tl
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tl, ok := m.tenants[id]; ok {
+		return tl
+	}
+	tl = m.newTenantLogger(id, m.configs[id])
+	m.tenants[id] = tl
+	return tl
+}
+
+// newTenantLogger 创建租户 Logger
+func (m *LoggerManager) newTenantLogger(id string, opts []TenantOption) *Logger {
+	cfg := &tenantConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	tl := m.root.With(TenantKey, id)
+	if cfg.level != nil {
+		tl.level = *cfg.level
+	}
+	if cfg.output != nil {
+		// 独立输出时不复用根 Logger 的异步队列与写锁，避免写回共享输出
+		tl.async = nil
+		tl.mu = &sync.Mutex{}
+		tl.WithOutput(cfg.output)
+	}
+	if len(cfg.hooks) > 0 {
+		tl.hooks = append(tl.hooks[:len(tl.hooks):len(tl.hooks)], cfg.hooks...)
+	}
+	if cfg.hasQuota {
+		tl.WithQuota(cfg.quota)
+	}
+	return tl
+}
+
+// RemoveTenant 移除租户配置与缓存的 Logger
+func (m *LoggerManager) RemoveTenant(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.configs, id)
+	delete(m.tenants, id)
+}
+
+// Tenants 返回已配置或已创建的租户（按 id 排序）
+func (m *LoggerManager) Tenants() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]struct{}, len(m.configs)+len(m.tenants))
+	for id := range m.configs {
+		seen[id] = struct{}{}
+	}
+	for id := range m.tenants {
+		seen[id] = struct{}{}
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TenantSuppressed 返回租户因配额被抑制的日志条数
+func (m *LoggerManager) TenantSuppressed(id string) int64 {
+	m.mu.RLock()
+	tl, ok := m.tenants[id]
+	m.mu.RUnlock()
+	if !ok {
+		return 0
+	}
+	return tl.QuotaSuppressed()
+}
+
+// Named 获取命名 Logger（日志带 logger 标签），用于按模块设置级别与配额
+func (m *LoggerManager) Named(name string) *Logger {
+	m.mu.RLock()
+	nl, ok := m.named[name]
+	m.mu.RUnlock()
+	if ok {
+		return nl
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if nl, ok := m.named[name]; ok {
+		return nl
+	}
+	nl = m.root.With(LoggerNameKey, name)
+	m.named[name] = nl
+	return nl
+}
+
+// SetQuota 设置命名 Logger 的配额，防止单个模块刷屏占满共享输出
+func (m *LoggerManager) SetQuota(name string, quota Quota) {
+	m.Named(name).WithQuota(quota)
+}
+
+// Names 返回已创建的命名 Logger（按名称排序）
+func (m *LoggerManager) Names() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := make([]string, 0, len(m.named))
+	for name := range m.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,152 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\middleware.go
+ * @Description: 通用中间件：按字段名脱敏（RedactMiddleware）与按级别采样（SamplingMiddleware）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// RedactMiddleware 按字段名脱敏中间件（不区分大小写），适用于无法在调用处标记 PII / Secret 的字段
+type RedactMiddleware struct {
+	keys     map[string]struct{}
+	action   PolicyAction
+	hasher   *HashTransformer
+	priority int
+}
+
+// RedactOption 脱敏中间件配置选项
+type RedactOption func(*RedactMiddleware)
+
+// WithRedactAction 设置处理方式（默认 PolicyMask；PolicyHash 使用 WithRedactSalt 设置的盐）
+func WithRedactAction(action PolicyAction) RedactOption {
+	return func(m *RedactMiddleware) {
+		m.action = action
+	}
+}
+
+// WithRedactSalt 设置 PolicyHash 使用的盐
+func WithRedactSalt(salt []byte) RedactOption {
+	return func(m *RedactMiddleware) {
+		m.hasher = NewHashTransformer(salt)
+	}
+}
+
+// WithRedactPriority 设置中间件优先级（默认 900，在密钥自检之前执行）
+func WithRedactPriority(priority int) RedactOption {
+	return func(m *RedactMiddleware) {
+		m.priority = priority
+	}
+}
+
+// NewRedactMiddleware 创建按字段名脱敏中间件
+//
+//	log.WithMiddleware([]logger.IMiddleware{logger.NewRedactMiddleware([]string{"password", "token"})})
+func NewRedactMiddleware(keys []string, opts ...RedactOption) *RedactMiddleware {
+	m := &RedactMiddleware{
+		keys:     make(map[string]struct{}, len(keys)),
+		action:   PolicyMask,
+		hasher:   NewHashTransformer(nil),
+		priority: 900,
+	}
+	for _, key := range keys {
+		m.keys[strings.ToLower(key)] = struct{}{}
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Process 实现 IMiddleware 接口
+func (m *RedactMiddleware) Process(entry *LogEntry, next func(*LogEntry) error) error {
+	for key, value := range entry.Fields {
+		if _, ok := m.keys[strings.ToLower(key)]; !ok {
+			continue
+		}
+		switch m.action {
+		case PolicyMask:
+			entry.Fields[key] = maskValue(value)
+		case PolicyHash:
+			entry.Fields[key] = m.hasher.Hash(value)
+		case PolicyDrop:
+			delete(entry.Fields, key)
+		}
+	}
+	return next(entry)
+}
+
+// GetName 实现 IMiddleware 接口
+func (m *RedactMiddleware) GetName() string {
+	return "redact"
+}
+
+// GetPriority 实现 IMiddleware 接口
+func (m *RedactMiddleware) GetPriority() int {
+	return m.priority
+}
+
+// SamplingMiddleware 采样中间件：低于 below 级别的日志每个级别每 every 条保留第一条，其余丢弃
+type SamplingMiddleware struct {
+	every    uint64
+	below    LogLevel
+	priority int
+	counters [FATAL - TRACE + 1]atomic.Uint64 // 按级别计数（TRACE 至 FATAL）
+}
+
+// SamplingOption 采样中间件配置选项
+type SamplingOption func(*SamplingMiddleware)
+
+// WithSamplingBelow 设置采样的级别上限，该级别及以上的日志全部保留（默认 WARN）
+func WithSamplingBelow(level LogLevel) SamplingOption {
+	return func(m *SamplingMiddleware) {
+		m.below = level
+	}
+}
+
+// WithSamplingPriority 设置中间件优先级（默认 0，最先执行以减少后续开销）
+func WithSamplingPriority(priority int) SamplingOption {
+	return func(m *SamplingMiddleware) {
+		m.priority = priority
+	}
+}
+
+// NewSamplingMiddleware 创建采样中间件，every <= 1 时不采样
+func NewSamplingMiddleware(every int, opts ...SamplingOption) *SamplingMiddleware {
+	m := &SamplingMiddleware{
+		every: uint64(max(every, 1)),
+		below: WARN,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Process 实现 IMiddleware 接口
+func (m *SamplingMiddleware) Process(entry *LogEntry, next func(*LogEntry) error) error {
+	if m.every > 1 && entry.Level < m.below && entry.Level >= TRACE && entry.Level <= FATAL {
+		if (m.counters[entry.Level-TRACE].Add(1)-1)%m.every != 0 {
+			return nil
+		}
+	}
+	return next(entry)
+}
+
+// GetName 实现 IMiddleware 接口
+func (m *SamplingMiddleware) GetName() string {
+	return "sampling"
+}
+
+// GetPriority 实现 IMiddleware 接口
+func (m *SamplingMiddleware) GetPriority() int {
+	return m.priority
+}
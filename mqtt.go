@@ -0,0 +1,529 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\mqtt.go
+ * @Description: MQTT 适配器：以 MQTT 3.1.1 将日志发布到主题（QoS 0/1/2、retain、keepalive），适用于 IoT 与边缘部署
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MQTT QoS 级别
+const (
+	MQTTAtMostOnce  byte = 0 // 至多一次
+	MQTTAtLeastOnce byte = 1 // 至少一次（等待 PUBACK）
+	MQTTExactlyOnce byte = 2 // 恰好一次（PUBREC / PUBREL / PUBCOMP）
+)
+
+// 默认配置
+const (
+	DefaultMQTTAddress   = "127.0.0.1:1883"
+	DefaultMQTTKeepAlive = 30 * time.Second
+)
+
+// MQTT 控制报文类型
+const (
+	mqttConnect    byte = 0x10
+	mqttConnAck    byte = 0x20
+	mqttPublish    byte = 0x30
+	mqttPubAck     byte = 0x40
+	mqttPubRec     byte = 0x50
+	mqttPubRel     byte = 0x62 // 固定标志位 0010
+	mqttPubComp    byte = 0x70
+	mqttPingReq    byte = 0xc0
+	mqttPingResp   byte = 0xd0
+	mqttDisconnect byte = 0xe0
+)
+
+// MQTTAdapter MQTT 发布适配器：
+//
+//	mqtt := logger.NewMQTTAdapter(logger.WithMQTTAddress("tcp://broker:1883"), logger.WithMQTTTopic("devices/gw-1/logs/{level}"), logger.WithMQTTQoS(logger.MQTTAtLeastOnce))
+//	log := logger.NewLogger().WithAdapters(mqtt)
+//
+// 每条日志编码为一条 JSON 消息，主题中的 {level} 替换为小写级别名；
+// QoS 1/2 时每条消息等待代理确认后才发布下一条，吞吐受网络往返时间限制；
+// 等待确认超时或断线后重连时保留会话（clean session = 0），沿用原报文 ID 并置 DUP 标志重发 PUBLISH，
+// 已收到 PUBREC 的 QoS 2 消息只重发 PUBREL，代理据此去重
+type MQTTAdapter struct {
+	*BaseAdapter
+	address   string
+	topic     string
+	clientID  string
+	username  string
+	password  string
+	qos       byte
+	retain    bool
+	keepAlive time.Duration
+	timeout   time.Duration
+	tls       *tls.Config
+	queueSize int
+	retries   int
+	session   mqttSession
+	publisher *brokerPublisher
+}
+
+// MQTTOption MQTT 适配器配置选项
+type MQTTOption func(*MQTTAdapter)
+
+// WithMQTTAddress 设置代理地址（host:port，可带 tcp:// 或 mqtt:// 前缀）
+func WithMQTTAddress(address string) MQTTOption {
+	return func(a *MQTTAdapter) {
+		address = strings.TrimPrefix(address, "tcp://")
+		a.address = strings.TrimPrefix(address, "mqtt://")
+	}
+}
+
+// WithMQTTTopic 设置发布主题（支持 {level}，默认 logs/{level}）
+func WithMQTTTopic(topic string) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.topic = topic
+	}
+}
+
+// WithMQTTClientID 设置客户端 ID（默认 go-logger-<主机名>-<进程号>）
+func WithMQTTClientID(clientID string) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.clientID = clientID
+	}
+}
+
+// WithMQTTCredentials 设置用户名与密码
+func WithMQTTCredentials(username, password string) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.username = username
+		a.password = password
+	}
+}
+
+// WithMQTTQoS 设置 QoS（MQTTAtMostOnce、MQTTAtLeastOnce、MQTTExactlyOnce，默认至多一次）
+func WithMQTTQoS(qos byte) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.qos = min(qos, MQTTExactlyOnce)
+	}
+}
+
+// WithMQTTRetain 设置是否为保留消息
+func WithMQTTRetain(retain bool) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.retain = retain
+	}
+}
+
+// WithMQTTKeepAlive 设置 keepalive 间隔（默认 DefaultMQTTKeepAlive）
+func WithMQTTKeepAlive(keepAlive time.Duration) MQTTOption {
+	return func(a *MQTTAdapter) {
+		if keepAlive >= time.Second {
+			a.keepAlive = keepAlive
+		}
+	}
+}
+
+// WithMQTTTimeout 设置连接与等待确认的超时（默认 DefaultBrokerTimeout）
+func WithMQTTTimeout(timeout time.Duration) MQTTOption {
+	return func(a *MQTTAdapter) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithMQTTTLS 通过 TLS 连接（通常为 8883 端口）
+func WithMQTTTLS(conf *tls.Config) MQTTOption {
+	return func(a *MQTTAdapter) {
+		a.tls = conf
+	}
+}
+
+// WithMQTTQueueSize 设置发布队列长度（默认 DefaultBrokerQueueSize）
+func WithMQTTQueueSize(size int) MQTTOption {
+	return func(a *MQTTAdapter) {
+		if size > 0 {
+			a.queueSize = size
+		}
+	}
+}
+
+// WithMQTTRetries 设置发布失败重试次数（默认 DefaultBrokerRetries）
+func WithMQTTRetries(retries int) MQTTOption {
+	return func(a *MQTTAdapter) {
+		if retries >= 0 {
+			a.retries = retries
+		}
+	}
+}
+
+// NewMQTTAdapter 创建 MQTT 适配器并启动发布协程（连接在首次发布时建立）
+func NewMQTTAdapter(opts ...MQTTOption) *MQTTAdapter {
+	host, _ := os.Hostname()
+	a := &MQTTAdapter{
+		address:   DefaultMQTTAddress,
+		topic:     "logs/{level}",
+		clientID:  "go-logger-" + host + "-" + strconv.Itoa(os.Getpid()),
+		keepAlive: DefaultMQTTKeepAlive,
+		timeout:   DefaultBrokerTimeout,
+		queueSize: DefaultBrokerQueueSize,
+		retries:   DefaultBrokerRetries,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.BaseAdapter = NewBaseAdapter("mqtt", "1.0.0", a.emit)
+	a.publisher = newBrokerPublisher(OverflowComponentMQTT, a.queueSize, a.retries, a.dial, a.SetHealthy)
+	return a
+}
+
+// emit 日志编码后入队
+func (a *MQTTAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	a.publisher.enqueue(brokerMessage{topic: expandLevelTopic(a.topic, level), payload: brokerPayload(level, msg, fields)})
+}
+
+// Flush 发布队列中的消息
+func (a *MQTTAdapter) Flush() error {
+	return a.publisher.flush()
+}
+
+// Close 发布剩余消息后断开连接
+func (a *MQTTAdapter) Close() error {
+	a.publisher.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *MQTTAdapter) QueueDepth() (depth, capacity int) {
+	return a.publisher.queueDepth()
+}
+
+// Stats 返回发布统计
+func (a *MQTTAdapter) Stats() BrokerStats {
+	return a.publisher.stats()
+}
+
+// mqttAck 代理返回的确认报文
+type mqttAck struct {
+	kind byte
+	id   uint16
+}
+
+// mqttSession 跨重连保留的会话状态（仅由发布协程访问）
+type mqttSession struct {
+	established bool         // 已成功连接过，之后重连保留会话，代理才能识别重发的报文 ID
+	nextID      uint16       // 上一个分配的报文 ID
+	inflight    mqttInflight // 最近一条 QoS 1/2 消息的确认进度
+}
+
+// mqttInflight 等待确认的消息
+type mqttInflight struct {
+	id       uint16
+	sent     bool // 已发送过 PUBLISH，重发时置 DUP 标志
+	received bool // 已收到 PUBREC，重发时只需发送 PUBREL
+}
+
+// mqttConn MQTT 连接
+type mqttConn struct {
+	conn    net.Conn
+	qos     byte
+	retain  bool
+	timeout time.Duration
+	mu      sync.Mutex // 保护写入（keepalive 协程发送 PINGREQ）
+	w       *bufio.Writer
+	session *mqttSession
+	acks    chan mqttAck
+	errs    chan error
+	stop    chan struct{}
+}
+
+// dial 建立连接并完成 CONNECT / CONNACK 握手
+func (a *MQTTAdapter) dial() (brokerConn, error) {
+	conn, err := dialTLS("tcp", a.address, a.timeout, a.tls)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags byte = 0x02 // clean session
+	if a.qos > MQTTAtMostOnce && a.session.established {
+		flags = 0 // 重连时保留会话，未确认的消息以原报文 ID 重发
+	}
+	payload := mqttAppendString(nil, a.clientID)
+	if a.username != "" {
+		flags |= 0x80
+		payload = mqttAppendString(payload, a.username)
+	}
+	if a.password != "" {
+		flags |= 0x40
+		payload = mqttAppendString(payload, a.password)
+	}
+	body := mqttAppendString(nil, "MQTT")
+	body = append(body, 0x04, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(a.keepAlive/time.Second))
+	body = append(body, payload...)
+
+	conn.SetDeadline(time.Now().Add(a.timeout))
+	if _, err := conn.Write(mqttPacket(mqttConnect, body)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	r := bufio.NewReader(conn)
+	kind, resp, err := mqttReadPacket(r)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if kind != mqttConnAck || len(resp) < 2 {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt: unexpected packet 0x%02x during connect", kind)
+	}
+	if resp[1] != 0 {
+		conn.Close()
+		return nil, fmt.Errorf("mqtt: connection refused, return code %d", resp[1])
+	}
+	conn.SetDeadline(time.Time{})
+	a.session.established = true
+
+	c := &mqttConn{
+		conn:    conn,
+		qos:     a.qos,
+		retain:  a.retain,
+		timeout: a.timeout,
+		w:       bufio.NewWriterSize(conn, 32*1024),
+		session: &a.session,
+		acks:    make(chan mqttAck, 16),
+		errs:    make(chan error, 1),
+		stop:    make(chan struct{}),
+	}
+	go c.read(r)
+	go c.keepAlive(a.keepAlive)
+	return c, nil
+}
+
+// read 读取代理报文并转发确认
+func (c *mqttConn) read(r *bufio.Reader) {
+	for {
+		kind, body, err := mqttReadPacket(r)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		switch kind &^ 0x0f {
+		case mqttPubAck, mqttPubRec, mqttPubComp:
+			if len(body) >= 2 {
+				select {
+				case c.acks <- mqttAck{kind: kind &^ 0x0f, id: binary.BigEndian.Uint16(body)}:
+				case <-c.stop:
+					return
+				}
+			}
+		}
+	}
+}
+
+// keepAlive 按 keepalive 的一半间隔发送 PINGREQ
+func (c *mqttConn) keepAlive(interval time.Duration) {
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.write(mqttPacket(mqttPingReq, nil), true); err != nil {
+				c.fail(err)
+				return
+			}
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// fail 记录连接错误（只保留第一个）
+func (c *mqttConn) fail(err error) {
+	select {
+	case c.errs <- err:
+	default:
+	}
+}
+
+// write 写入报文
+func (c *mqttConn) write(packet []byte, flush bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if _, err := c.w.Write(packet); err != nil {
+		return err
+	}
+	if flush {
+		return c.w.Flush()
+	}
+	return nil
+}
+
+// publish 发布消息：QoS 0 只写入缓冲区，QoS 1/2 写出后等待确认
+// retry 时沿用上一次分配的报文 ID：未收到 PUBREC 则置 DUP 标志重发 PUBLISH，否则只重发 PUBREL
+func (c *mqttConn) publish(topic string, payload []byte, retry bool) error {
+	select {
+	case err := <-c.errs:
+		c.fail(err)
+		return err
+	default:
+	}
+
+	if c.qos == MQTTAtMostOnce {
+		return c.write(c.publishPacket(topic, payload, 0, false), false)
+	}
+
+	s := c.session
+	if !retry {
+		s.nextID++
+		if s.nextID == 0 {
+			s.nextID = 1
+		}
+		s.inflight = mqttInflight{id: s.nextID}
+	}
+	id := s.inflight.id
+
+	if !s.inflight.received {
+		dup := s.inflight.sent
+		s.inflight.sent = true
+		if err := c.write(c.publishPacket(topic, payload, id, dup), true); err != nil {
+			return err
+		}
+		if c.qos == MQTTAtLeastOnce {
+			return c.waitAck(mqttPubAck, id)
+		}
+		if err := c.waitAck(mqttPubRec, id); err != nil {
+			return err
+		}
+		s.inflight.received = true
+	}
+	if err := c.write(mqttPacket(mqttPubRel, binary.BigEndian.AppendUint16(nil, id)), true); err != nil {
+		return err
+	}
+	return c.waitAck(mqttPubComp, id)
+}
+
+// publishPacket 组装 PUBLISH 报文（QoS 0 不含报文 ID，dup 表示重发）
+func (c *mqttConn) publishPacket(topic string, payload []byte, id uint16, dup bool) []byte {
+	header := mqttPublish | c.qos<<1
+	if dup {
+		header |= 0x08
+	}
+	if c.retain {
+		header |= 0x01
+	}
+	body := mqttAppendString(nil, topic)
+	if c.qos > MQTTAtMostOnce {
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	return mqttPacket(header, append(body, payload...))
+}
+
+// waitAck 等待指定报文 ID 的确认（忽略过期的确认）
+func (c *mqttConn) waitAck(kind byte, id uint16) error {
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case ack := <-c.acks:
+			if ack.kind == kind && ack.id == id {
+				return nil
+			}
+		case err := <-c.errs:
+			c.fail(err)
+			return err
+		case <-timer.C:
+			return fmt.Errorf("mqtt: timeout waiting for ack of packet %d", id)
+		}
+	}
+}
+
+// flush 写出缓冲区
+func (c *mqttConn) flush() error {
+	select {
+	case err := <-c.errs:
+		c.fail(err)
+		return err
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	return c.w.Flush()
+}
+
+// close 发送 DISCONNECT 后关闭连接
+func (c *mqttConn) close() error {
+	close(c.stop)
+	c.write(mqttPacket(mqttDisconnect, nil), true)
+	return c.conn.Close()
+}
+
+// mqttPacket 组装报文（固定头 + 剩余长度 + 内容）
+func mqttPacket(header byte, body []byte) []byte {
+	packet := make([]byte, 0, len(body)+5)
+	packet = append(packet, header)
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		packet = append(packet, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// mqttAppendString 追加 UTF-8 字符串（2 字节长度前缀）
+func mqttAppendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// mqttReadPacket 读取一个报文，返回固定头与内容
+func mqttReadPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, errors.New("mqtt: malformed remaining length")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+var _ IAdapter = (*MQTTAdapter)(nil)
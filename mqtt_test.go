@@ -0,0 +1,210 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\mqtt_test.go
+ * @Description: MQTT 适配器测试（QoS 1/2 确认超时后以原报文 ID 与 DUP 标志重发）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMQTTBroker 记录每个连接收到的报文；首个连接上 silent 中的报文类型不回复确认
+type fakeMQTTBroker struct {
+	ln     net.Listener
+	silent map[byte]bool
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	traces [][]string
+}
+
+func newFakeMQTTBroker(t *testing.T, silent ...byte) *fakeMQTTBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeMQTTBroker{ln: ln, silent: map[byte]bool{}}
+	for _, kind := range silent {
+		b.silent[kind] = true
+	}
+	b.wg.Add(1)
+	go b.accept()
+	return b
+}
+
+func (b *fakeMQTTBroker) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		index := len(b.traces)
+		b.traces = append(b.traces, nil)
+		b.mu.Unlock()
+		b.wg.Add(1)
+		go b.serve(conn, index)
+	}
+}
+
+// serve 按报文类型回复：CONNECT→CONNACK，PUBLISH→PUBACK/PUBREC，PUBREL→PUBCOMP
+func (b *fakeMQTTBroker) serve(conn net.Conn, index int) {
+	defer b.wg.Done()
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := mqttReadPacket(r)
+		if err != nil {
+			return
+		}
+		kind := header & 0xf0
+		if kind == mqttPingReq {
+			continue
+		}
+		b.mu.Lock()
+		b.traces[index] = append(b.traces[index], describeMQTTPacket(header, body))
+		b.mu.Unlock()
+		if index == 0 && b.silent[kind] {
+			continue
+		}
+
+		switch kind {
+		case mqttConnect:
+			conn.Write(mqttPacket(mqttConnAck, []byte{0, 0}))
+		case mqttPublish:
+			switch qos := header >> 1 & 0x03; qos {
+			case MQTTAtLeastOnce, MQTTExactlyOnce:
+				topicLen := int(binary.BigEndian.Uint16(body))
+				id := body[2+topicLen : 4+topicLen]
+				reply := mqttPubAck
+				if qos == MQTTExactlyOnce {
+					reply = mqttPubRec
+				}
+				conn.Write(mqttPacket(reply, id))
+			}
+		case mqttPubRel & 0xf0:
+			conn.Write(mqttPacket(mqttPubComp, body[:2]))
+		}
+	}
+}
+
+// close 关闭监听并等待所有连接结束
+func (b *fakeMQTTBroker) close() [][]string {
+	b.ln.Close()
+	b.wg.Wait()
+	return b.traces
+}
+
+// describeMQTTPacket 报文摘要（连接是否保留会话、报文 ID 与 DUP 标志）
+func describeMQTTPacket(header byte, body []byte) string {
+	switch header & 0xf0 {
+	case mqttConnect:
+		if body[7]&0x02 != 0 {
+			return "connect clean"
+		}
+		return "connect resume"
+	case mqttPublish:
+		topicLen := int(binary.BigEndian.Uint16(body))
+		s := fmt.Sprintf("publish id=%d", binary.BigEndian.Uint16(body[2+topicLen:]))
+		if header&0x08 != 0 {
+			s += " dup"
+		}
+		return s
+	case mqttPubRel & 0xf0:
+		return fmt.Sprintf("pubrel id=%d", binary.BigEndian.Uint16(body))
+	case mqttDisconnect:
+		return "disconnect"
+	}
+	return fmt.Sprintf("0x%02x", header)
+}
+
+// TestMQTTRetryResendsSamePacket 确认超时后重连并保留会话：未收到 PUBREC 时以原报文 ID 置 DUP 重发 PUBLISH，
+// 已收到 PUBREC 时只重发 PUBREL；之后的消息分配新报文 ID
+func TestMQTTRetryResendsSamePacket(t *testing.T) {
+	tests := []struct {
+		name   string
+		qos    byte
+		silent byte // 首个连接上不回复的报文类型（0 表示正常回复）
+		want   [][]string
+	}{
+		{
+			name: "qos2 acknowledged",
+			qos:  MQTTExactlyOnce,
+			want: [][]string{
+				{"connect clean", "publish id=1", "pubrel id=1", "publish id=2", "pubrel id=2", "disconnect"},
+			},
+		},
+		{
+			name:   "qos1 puback timeout",
+			qos:    MQTTAtLeastOnce,
+			silent: mqttPublish,
+			want: [][]string{
+				{"connect clean", "publish id=1", "disconnect"},
+				{"connect resume", "publish id=1 dup", "publish id=2", "disconnect"},
+			},
+		},
+		{
+			name:   "qos2 pubrec timeout",
+			qos:    MQTTExactlyOnce,
+			silent: mqttPublish,
+			want: [][]string{
+				{"connect clean", "publish id=1", "disconnect"},
+				{"connect resume", "publish id=1 dup", "pubrel id=1", "publish id=2", "pubrel id=2", "disconnect"},
+			},
+		},
+		{
+			name:   "qos2 pubcomp timeout",
+			qos:    MQTTExactlyOnce,
+			silent: mqttPubRel & 0xf0,
+			want: [][]string{
+				{"connect clean", "publish id=1", "pubrel id=1", "disconnect"},
+				{"connect resume", "pubrel id=1", "publish id=2", "pubrel id=2", "disconnect"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var silent []byte
+			if tt.silent != 0 {
+				silent = append(silent, tt.silent)
+			}
+			broker := newFakeMQTTBroker(t, silent...)
+
+			a := NewMQTTAdapter(
+				WithMQTTAddress(broker.ln.Addr().String()),
+				WithMQTTTopic("logs"),
+				WithMQTTQoS(tt.qos),
+				WithMQTTTimeout(200*time.Millisecond),
+				WithMQTTRetries(2),
+			)
+			a.Info("first")
+			if err := a.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			a.Info("second")
+			a.Close()
+
+			if got := broker.close(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("packets =\n%q\nwant\n%q", got, tt.want)
+			}
+			if stats := a.Stats(); stats.Published != 2 || stats.Failed != 0 {
+				t.Errorf("stats = %+v, want 2 published", stats)
+			}
+		})
+	}
+}
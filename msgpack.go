@@ -0,0 +1,389 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\msgpack.go
+ * @Description: 精简的 MessagePack 编解码（Fluent forward 协议使用），覆盖日志字段常见类型
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// 解码上限：解码的数据来自网络（Fluent ack），长度字段不可信，超过上限直接报错而不按声明的长度分配内存
+const (
+	msgpackMaxBytes = 1 << 20 // 字符串、二进制与扩展类型的最大字节数
+	msgpackMaxItems = 1 << 16 // 数组元素与 map 键值对的最大数量
+	msgpackMaxDepth = 32      // 数组与 map 的最大嵌套层数
+)
+
+// errMsgpackLimit 长度或嵌套层数超过解码上限
+var errMsgpackLimit = errors.New("msgpack: value exceeds decode limit")
+
+// msgpackAppendValue 追加任意值的编码（不支持的类型按 fmt.Sprint 编码为字符串）
+func msgpackAppendValue(b []byte, v any) []byte {
+	switch x := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if x {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case string:
+		return msgpackAppendString(b, x)
+	case []byte:
+		return msgpackAppendBinary(b, x)
+	case int:
+		return msgpackAppendInt(b, int64(x))
+	case int8:
+		return msgpackAppendInt(b, int64(x))
+	case int16:
+		return msgpackAppendInt(b, int64(x))
+	case int32:
+		return msgpackAppendInt(b, int64(x))
+	case int64:
+		return msgpackAppendInt(b, x)
+	case uint:
+		return msgpackAppendUint(b, uint64(x))
+	case uint8:
+		return msgpackAppendUint(b, uint64(x))
+	case uint16:
+		return msgpackAppendUint(b, uint64(x))
+	case uint32:
+		return msgpackAppendUint(b, uint64(x))
+	case uint64:
+		return msgpackAppendUint(b, x)
+	case float32:
+		b = append(b, 0xca)
+		return binary.BigEndian.AppendUint32(b, math.Float32bits(x))
+	case float64:
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(x))
+	case time.Time:
+		return msgpackAppendString(b, x.Format(time.RFC3339Nano))
+	case time.Duration:
+		return msgpackAppendString(b, x.String())
+	case error:
+		return msgpackAppendString(b, x.Error())
+	case fmt.Stringer:
+		return msgpackAppendString(b, x.String())
+	case map[string]any:
+		return msgpackAppendMap(b, x)
+	case []any:
+		b = msgpackAppendArrayHeader(b, len(x))
+		for _, item := range x {
+			b = msgpackAppendValue(b, item)
+		}
+		return b
+	case []string:
+		b = msgpackAppendArrayHeader(b, len(x))
+		for _, item := range x {
+			b = msgpackAppendString(b, item)
+		}
+		return b
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		b = msgpackAppendArrayHeader(b, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			b = msgpackAppendValue(b, rv.Index(i).Interface())
+		}
+		return b
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return append(b, 0xc0)
+		}
+		return msgpackAppendValue(b, rv.Elem().Interface())
+	}
+	return msgpackAppendString(b, fmt.Sprint(v))
+}
+
+// msgpackAppendMap 追加 map 编码（按键排序，输出稳定）
+func msgpackAppendMap(b []byte, m map[string]any) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b = msgpackAppendMapHeader(b, len(m))
+	for _, k := range keys {
+		b = msgpackAppendString(b, k)
+		b = msgpackAppendValue(b, m[k])
+	}
+	return b
+}
+
+// msgpackAppendInt 追加有符号整数（非负数按无符号编码）
+func msgpackAppendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return msgpackAppendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+// msgpackAppendUint 追加无符号整数
+func msgpackAppendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+// msgpackAppendString 追加字符串
+func msgpackAppendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// msgpackAppendBinary 追加二进制数据
+func msgpackAppendBinary(b []byte, data []byte) []byte {
+	n := len(data)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xc5), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, data...)
+}
+
+// msgpackAppendArrayHeader 追加数组头
+func msgpackAppendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+}
+
+// msgpackAppendMapHeader 追加 map 头
+func msgpackAppendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+// msgpackAppendEventTime 追加 Fluent EventTime 扩展类型（type 0，秒 + 纳秒）
+func msgpackAppendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// msgpackDecode 解码一个值：map 解码为 map[string]any，数组为 []any，整数为 int64/uint64，扩展类型为 []byte
+// 长度、元素数量或嵌套层数超过解码上限时返回 errMsgpackLimit
+func msgpackDecode(r *bufio.Reader) (any, error) {
+	return msgpackDecodeDepth(r, 0)
+}
+
+// msgpackDecodeDepth 解码一个值，depth 为当前嵌套层数
+func msgpackDecodeDepth(r *bufio.Reader, depth int) (any, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return msgpackReadString(r, int(c&0x1f))
+	case c&0xf0 == 0x90:
+		return msgpackReadArray(r, int(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return msgpackReadMap(r, int(c&0x0f), depth)
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := msgpackReadLength(r, c-0xc4)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackReadBytes(r, n)
+	case 0xca:
+		v, err := msgpackReadUint(r, 4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := msgpackReadUint(r, 8)
+		return math.Float64frombits(v), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return msgpackReadUint(r, 1<<(c-0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		v, err := msgpackReadUint(r, size)
+		shift := 64 - 8*size
+		return int64(v<<shift) >> shift, err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		if _, err := r.ReadByte(); err != nil {
+			return nil, err
+		}
+		return msgpackReadBytes(r, 1<<(c-0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := msgpackReadLength(r, c-0xc7)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.ReadByte(); err != nil {
+			return nil, err
+		}
+		return msgpackReadBytes(r, n)
+	case 0xd9, 0xda, 0xdb:
+		n, err := msgpackReadLength(r, c-0xd9)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackReadString(r, n)
+	case 0xdc, 0xdd:
+		n, err := msgpackReadLength(r, c-0xdc+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackReadArray(r, n, depth)
+	case 0xde, 0xdf:
+		n, err := msgpackReadLength(r, c-0xde+1)
+		if err != nil {
+			return nil, err
+		}
+		return msgpackReadMap(r, n, depth)
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type 0x%02x", c)
+}
+
+// msgpackReadLength 读取长度（sizeClass 0/1/2 分别为 1/2/4 字节）
+func msgpackReadLength(r *bufio.Reader, sizeClass byte) (int, error) {
+	v, err := msgpackReadUint(r, 1<<sizeClass)
+	return int(v), err
+}
+
+// msgpackReadUint 读取 size 字节的大端无符号整数
+func msgpackReadUint(r *bufio.Reader, size int) (uint64, error) {
+	var v uint64
+	for i := 0; i < size; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// msgpackReadBytes 读取 n 字节
+func msgpackReadBytes(r *bufio.Reader, n int) ([]byte, error) {
+	if n < 0 || n > msgpackMaxBytes {
+		return nil, fmt.Errorf("%w: %d bytes", errMsgpackLimit, n)
+	}
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return buf, err
+}
+
+// msgpackReadString 读取 n 字节字符串
+func msgpackReadString(r *bufio.Reader, n int) (string, error) {
+	buf, err := msgpackReadBytes(r, n)
+	return string(buf), err
+}
+
+// msgpackReadArray 读取 n 个元素
+func msgpackReadArray(r *bufio.Reader, n, depth int) ([]any, error) {
+	if err := msgpackCheckContainer(n, depth); err != nil {
+		return nil, err
+	}
+	items := make([]any, 0, n)
+	for i := 0; i < n; i++ {
+		v, err := msgpackDecodeDepth(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, v)
+	}
+	return items, nil
+}
+
+// msgpackReadMap 读取 n 个键值对（键按字符串处理）
+func msgpackReadMap(r *bufio.Reader, n, depth int) (map[string]any, error) {
+	if err := msgpackCheckContainer(n, depth); err != nil {
+		return nil, err
+	}
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := msgpackDecodeDepth(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := msgpackDecodeDepth(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
+
+// msgpackCheckContainer 检查数组或 map 的元素数量与嵌套层数
+func msgpackCheckContainer(n, depth int) error {
+	if n < 0 || n > msgpackMaxItems {
+		return fmt.Errorf("%w: %d items", errMsgpackLimit, n)
+	}
+	if depth >= msgpackMaxDepth {
+		return fmt.Errorf("%w: nested deeper than %d", errMsgpackLimit, msgpackMaxDepth)
+	}
+	return nil
+}
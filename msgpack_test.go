@@ -0,0 +1,213 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\msgpack_test.go
+ * @Description: MessagePack 编解码与 Fluent forward 协议测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMsgpackEncoding 编码结果与 MessagePack 规范一致
+func TestMsgpackEncoding(t *testing.T) {
+	cases := []struct {
+		value any
+		want  string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{int8(5), "05"},
+		{-1, "ff"},
+		{-33, "d0df"},
+		{-200, "d1ff38"},
+		{200, "ccc8"},
+		{300, "cd012c"},
+		{uint32(70000), "ce00011170"},
+		{uint64(1) << 40, "cf0000010000000000"},
+		{1.5, "cb3ff8000000000000"},
+		{"a", "a161"},
+		{strings.Repeat("x", 40), "d928" + strings.Repeat("78", 40)},
+		{[]byte{1, 2}, "c4020102"},
+		{[]any{1, "b"}, "9201a162"},
+		{map[string]any{"b": 2, "a": 1}, "82a16101a16202"},
+	}
+	for _, tc := range cases {
+		if got := hex.EncodeToString(msgpackAppendValue(nil, tc.value)); got != tc.want {
+			t.Errorf("encode %#v = %s, want %s", tc.value, got, tc.want)
+		}
+	}
+
+	ts := time.Unix(1700000000, 123)
+	if got := hex.EncodeToString(msgpackAppendEventTime(nil, ts)); got != "d7006553f1000000007b" {
+		t.Errorf("event time = %s", got)
+	}
+}
+
+// TestMsgpackRoundTrip 编码后解码得到等价的值
+func TestMsgpackRoundTrip(t *testing.T) {
+	in := map[string]any{
+		"str":    "hello",
+		"long":   strings.Repeat("y", 70000),
+		"small":  7,
+		"big":    uint64(1) << 63,
+		"neg":    int64(-1) << 40,
+		"float":  2.25,
+		"bool":   false,
+		"nil":    nil,
+		"list":   []any{"a", -3, []any{true}},
+		"nested": map[string]any{"k": "v"},
+	}
+	want := map[string]any{
+		"str":    "hello",
+		"long":   strings.Repeat("y", 70000),
+		"small":  int64(7),
+		"big":    uint64(1) << 63,
+		"neg":    int64(-1) << 40,
+		"float":  2.25,
+		"bool":   false,
+		"nil":    nil,
+		"list":   []any{"a", int64(-3), []any{true}},
+		"nested": map[string]any{"k": "v"},
+	}
+	got, err := msgpackDecode(bufio.NewReader(bytes.NewReader(msgpackAppendValue(nil, in))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %#v, want %#v", got, want)
+	}
+}
+
+// TestMsgpackDecodeLimits 超过解码上限的长度与嵌套直接报错，不按声明的长度分配内存
+func TestMsgpackDecodeLimits(t *testing.T) {
+	cases := map[string][]byte{
+		"str32":   {0xdb, 0xff, 0xff, 0xff, 0xff},
+		"bin32":   {0xc6, 0x7f, 0xff, 0xff, 0xff},
+		"ext32":   {0xc9, 0x7f, 0xff, 0xff, 0xff, 0x01},
+		"array32": {0xdd, 0xff, 0xff, 0xff, 0xff},
+		"map32":   {0xdf, 0x10, 0x00, 0x00, 0x00},
+		"nesting": bytes.Repeat([]byte{0x91}, 1000),
+	}
+	for name, data := range cases {
+		allocs := testing.AllocsPerRun(1, func() {
+			_, err := msgpackDecode(bufio.NewReader(bytes.NewReader(data)))
+			if !errors.Is(err, errMsgpackLimit) {
+				t.Errorf("%s: err = %v, want errMsgpackLimit", name, err)
+			}
+		})
+		if name != "nesting" && allocs > 10 {
+			t.Errorf("%s: %v allocations before rejecting", name, allocs)
+		}
+	}
+}
+
+// TestFluentForwardAck 以 Forward 模式发送一批事件，服务端返回的 ack 与 chunk 一致时视为发送成功
+func TestFluentForwardAck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan []any, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		msg, err := msgpackDecode(r)
+		if err != nil {
+			t.Errorf("server decode: %v", err)
+			return
+		}
+		forward, _ := msg.([]any)
+		received <- forward
+		if len(forward) == 3 {
+			option, _ := forward[2].(map[string]any)
+			conn.Write(msgpackAppendMap(nil, map[string]any{"ack": option["chunk"]}))
+		}
+	}()
+
+	a := NewFluentAdapter(
+		WithFluentAddress(ln.Addr().String()),
+		WithFluentTag("app.test"),
+		WithFluentAck(true),
+		WithFluentTimeout(5*time.Second),
+		WithFluentRetries(0),
+	)
+	defer a.Close()
+	a.InfoKV("first", "user", "u1")
+	a.WarnKV("second", "attempt", 2)
+	if err := a.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	forward := <-received
+	if len(forward) != 3 || forward[0] != "app.test" {
+		t.Fatalf("unexpected forward message: %#v", forward)
+	}
+	entries, _ := forward[1].([]any)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	first, _ := entries[0].([]any)
+	if len(first) != 2 {
+		t.Fatalf("entry = %#v, want [time, record]", entries[0])
+	}
+	if ts, ok := first[0].([]byte); !ok || len(ts) != 8 {
+		t.Errorf("event time = %#v, want 8-byte EventTime", first[0])
+	}
+	record, _ := first[1].(map[string]any)
+	if record["message"] != "first" || record["level"] != INFO.String() || record["user"] != "u1" {
+		t.Errorf("record = %#v", record)
+	}
+	option, _ := forward[2].(map[string]any)
+	if option["size"] != int64(2) || option["chunk"] == "" {
+		t.Errorf("option = %#v", option)
+	}
+	if sent, failed, _ := a.Stats(); sent != 2 || failed != 0 {
+		t.Errorf("sent=%d failed=%d, want 2/0", sent, failed)
+	}
+}
+
+// TestFluentAckMismatch 服务端返回的 ack 与 chunk 不一致时发送失败
+func TestFluentAckMismatch(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := msgpackDecode(bufio.NewReader(conn)); err == nil {
+			conn.Write(msgpackAppendMap(nil, map[string]any{"ack": "other"}))
+		}
+	}()
+
+	a := NewFluentAdapter(WithFluentAddress(ln.Addr().String()), WithFluentAck(true), WithFluentRetries(0), WithFluentTimeout(5*time.Second))
+	defer a.Close()
+	a.Info("lost")
+	if err := a.Flush(); !errors.Is(err, ErrFluentAckMismatch) {
+		t.Errorf("Flush err = %v, want ErrFluentAckMismatch", err)
+	}
+}
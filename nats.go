@@ -0,0 +1,340 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\nats.go
+ * @Description: NATS 适配器：以 NATS 核心协议（PUB）将日志发布到主题，适用于边缘与轻量部署
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNATSAddress 默认 NATS 地址
+const DefaultNATSAddress = "127.0.0.1:4222"
+
+// NATSAdapter NATS 发布适配器：
+//
+//	nats := logger.NewNATSAdapter(logger.WithNATSAddress("nats:4222"), logger.WithNATSSubject("logs.checkout.{level}"))
+//	log := logger.NewLogger().WithAdapters(nats)
+//
+// 每条日志编码为一条 JSON 消息，主题中的 {level} 替换为小写级别名；
+// 核心 NATS 为至多一次语义，Flush 通过 PING/PONG 确认服务端已收到此前发布的消息
+type NATSAdapter struct {
+	*BaseAdapter
+	address   string
+	subject   string
+	name      string
+	user      string
+	password  string
+	token     string
+	timeout   time.Duration
+	tls       *tls.Config
+	queueSize int
+	retries   int
+	publisher *brokerPublisher
+}
+
+// NATSOption NATS 适配器配置选项
+type NATSOption func(*NATSAdapter)
+
+// WithNATSAddress 设置服务端地址（host:port，可带 nats:// 前缀）
+func WithNATSAddress(address string) NATSOption {
+	return func(a *NATSAdapter) {
+		a.address = strings.TrimPrefix(address, "nats://")
+	}
+}
+
+// WithNATSSubject 设置发布主题（支持 {level}，默认 logs.{level}）
+func WithNATSSubject(subject string) NATSOption {
+	return func(a *NATSAdapter) {
+		a.subject = subject
+	}
+}
+
+// WithNATSName 设置连接名（便于在服务端监控中识别）
+func WithNATSName(name string) NATSOption {
+	return func(a *NATSAdapter) {
+		a.name = name
+	}
+}
+
+// WithNATSUserInfo 设置用户名与密码认证
+func WithNATSUserInfo(user, password string) NATSOption {
+	return func(a *NATSAdapter) {
+		a.user = user
+		a.password = password
+	}
+}
+
+// WithNATSToken 设置 token 认证
+func WithNATSToken(token string) NATSOption {
+	return func(a *NATSAdapter) {
+		a.token = token
+	}
+}
+
+// WithNATSTimeout 设置连接与 Flush 超时（默认 DefaultBrokerTimeout）
+func WithNATSTimeout(timeout time.Duration) NATSOption {
+	return func(a *NATSAdapter) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithNATSTLS 读取 INFO 后将连接升级为 TLS（服务端要求 tls_required 时必须设置）
+func WithNATSTLS(conf *tls.Config) NATSOption {
+	return func(a *NATSAdapter) {
+		a.tls = conf
+	}
+}
+
+// WithNATSQueueSize 设置发布队列长度（默认 DefaultBrokerQueueSize）
+func WithNATSQueueSize(size int) NATSOption {
+	return func(a *NATSAdapter) {
+		if size > 0 {
+			a.queueSize = size
+		}
+	}
+}
+
+// WithNATSRetries 设置发布失败重试次数（默认 DefaultBrokerRetries）
+func WithNATSRetries(retries int) NATSOption {
+	return func(a *NATSAdapter) {
+		if retries >= 0 {
+			a.retries = retries
+		}
+	}
+}
+
+// NewNATSAdapter 创建 NATS 适配器并启动发布协程（连接在首次发布时建立）
+func NewNATSAdapter(opts ...NATSOption) *NATSAdapter {
+	a := &NATSAdapter{
+		address:   DefaultNATSAddress,
+		subject:   "logs.{level}",
+		timeout:   DefaultBrokerTimeout,
+		queueSize: DefaultBrokerQueueSize,
+		retries:   DefaultBrokerRetries,
+	}
+	a.name, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.BaseAdapter = NewBaseAdapter("nats", "1.0.0", a.emit)
+	a.publisher = newBrokerPublisher(OverflowComponentNATS, a.queueSize, a.retries, a.dial, a.SetHealthy)
+	return a
+}
+
+// emit 日志编码后入队
+func (a *NATSAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	a.publisher.enqueue(brokerMessage{topic: expandLevelTopic(a.subject, level), payload: brokerPayload(level, msg, fields)})
+}
+
+// Flush 发布队列中的消息并等待服务端确认
+func (a *NATSAdapter) Flush() error {
+	return a.publisher.flush()
+}
+
+// Close 发布剩余消息后关闭连接
+func (a *NATSAdapter) Close() error {
+	a.publisher.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *NATSAdapter) QueueDepth() (depth, capacity int) {
+	return a.publisher.queueDepth()
+}
+
+// Stats 返回发布统计
+func (a *NATSAdapter) Stats() BrokerStats {
+	return a.publisher.stats()
+}
+
+// natsConn NATS 连接
+type natsConn struct {
+	conn    net.Conn
+	timeout time.Duration
+	mu      sync.Mutex // 保护写入（读协程需要回复 PONG）
+	w       *bufio.Writer
+	pongs   chan struct{}
+	errs    chan error
+}
+
+// dial 建立连接并完成握手（INFO → CONNECT → PING/PONG）
+func (a *NATSAdapter) dial() (brokerConn, error) {
+	conn, err := net.DialTimeout("tcp", a.address, a.timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(a.timeout))
+	r := bufio.NewReader(conn)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return nil, fmt.Errorf("nats: unexpected greeting %q", strings.TrimSpace(line))
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	json.Unmarshal([]byte(line[5:]), &info)
+	if a.tls != nil {
+		// NATS 先以明文发送 INFO，随后在同一连接上升级为 TLS
+		if conn, err = clientTLS(conn, a.address, a.timeout, a.tls); err != nil {
+			return nil, fmt.Errorf("nats: tls handshake: %w", err)
+		}
+		r = bufio.NewReader(conn)
+	} else if info.TLSRequired {
+		conn.Close()
+		return nil, errors.New("nats: server requires TLS (see WithNATSTLS)")
+	}
+
+	connect, _ := json.Marshal(map[string]any{
+		"verbose":    false,
+		"pedantic":   false,
+		"name":       a.name,
+		"lang":       "go",
+		"version":    "1.0.0",
+		"user":       a.user,
+		"pass":       a.password,
+		"auth_token": a.token,
+	})
+	if _, err := conn.Write([]byte("CONNECT " + string(connect) + "\r\nPING\r\n")); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	for {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "PONG" {
+			break
+		}
+		if line == "PING" {
+			conn.Write([]byte("PONG\r\n"))
+		}
+		if strings.HasPrefix(line, "-ERR") {
+			conn.Close()
+			return nil, fmt.Errorf("nats: %s", line)
+		}
+	}
+	conn.SetDeadline(time.Time{})
+
+	c := &natsConn{
+		conn:    conn,
+		timeout: a.timeout,
+		w:       bufio.NewWriterSize(conn, 32*1024),
+		pongs:   make(chan struct{}, 16),
+		errs:    make(chan error, 1),
+	}
+	go c.read(r)
+	return c, nil
+}
+
+// read 处理服务端消息：回复 PING，转发 PONG 与错误
+func (c *natsConn) read(r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		switch line = strings.TrimSpace(line); {
+		case line == "PING":
+			c.mu.Lock()
+			c.w.WriteString("PONG\r\n")
+			err = c.w.Flush()
+			c.mu.Unlock()
+			if err != nil {
+				c.fail(err)
+				return
+			}
+		case line == "PONG":
+			select {
+			case c.pongs <- struct{}{}:
+			default:
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			c.fail(fmt.Errorf("nats: %s", line))
+		}
+	}
+}
+
+// fail 记录连接错误（只保留第一个）
+func (c *natsConn) fail(err error) {
+	select {
+	case c.errs <- err:
+	default:
+	}
+}
+
+// publish 写入 PUB 命令（缓冲）
+func (c *natsConn) publish(subject string, payload []byte, _ bool) error {
+	select {
+	case err := <-c.errs:
+		c.fail(err)
+		return err
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.w.WriteString("PUB " + subject + " " + strconv.Itoa(len(payload)) + "\r\n")
+	c.w.Write(payload)
+	_, err := c.w.WriteString("\r\n")
+	return err
+}
+
+// flush 写出缓冲区并通过 PING/PONG 确认
+func (c *natsConn) flush() error {
+	c.mu.Lock()
+	c.w.WriteString("PING\r\n")
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	err := c.w.Flush()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case <-c.pongs:
+		return nil
+	case err := <-c.errs:
+		c.fail(err)
+		return err
+	case <-timer.C:
+		return errors.New("nats: flush timeout")
+	}
+}
+
+// close 关闭连接
+func (c *natsConn) close() error {
+	return c.conn.Close()
+}
+
+var _ IAdapter = (*NATSAdapter)(nil)
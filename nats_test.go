@@ -0,0 +1,197 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\nats_test.go
+ * @Description: NATS 适配器测试（握手、按级别展开主题、PING/PONG 确认与认证失败）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// natsPub 伪服务端收到的 PUB 命令
+type natsPub struct {
+	subject string
+	event   map[string]any
+}
+
+// fakeNATSServer 单连接 NATS 伪服务端：记录 CONNECT 与 PUB，回复 PING；reject 非空时以 -ERR 拒绝连接
+type fakeNATSServer struct {
+	ln      net.Listener
+	reject  string
+	done    chan struct{}
+	mu      sync.Mutex
+	connect map[string]any
+	pubs    []natsPub
+}
+
+func newFakeNATSServer(t *testing.T, reject string) *fakeNATSServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeNATSServer{ln: ln, reject: reject, done: make(chan struct{})}
+	go s.serve(t)
+	return s
+}
+
+func (s *fakeNATSServer) serve(t *testing.T) {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte(`INFO {"server_id":"test","max_payload":1048576}` + "\r\n"))
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			s.mu.Lock()
+			json.Unmarshal([]byte(line[len("CONNECT "):]), &s.connect)
+			s.mu.Unlock()
+			if s.reject != "" {
+				conn.Write([]byte("-ERR '" + s.reject + "'\r\n"))
+				return
+			}
+		case line == "PING":
+			conn.Write([]byte("PONG\r\n"))
+		case strings.HasPrefix(line, "PUB "):
+			parts := strings.Fields(line)
+			size, _ := strconv.Atoi(parts[len(parts)-1])
+			payload := make([]byte, size+2) // 含结尾 \r\n
+			if _, err := io.ReadFull(r, payload); err != nil {
+				t.Errorf("read payload: %v", err)
+				return
+			}
+			var event map[string]any
+			if err := json.Unmarshal(payload[:size], &event); err != nil {
+				t.Errorf("payload is not JSON: %q", payload[:size])
+			}
+			s.mu.Lock()
+			s.pubs = append(s.pubs, natsPub{subject: parts[1], event: event})
+			s.mu.Unlock()
+		}
+	}
+}
+
+// close 关闭监听并等待连接结束
+func (s *fakeNATSServer) close() {
+	s.ln.Close()
+	<-s.done
+}
+
+// TestNATSPublish 按主题模板展开级别，PUB 内容为 JSON 日志，CONNECT 携带连接名与认证信息
+func TestNATSPublish(t *testing.T) {
+	tests := []struct {
+		name     string
+		subject  string
+		log      func(a *NATSAdapter)
+		wantSubj string
+		wantMsg  string
+		wantLvl  LogLevel
+	}{
+		{"fixed subject", "logs.app", func(a *NATSAdapter) { a.Info("started") }, "logs.app", "started", INFO},
+		{"level placeholder", "logs.{level}", func(a *NATSAdapter) { a.Error("boom") }, "logs.error", "boom", ERROR},
+		{"placeholder in middle", "svc.{level}.checkout", func(a *NATSAdapter) { a.WarnKV("slow", "ms", 900) }, "svc.warn.checkout", "slow", WARN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeNATSServer(t, "")
+			a := NewNATSAdapter(
+				WithNATSAddress("nats://"+server.ln.Addr().String()),
+				WithNATSSubject(tt.subject),
+				WithNATSName("gw-1"),
+				WithNATSToken("secret"),
+				WithNATSTimeout(2*time.Second),
+			)
+			tt.log(a)
+			if err := a.Flush(); err != nil {
+				t.Fatalf("Flush: %v", err)
+			}
+			a.Close()
+			server.close()
+
+			if server.connect["name"] != "gw-1" || server.connect["auth_token"] != "secret" {
+				t.Errorf("CONNECT = %v, want name and auth_token", server.connect)
+			}
+			if len(server.pubs) != 1 {
+				t.Fatalf("got %d PUB commands, want 1", len(server.pubs))
+			}
+			pub := server.pubs[0]
+			if pub.subject != tt.wantSubj {
+				t.Errorf("subject = %q, want %q", pub.subject, tt.wantSubj)
+			}
+			if pub.event["message"] != tt.wantMsg || pub.event["level"] != tt.wantLvl.String() {
+				t.Errorf("event = %v, want message %q level %s", pub.event, tt.wantMsg, tt.wantLvl)
+			}
+			if stats := a.Stats(); stats.Published != 1 || stats.Failed != 0 {
+				t.Errorf("stats = %+v, want 1 published", stats)
+			}
+		})
+	}
+}
+
+// TestNATSConnectRejected 服务端以 -ERR 拒绝连接时，重试耗尽后计为失败并上报内部错误
+func TestNATSConnectRejected(t *testing.T) {
+	var reported []InternalError
+	SetInternalErrorHandler(func(e InternalError) { reported = append(reported, e) })
+	defer SetInternalErrorHandler(nil)
+
+	server := newFakeNATSServer(t, "Authorization Violation")
+	a := NewNATSAdapter(WithNATSAddress(server.ln.Addr().String()), WithNATSRetries(0), WithNATSTimeout(2*time.Second))
+	a.Info("lost")
+	a.Flush()
+	healthy := a.IsHealthy()
+	a.Close()
+	server.close()
+
+	if stats := a.Stats(); stats.Published != 0 || stats.Failed != 1 {
+		t.Errorf("stats = %+v, want 1 failed", stats)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Err.Error(), "Authorization Violation") {
+		t.Errorf("internal errors = %v, want the -ERR reason", reported)
+	}
+	if healthy {
+		t.Error("adapter should be unhealthy after publish failure")
+	}
+}
+
+// TestExpandLevelTopic 主题模板中的 {level} 替换为小写级别名
+func TestExpandLevelTopic(t *testing.T) {
+	tests := []struct {
+		template string
+		level    LogLevel
+		want     string
+	}{
+		{"logs", ERROR, "logs"},
+		{"logs/{level}", DEBUG, "logs/debug"},
+		{"{level}.{level}", WARN, "warn.warn"},
+	}
+	for _, tt := range tests {
+		if got := expandLevelTopic(tt.template, tt.level); got != tt.want {
+			t.Errorf("expandLevelTopic(%q, %s) = %q, want %q", tt.template, tt.level, got, tt.want)
+		}
+	}
+}
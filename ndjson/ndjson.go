@@ -0,0 +1,254 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\ndjson\ndjson.go
+ * @Description: NDJSON 编解码：LogEntry JSON 格式与日志条目之间的规范转换（保留字段类型）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+)
+
+// CallerKey 调用者信息在 Fields 中的键名（"file:line:function"）
+const CallerKey = "caller"
+
+// SyntaxError 某一行无法解析
+type SyntaxError struct {
+	Line int   // 行号（从 1 开始）
+	Err  error // 原始错误
+}
+
+// Error 实现 error 接口
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("ndjson: line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap 返回原始错误
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// record JSON 行结构（与 logger.LogEntry 的 JSON 标签一致，额外兼容字符串时间）
+type record struct {
+	Level     logger.LogLevel    `json:"level"`
+	Message   string             `json:"message"`
+	Timestamp json.RawMessage    `json:"timestamp"`
+	Time      string             `json:"time"`
+	Fields    map[string]any     `json:"fields"`
+	Caller    *logger.CallerInfo `json:"caller"`
+}
+
+// Unmarshal 解析一行 JSON 为日志条目
+// 整数字段解析为 int64，小数为 float64，嵌套对象与数组保持结构；
+// timestamp 支持纳秒整数与 RFC3339 字符串
+func Unmarshal(line []byte) (logger.Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var rec record
+	if err := dec.Decode(&rec); err != nil {
+		return logger.Entry{}, err
+	}
+
+	entry := logger.Entry{
+		Level:   rec.Level,
+		Message: rec.Message,
+		Fields:  normalizeMap(rec.Fields),
+	}
+
+	var err error
+	if entry.Time, err = parseTime(rec.Timestamp, rec.Time); err != nil {
+		return logger.Entry{}, err
+	}
+	if rec.Caller != nil {
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]any, 1)
+		}
+		entry.Fields[CallerKey] = rec.Caller.File + ":" + strconv.Itoa(rec.Caller.Line) + ":" + rec.Caller.Function
+	}
+	return entry, nil
+}
+
+// parseTime 解析时间（纳秒整数或 RFC3339 字符串，整数 0 表示未设置）
+func parseTime(raw json.RawMessage, text string) (time.Time, error) {
+	if len(raw) > 0 && !bytes.Equal(raw, []byte("null")) {
+		if raw[0] == '"' {
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return time.Time{}, err
+			}
+			text = s
+		} else {
+			ns, err := strconv.ParseInt(string(raw), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid timestamp %s: %w", raw, err)
+			}
+			if ns == 0 {
+				// Marshal 将零值时间编码为 0
+				return time.Time{}, nil
+			}
+			return time.Unix(0, ns), nil
+		}
+	}
+	if text == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, text)
+}
+
+// normalizeMap 将 json.Number 转换为 int64/float64
+func normalizeMap(m map[string]any) map[string]any {
+	for k, v := range m {
+		m[k] = normalize(v)
+	}
+	return m
+}
+
+// normalize 递归转换数字类型
+func normalize(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		return normalizeMap(val)
+	case []any:
+		for i := range val {
+			val[i] = normalize(val[i])
+		}
+		return val
+	}
+	return v
+}
+
+// Decoder 逐行解码 NDJSON 日志
+type Decoder struct {
+	reader *bufio.Reader
+	line   int
+}
+
+// NewDecoder 创建解码器
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(r)}
+}
+
+// Decode 解码下一条日志，结束时返回 io.EOF
+// 某行无法解析时返回 *SyntaxError，可继续调用 Decode 读取后续行；空行被跳过
+func (d *Decoder) Decode() (logger.Entry, error) {
+	for {
+		line, err := d.reader.ReadBytes('\n')
+		if len(line) == 0 && err != nil {
+			return logger.Entry{}, err
+		}
+		d.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return logger.Entry{}, err
+			}
+			continue
+		}
+
+		entry, perr := Unmarshal(line)
+		if perr != nil {
+			return logger.Entry{}, &SyntaxError{Line: d.line, Err: perr}
+		}
+		return entry, nil
+	}
+}
+
+// Line 返回最近读取的行号
+func (d *Decoder) Line() int {
+	return d.line
+}
+
+// Encoder 将日志条目编码为 NDJSON（logger.LogEntry 的 JSON 格式）
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder 创建编码器
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode 编码一条日志（Fields 中的 caller 字段还原为 caller 对象）
+func (e *Encoder) Encode(entry logger.Entry) error {
+	data, err := Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+// Marshal 编码一条日志为以换行结尾的 JSON 行
+func Marshal(entry logger.Entry) ([]byte, error) {
+	out := logger.LogEntry{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  entry.Fields,
+	}
+	if !entry.Time.IsZero() {
+		out.Timestamp = entry.Time.UnixNano()
+	}
+	if s, ok := entry.Fields[CallerKey].(string); ok {
+		if caller, ok := parseCaller(s); ok {
+			out.Fields = make(map[string]any, len(entry.Fields))
+			for k, v := range entry.Fields {
+				if k != CallerKey {
+					out.Fields[k] = v
+				}
+			}
+			out.Caller = caller
+		}
+	}
+
+	data, err := logger.GetJSONEngine().Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// parseCaller 解析 "file:line:function"（从右侧拆分，兼容带盘符的 Windows 路径）
+func parseCaller(s string) (*logger.CallerInfo, bool) {
+	fnSep := strings.LastIndexByte(s, ':')
+	if fnSep < 0 {
+		return nil, false
+	}
+	lineSep := strings.LastIndexByte(s[:fnSep], ':')
+	if lineSep < 0 {
+		return nil, false
+	}
+	line, err := strconv.Atoi(s[lineSep+1 : fnSep])
+	if err != nil {
+		return nil, false
+	}
+	return &logger.CallerInfo{File: s[:lineSep], Line: line, Function: s[fnSep+1:]}, true
+}
+
+// IsSyntaxError 判断是否为单行解析错误（可跳过继续解码）
+func IsSyntaxError(err error) bool {
+	var se *SyntaxError
+	return errors.As(err, &se)
+}
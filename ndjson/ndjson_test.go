@@ -0,0 +1,175 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\ndjson\ndjson_test.go
+ * @Description: NDJSON 编解码测试（字段类型、时间格式、调用者信息与逐行错误）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package ndjson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+)
+
+// TestUnmarshal 数字按整数/小数区分类型，时间支持纳秒整数与 RFC3339，caller 对象转为字段
+func TestUnmarshal(t *testing.T) {
+	at := time.Date(2026, 10, 15, 8, 0, 0, 123, time.UTC)
+	tests := []struct {
+		name    string
+		line    string
+		want    logger.Entry
+		wantErr bool
+	}{
+		{"nanosecond timestamp", `{"level":"INFO","message":"m","timestamp":1792051200000000123}`,
+			logger.Entry{Level: logger.INFO, Message: "m", Time: at}, false},
+		{"rfc3339 timestamp", `{"level":"WARN","message":"m","timestamp":"2026-10-15T08:00:00.000000123Z"}`,
+			logger.Entry{Level: logger.WARN, Message: "m", Time: at}, false},
+		{"time field", `{"level":"ERROR","message":"m","time":"2026-10-15T08:00:00.000000123Z"}`,
+			logger.Entry{Level: logger.ERROR, Message: "m", Time: at}, false},
+		{"no time", `{"level":"DEBUG","message":"m"}`, logger.Entry{Level: logger.DEBUG, Message: "m"}, false},
+		{"typed fields", `{"level":"INFO","message":"m","fields":{"n":3,"f":1.5,"big":9007199254740993,"s":"x","ok":true,"nested":{"a":[1,2.5]}}}`,
+			logger.Entry{Level: logger.INFO, Message: "m", Fields: map[string]any{
+				"n": int64(3), "f": 1.5, "big": int64(9007199254740993), "s": "x", "ok": true,
+				"nested": map[string]any{"a": []any{int64(1), 2.5}},
+			}}, false},
+		{"caller", `{"level":"INFO","message":"m","caller":{"file":"main.go","line":12,"function":"main.run"}}`,
+			logger.Entry{Level: logger.INFO, Message: "m", Fields: map[string]any{CallerKey: "main.go:12:main.run"}}, false},
+		{"invalid json", `{"level":`, logger.Entry{}, true},
+		{"invalid timestamp", `{"level":"INFO","message":"m","timestamp":1.5}`, logger.Entry{}, true},
+		{"invalid time text", `{"level":"INFO","message":"m","time":"yesterday"}`, logger.Entry{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Unmarshal([]byte(tt.line))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Time.Equal(tt.want.Time) {
+				t.Errorf("time = %v, want %v", got.Time, tt.want.Time)
+			}
+			got.Time, tt.want.Time = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("entry = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRoundTrip Marshal 后 Unmarshal 得到相同条目，caller 字段还原为 caller 对象
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry logger.Entry
+	}{
+		{"plain", logger.Entry{Level: logger.INFO, Message: "hello", Time: time.Unix(0, 1792051200000000123)}},
+		{"fields", logger.Entry{Level: logger.WARN, Message: "slow", Time: time.Unix(1792051200, 0),
+			Fields: map[string]any{"ms": int64(900), "ratio": 0.25, "tags": []any{"a", "b"}}}},
+		{"caller", logger.Entry{Level: logger.ERROR, Message: "boom", Time: time.Unix(1792051200, 0),
+			Fields: map[string]any{CallerKey: `C:\app\main.go:12:main.run`, "k": "v"}}},
+		{"zero time", logger.Entry{Level: logger.DEBUG, Message: "no time"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Marshal(tt.entry)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if !bytes.HasSuffix(data, []byte("\n")) || bytes.Count(data, []byte("\n")) != 1 {
+				t.Errorf("Marshal output is not a single line: %q", data)
+			}
+			if _, ok := tt.entry.Fields[CallerKey]; ok && !bytes.Contains(data, []byte(`"caller":{`)) {
+				t.Errorf("caller not encoded as object: %s", data)
+			}
+			got, err := Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal(%s): %v", data, err)
+			}
+			if !got.Time.Equal(tt.entry.Time) {
+				t.Errorf("time = %v, want %v", got.Time, tt.entry.Time)
+			}
+			got.Time, tt.entry.Time = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(got, tt.entry) {
+				t.Errorf("round trip = %#v, want %#v", got, tt.entry)
+			}
+		})
+	}
+}
+
+// TestDecoder 跳过空行，某行解析失败时返回带行号的 SyntaxError 且可继续解码
+func TestDecoder(t *testing.T) {
+	input := `{"level":"INFO","message":"first"}` + "\n\n" +
+		`not json` + "\r\n" +
+		`{"level":"WARN","message":"last"}`
+	dec := NewDecoder(strings.NewReader(input))
+
+	var msgs []string
+	var syntaxLines []int
+	for {
+		e, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if !IsSyntaxError(err) {
+				t.Fatalf("Decode: %v", err)
+			}
+			var se *SyntaxError
+			errors.As(err, &se)
+			syntaxLines = append(syntaxLines, se.Line)
+			if se.Unwrap() == nil || !strings.Contains(se.Error(), "line 3") {
+				t.Errorf("SyntaxError = %v", se)
+			}
+			continue
+		}
+		msgs = append(msgs, e.Message)
+	}
+
+	if !reflect.DeepEqual(msgs, []string{"first", "last"}) {
+		t.Errorf("messages = %v", msgs)
+	}
+	if !reflect.DeepEqual(syntaxLines, []int{3}) {
+		t.Errorf("syntax error lines = %v, want [3]", syntaxLines)
+	}
+	if dec.Line() != 4 {
+		t.Errorf("Line = %d, want 4", dec.Line())
+	}
+	if IsSyntaxError(io.EOF) {
+		t.Error("io.EOF is not a syntax error")
+	}
+}
+
+// TestEncoderLoggerCompatible Encoder 输出与 Logger 的 JSON 格式化器一致，可被 Decoder 读回
+func TestEncoderLoggerCompatible(t *testing.T) {
+	var fromLogger bytes.Buffer
+	logger.NewLogger().WithOutput(&fromLogger).WithFormatter(logger.NewJSONFormatter()).InfoKV("paid", "order", "o-1", "amount", 12)
+	entry, err := Unmarshal(fromLogger.Bytes())
+	if err != nil {
+		t.Fatalf("Unmarshal logger output %q: %v", fromLogger.String(), err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(entry); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	got, err := NewDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := map[string]any{"order": "o-1", "amount": int64(12)}
+	if got.Level != logger.INFO || got.Message != "paid" || !reflect.DeepEqual(got.Fields, want) || !got.Time.Equal(entry.Time) {
+		t.Errorf("decoded = %#v, want INFO paid %v", got, want)
+	}
+}
@@ -0,0 +1,407 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\notify.go
+ * @Description: 聊天机器人告警钩子（Slack/Teams/钉钉/飞书），按批合并同类日志并限制发送频率
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// NotifyPlatform 告警平台
+type NotifyPlatform string
+
+const (
+	NotifySlack    NotifyPlatform = "slack"
+	NotifyTeams    NotifyPlatform = "teams"
+	NotifyDingTalk NotifyPlatform = "dingtalk"
+	NotifyFeishu   NotifyPlatform = "feishu"
+)
+
+// notifyGroup 批次内按指纹合并的同类日志
+type notifyGroup struct {
+	level   LogLevel
+	message string
+	fields  string
+	count   int
+	first   time.Time
+}
+
+// NotifyHook 聊天机器人告警钩子
+// 日志先按指纹合并进当前批次，每个批次间隔发送一条汇总卡片；
+// 超出频率限制时批次继续累积，窗口恢复后合并发送，避免错误风暴刷屏
+type NotifyHook struct {
+	platform      NotifyPlatform
+	webhookURL    string
+	secret        string
+	title         string
+	levels        []LogLevel
+	filter        func(*LogEntry) bool
+	batchInterval time.Duration
+	maxGroups     int
+	maxPerWindow  int
+	window        time.Duration
+	client        *http.Client
+
+	mu          sync.Mutex
+	groups      map[string]*notifyGroup
+	order       []string
+	overflow    int
+	windowStart time.Time
+	sentInWin   int
+	failed      int64
+
+	stop      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+}
+
+// NotifyHookOption 告警钩子配置选项
+type NotifyHookOption func(*NotifyHook)
+
+// WithNotifySecret 设置签名密钥（钉钉、飞书机器人的加签校验）
+func WithNotifySecret(secret string) NotifyHookOption {
+	return func(h *NotifyHook) {
+		h.secret = secret
+	}
+}
+
+// WithNotifyTitle 设置卡片标题（默认 "Log Alert"）
+func WithNotifyTitle(title string) NotifyHookOption {
+	return func(h *NotifyHook) {
+		h.title = title
+	}
+}
+
+// WithNotifyLevels 设置告警级别（默认 ERROR、FATAL）
+func WithNotifyLevels(levels ...LogLevel) NotifyHookOption {
+	return func(h *NotifyHook) {
+		h.levels = levels
+	}
+}
+
+// WithNotifyFilter 设置过滤条件，返回 false 的日志不告警
+func WithNotifyFilter(filter func(*LogEntry) bool) NotifyHookOption {
+	return func(h *NotifyHook) {
+		h.filter = filter
+	}
+}
+
+// WithNotifyBatchInterval 设置批次间隔（默认 10 秒）
+func WithNotifyBatchInterval(interval time.Duration) NotifyHookOption {
+	return func(h *NotifyHook) {
+		if interval > 0 {
+			h.batchInterval = interval
+		}
+	}
+}
+
+// WithNotifyMaxGroups 设置单条卡片最多展示的日志种类（默认 10，其余计入 "更多"）
+func WithNotifyMaxGroups(n int) NotifyHookOption {
+	return func(h *NotifyHook) {
+		if n > 0 {
+			h.maxGroups = n
+		}
+	}
+}
+
+// WithNotifyRateLimit 设置频率限制：每个时间窗口最多发送 maxMessages 条（默认每分钟 5 条）
+func WithNotifyRateLimit(maxMessages int, window time.Duration) NotifyHookOption {
+	return func(h *NotifyHook) {
+		if maxMessages > 0 && window > 0 {
+			h.maxPerWindow = maxMessages
+			h.window = window
+		}
+	}
+}
+
+// WithNotifyHTTPClient 设置 HTTP 客户端
+func WithNotifyHTTPClient(client *http.Client) NotifyHookOption {
+	return func(h *NotifyHook) {
+		h.client = client
+	}
+}
+
+// NewNotifyHook 创建告警钩子
+func NewNotifyHook(platform NotifyPlatform, webhookURL string, opts ...NotifyHookOption) (*NotifyHook, error) {
+	switch platform {
+	case NotifySlack, NotifyTeams, NotifyDingTalk, NotifyFeishu:
+	default:
+		return nil, fmt.Errorf("unsupported notify platform: %s", platform)
+	}
+	if webhookURL == "" {
+		return nil, fmt.Errorf("notify webhook url is empty")
+	}
+
+	h := &NotifyHook{
+		platform:      platform,
+		webhookURL:    webhookURL,
+		title:         "Log Alert",
+		levels:        []LogLevel{ERROR, FATAL},
+		batchInterval: 10 * time.Second,
+		maxGroups:     10,
+		maxPerWindow:  5,
+		window:        time.Minute,
+		client:        newHTTPClient(5 * time.Second),
+		groups:        make(map[string]*notifyGroup),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	go h.run()
+	return h, nil
+}
+
+// Fire 实现 IHook 接口（仅合并进当前批次，不发起网络请求）
+func (h *NotifyHook) Fire(entry *LogEntry) error {
+	if h.filter != nil && !h.filter(entry) {
+		return nil
+	}
+
+	key := entry.Level.String() + ":" + entryFingerprint(entry)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if g, ok := h.groups[key]; ok {
+		g.count++
+		return nil
+	}
+	if len(h.order) >= h.maxGroups {
+		h.overflow++
+		return nil
+	}
+	h.groups[key] = &notifyGroup{
+		level:   entry.Level,
+		message: entry.Message,
+		fields:  formatNotifyFields(entry.Fields),
+		count:   1,
+		first:   time.Unix(0, entry.Timestamp),
+	}
+	h.order = append(h.order, key)
+	return nil
+}
+
+// Levels 实现 IHook 接口
+func (h *NotifyHook) Levels() []LogLevel {
+	return h.levels
+}
+
+// formatNotifyFields 将字段格式化为按键排序的 "k=v" 列表
+func formatNotifyFields(fields map[string]any) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf []byte
+	for i, k := range keys {
+		if i > 0 {
+			buf = append(buf, ", "...)
+		}
+		buf = append(buf, k...)
+		buf = append(buf, '=')
+		buf = convert.AppendValue(buf, fields[k])
+	}
+	return string(buf)
+}
+
+// run 按批次间隔发送
+func (h *NotifyHook) run() {
+	defer close(h.stopped)
+	ticker := time.NewTicker(h.batchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.flush(false)
+		case <-h.stop:
+			h.flush(true)
+			return
+		}
+	}
+}
+
+// flush 发送当前批次（force 为 true 时忽略频率限制）
+func (h *NotifyHook) flush(force bool) {
+	h.mu.Lock()
+	if len(h.order) == 0 {
+		h.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	if now.Sub(h.windowStart) >= h.window {
+		h.windowStart = now
+		h.sentInWin = 0
+	}
+	if !force && h.sentInWin >= h.maxPerWindow {
+		// 超出频率限制，继续累积到下一个窗口
+		h.mu.Unlock()
+		return
+	}
+	h.sentInWin++
+
+	groups := make([]*notifyGroup, 0, len(h.order))
+	for _, key := range h.order {
+		groups = append(groups, h.groups[key])
+	}
+	overflow := h.overflow
+	h.groups = make(map[string]*notifyGroup)
+	h.order = h.order[:0]
+	h.overflow = 0
+	h.mu.Unlock()
+
+	if err := h.post(h.render(groups, overflow)); err != nil {
+		reportInternalError("notify", err)
+		h.mu.Lock()
+		h.failed++
+		h.mu.Unlock()
+	}
+}
+
+// render 渲染为 Markdown 文本
+func (h *NotifyHook) render(groups []*notifyGroup, overflow int) string {
+	var b strings.Builder
+	for _, g := range groups {
+		fmt.Fprintf(&b, "**[%s]** %s", g.level, g.message)
+		if g.count > 1 {
+			fmt.Fprintf(&b, " ×%d", g.count)
+		}
+		fmt.Fprintf(&b, " (%s)\n", g.first.Format(time.DateTime))
+		if g.fields != "" {
+			fmt.Fprintf(&b, "> %s\n", g.fields)
+		}
+		b.WriteString("\n")
+	}
+	if overflow > 0 {
+		fmt.Fprintf(&b, "... and %d more\n", overflow)
+	}
+	return b.String()
+}
+
+// payload 按平台构建请求体
+func (h *NotifyHook) payload(text string) map[string]any {
+	switch h.platform {
+	case NotifyTeams:
+		return map[string]any{
+			"@type":      "MessageCard",
+			"@context":   "http://schema.org/extensions",
+			"themeColor": "D70000",
+			"title":      h.title,
+			"text":       strings.ReplaceAll(text, "\n", "\n\n"),
+		}
+	case NotifyDingTalk:
+		return map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"title": h.title, "text": "### " + h.title + "\n\n" + text},
+		}
+	case NotifyFeishu:
+		body := map[string]any{
+			"msg_type": "interactive",
+			"card": map[string]any{
+				"header": map[string]any{
+					"title":    map[string]any{"tag": "plain_text", "content": h.title},
+					"template": "red",
+				},
+				"elements": []any{map[string]any{"tag": "markdown", "content": text}},
+			},
+		}
+		if h.secret != "" {
+			timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+			mac := hmac.New(sha256.New, []byte(timestamp+"\n"+h.secret))
+			body["timestamp"] = timestamp
+			body["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		}
+		return body
+	default:
+		slackText := strings.ReplaceAll(text, "**", "*")
+		return map[string]any{
+			"text": h.title,
+			"blocks": []any{
+				map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": h.title}},
+				map[string]any{"type": "section", "text": map[string]any{"type": "mrkdwn", "text": slackText}},
+			},
+		}
+	}
+}
+
+// endpoint 获取请求地址（钉钉加签时追加 timestamp 和 sign 参数）
+func (h *NotifyHook) endpoint() string {
+	if h.platform != NotifyDingTalk || h.secret == "" {
+		return h.webhookURL
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(h.secret))
+	mac.Write([]byte(timestamp + "\n" + h.secret))
+	sign := url.QueryEscape(base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+
+	sep := "?"
+	if strings.Contains(h.webhookURL, "?") {
+		sep = "&"
+	}
+	return h.webhookURL + sep + "timestamp=" + timestamp + "&sign=" + sign
+}
+
+// post 发送消息
+func (h *NotifyHook) post(text string) error {
+	body, err := marshalJSON(h.payload(text))
+	if err != nil {
+		return err
+	}
+	resp, err := h.client.Post(h.endpoint(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notify webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close 发送剩余批次并停止后台协程
+func (h *NotifyHook) Close(ctx context.Context) error {
+	h.closeOnce.Do(func() { close(h.stop) })
+	select {
+	case <-h.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Failed 返回发送失败次数
+func (h *NotifyHook) Failed() int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.failed
+}
@@ -0,0 +1,162 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\options.go
+ * @Description: 函数式选项构造（logger.New(logger.WithLevel(logger.DEBUG), logger.WithJSON())）与 JSON 格式化器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "io"
+
+// Option Logger 构造选项，与同名的 Logger.WithXxx 方法等价；自定义选项可直接写为 func(*Logger)
+type Option func(*Logger)
+
+// WithLevel 设置日志级别
+func WithLevel(level LogLevel) Option {
+	return func(l *Logger) {
+		l.WithLevel(level)
+	}
+}
+
+// WithOutput 设置输出目标
+func WithOutput(output io.Writer) Option {
+	return func(l *Logger) {
+		l.WithOutput(output)
+	}
+}
+
+// WithOutputs 设置多个按级别阈值分发的输出目标
+func WithOutputs(outputs []OutputSpec) Option {
+	return func(l *Logger) {
+		l.WithOutputs(outputs)
+	}
+}
+
+// WithJSON 以 JSON 行输出（使用 JSONFormatter）
+func WithJSON() Option {
+	return WithFormatter(NewJSONFormatter())
+}
+
+// WithFormatter 设置格式化器
+func WithFormatter(formatter IFormatter) Option {
+	return func(l *Logger) {
+		l.WithFormatter(formatter)
+	}
+}
+
+// WithPrefix 设置日志前缀
+func WithPrefix(prefix string) Option {
+	return func(l *Logger) {
+		l.WithPrefix(prefix)
+	}
+}
+
+// WithShowCaller 设置是否显示调用者信息
+func WithShowCaller(show bool) Option {
+	return func(l *Logger) {
+		l.WithShowCaller(show)
+	}
+}
+
+// WithColorful 设置是否使用彩色输出
+func WithColorful(colorful bool) Option {
+	return func(l *Logger) {
+		l.WithColorful(colorful)
+	}
+}
+
+// WithTimeFormat 设置时间格式
+func WithTimeFormat(format string) Option {
+	return func(l *Logger) {
+		l.WithTimeFormat(format)
+	}
+}
+
+// WithClock 设置时间源
+func WithClock(clock Clock) Option {
+	return func(l *Logger) {
+		l.WithClock(clock)
+	}
+}
+
+// WithAsyncWrite 设置是否异步写入
+func WithAsyncWrite(async bool) Option {
+	return func(l *Logger) {
+		l.WithAsyncWrite(async)
+	}
+}
+
+// WithSanitize 设置消息与字段值中控制字符的处理方式
+func WithSanitize(mode SanitizeMode) Option {
+	return func(l *Logger) {
+		l.WithSanitize(mode)
+	}
+}
+
+// WithNewline 设置消息与字段值中换行的处理方式
+func WithNewline(mode NewlineMode) Option {
+	return func(l *Logger) {
+		l.WithNewline(mode)
+	}
+}
+
+// WithSpanRecorder 设置 span 记录器
+func WithSpanRecorder(recorder SpanRecorder) Option {
+	return func(l *Logger) {
+		l.WithSpanRecorder(recorder)
+	}
+}
+
+// WithHooks 追加钩子
+func WithHooks(hooks ...IHook) Option {
+	return func(l *Logger) {
+		l.WithHooks(append(append([]IHook(nil), l.hooks...), hooks...))
+	}
+}
+
+// WithMiddleware 追加中间件（按优先级升序执行）
+func WithMiddleware(middleware ...IMiddleware) Option {
+	return func(l *Logger) {
+		l.WithMiddleware(append(append([]IMiddleware(nil), l.middleware...), middleware...))
+	}
+}
+
+// WithAdapters 将日志同时转发给适配器
+func WithAdapters(adapters ...IAdapter) Option {
+	return func(l *Logger) {
+		l.WithAdapters(adapters...)
+	}
+}
+
+// WithContextExtractor 设置上下文提取器
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return func(l *Logger) {
+		l.WithContextExtractor(extractor)
+	}
+}
+
+// JSONFormatter JSON 行格式化器，输出 LogEntry 的 JSON 编码（与 ndjson 包的格式一致）
+type JSONFormatter struct{}
+
+// NewJSONFormatter 创建 JSON 格式化器
+func NewJSONFormatter() *JSONFormatter {
+	return &JSONFormatter{}
+}
+
+// Format 实现 IFormatter 接口
+func (f *JSONFormatter) Format(entry *LogEntry) ([]byte, error) {
+	data, err := marshalJSON(entry)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// GetName 实现 IFormatter 接口
+func (f *JSONFormatter) GetName() string {
+	return string(FormatJSON)
+}
@@ -0,0 +1,266 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\otellog\otellog.go
+ * @Description: OpenTelemetry Logs Bridge：将 go-logger 日志作为 otel 日志记录交给 LoggerProvider（独立模块）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+
+// Package otellog 实现 OpenTelemetry Logs Bridge API 的 appender：日志经由已配置的 LoggerProvider
+// 交给任意 otel 导出器（OTLP、stdout 等），无需使用 go-logger 自带的适配器：
+//
+//	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+//	log := logger.New(logger.WithHooks(otellog.NewHook(otellog.WithLoggerProvider(provider))))
+//
+// 字段中的 trace_id / span_id（可通过 WithTraceKeys 修改）转换为记录的追踪上下文，
+// 调用者信息转换为 code.filepath、code.lineno 与 code.function 属性。
+// RecordErrors 另将错误日志记录为当前 span 的 exception 事件（见 logger.WithSpanRecorder）
+package otellog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 默认配置
+const (
+	DefaultName    = "github.com/kamalyes/go-logger" // 默认 instrumentation scope 名称
+	DefaultVersion = "1.0.0"
+)
+
+// 调用者属性名（OpenTelemetry 语义约定）
+const (
+	CodeFilepathKey = "code.filepath"
+	CodeLinenoKey   = "code.lineno"
+	CodeFunctionKey = "code.function"
+)
+
+// Hook 将日志条目转换为 otel 日志记录并交给 LoggerProvider 的钩子
+type Hook struct {
+	provider log.LoggerProvider
+	name     string
+	version  string
+	traceKey string
+	spanKey  string
+	levels   []logger.LogLevel
+	logger   log.Logger
+}
+
+// Option 钩子配置选项
+type Option func(*Hook)
+
+// WithLoggerProvider 设置 LoggerProvider（默认 global.GetLoggerProvider()）
+func WithLoggerProvider(provider log.LoggerProvider) Option {
+	return func(h *Hook) {
+		if provider != nil {
+			h.provider = provider
+		}
+	}
+}
+
+// WithName 设置 instrumentation scope 名称（默认 DefaultName）
+func WithName(name string) Option {
+	return func(h *Hook) {
+		if name != "" {
+			h.name = name
+		}
+	}
+}
+
+// WithVersion 设置 instrumentation scope 版本（默认 DefaultVersion）
+func WithVersion(version string) Option {
+	return func(h *Hook) {
+		h.version = version
+	}
+}
+
+// WithTraceKeys 设置 trace id 与 span id 所在的字段名（默认 trace_id、span_id）
+func WithTraceKeys(traceKey, spanKey string) Option {
+	return func(h *Hook) {
+		h.traceKey, h.spanKey = traceKey, spanKey
+	}
+}
+
+// WithLevels 只转发指定级别（默认全部级别）
+func WithLevels(levels ...logger.LogLevel) Option {
+	return func(h *Hook) {
+		h.levels = levels
+	}
+}
+
+// NewHook 创建 otel 日志桥接钩子
+func NewHook(opts ...Option) *Hook {
+	h := &Hook{
+		name:     DefaultName,
+		version:  DefaultVersion,
+		traceKey: logger.ContextKeyTraceID,
+		spanKey:  "span_id",
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.provider == nil {
+		h.provider = global.GetLoggerProvider()
+	}
+	h.logger = h.provider.Logger(h.name, log.WithInstrumentationVersion(h.version))
+	return h
+}
+
+// Fire 实现 logger.IHook 接口
+func (h *Hook) Fire(entry *logger.LogEntry) error {
+	ctx := context.Background()
+	var record log.Record
+	record.SetTimestamp(time.Unix(0, entry.Timestamp))
+	record.SetObservedTimestamp(time.Now())
+	record.SetSeverity(Severity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(log.StringValue(entry.Message))
+
+	var sc trace.SpanContextConfig
+	attrs := make([]log.KeyValue, 0, len(entry.Fields)+3)
+	for k, v := range entry.Fields {
+		switch k {
+		case h.traceKey:
+			if id, err := trace.TraceIDFromHex(fmt.Sprint(v)); err == nil {
+				sc.TraceID = id
+				continue
+			}
+		case h.spanKey:
+			if id, err := trace.SpanIDFromHex(fmt.Sprint(v)); err == nil {
+				sc.SpanID = id
+				continue
+			}
+		}
+		attrs = append(attrs, log.KeyValue{Key: k, Value: Value(v)})
+	}
+	if sc.TraceID.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(sc))
+	}
+	if c := entry.Caller; c != nil {
+		attrs = append(attrs,
+			log.String(CodeFilepathKey, c.File),
+			log.Int(CodeLinenoKey, c.Line),
+			log.String(CodeFunctionKey, c.Function),
+		)
+	}
+	record.AddAttributes(attrs...)
+
+	h.logger.Emit(ctx, record)
+	return nil
+}
+
+// Levels 实现 logger.IHook 接口
+func (h *Hook) Levels() []logger.LogLevel {
+	return h.levels
+}
+
+// Severity 将日志级别映射为 otel 严重程度（扩展级别映射为 INFO）
+func Severity(level logger.LogLevel) log.Severity {
+	switch level {
+	case logger.TRACE:
+		return log.SeverityTrace
+	case logger.DEBUG:
+		return log.SeverityDebug
+	case logger.INFO:
+		return log.SeverityInfo
+	case logger.WARN:
+		return log.SeverityWarn
+	case logger.ERROR:
+		return log.SeverityError
+	case logger.FATAL:
+		return log.SeverityFatal
+	default:
+		return log.SeverityInfo
+	}
+}
+
+// Value 将字段值转换为 otel 日志值：基础类型保持类型，map 与切片递归转换，
+// 时间为 RFC3339Nano 字符串，其余类型优先使用 error / fmt.Stringer，最后按 JSON 编码
+func Value(v any) log.Value {
+	switch x := v.(type) {
+	case nil:
+		return log.Value{}
+	case string:
+		return log.StringValue(x)
+	case bool:
+		return log.BoolValue(x)
+	case int:
+		return log.IntValue(x)
+	case int8:
+		return log.Int64Value(int64(x))
+	case int16:
+		return log.Int64Value(int64(x))
+	case int32:
+		return log.Int64Value(int64(x))
+	case int64:
+		return log.Int64Value(x)
+	case uint:
+		return uintValue(uint64(x))
+	case uint8:
+		return log.Int64Value(int64(x))
+	case uint16:
+		return log.Int64Value(int64(x))
+	case uint32:
+		return log.Int64Value(int64(x))
+	case uint64:
+		return uintValue(x)
+	case float32:
+		return log.Float64Value(float64(x))
+	case float64:
+		return log.Float64Value(x)
+	case []byte:
+		return log.BytesValue(x)
+	case time.Time:
+		return log.StringValue(x.Format(time.RFC3339Nano))
+	case time.Duration:
+		return log.StringValue(x.String())
+	case map[string]any:
+		kvs := make([]log.KeyValue, 0, len(x))
+		for k, item := range x {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: Value(item)})
+		}
+		return log.MapValue(kvs...)
+	case []any:
+		values := make([]log.Value, len(x))
+		for i, item := range x {
+			values[i] = Value(item)
+		}
+		return log.SliceValue(values...)
+	case []string:
+		values := make([]log.Value, len(x))
+		for i, item := range x {
+			values[i] = log.StringValue(item)
+		}
+		return log.SliceValue(values...)
+	case error:
+		return log.StringValue(x.Error())
+	case fmt.Stringer:
+		return log.StringValue(x.String())
+	default:
+		if data, err := json.Marshal(x); err == nil {
+			return log.StringValue(string(data))
+		}
+		return log.StringValue(fmt.Sprint(x))
+	}
+}
+
+// uintValue 超出 int64 范围的无符号整数以字符串表示
+func uintValue(v uint64) log.Value {
+	if v > math.MaxInt64 {
+		return log.StringValue(fmt.Sprint(v))
+	}
+	return log.Int64Value(int64(v))
+}
+
+var _ logger.IHook = (*Hook)(nil)
@@ -0,0 +1,154 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\otellog\otellog_test.go
+ * @Description: otel 日志桥接钩子测试（级别映射、属性转换、追踪上下文）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package otellog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestSeverity 基础级别一一映射，扩展级别映射为 INFO
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		level logger.LogLevel
+		want  log.Severity
+	}{
+		{logger.TRACE, log.SeverityTrace},
+		{logger.DEBUG, log.SeverityDebug},
+		{logger.INFO, log.SeverityInfo},
+		{logger.WARN, log.SeverityWarn},
+		{logger.ERROR, log.SeverityError},
+		{logger.FATAL, log.SeverityFatal},
+		{logger.SYSTEM, log.SeverityInfo},
+		{logger.BUSINESS, log.SeverityInfo},
+		{logger.SECURITY, log.SeverityInfo},
+		{logger.AUDIT, log.SeverityInfo},
+		{logger.PERFORMANCE, log.SeverityInfo},
+	}
+	for _, tt := range tests {
+		if got := Severity(tt.level); got != tt.want {
+			t.Errorf("Severity(%v) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestValue 字段值保持类型，超出 int64 的无符号整数转为字符串
+func TestValue(t *testing.T) {
+	tests := []struct {
+		in   any
+		want log.Value
+	}{
+		{"s", log.StringValue("s")},
+		{true, log.BoolValue(true)},
+		{42, log.IntValue(42)},
+		{int32(-7), log.Int64Value(-7)},
+		{uint64(1 << 63), log.StringValue("9223372036854775808")},
+		{1.5, log.Float64Value(1.5)},
+		{[]byte("ab"), log.BytesValue([]byte("ab"))},
+		{time.Second, log.StringValue("1s")},
+		{errors.New("boom"), log.StringValue("boom")},
+		{[]string{"a", "b"}, log.SliceValue(log.StringValue("a"), log.StringValue("b"))},
+		{struct{ A int }{1}, log.StringValue(`{"A":1}`)},
+	}
+	for _, tt := range tests {
+		if got := Value(tt.in); !got.Equal(tt.want) {
+			t.Errorf("Value(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestHookFire 日志条目转换为 otel 记录：trace_id / span_id 转为追踪上下文，调用者信息转为 code.* 属性
+func TestHookFire(t *testing.T) {
+	rec := logtest.NewRecorder()
+	h := NewHook(WithLoggerProvider(rec), WithName("test-scope"), WithVersion("9.9.9"))
+
+	ts := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	err := h.Fire(&logger.LogEntry{
+		Level:     logger.WARN,
+		Message:   "disk almost full",
+		Timestamp: ts.UnixNano(),
+		Fields: map[string]any{
+			"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+			"span_id":  "00f067aa0ba902b7",
+			"usage":    0.93,
+		},
+		Caller: &logger.CallerInfo{File: "disk.go", Line: 42, Function: "main.check"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scopes := rec.Result()
+	if len(scopes) != 1 || scopes[0].Name != "test-scope" || scopes[0].Version != "9.9.9" {
+		t.Fatalf("unexpected scopes: %+v", scopes)
+	}
+	if len(scopes[0].Records) != 1 {
+		t.Fatalf("got %d records, want 1", len(scopes[0].Records))
+	}
+	r := scopes[0].Records[0]
+	if r.Severity() != log.SeverityWarn || r.SeverityText() != logger.WARN.String() {
+		t.Errorf("severity = %v/%q", r.Severity(), r.SeverityText())
+	}
+	if r.Body().AsString() != "disk almost full" || !r.Timestamp().Equal(ts) {
+		t.Errorf("body/timestamp = %q/%v", r.Body().AsString(), r.Timestamp())
+	}
+
+	sc := trace.SpanContextFromContext(r.Context())
+	if sc.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span context = %s/%s", sc.TraceID(), sc.SpanID())
+	}
+
+	attrs := map[string]log.Value{}
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	want := map[string]log.Value{
+		"usage":         log.Float64Value(0.93),
+		CodeFilepathKey: log.StringValue("disk.go"),
+		CodeLinenoKey:   log.IntValue(42),
+		CodeFunctionKey: log.StringValue("main.check"),
+	}
+	if len(attrs) != len(want) {
+		t.Errorf("attributes = %v, want %v", attrs, want)
+	}
+	for k, v := range want {
+		if !attrs[k].Equal(v) {
+			t.Errorf("attribute %s = %v, want %v", k, attrs[k], v)
+		}
+	}
+}
+
+// TestHookInvalidTraceID 无法解析的 trace_id 作为普通属性保留
+func TestHookInvalidTraceID(t *testing.T) {
+	rec := logtest.NewRecorder()
+	h := NewHook(WithLoggerProvider(rec))
+	h.Fire(&logger.LogEntry{Level: logger.INFO, Message: "m", Fields: map[string]any{"trace_id": "not-hex"}})
+
+	r := rec.Result()[0].Records[0]
+	if trace.SpanContextFromContext(r.Context()).IsValid() {
+		t.Error("invalid trace id produced a span context")
+	}
+	var found bool
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		found = found || (kv.Key == "trace_id" && kv.Value.AsString() == "not-hex")
+		return true
+	})
+	if !found {
+		t.Error("unparsable trace_id was dropped")
+	}
+}
@@ -0,0 +1,165 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\otellog\span.go
+ * @Description: 错误日志记录为 span 事件：添加 exception 事件（exception.* 属性）并设置 span 状态
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exception 事件属性名（OpenTelemetry 语义约定）
+const (
+	ExceptionEventName      = "exception"
+	ExceptionTypeKey        = "exception.type"
+	ExceptionMessageKey     = "exception.message"
+	ExceptionStacktraceKey  = "exception.stacktrace"
+	LogSeverityKey          = "log.severity"
+	DefaultErrorTypeKey     = "error_type" // 异常类型所在的字段名
+	DefaultErrorKey         = logger.ErrorKey
+	DefaultStacktraceKey    = "stacktrace"
+	defaultExceptionTypeTag = "go-logger."
+)
+
+// spanConfig span 事件配置
+type spanConfig struct {
+	setStatus     bool
+	attributes    bool
+	errorKey      string
+	errorTypeKey  string
+	stacktraceKey string
+}
+
+// SpanOption span 事件配置选项
+type SpanOption func(*spanConfig)
+
+// WithSpanStatus 是否将 span 状态设置为 Error（默认 true）
+func WithSpanStatus(enabled bool) SpanOption {
+	return func(c *spanConfig) {
+		c.setStatus = enabled
+	}
+}
+
+// WithSpanAttributes 是否将日志字段一并作为事件属性（默认 true）
+func WithSpanAttributes(enabled bool) SpanOption {
+	return func(c *spanConfig) {
+		c.attributes = enabled
+	}
+}
+
+// WithSpanErrorKeys 设置错误信息、异常类型与堆栈所在的字段名（默认 error、error_type、stacktrace）
+func WithSpanErrorKeys(errorKey, errorTypeKey, stacktraceKey string) SpanOption {
+	return func(c *spanConfig) {
+		c.errorKey, c.errorTypeKey, c.stacktraceKey = errorKey, errorTypeKey, stacktraceKey
+	}
+}
+
+// RecordErrors 返回将 ERROR / FATAL 日志记录到 ctx 中活跃 span 的 logger.SpanRecorder：
+//
+//	log.WithSpanRecorder(otellog.RecordErrors())
+//	log.ErrorContextKV(ctx, "charge failed", "error", err)
+//
+// 添加 exception 事件：exception.message 取 error 字段（不存在时为日志消息），exception.type 取 error_type 字段
+// （不存在时为 go-logger.<级别>），exception.stacktrace 取 stacktrace 字段；随后将 span 状态设置为 Error
+func RecordErrors(opts ...SpanOption) logger.SpanRecorder {
+	cfg := &spanConfig{
+		setStatus:     true,
+		attributes:    true,
+		errorKey:      DefaultErrorKey,
+		errorTypeKey:  DefaultErrorTypeKey,
+		stacktraceKey: DefaultStacktraceKey,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(ctx context.Context, entry *logger.LogEntry) {
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return
+		}
+
+		message := entry.Message
+		if v, ok := entry.Fields[cfg.errorKey]; ok {
+			message = fmt.Sprint(v)
+		}
+		excType := defaultExceptionTypeTag + entry.Level.String()
+		if v, ok := entry.Fields[cfg.errorTypeKey]; ok {
+			excType = fmt.Sprint(v)
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(entry.Fields)+4)
+		attrs = append(attrs,
+			attribute.String(ExceptionTypeKey, excType),
+			attribute.String(ExceptionMessageKey, message),
+			attribute.String(LogSeverityKey, entry.Level.String()),
+		)
+		if v, ok := entry.Fields[cfg.stacktraceKey]; ok {
+			attrs = append(attrs, attribute.String(ExceptionStacktraceKey, fmt.Sprint(v)))
+		}
+		if cfg.attributes {
+			for k, v := range entry.Fields {
+				if k == cfg.errorKey || k == cfg.errorTypeKey || k == cfg.stacktraceKey {
+					continue
+				}
+				attrs = append(attrs, attributeValue(k, v))
+			}
+		}
+		if c := entry.Caller; c != nil {
+			attrs = append(attrs,
+				attribute.String(CodeFilepathKey, c.File),
+				attribute.Int(CodeLinenoKey, c.Line),
+				attribute.String(CodeFunctionKey, c.Function),
+			)
+		}
+
+		span.AddEvent(ExceptionEventName,
+			trace.WithTimestamp(time.Unix(0, entry.Timestamp)),
+			trace.WithAttributes(attrs...),
+		)
+		if cfg.setStatus {
+			span.SetStatus(codes.Error, entry.Message)
+		}
+	}
+}
+
+// attributeValue 将字段转换为 span 属性（基础类型保持类型，其余格式化为字符串）
+func attributeValue(key string, v any) attribute.KeyValue {
+	switch x := v.(type) {
+	case string:
+		return attribute.String(key, x)
+	case bool:
+		return attribute.Bool(key, x)
+	case int:
+		return attribute.Int(key, x)
+	case int64:
+		return attribute.Int64(key, x)
+	case int32:
+		return attribute.Int64(key, int64(x))
+	case float64:
+		return attribute.Float64(key, x)
+	case float32:
+		return attribute.Float64(key, float64(x))
+	case []string:
+		return attribute.StringSlice(key, x)
+	case error:
+		return attribute.String(key, x.Error())
+	case fmt.Stringer:
+		return attribute.String(key, x.String())
+	default:
+		return attribute.String(key, fmt.Sprint(x))
+	}
+}
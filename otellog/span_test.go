@@ -0,0 +1,134 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\otellog\span_test.go
+ * @Description: 错误日志记录为 span 事件的测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package otellog
+
+import (
+	"context"
+	"testing"
+
+	logger "github.com/kamalyes/go-logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordingSpan 记录事件与状态的 span
+type recordingSpan struct {
+	noop.Span
+	events      []string
+	attrs       []attribute.KeyValue
+	statusCode  codes.Code
+	statusDesc  string
+	isRecording bool
+}
+
+func (s *recordingSpan) IsRecording() bool { return s.isRecording }
+
+func (s *recordingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.events = append(s.events, name)
+	cfg := trace.NewEventConfig(opts...)
+	s.attrs = append(s.attrs, cfg.Attributes()...)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode, s.statusDesc = code, description
+}
+
+// attr 按键查找事件属性
+func (s *recordingSpan) attr(key string) (attribute.Value, bool) {
+	for _, kv := range s.attrs {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// TestRecordErrorsAttributes exception 事件属性取自 error / error_type / stacktrace 字段，其余字段保持类型
+func TestRecordErrorsAttributes(t *testing.T) {
+	span := &recordingSpan{isRecording: true}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	RecordErrors()(ctx, &logger.LogEntry{
+		Level:   logger.ERROR,
+		Message: "charge failed",
+		Fields: map[string]any{
+			"error":      "card declined",
+			"error_type": "PaymentError",
+			"stacktrace": "main.charge()",
+			"amount":     int64(1999),
+			"retry":      true,
+			"tags":       []string{"a", "b"},
+		},
+		Caller: &logger.CallerInfo{File: "pay.go", Line: 7, Function: "main.charge"},
+	})
+
+	if len(span.events) != 1 || span.events[0] != ExceptionEventName {
+		t.Fatalf("events = %v", span.events)
+	}
+	tests := []struct {
+		key  string
+		want attribute.Value
+	}{
+		{ExceptionMessageKey, attribute.StringValue("card declined")},
+		{ExceptionTypeKey, attribute.StringValue("PaymentError")},
+		{ExceptionStacktraceKey, attribute.StringValue("main.charge()")},
+		{LogSeverityKey, attribute.StringValue(logger.ERROR.String())},
+		{"amount", attribute.Int64Value(1999)},
+		{"retry", attribute.BoolValue(true)},
+		{"tags", attribute.StringSliceValue([]string{"a", "b"})},
+		{CodeFilepathKey, attribute.StringValue("pay.go")},
+		{CodeLinenoKey, attribute.IntValue(7)},
+		{CodeFunctionKey, attribute.StringValue("main.charge")},
+	}
+	for _, tt := range tests {
+		got, ok := span.attr(tt.key)
+		if !ok || got != tt.want {
+			t.Errorf("attribute %s = %v (present %v), want %v", tt.key, got.Emit(), ok, tt.want.Emit())
+		}
+	}
+	for _, key := range []string{"error", "error_type", "stacktrace"} {
+		if _, ok := span.attr(key); ok {
+			t.Errorf("field %s duplicated as a plain attribute", key)
+		}
+	}
+	if span.statusCode != codes.Error || span.statusDesc != "charge failed" {
+		t.Errorf("status = %v %q", span.statusCode, span.statusDesc)
+	}
+}
+
+// TestRecordErrorsOptions 关闭字段属性与状态设置；未记录的 span 不添加事件
+func TestRecordErrorsOptions(t *testing.T) {
+	span := &recordingSpan{isRecording: true}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+	RecordErrors(WithSpanAttributes(false), WithSpanStatus(false))(ctx, &logger.LogEntry{
+		Level: logger.FATAL, Message: "oom", Fields: map[string]any{"heap": 1},
+	})
+	if _, ok := span.attr("heap"); ok {
+		t.Error("field attributes added with WithSpanAttributes(false)")
+	}
+	if got, _ := span.attr(ExceptionTypeKey); got.AsString() != "go-logger."+logger.FATAL.String() {
+		t.Errorf("default exception.type = %q", got.AsString())
+	}
+	if got, _ := span.attr(ExceptionMessageKey); got.AsString() != "oom" {
+		t.Errorf("exception.message without error field = %q", got.AsString())
+	}
+	if span.statusCode != codes.Unset {
+		t.Errorf("status set with WithSpanStatus(false): %v", span.statusCode)
+	}
+
+	idle := &recordingSpan{}
+	RecordErrors()(trace.ContextWithSpan(context.Background(), idle), &logger.LogEntry{Level: logger.ERROR, Message: "m"})
+	if len(idle.events) != 0 {
+		t.Error("event added to a non-recording span")
+	}
+}
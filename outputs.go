@@ -0,0 +1,101 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\outputs.go
+ * @Description: 单个 Logger 的多输出目标，每个目标有独立的级别阈值
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// OutputSpec 输出目标及其级别阈值
+type OutputSpec struct {
+	Writer io.Writer // 输出目标
+	Level  LogLevel  // 最低输出级别
+	Below  LogLevel  // 只接收低于该级别的日志（零值表示不限制）
+}
+
+// SplitStdStreams 标准输出流拆分：WARN 及以下写入 stdout，ERROR、FATAL 写入 stderr
+// 部分容器日志采集器按输出流区分严重程度
+func SplitStdStreams() []OutputSpec {
+	return []OutputSpec{
+		{Writer: os.Stdout, Level: DEBUG, Below: ERROR},
+		{Writer: os.Stderr, Level: ERROR},
+	}
+}
+
+// outputRouter 按级别把日志行分发到各输出目标
+type outputRouter struct {
+	outputs []OutputSpec
+}
+
+// WithOutputs 设置多个输出目标，每个目标只接收不低于其阈值的日志
+// 例如控制台 >= INFO、文件 >= DEBUG、stderr >= ERROR；Logger 自身级别仍需不高于各目标的最低阈值
+func (l *Logger) WithOutputs(outputs []OutputSpec) *Logger {
+	specs := make([]OutputSpec, 0, len(outputs))
+	for _, spec := range outputs {
+		if spec.Writer != nil {
+			specs = append(specs, spec)
+		}
+	}
+	return l.WithOutput(&outputRouter{outputs: specs})
+}
+
+// WithSplitStdStreams 使用 SplitStdStreams 拆分 stdout 与 stderr
+func (l *Logger) WithSplitStdStreams() *Logger {
+	return l.WithOutputs(SplitStdStreams())
+}
+
+// Write 写入级别未知的内容（如标准库 log 输出），按 INFO 级别分发
+func (r *outputRouter) Write(p []byte) (int, error) {
+	return r.WriteLevel(INFO, p)
+}
+
+// WriteLevel 写入阈值不高于 level 的输出目标
+func (r *outputRouter) WriteLevel(level LogLevel, p []byte) (int, error) {
+	var lastErr error
+	for _, spec := range r.outputs {
+		if level < spec.Level || (spec.Below > DEBUG && level >= spec.Below) {
+			continue
+		}
+		var err error
+		if lw, ok := spec.Writer.(interface {
+			WriteLevel(LogLevel, []byte) (int, error)
+		}); ok {
+			_, err = lw.WriteLevel(level, p)
+		} else {
+			_, err = spec.Writer.Write(p)
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return len(p), lastErr
+}
+
+// Flush 刷新支持 Flush/Sync 的输出目标
+func (r *outputRouter) Flush() error {
+	var lastErr error
+	for _, spec := range r.outputs {
+		var err error
+		switch out := spec.Writer.(type) {
+		case interface{ Flush() error }:
+			err = out.Flush()
+		case interface{ Sync() error }:
+			if out != os.Stdout && out != os.Stderr {
+				err = out.Sync()
+			}
+		}
+		if err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
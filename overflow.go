@@ -0,0 +1,88 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\overflow.go
+ * @Description: 异步组件队列溢出事件（异步写入、钩子发送队列、请求缓冲），便于上报指标或降级
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// OverflowKind 溢出类型
+type OverflowKind int
+
+const (
+	OverflowBlocked OverflowKind = iota // 队列已满，调用方等待（不丢失）
+	OverflowDropped                     // 队列已满，日志或请求被丢弃
+)
+
+// String 返回溢出类型名称
+func (k OverflowKind) String() string {
+	if k == OverflowDropped {
+		return "dropped"
+	}
+	return "blocked"
+}
+
+// 溢出组件名
+const (
+	OverflowComponentAsync         = "async"          // 异步写入队列
+	OverflowComponentHook          = "hook"           // 钩子 HTTP 发送队列
+	OverflowComponentRequestBuffer = "request_buffer" // 请求级缓冲
+	OverflowComponentFluent        = "fluent"         // Fluent forward 发送队列
+	OverflowComponentDatadog       = "datadog"        // Datadog 日志发送队列
+	OverflowComponentSplunk        = "splunk"         // Splunk HEC 发送队列
+	OverflowComponentNATS          = "nats"           // NATS 发布队列
+	OverflowComponentMQTT          = "mqtt"           // MQTT 发布队列
+	OverflowComponentUnixgram      = "unixgram"       // unix 数据报发送队列
+	OverflowComponentSubscriber    = "subscriber"     // 进程内日志订阅通道
+)
+
+// OverflowEvent 溢出事件
+type OverflowEvent struct {
+	Time      time.Time    `json:"time"`
+	Component string       `json:"component"`
+	Kind      OverflowKind `json:"kind"`
+	Capacity  int          `json:"capacity"` // 队列容量
+}
+
+// OverflowHandler 溢出事件处理函数（在写日志的协程中同步调用，应尽快返回）
+type OverflowHandler func(OverflowEvent)
+
+// overflowHandler 当前处理函数
+var overflowHandler atomic.Pointer[OverflowHandler]
+
+// SetOverflowHandler 设置溢出事件处理函数，传入 nil 取消
+func SetOverflowHandler(handler OverflowHandler) {
+	if handler == nil {
+		overflowHandler.Store(nil)
+		return
+	}
+	overflowHandler.Store(&handler)
+}
+
+// OverflowChannel 以通道形式接收溢出事件（替换当前处理函数），通道满时丢弃事件
+func OverflowChannel(size int) <-chan OverflowEvent {
+	ch := make(chan OverflowEvent, size)
+	SetOverflowHandler(func(e OverflowEvent) {
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+	return ch
+}
+
+// reportOverflow 上报溢出事件
+func reportOverflow(component string, kind OverflowKind, capacity int) {
+	if h := overflowHandler.Load(); h != nil {
+		(*h)(OverflowEvent{Time: time.Now(), Component: component, Kind: kind, Capacity: capacity})
+	}
+}
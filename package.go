@@ -0,0 +1,137 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\package.go
+ * @Description: 按调用方包路径自动命名 Logger，支持按包覆盖日志级别
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"runtime"
+	"strings"
+)
+
+// PackageKey 包 Logger 的标签字段名
+const PackageKey = "package"
+
+// ForPackage 通过默认管理器获取调用方所在包的 Logger
+func ForPackage() *Logger {
+	return DefaultManager().forPackage(callerPackage(2))
+}
+
+// ForPackage 获取调用方所在包的 Logger（日志带 package 标签，级别受 SetPackageLevel 覆盖）
+func (m *LoggerManager) ForPackage() *Logger {
+	return m.forPackage(callerPackage(2))
+}
+
+// forPackage 获取指定包的 Logger
+func (m *LoggerManager) forPackage(pkg string) *Logger {
+	m.mu.RLock()
+	pl, ok := m.packages[pkg]
+	m.mu.RUnlock()
+	if ok {
+		return pl
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if pl, ok := m.packages[pkg]; ok {
+		return pl
+	}
+	pl = m.root.With(PackageKey, pkg)
+	if level, ok := m.packageLevelLocked(pkg); ok {
+		pl.level = level
+	}
+	m.packages[pkg] = pl
+	return pl
+}
+
+// SetPackageLevel 覆盖包的日志级别
+// pattern 为完整包路径，或以 "/..." 结尾匹配该路径及其子包；多条规则命中时最长的规则生效
+func (m *LoggerManager) SetPackageLevel(pattern string, level LogLevel) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.packageLevels[pattern] = level
+	for pkg, pl := range m.packages {
+		if lv, ok := m.packageLevelLocked(pkg); ok {
+			pl.SetLevel(lv)
+		}
+	}
+}
+
+// ClearPackageLevel 移除包级别覆盖（已创建的包 Logger 恢复为根 Logger 级别或其他命中规则）
+func (m *LoggerManager) ClearPackageLevel(pattern string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.packageLevels, pattern)
+	for pkg, pl := range m.packages {
+		if lv, ok := m.packageLevelLocked(pkg); ok {
+			pl.SetLevel(lv)
+		} else {
+			pl.SetLevel(m.root.GetLevel())
+		}
+	}
+}
+
+// PackageLevels 返回包级别覆盖规则
+func (m *LoggerManager) PackageLevels() map[string]LogLevel {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	levels := make(map[string]LogLevel, len(m.packageLevels))
+	for pattern, level := range m.packageLevels {
+		levels[pattern] = level
+	}
+	return levels
+}
+
+// packageLevelLocked 查找包的级别覆盖（调用方持有锁）
+func (m *LoggerManager) packageLevelLocked(pkg string) (LogLevel, bool) {
+	var (
+		best    LogLevel
+		bestLen = -1
+	)
+	for pattern, level := range m.packageLevels {
+		if !matchPackage(pattern, pkg) || len(pattern) <= bestLen {
+			continue
+		}
+		best, bestLen = level, len(pattern)
+	}
+	return best, bestLen >= 0
+}
+
+// matchPackage 包路径匹配（"a/b/..." 匹配 a/b 及其子包）
+func matchPackage(pattern, pkg string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+	return pattern == pkg
+}
+
+// callerPackage 获取调用栈上第 skip 层函数所在的包路径
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return "unknown"
+	}
+	return packageOf(fn.Name())
+}
+
+// packageOf 从完整函数名提取包路径，如 "github.com/a/b.(*T).M" -> "github.com/a/b"
+func packageOf(funcName string) string {
+	slash := strings.LastIndexByte(funcName, '/')
+	if dot := strings.IndexByte(funcName[slash+1:], '.'); dot >= 0 {
+		return funcName[:slash+1+dot]
+	}
+	return funcName
+}
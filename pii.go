@@ -0,0 +1,203 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\pii.go
+ * @Description: 敏感字段分级（PII/Secret）与按级别的处理策略
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// FieldClass 字段敏感级别
+type FieldClass uint8
+
+const (
+	ClassNone   FieldClass = iota // 普通字段
+	ClassPII                      // 个人身份信息（邮箱、手机号、IP 等）
+	ClassSecret                   // 密钥类信息（密码、令牌等）
+)
+
+// PolicyAction 敏感字段处理方式
+type PolicyAction uint8
+
+const (
+	PolicyKeep PolicyAction = iota // 原样输出
+	PolicyMask                     // 掩码，仅保留首尾字符
+	PolicyHash                     // 加盐哈希，可关联但不可还原
+	PolicyDrop                     // 整个字段不输出
+)
+
+const (
+	hashedValueLen = 16 // 哈希输出的十六进制长度
+)
+
+var maskPlaceholder = []byte("****")
+
+// Field 带敏感级别的字段，可作为键值对中的独立元素或字段值使用：
+//
+//	log.InfoKV("login", logger.PII("email", email), "result", "ok")
+//	log.WithField("token", logger.Secret("token", token))
+type Field struct {
+	Key   string
+	Value any
+	Class FieldClass
+}
+
+// PII 标记个人身份信息字段
+func PII(key string, value any) Field {
+	return Field{Key: key, Value: value, Class: ClassPII}
+}
+
+// Secret 标记密钥类字段
+func Secret(key string, value any) Field {
+	return Field{Key: key, Value: value, Class: ClassSecret}
+}
+
+// String 实现 fmt.Stringer，避免在未经策略处理的路径上泄露原始值
+func (f Field) String() string {
+	if f.Class == ClassNone {
+		return fmt.Sprint(f.Value)
+	}
+	return string(maskPlaceholder)
+}
+
+// FieldPolicy 敏感字段处理策略
+type FieldPolicy struct {
+	Actions  map[FieldClass]PolicyAction // 各级别的处理方式
+	HashSalt []byte                      // PolicyHash 使用的盐
+}
+
+// DefaultFieldPolicy 默认策略：PII 掩码，Secret 丢弃
+func DefaultFieldPolicy() *FieldPolicy {
+	return &FieldPolicy{
+		Actions: map[FieldClass]PolicyAction{
+			ClassPII:    PolicyMask,
+			ClassSecret: PolicyDrop,
+		},
+	}
+}
+
+// action 获取级别对应的处理方式
+func (p *FieldPolicy) action(class FieldClass) PolicyAction {
+	if class == ClassNone || p == nil {
+		return PolicyKeep
+	}
+	return p.Actions[class]
+}
+
+// WithFieldPolicy 设置敏感字段处理策略（nil 表示全部原样输出）
+func (l *Logger) WithFieldPolicy(policy *FieldPolicy) *Logger {
+	l.fieldPolicy = policy
+	return l
+}
+
+// appendField 按字段策略追加 "k: v"，sep 为 true 时先追加分隔符
+// 分级字段按策略处理，普通字段依次经过字段转换器；字段被丢弃时原样返回 buf 和 false
+func (l *Logger) appendField(buf []byte, sep bool, key string, value any) ([]byte, bool) {
+	value, action := l.fieldAction(key, value)
+	if action == PolicyDrop {
+		return buf, false
+	}
+
+	if sep {
+		buf = append(buf, kvDelimiter...)
+	}
+	buf = append(buf, convert.S2B(key)...)
+	buf = append(buf, kvSeparator...)
+
+	start := len(buf)
+	buf = convert.AppendValue(buf, value)
+	switch action {
+	case PolicyMask:
+		buf = maskTail(buf, start)
+	case PolicyHash:
+		buf = hashTail(buf, start, l.fieldPolicy.HashSalt)
+	}
+	return l.limitFieldValue(buf, start), true
+}
+
+// fieldAction 解包分级字段并确定处理方式，普通字段依次经过字段转换器（转换器丢弃时返回 PolicyDrop）
+// 含脱敏标签的结构体转为脱敏后的字段映射；转换后的值超过摘要阈值时替换为摘要（Full 标记的值除外）
+func (l *Logger) fieldAction(key string, value any) (any, PolicyAction) {
+	action := PolicyKeep
+	if f, ok := value.(Field); ok {
+		value = f.Value
+		action = l.fieldPolicy.action(f.Class)
+	}
+	full := false
+	if f, ok := value.(FullValue); ok {
+		value, full = f.Value, true
+	}
+	value = redactTagged(value)
+
+	if action == PolicyKeep {
+		for _, t := range l.fieldTransformers {
+			var keep bool
+			if value, keep = t.TransformField(key, value); !keep {
+				return nil, PolicyDrop
+			}
+		}
+		if l.summarizer != nil && !full {
+			value = l.summarizer.summarize(value)
+		}
+	}
+	return value, action
+}
+
+// resolveField 按字段策略得到字段的最终值（供结构化日志条目使用），字段被丢弃时返回 false
+func (l *Logger) resolveField(key string, value any) (any, bool) {
+	value, action := l.fieldAction(key, value)
+	switch action {
+	case PolicyDrop:
+		return nil, false
+	case PolicyMask:
+		return string(maskTail(convert.AppendValue(nil, value), 0)), true
+	case PolicyHash:
+		return string(hashTail(convert.AppendValue(nil, value), 0, l.fieldPolicy.HashSalt)), true
+	}
+	return value, true
+}
+
+// maskTail 将 buf[start:] 替换为 "首字符****尾字符"，过短的值整体替换
+func maskTail(buf []byte, start int) []byte {
+	raw := buf[start:]
+	if utf8.RuneCount(raw) <= 2 {
+		return append(buf[:start], maskPlaceholder...)
+	}
+
+	_, firstSize := utf8.DecodeRune(raw)
+	_, lastSize := utf8.DecodeLastRune(raw)
+	var last [utf8.UTFMax]byte
+	n := copy(last[:], raw[len(raw)-lastSize:])
+
+	buf = append(buf[:start+firstSize], maskPlaceholder...)
+	return append(buf, last[:n]...)
+}
+
+// hashTail 将 buf[start:] 替换为加盐 HMAC-SHA256 的前 16 位十六进制
+func hashTail(buf []byte, start int, salt []byte) []byte {
+	var out [hashedValueLen]byte
+	hashInto(out[:], buf[start:], salt)
+	return append(buf[:start], out[:]...)
+}
+
+// hashInto 计算 HMAC-SHA256(salt, raw) 并将前 len(out)/2 字节以十六进制写入 out
+func hashInto(out, raw, salt []byte) {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(raw)
+	var sum [sha256.Size]byte
+	digest := mac.Sum(sum[:0])
+	hex.Encode(out, digest[:len(out)/2])
+}
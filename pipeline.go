@@ -0,0 +1,253 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\pipeline.go
+ * @Description: 结构化日志管道（中间件 -> 钩子 -> 格式化输出），仅在配置了钩子/中间件/格式化器时启用
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+	"github.com/kamalyes/go-toolbox/pkg/mathx"
+	"github.com/kamalyes/go-toolbox/pkg/stringx"
+)
+
+// ErrHookClosed 钩子已关闭
+var ErrHookClosed = errors.New("hook closed")
+
+// hasPipeline 是否启用结构化管道（未启用时走直接编码的快速路径）
+func (l *Logger) hasPipeline() bool {
+	return len(l.hooks) > 0 || len(l.middleware) > 0 || l.formatter != nil || l.renderer != nil || l.fingerprint || l.subs.hasSubscribers() ||
+		l.spanCtx != nil || (l.spanRecorder != nil && l.context != nil)
+}
+
+// dispatch 构建日志条目并依次经过中间件、钩子、格式化后写出
+// 字段顺序为静态字段、字段映射、键值对，分级字段在此按策略处理；
+// template 为消息模板（格式化日志为格式串，其余为消息本身），用于计算指纹；skip 含义同 appendHeader
+func (l *Logger) dispatch(level LogLevel, msg, template string, fields map[string]any, keysAndValues []any, skip int) {
+	l.dispatchAt(l.now(), level, msg, template, fields, keysAndValues, skip+1)
+
+	if level == FATAL {
+		l.exitFatal()
+	}
+}
+
+// dispatchAt 同 dispatch，使用指定的时间戳且不处理 FATAL 退出
+func (l *Logger) dispatchAt(t time.Time, level LogLevel, msg, template string, fields map[string]any, keysAndValues []any, skip int) {
+	entry := AcquireLogEntry()
+	entry.Level = level
+	entry.Message = msg
+	entry.Timestamp = t.UnixNano()
+	if l.logID != nil {
+		entry.Fields[LogIDKey] = l.logID()
+	}
+	if l.goroutineID {
+		entry.Fields[GoroutineIDKey] = GoroutineID()
+	}
+	l.collectKV(entry.Fields, l.staticKV)
+	l.collectLevelFields(entry.Fields, level)
+	for k, v := range gateFields(level, fields) {
+		l.collectField(entry.Fields, k, v)
+	}
+	l.collectKV(entry.Fields, gateKV(level, keysAndValues))
+	if l.fingerprint {
+		entry.Fields[FingerprintKey] = Fingerprint(template)
+	}
+
+	if l.showCaller {
+		if f, ok := lookupCaller(skip + 1 + l.callerSkip); ok {
+			entry.Caller = &CallerInfo{File: f.file, Line: f.line, Function: f.function}
+		}
+	}
+
+	if err := l.runMiddleware(0, entry); err != nil && !errors.Is(err, ErrSecretDetected) {
+		reportInternalError("pipeline", err)
+	}
+	ReleaseLogEntry(entry)
+}
+
+// collectKV 按策略将键值对写入字段映射，键值对规则同 appendKVPairs
+func (l *Logger) collectKV(dst map[string]any, keysAndValues []any) {
+	for i := 0; i < len(keysAndValues); {
+		if f, ok := keysAndValues[i].(Field); ok {
+			if resolved, ok := l.resolveField(f.Key, f); ok {
+				dst[f.Key] = resolved
+			}
+			i++
+			continue
+		}
+
+		var (
+			key   string
+			value any
+		)
+		key, value, i = kvPair(keysAndValues, i)
+		l.collectField(dst, key, value)
+	}
+}
+
+// collectField 按策略将单个字段写入字段映射（启用结构体展开时写入展开后的各字段）
+func (l *Logger) collectField(dst map[string]any, key string, value any) {
+	if l.flattener != nil && l.flattenField(key, value, func(k string, v any) {
+		if resolved, ok := l.resolveField(k, v); ok {
+			dst[k] = resolved
+		}
+	}) {
+		return
+	}
+	if resolved, ok := l.resolveField(key, value); ok {
+		dst[key] = resolved
+	}
+}
+
+// runMiddleware 执行第 i 个中间件，全部执行完后触发钩子并写出
+// 中间件不调用 next 即可拦截该条日志
+func (l *Logger) runMiddleware(i int, entry *LogEntry) error {
+	if i >= len(l.middleware) {
+		return l.emitEntry(entry)
+	}
+	return l.middleware[i].Process(entry, func(e *LogEntry) error {
+		return l.runMiddleware(i+1, e)
+	})
+}
+
+// emitEntry 触发钩子并格式化写出
+func (l *Logger) emitEntry(entry *LogEntry) error {
+	for _, hook := range l.hooks {
+		if hookFiresAt(hook, entry.Level) {
+			if err := hook.Fire(entry); err != nil {
+				reportInternalError("hook", err)
+			}
+		}
+	}
+	if l.subs != nil {
+		l.subs.publish(entry)
+	}
+	l.recordSpan(entry)
+
+	bp := bytePool.Get().(*[]byte)
+	var line []byte
+	if formatter := mathx.IF(l.renderer != nil, l.renderer, l.formatter); formatter != nil {
+		formatted, err := formatter.Format(l.limitEntry(entry))
+		if err != nil {
+			putLineBuf(bp, (*bp)[:0])
+			return err
+		}
+		line = append((*bp)[:0], formatted...)
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			line = append(line, newline...)
+		}
+	} else {
+		line = l.appendEntry((*bp)[:0], entry)
+	}
+
+	l.writeOutput(entry.Level, line)
+	putLineBuf(bp, line)
+	return nil
+}
+
+// hookFiresAt 钩子是否在该级别触发（未声明级别的钩子对所有级别触发）
+func hookFiresAt(hook IHook, level LogLevel) bool {
+	levels := hook.Levels()
+	if len(levels) == 0 {
+		return true
+	}
+	for _, lv := range levels {
+		if lv == level {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEntry 以默认文本格式编码日志条目（与快速路径输出一致）
+func (l *Logger) appendEntry(buf []byte, entry *LogEntry) []byte {
+	buf = stringx.FastFormatTime(buf, time.Unix(0, entry.Timestamp))
+	if l.prefix != "" {
+		buf = append(buf, convert.S2B(l.prefix)...)
+	}
+	buf = append(buf, mathx.IF(l.colorful, levelPrefixesColor[entry.Level], levelPrefixes[entry.Level])...)
+
+	if entry.Caller != nil {
+		file := entry.Caller.File
+		if idx := strings.LastIndex(file, "/"); idx != -1 {
+			file = file[idx+1:]
+		}
+		funcName := entry.Caller.Function
+		if idx := strings.LastIndex(funcName, "."); idx != -1 {
+			funcName = funcName[idx+1:]
+		}
+		buf = append(buf, '[')
+		buf = append(buf, convert.S2B(file)...)
+		buf = append(buf, ':')
+		buf = stringx.FastAppendInt(buf, entry.Caller.Line)
+		buf = append(buf, ':')
+		buf = append(buf, convert.S2B(funcName)...)
+		buf = append(buf, ']', ' ')
+	}
+
+	msgStart := len(buf)
+	buf = append(buf, convert.S2B(entry.Message)...)
+	buf = l.limitMessage(buf, msgStart)
+
+	if len(entry.Fields) > 0 {
+		buf = append(buf, kvBraceOpen...)
+		sep := false
+		var keysBuf [16]string
+		for _, k := range sortedFieldKeys(keysBuf[:0], entry.Fields) {
+			v := entry.Fields[k]
+			if sep {
+				buf = append(buf, kvDelimiter...)
+			}
+			buf = append(buf, convert.S2B(k)...)
+			buf = append(buf, kvSeparator...)
+			start := len(buf)
+			buf = convert.AppendValue(buf, v)
+			buf = l.limitFieldValue(buf, start)
+			sep = true
+		}
+		buf = append(buf, kvBraceClose...)
+	}
+	return append(buf, newline...)
+}
+
+// sortMiddleware 按优先级升序排列中间件（数值小的先执行）
+func sortMiddleware(middleware []IMiddleware) []IMiddleware {
+	sorted := append([]IMiddleware(nil), middleware...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetPriority() < sorted[j].GetPriority()
+	})
+	return sorted
+}
+
+// exitFatal FATAL 日志后停止异步写入并退出进程
+func (l *Logger) exitFatal() {
+	if l.async != nil {
+		l.async.Close()
+	}
+	os.Exit(1)
+}
+
+// Copy 深拷贝日志条目（钩子需要在 Fire 返回后继续使用条目时调用，原条目会被复用）
+func (e *LogEntry) Copy() *LogEntry {
+	c := *e
+	c.Fields = make(map[string]interface{}, len(e.Fields))
+	for k, v := range e.Fields {
+		c.Fields[k] = v
+	}
+	if e.Caller != nil {
+		caller := *e.Caller
+		c.Caller = &caller
+	}
+	return &c
+}
@@ -0,0 +1,152 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\pool.go
+ * @Description: 带统计的对象池（日志条目、字段容器、缓冲区）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledFieldMapSize 超过此字段数的 map 不再放回池中
+const maxPooledFieldMapSize = 64
+
+// poolStatsEnabled 是否统计对象池命中情况（默认关闭，避免热路径上的原子操作竞争）
+var poolStatsEnabled atomic.Bool
+
+// EnablePoolStats 开启或关闭对象池统计
+func EnablePoolStats(enabled bool) {
+	poolStatsEnabled.Store(enabled)
+}
+
+// PoolStats 对象池统计快照
+type PoolStats struct {
+	Name    string  `json:"name"`     // 池名称
+	Gets    int64   `json:"gets"`     // 获取次数
+	Puts    int64   `json:"puts"`     // 归还次数
+	News    int64   `json:"news"`     // 未命中而新建的次数
+	HitRate float64 `json:"hit_rate"` // 命中率（0-1）
+}
+
+// statPool 带统计的 sync.Pool 包装
+type statPool struct {
+	name string
+	pool sync.Pool
+	gets atomic.Int64
+	puts atomic.Int64
+	news atomic.Int64
+}
+
+// allPools 已注册的对象池（用于统计导出）
+var allPools []*statPool
+
+// newStatPool 创建并注册对象池
+func newStatPool(name string, newFn func() any) *statPool {
+	p := &statPool{name: name}
+	p.pool.New = func() any {
+		if poolStatsEnabled.Load() {
+			p.news.Add(1)
+		}
+		return newFn()
+	}
+	allPools = append(allPools, p)
+	return p
+}
+
+// Get 从池中获取对象
+func (p *statPool) Get() any {
+	if poolStatsEnabled.Load() {
+		p.gets.Add(1)
+	}
+	return p.pool.Get()
+}
+
+// Put 归还对象
+func (p *statPool) Put(x any) {
+	if poolStatsEnabled.Load() {
+		p.puts.Add(1)
+	}
+	p.pool.Put(x)
+}
+
+// snapshot 获取统计快照
+func (p *statPool) snapshot() PoolStats {
+	stats := PoolStats{
+		Name: p.name,
+		Gets: p.gets.Load(),
+		Puts: p.puts.Load(),
+		News: p.news.Load(),
+	}
+	if stats.Gets > 0 {
+		stats.HitRate = float64(stats.Gets-stats.News) / float64(stats.Gets)
+	}
+	return stats
+}
+
+// GetPoolStats 获取所有对象池的统计信息（需先调用 EnablePoolStats(true)）
+func GetPoolStats() []PoolStats {
+	stats := make([]PoolStats, 0, len(allPools))
+	for _, p := range allPools {
+		stats = append(stats, p.snapshot())
+	}
+	return stats
+}
+
+// ResetPoolStats 清零对象池统计
+func ResetPoolStats() {
+	for _, p := range allPools {
+		p.gets.Store(0)
+		p.puts.Store(0)
+		p.news.Store(0)
+	}
+}
+
+// ============================================================================
+// 日志条目池
+// ============================================================================
+
+var entryPool = newStatPool("entry", func() any {
+	return &LogEntry{Fields: make(map[string]interface{}, 8)}
+})
+
+// AcquireLogEntry 从池中获取日志条目，使用完毕后调用 ReleaseLogEntry 归还
+func AcquireLogEntry() *LogEntry {
+	return entryPool.Get().(*LogEntry)
+}
+
+// ReleaseLogEntry 重置并归还日志条目，归还后不得再使用
+func ReleaseLogEntry(entry *LogEntry) {
+	if entry == nil {
+		return
+	}
+	fields := entry.Fields
+	if len(fields) > maxPooledFieldMapSize {
+		fields = nil
+	}
+	clear(fields)
+	*entry = LogEntry{Fields: fields}
+	if entry.Fields == nil {
+		entry.Fields = make(map[string]interface{}, 8)
+	}
+	entryPool.Put(entry)
+}
+
+// ============================================================================
+// 字段容器池
+// ============================================================================
+
+// putFieldMap 清空并归还字段 map
+func putFieldMap(m map[string]any) {
+	if len(m) > maxPooledFieldMapSize {
+		return
+	}
+	clear(m)
+	fieldMapPool.Put(m)
+}
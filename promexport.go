@@ -0,0 +1,309 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\promexport.go
+ * @Description: Prometheus 日志计数导出（按级别计数，OpenMetrics 格式下附带 trace_id 样例，便于从错误率尖峰跳转到对应链路）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PromContentType Prometheus 文本格式（不支持样例）
+	PromContentType = "text/plain; version=0.0.4; charset=utf-8"
+	// OpenMetricsContentType OpenMetrics 文本格式（支持样例）
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	// promOverflowValue 序列数超过上限后附加标签统一使用的取值
+	promOverflowValue = "other"
+	// promExemplarMaxRunes OpenMetrics 规定样例标签名与值合计不超过 128 个字符
+	promExemplarMaxRunes = 128
+)
+
+// promExemplar 计数样例
+type promExemplar struct {
+	traceID string
+	spanID  string
+	at      time.Time
+}
+
+// promSeries 单个计数序列
+type promSeries struct {
+	labels   []string
+	count    uint64
+	exemplar *promExemplar
+}
+
+// PromExporter Prometheus 日志计数导出钩子
+// 按级别（及可选的字段标签）统计日志条数；日志字段中带有 trace_id 时记录为该序列最近一次的样例，
+// 以 OpenMetrics 格式抓取时输出 `# {trace_id="..."} 1 <时间戳>`，Grafana 可据此从计数曲线直接跳转到链路与日志
+type PromExporter struct {
+	mu        sync.Mutex
+	name      string
+	labels    []string
+	traceKey  string
+	spanKey   string
+	exemplars bool
+	maxSeries int
+	series    map[string]*promSeries
+}
+
+// PromExporterOption Prometheus 导出配置选项
+type PromExporterOption func(*PromExporter)
+
+// WithPromName 设置指标名（默认 go_logger_entries，输出为 <名称>_total）
+func WithPromName(name string) PromExporterOption {
+	return func(p *PromExporter) {
+		if name != "" {
+			p.name = strings.TrimSuffix(name, "_total")
+		}
+	}
+}
+
+// WithPromLabels 将指定字段作为附加标签（如 service、tenant_id），缺失时取空字符串
+// 仅适用于取值有限的字段，序列数受 WithPromMaxSeries 限制
+func WithPromLabels(keys ...string) PromExporterOption {
+	return func(p *PromExporter) {
+		p.labels = append(p.labels[:0], keys...)
+	}
+}
+
+// WithPromTraceKeys 设置 trace id 与 span id 所在的字段名（默认 trace_id、span_id）
+func WithPromTraceKeys(traceKey, spanKey string) PromExporterOption {
+	return func(p *PromExporter) {
+		if traceKey != "" {
+			p.traceKey = traceKey
+		}
+		p.spanKey = spanKey
+	}
+}
+
+// WithPromExemplars 设置是否记录样例（默认开启）
+func WithPromExemplars(enabled bool) PromExporterOption {
+	return func(p *PromExporter) {
+		p.exemplars = enabled
+	}
+}
+
+// WithPromMaxSeries 设置最大序列数（默认 1000），超过后附加标签取值统一记为 other
+func WithPromMaxSeries(n int) PromExporterOption {
+	return func(p *PromExporter) {
+		if n > 0 {
+			p.maxSeries = n
+		}
+	}
+}
+
+// NewPromExporter 创建 Prometheus 日志计数导出钩子，通过 Logger.WithHooks 启用，并将其挂载为 HTTP 抓取端点：
+//
+//	exporter := logger.NewPromExporter()
+//	log.WithHooks([]logger.IHook{exporter})
+//	mux.Handle("/metrics/logs", exporter)
+func NewPromExporter(opts ...PromExporterOption) *PromExporter {
+	p := &PromExporter{
+		name:      "go_logger_entries",
+		traceKey:  ContextKeyTraceID,
+		spanKey:   "span_id",
+		exemplars: true,
+		maxSeries: 1000,
+		series:    make(map[string]*promSeries),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Fire 实现 IHook 接口
+func (p *PromExporter) Fire(entry *LogEntry) error {
+	values := make([]string, 1+len(p.labels))
+	values[0] = strings.ToLower(entry.Level.String())
+	for i, key := range p.labels {
+		if v, ok := entry.Fields[key]; ok {
+			values[i+1] = fmt.Sprint(v)
+		}
+	}
+
+	var exemplar *promExemplar
+	if p.exemplars {
+		exemplar = p.exemplarOf(entry)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	series := p.seriesLocked(values)
+	series.count++
+	if exemplar != nil {
+		series.exemplar = exemplar
+	}
+	return nil
+}
+
+// Levels 实现 IHook 接口（所有级别）
+func (p *PromExporter) Levels() []LogLevel {
+	return nil
+}
+
+// exemplarOf 从日志字段中提取样例，没有活动链路时返回 nil
+func (p *PromExporter) exemplarOf(entry *LogEntry) *promExemplar {
+	traceID, _ := entry.Fields[p.traceKey].(string)
+	if traceID == "" {
+		return nil
+	}
+	exemplar := &promExemplar{traceID: traceID, at: time.Unix(0, entry.Timestamp)}
+	if p.spanKey != "" {
+		exemplar.spanID, _ = entry.Fields[p.spanKey].(string)
+	}
+	return exemplar
+}
+
+// seriesLocked 查找或创建序列（调用方持有锁）
+func (p *PromExporter) seriesLocked(values []string) *promSeries {
+	id := strings.Join(values, "\x00")
+	if series, ok := p.series[id]; ok {
+		return series
+	}
+	if len(p.series) >= p.maxSeries && len(values) > 1 {
+		for i := 1; i < len(values); i++ {
+			values[i] = promOverflowValue
+		}
+		id = strings.Join(values, "\x00")
+		if series, ok := p.series[id]; ok {
+			return series
+		}
+	}
+	series := &promSeries{labels: values}
+	p.series[id] = series
+	return series
+}
+
+// Reset 清空所有计数
+func (p *PromExporter) Reset() {
+	p.mu.Lock()
+	p.series = make(map[string]*promSeries)
+	p.mu.Unlock()
+}
+
+// Render 以 Prometheus 文本格式（openMetrics 为 true 时使用 OpenMetrics 格式并附带样例）输出计数
+func (p *PromExporter) Render(w *strings.Builder, openMetrics bool) {
+	p.mu.Lock()
+	series := make([]promSeries, 0, len(p.series))
+	for _, s := range p.series {
+		series = append(series, *s)
+	}
+	p.mu.Unlock()
+	sort.Slice(series, func(i, j int) bool {
+		return strings.Join(series[i].labels, "\x00") < strings.Join(series[j].labels, "\x00")
+	})
+
+	family := p.name
+	if !openMetrics {
+		family += "_total"
+	}
+	fmt.Fprintf(w, "# HELP %s Number of log entries by level.\n", family)
+	fmt.Fprintf(w, "# TYPE %s counter\n", family)
+	for _, s := range series {
+		w.WriteString(p.name)
+		w.WriteString("_total{")
+		p.writeLabel(w, "level", s.labels[0])
+		for i, key := range p.labels {
+			w.WriteByte(',')
+			p.writeLabel(w, key, s.labels[i+1])
+		}
+		w.WriteString("} ")
+		w.WriteString(strconv.FormatUint(s.count, 10))
+		if openMetrics && s.exemplar != nil {
+			p.writeExemplar(w, s.exemplar)
+		}
+		w.WriteByte('\n')
+	}
+	if openMetrics {
+		w.WriteString("# EOF\n")
+	}
+}
+
+// writeLabel 输出 name="value"（值按文本格式转义）
+func (p *PromExporter) writeLabel(w *strings.Builder, name, value string) {
+	w.WriteString(promLabelName(name))
+	w.WriteString(`="`)
+	w.WriteString(promEscape(value))
+	w.WriteByte('"')
+}
+
+// writeExemplar 输出样例；标签超出长度上限时仅保留 trace_id，仍超出则放弃样例
+func (p *PromExporter) writeExemplar(w *strings.Builder, e *promExemplar) {
+	traceName := promLabelName(p.traceKey)
+	size := len([]rune(traceName)) + len([]rune(e.traceID))
+	if size > promExemplarMaxRunes {
+		return
+	}
+	w.WriteString(" # {")
+	p.writeLabel(w, traceName, e.traceID)
+	if e.spanID != "" {
+		spanName := promLabelName(p.spanKey)
+		if size+len([]rune(spanName))+len([]rune(e.spanID)) <= promExemplarMaxRunes {
+			w.WriteByte(',')
+			p.writeLabel(w, spanName, e.spanID)
+		}
+	}
+	w.WriteString("} 1 ")
+	w.WriteString(strconv.FormatFloat(float64(e.at.UnixNano())/1e9, 'f', 3, 64))
+}
+
+// ServeHTTP 实现 http.Handler 接口，Accept 中包含 application/openmetrics-text 时输出 OpenMetrics 格式
+func (p *PromExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	var b strings.Builder
+	p.Render(&b, openMetrics)
+	if openMetrics {
+		w.Header().Set("Content-Type", OpenMetricsContentType)
+	} else {
+		w.Header().Set("Content-Type", PromContentType)
+	}
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// promLabelName 将字段名转换为合法的标签名（非 [a-zA-Z0-9_] 字符替换为下划线，不能以数字开头）
+func promLabelName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// promEscape 转义标签值中的反斜杠、双引号与换行
+func promEscape(value string) string {
+	if !strings.ContainsAny(value, "\\\"\n") {
+		return value
+	}
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+var _ IHook = (*PromExporter)(nil)
+var _ http.Handler = (*PromExporter)(nil)
@@ -0,0 +1,93 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\query.go
+ * @Description: 日志查询条件与数据源（内存环形缓冲与带索引文件的实现见 query 子包）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// QueryCriteria 日志查询条件（零值字段不参与过滤）
+type QueryCriteria struct {
+	Since  time.Time         // 起始时间（含）
+	Until  time.Time         // 结束时间（含）
+	Level  LogLevel          // 最低级别
+	Fields map[string]string // 字段精确匹配（字段值按 %v 格式化后比较）
+	Text   string            // 全文匹配（消息与字段值，不区分大小写）
+	Limit  int               // 最多返回条数（超出时保留最新的）
+}
+
+// QuerySource 可查询的日志数据源
+type QuerySource interface {
+	Query(criteria QueryCriteria) ([]Entry, error)
+}
+
+// Match 判断条目是否满足查询条件
+func (c QueryCriteria) Match(e Entry) bool {
+	if e.Level < c.Level {
+		return false
+	}
+	if !c.Since.IsZero() && e.Time.Before(c.Since) {
+		return false
+	}
+	if !c.Until.IsZero() && e.Time.After(c.Until) {
+		return false
+	}
+	for k, want := range c.Fields {
+		v, ok := e.Fields[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	if c.Text != "" && !entryContains(e, strings.ToLower(c.Text)) {
+		return false
+	}
+	return true
+}
+
+// entryContains 消息或字段值包含 text（text 已转小写）
+func entryContains(e Entry, text string) bool {
+	if strings.Contains(strings.ToLower(e.Message), text) {
+		return true
+	}
+	for _, v := range e.Fields {
+		if strings.Contains(strings.ToLower(fmt.Sprint(v)), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Truncate 按 Limit 截取最新的条目（entries 按时间排序）
+func (c QueryCriteria) Truncate(entries []Entry) []Entry {
+	if c.Limit > 0 && len(entries) > c.Limit {
+		return entries[len(entries)-c.Limit:]
+	}
+	return entries
+}
+
+// Query 在多个数据源上查询，结果按时间排序
+func Query(criteria QueryCriteria, sources ...QuerySource) ([]Entry, error) {
+	var result []Entry
+	for _, src := range sources {
+		entries, err := src.Query(criteria)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return criteria.Truncate(result), nil
+}
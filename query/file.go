@@ -0,0 +1,58 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\query\file.go
+ * @Description: 日志文件查询，存在偏移量索引时先定位再扫描
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package query
+
+import (
+	"io"
+	"os"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/tailer"
+)
+
+// File 日志文件数据源
+type File struct {
+	Path string
+}
+
+// NewFile 创建日志文件数据源
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+// Query 实现 logger.QuerySource 接口
+// 文件旁存在 WithFileIndex 生成的索引时从 Since 所在时间桶的偏移开始扫描
+func (f *File) Query(criteria logger.QueryCriteria) ([]logger.Entry, error) {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if index, err := logger.ReadFileIndex(f.Path); err == nil && len(index) > 0 {
+		offset := index.Seek(criteria.Since, criteria.Level)
+		if offset < 0 {
+			return nil, nil
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	var result []logger.Entry
+	err = tailer.ReadEntries(file, func(e logger.Entry) bool {
+		if criteria.Match(e) {
+			result = append(result, e)
+		}
+		return true
+	})
+	return criteria.Truncate(result), err
+}
@@ -0,0 +1,165 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\query\query_test.go
+ * @Description: 内存环形缓冲与日志文件查询测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package query
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+)
+
+// messages 提取条目的消息
+func messages(entries []logger.Entry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Message)
+	}
+	return result
+}
+
+// TestRing 作为 Logger 输出保留最近的写入，查询时解析并按条件过滤
+func TestRing(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     int
+		criteria logger.QueryCriteria
+		wantLen  int
+		want     []string
+	}{
+		{"keeps all", 10, logger.QueryCriteria{}, 5, []string{"m0", "m1", "m2", "m3", "m4"}},
+		{"overwrites oldest", 3, logger.QueryCriteria{}, 3, []string{"m2", "m3", "m4"}},
+		{"level", 10, logger.QueryCriteria{Level: logger.WARN}, 5, []string{"m1", "m3"}},
+		{"field", 10, logger.QueryCriteria{Fields: map[string]string{"seq": "4"}}, 5, []string{"m4"}},
+		{"limit", 10, logger.QueryCriteria{Limit: 2}, 5, []string{"m3", "m4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := NewRing(tt.size)
+			log := logger.NewLogger().WithOutput(ring).WithColorful(false)
+			for i := 0; i < 5; i++ {
+				if i%2 == 1 {
+					log.WarnKV(fmt.Sprintf("m%d", i), "seq", i)
+				} else {
+					log.InfoKV(fmt.Sprintf("m%d", i), "seq", i)
+				}
+			}
+
+			if got := ring.Len(); got != tt.wantLen {
+				t.Errorf("Len = %d, want %d", got, tt.wantLen)
+			}
+			entries, err := ring.Query(tt.criteria)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if got := messages(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRingReset 清空后不再返回旧日志，默认容量为 1000
+func TestRingReset(t *testing.T) {
+	ring := NewRing(0)
+	if len(ring.items) != 1000 {
+		t.Errorf("default size = %d, want 1000", len(ring.items))
+	}
+	log := logger.NewLogger().WithOutput(ring).WithColorful(false)
+	log.Info("before")
+	ring.Reset()
+	log.Info("after")
+
+	entries, err := ring.Query(logger.QueryCriteria{})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if got := messages(entries); !reflect.DeepEqual(got, []string{"after"}) {
+		t.Errorf("messages = %v, want [after]", got)
+	}
+}
+
+// TestFile 按索引定位到 Since 所在时间桶后扫描，索引中没有满足级别的条目时直接返回空结果
+func TestFile(t *testing.T) {
+	base := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	lines := []struct {
+		minute int
+		level  logger.LogLevel
+		msg    string
+	}{
+		{0, logger.INFO, "boot"},
+		{0, logger.DEBUG, "probe"},
+		{1, logger.INFO, "serve"},
+		{2, logger.WARN, "slow"},
+		{2, logger.INFO, "done"},
+	}
+
+	var data, index strings.Builder
+	indexed := map[string]bool{}
+	for _, l := range lines {
+		at := base.Add(time.Duration(l.minute) * time.Minute)
+		key := fmt.Sprintf("%d %s", at.Unix(), l.level)
+		if !indexed[key] {
+			indexed[key] = true
+			fmt.Fprintf(&index, "%s %d\n", key, data.Len())
+		}
+		fmt.Fprintf(&data, `{"level":%q,"message":%q,"time":%q}`+"\n", l.level.String(), l.msg, at.Format(time.RFC3339Nano))
+	}
+
+	tests := []struct {
+		name     string
+		index    bool
+		criteria logger.QueryCriteria
+		want     []string
+	}{
+		{"no index scans all", false, logger.QueryCriteria{}, []string{"boot", "probe", "serve", "slow", "done"}},
+		{"no index since", false, logger.QueryCriteria{Since: base.Add(time.Minute)}, []string{"serve", "slow", "done"}},
+		{"index since", true, logger.QueryCriteria{Since: base.Add(90 * time.Second)}, []string{"slow", "done"}},
+		{"index level", true, logger.QueryCriteria{Level: logger.WARN}, []string{"slow"}},
+		{"index no match", true, logger.QueryCriteria{Level: logger.ERROR}, nil},
+		{"text and limit", false, logger.QueryCriteria{Text: "o", Limit: 2}, []string{"slow", "done"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			if err := os.WriteFile(path, []byte(data.String()), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.index {
+				if err := os.WriteFile(path+logger.FileIndexSuffix, []byte(index.String()), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entries, err := NewFile(path).Query(tt.criteria)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if got := messages(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileMissing 文件不存在时返回错误
+func TestFileMissing(t *testing.T) {
+	if _, err := NewFile(filepath.Join(t.TempDir(), "none.log")).Query(logger.QueryCriteria{}); !os.IsNotExist(err) {
+		t.Errorf("Query error = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\query\ring.go
+ * @Description: 内存环形缓冲输出，保留最近的日志供查询
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package query
+
+import (
+	"bytes"
+	"sync"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/tailer"
+)
+
+// Ring 内存环形缓冲，作为 Logger 输出保留最近 size 次写入的内容
+// 通过 WithOutputs 或 MultiWriter 与其他输出并用；写入时只拷贝原始字节，查询时才解析
+type Ring struct {
+	mu    sync.Mutex
+	items [][]byte
+	next  int
+	full  bool
+}
+
+// NewRing 创建容量为 size 的环形缓冲（<= 0 时为 1000）
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		size = 1000
+	}
+	return &Ring{items: make([][]byte, size)}
+}
+
+// Write 实现 io.Writer 接口
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[r.next] = append(r.items[r.next][:0], p...)
+	r.next++
+	if r.next == len(r.items) {
+		r.next = 0
+		r.full = true
+	}
+	return len(p), nil
+}
+
+// Len 返回当前保留的写入次数
+func (r *Ring) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full {
+		return len(r.items)
+	}
+	return r.next
+}
+
+// Reset 清空缓冲
+func (r *Ring) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next, r.full = 0, false
+}
+
+// snapshot 按写入顺序拷贝缓冲内容
+func (r *Ring) snapshot() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var buf []byte
+	if r.full {
+		for _, item := range r.items[r.next:] {
+			buf = append(buf, item...)
+		}
+	}
+	for _, item := range r.items[:r.next] {
+		buf = append(buf, item...)
+	}
+	return buf
+}
+
+// Query 实现 logger.QuerySource 接口
+func (r *Ring) Query(criteria logger.QueryCriteria) ([]logger.Entry, error) {
+	var result []logger.Entry
+	err := tailer.ReadEntries(bytes.NewReader(r.snapshot()), func(e logger.Entry) bool {
+		if criteria.Match(e) {
+			result = append(result, e)
+		}
+		return true
+	})
+	return criteria.Truncate(result), err
+}
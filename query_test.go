@@ -0,0 +1,110 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\query_test.go
+ * @Description: 日志查询条件与多数据源合并测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sliceSource 固定条目的数据源
+type sliceSource struct {
+	entries []Entry
+	err     error
+}
+
+func (s sliceSource) Query(c QueryCriteria) ([]Entry, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var result []Entry
+	for _, e := range s.entries {
+		if c.Match(e) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+// TestQueryCriteriaMatch 零值条件不过滤，非零条件同时满足才匹配
+func TestQueryCriteriaMatch(t *testing.T) {
+	at := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	entry := Entry{Level: WARN, Message: "Payment Declined", Time: at, Fields: map[string]any{"order": "o-1", "attempt": 2}}
+	tests := []struct {
+		name     string
+		criteria QueryCriteria
+		want     bool
+	}{
+		{"zero criteria", QueryCriteria{}, true},
+		{"level below", QueryCriteria{Level: INFO}, true},
+		{"level equal", QueryCriteria{Level: WARN}, true},
+		{"level above", QueryCriteria{Level: ERROR}, false},
+		{"since inclusive", QueryCriteria{Since: at}, true},
+		{"since after", QueryCriteria{Since: at.Add(time.Second)}, false},
+		{"until inclusive", QueryCriteria{Until: at}, true},
+		{"until before", QueryCriteria{Until: at.Add(-time.Second)}, false},
+		{"string field", QueryCriteria{Fields: map[string]string{"order": "o-1"}}, true},
+		{"int field formatted", QueryCriteria{Fields: map[string]string{"attempt": "2"}}, true},
+		{"field mismatch", QueryCriteria{Fields: map[string]string{"order": "o-2"}}, false},
+		{"missing field", QueryCriteria{Fields: map[string]string{"user": ""}}, false},
+		{"text in message ignores case", QueryCriteria{Text: "DECLINED"}, true},
+		{"text in field value", QueryCriteria{Text: "o-1"}, true},
+		{"text absent", QueryCriteria{Text: "refund"}, false},
+		{"all combined", QueryCriteria{Level: WARN, Since: at, Until: at, Fields: map[string]string{"order": "o-1"}, Text: "payment"}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.criteria.Match(entry); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestQueryMergesSources 多数据源结果按时间合并，Limit 保留最新的条目，任一数据源出错时返回错误
+func TestQueryMergesSources(t *testing.T) {
+	base := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	entry := func(sec int, msg string) Entry {
+		return Entry{Level: INFO, Message: msg, Time: base.Add(time.Duration(sec) * time.Second)}
+	}
+	memory := sliceSource{entries: []Entry{entry(1, "m1"), entry(4, "m4")}}
+	file := sliceSource{entries: []Entry{entry(0, "f0"), entry(2, "f2"), entry(3, "f3")}}
+	boom := errors.New("boom")
+
+	tests := []struct {
+		name     string
+		criteria QueryCriteria
+		sources  []QuerySource
+		want     []string
+		wantErr  error
+	}{
+		{"sorted by time", QueryCriteria{}, []QuerySource{memory, file}, []string{"f0", "m1", "f2", "f3", "m4"}, nil},
+		{"limit keeps newest", QueryCriteria{Limit: 2}, []QuerySource{memory, file}, []string{"f3", "m4"}, nil},
+		{"filter applied per source", QueryCriteria{Since: base.Add(2 * time.Second)}, []QuerySource{memory, file}, []string{"f2", "f3", "m4"}, nil},
+		{"no sources", QueryCriteria{}, nil, nil, nil},
+		{"source error", QueryCriteria{}, []QuerySource{memory, sliceSource{err: boom}}, nil, boom},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Query(tt.criteria, tt.sources...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Query error = %v, want %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, e := range entries {
+				got = append(got, e.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\quota.go
+ * @Description: 日志配额（按时间窗口限制条数与字节数，超出后进入摘要模式）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/stringx"
+)
+
+// Quota 日志配额：每个时间窗口内最多写出的条数与字节数（<= 0 表示该项不限制）
+// 超出后进入摘要模式：窗口内后续日志被抑制，下一窗口开始时写出一行
+// "quota exceeded, N entries suppressed" 汇总
+type Quota struct {
+	MaxEntries int
+	MaxBytes   int
+	Window     time.Duration
+}
+
+// quotaLimiter 配额计数器
+type quotaLimiter struct {
+	quota       Quota
+	mu          sync.Mutex
+	windowStart time.Time
+	entries     int
+	bytes       int
+	pending     int64 // 当前窗口被抑制的条数
+	suppressed  int64 // 累计被抑制的条数
+}
+
+// newQuotaLimiter 创建配额计数器，配额无效时返回 nil
+func newQuotaLimiter(q Quota) *quotaLimiter {
+	if q.Window <= 0 || (q.MaxEntries <= 0 && q.MaxBytes <= 0) {
+		return nil
+	}
+	return &quotaLimiter{quota: q}
+}
+
+// admit 检查配额，返回是否允许写出，以及进入新窗口时上一窗口被抑制的条数（需写出摘要）
+func (q *quotaLimiter) admit(size int) (ok bool, summary int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(q.windowStart) >= q.quota.Window {
+		q.windowStart = now
+		q.entries = 0
+		q.bytes = 0
+		summary = q.pending
+		q.pending = 0
+	}
+
+	if (q.quota.MaxEntries > 0 && q.entries >= q.quota.MaxEntries) ||
+		(q.quota.MaxBytes > 0 && q.bytes+size > q.quota.MaxBytes) {
+		q.pending++
+		q.suppressed++
+		return false, summary
+	}
+	q.entries++
+	q.bytes += size
+	return true, summary
+}
+
+// drain 取出当前窗口被抑制的条数（关闭时写出最后一次摘要）
+func (q *quotaLimiter) drain() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := q.pending
+	q.pending = 0
+	return n
+}
+
+// Suppressed 返回累计被抑制的条数
+func (q *quotaLimiter) Suppressed() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.suppressed
+}
+
+// appendQuotaMessage 追加摘要消息
+func appendQuotaMessage(buf []byte, suppressed int64) []byte {
+	buf = append(buf, "quota exceeded, "...)
+	buf = strconv.AppendInt(buf, suppressed, 10)
+	return append(buf, " entries suppressed"...)
+}
+
+// ============================================================================
+// Logger 配额
+// ============================================================================
+
+// WithQuota 设置 Logger 写出配额（子 Logger 共享同一配额）
+func (l *Logger) WithQuota(quota Quota) *Logger {
+	l.quota = newQuotaLimiter(quota)
+	return l
+}
+
+// QuotaSuppressed 返回因配额被抑制的日志条数
+func (l *Logger) QuotaSuppressed() int64 {
+	if l.quota == nil {
+		return 0
+	}
+	return l.quota.Suppressed()
+}
+
+// admitQuota 检查配额，进入新窗口时先写出上一窗口的摘要行
+func (l *Logger) admitQuota(line []byte) bool {
+	ok, suppressed := l.quota.admit(len(line))
+	if suppressed > 0 {
+		l.writeNotice(WARN, appendQuotaMessage(nil, suppressed), nil)
+	}
+	return ok
+}
+
+// ============================================================================
+// 输出器配额
+// ============================================================================
+
+// QuotaWriter 配额输出器（为底层输出器添加按窗口的条数、字节数配额，保护共享输出目标）
+type QuotaWriter struct {
+	baseWriter
+	underlying IWriter
+	name       string
+	quota      Quota
+	limiter    *quotaLimiter
+	closed     atomic.Bool
+}
+
+// QuotaWriterOption 配额输出器配置选项
+type QuotaWriterOption func(*QuotaWriter)
+
+// WithQuotaUnderlying 设置底层输出器
+func WithQuotaUnderlying(underlying IWriter) QuotaWriterOption {
+	return func(w *QuotaWriter) {
+		w.underlying = underlying
+	}
+}
+
+// WithQuotaLimit 设置配额
+func WithQuotaLimit(quota Quota) QuotaWriterOption {
+	return func(w *QuotaWriter) {
+		w.quota = quota
+	}
+}
+
+// WithQuotaName 设置名称（写入摘要行，便于区分是哪个输出器被限流）
+func WithQuotaName(name string) QuotaWriterOption {
+	return func(w *QuotaWriter) {
+		w.name = name
+	}
+}
+
+// WithQuotaLevel 设置日志级别
+func WithQuotaLevel(level LogLevel) QuotaWriterOption {
+	return func(w *QuotaWriter) {
+		w.level = level
+	}
+}
+
+// NewQuotaWriter 创建配额输出器
+func NewQuotaWriter(opts ...QuotaWriterOption) *QuotaWriter {
+	w := &QuotaWriter{
+		baseWriter: baseWriter{
+			level:   DEBUG,
+			healthy: true,
+			stats:   newWriterStats(),
+		},
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.limiter = newQuotaLimiter(w.quota)
+	return w
+}
+
+// Write 实现 io.Writer 接口（被抑制的日志视为写入成功）
+func (w *QuotaWriter) Write(p []byte) (n int, err error) {
+	if w.underlying == nil || w.closed.Load() {
+		return 0, fmt.Errorf("quota writer is not healthy")
+	}
+	if w.limiter != nil {
+		ok, suppressed := w.limiter.admit(len(p))
+		if suppressed > 0 {
+			w.writeSummary(suppressed)
+		}
+		if !ok {
+			return len(p), nil
+		}
+	}
+
+	n, err = w.underlying.Write(p)
+	if err != nil {
+		w.stats.addError()
+		return n, err
+	}
+	w.stats.addBytes(int64(n))
+	return n, nil
+}
+
+// writeSummary 写出摘要行
+func (w *QuotaWriter) writeSummary(suppressed int64) {
+	buf := stringx.FastFormatTime(make([]byte, 0, 128), time.Now())
+	buf = append(buf, levelPrefixes[WARN]...)
+	buf = appendQuotaMessage(buf, suppressed)
+	if w.name != "" {
+		buf = append(buf, " {quota: "...)
+		buf = append(buf, w.name...)
+		buf = append(buf, '}')
+	}
+	buf = append(buf, newline...)
+	w.underlying.Write(buf)
+}
+
+// WriteLevel 按级别写入
+func (w *QuotaWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
+	if level < w.level {
+		return len(data), nil
+	}
+	return w.Write(data)
+}
+
+// Flush 刷新底层输出器
+func (w *QuotaWriter) Flush() error {
+	if w.underlying == nil {
+		return nil
+	}
+	return w.underlying.Flush()
+}
+
+// Close 写出当前窗口的摘要并关闭底层输出器
+func (w *QuotaWriter) Close() error {
+	if w.closed.Swap(true) || w.underlying == nil {
+		return nil
+	}
+	if w.limiter != nil {
+		if suppressed := w.limiter.drain(); suppressed > 0 {
+			w.writeSummary(suppressed)
+		}
+	}
+	return w.underlying.Close()
+}
+
+// IsHealthy 检查健康状态
+func (w *QuotaWriter) IsHealthy() bool {
+	return !w.closed.Load() && w.underlying != nil && w.underlying.IsHealthy()
+}
+
+// GetStats 获取统计信息
+func (w *QuotaWriter) GetStats() WriterStatsSnapshot {
+	return w.stats.getSnapshot()
+}
+
+// Suppressed 返回因配额被抑制的日志条数
+func (w *QuotaWriter) Suppressed() int64 {
+	if w.limiter == nil {
+		return 0
+	}
+	return w.limiter.Suppressed()
+}
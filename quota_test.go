@@ -0,0 +1,76 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\quota_test.go
+ * @Description: 日志配额测试（条数、字节数限制与摘要模式）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoggerQuotaEntries 超出条数配额的日志被抑制，下一窗口写出摘要行，子 Logger 共享配额
+func TestLoggerQuotaEntries(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText).
+		WithQuota(Quota{MaxEntries: 3, Window: 50 * time.Millisecond})
+	child := l.With("component", "worker")
+
+	for i := 0; i < 5; i++ {
+		l.Info("parent %d", i)
+		child.Info("child")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Fatalf("got %d lines in the first window, want 3:\n%s", got, buf.String())
+	}
+	if got := l.QuotaSuppressed(); got != 7 {
+		t.Errorf("QuotaSuppressed = %d, want 7", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	buf.Reset()
+	l.Info("next window")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "quota exceeded, 7 entries suppressed") || !strings.Contains(lines[1], "next window") {
+		t.Errorf("next window output:\n%s", buf.String())
+	}
+}
+
+// TestQuotaWriterBytes 字节数配额：超出的写入视为成功但不写出，关闭时写出当前窗口的摘要
+func TestQuotaWriterBytes(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewQuotaWriter(
+		WithQuotaUnderlying(NewConsoleWriter(WithConsoleOutput(&buf), WithConsoleColor(false))),
+		WithQuotaLimit(Quota{MaxBytes: 20, Window: time.Hour}),
+		WithQuotaName("shared"),
+	)
+	for _, line := range []string{"0123456789\n", "abcdefgh\n", "dropped\n", "x\n"} {
+		if n, err := w.Write([]byte(line)); err != nil || n != len(line) {
+			t.Fatalf("Write(%q) = %d, %v", line, n, err)
+		}
+	}
+	if got := buf.String(); got != "0123456789\nabcdefgh\n" {
+		t.Errorf("written %q", got)
+	}
+	if got := w.Suppressed(); got != 2 {
+		t.Errorf("Suppressed = %d, want 2", got)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "quota exceeded, 2 entries suppressed {quota: shared}") {
+		t.Errorf("missing summary on close:\n%s", buf.String())
+	}
+	if _, err := w.Write([]byte("after close\n")); err == nil {
+		t.Error("write after close succeeded")
+	}
+}
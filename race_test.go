@@ -0,0 +1,16 @@
+//go:build race
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\race_test.go
+ * @Description: 竞态检测构建标记（-race 会引入额外分配，分配次数断言需跳过）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+// raceEnabled 当前测试是否以 -race 构建
+const raceEnabled = true
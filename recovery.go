@@ -0,0 +1,57 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\recovery.go
+ * @Description: 带 panic 恢复的协程启动（Go / SafeGo），panic 连同堆栈与上下文字段写入日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"runtime/debug"
+)
+
+// PanicKey panic 值字段名
+const PanicKey = "panic"
+
+// Go 启动协程执行 fn，fn panic 时恢复并以 ERROR 级别记录 panic 值与堆栈，避免无人关注的协程导致进程崩溃
+func (l *Logger) Go(fn func()) {
+	go func() {
+		defer l.recoverPanic(nil)
+		fn()
+	}()
+}
+
+// SafeGo 同 Go，fn 接收 ctx，panic 日志附加 ctx 中的上下文字段：
+//
+//	log.SafeGo(ctx, func(ctx context.Context) {
+//		process(ctx, job)
+//	})
+func (l *Logger) SafeGo(ctx context.Context, fn func(ctx context.Context)) {
+	go func() {
+		defer l.recoverPanic(ctx)
+		fn(ctx)
+	}()
+}
+
+// recoverPanic 恢复 panic 并记录日志（必须直接在 defer 中调用）
+func (l *Logger) recoverPanic(ctx context.Context) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stackKey := l.stacktraceKey
+	if stackKey == "" {
+		stackKey = "stacktrace"
+	}
+	kv := []any{PanicKey, r, stackKey, string(debug.Stack())}
+	if ctx == nil {
+		l.ErrorKV("goroutine panic recovered", kv...)
+		return
+	}
+	l.ErrorContextKV(ctx, "goroutine panic recovered", kv...)
+}
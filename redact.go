@@ -0,0 +1,177 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\redact.go
+ * @Description: 结构体字段脱敏标签（log:"-" 省略、log:"mask" 掩码），整体记录对象时自动生效
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// LogTagKey 结构体脱敏标签名：
+//
+//	type User struct {
+//		Name     string `json:"name"`
+//		Password string `json:"password" log:"-"`  // 不输出
+//		Phone    string `json:"phone" log:"mask"` // 仅保留首尾字符
+//	}
+//
+// 含有脱敏标签（包括嵌套结构体中）的结构体作为字段值记录时，转为按 json 标签命名的字段映射后输出；
+// 启用 WithStructFlatten 时展开的字段同样遵循标签
+const LogTagKey = "log"
+
+const (
+	logTagOmit = "-"
+	logTagMask = "mask"
+)
+
+// redactPlan 结构体类型的脱敏计划
+type redactPlan struct {
+	fields []redactField
+}
+
+// redactField 单个导出字段的处理方式
+type redactField struct {
+	index  int
+	name   string
+	action PolicyAction // PolicyKeep、PolicyMask 或 PolicyDrop
+	nested *redactPlan  // 字段为含标签的结构体（或其指针）时的嵌套计划
+}
+
+// redactPlans 按类型缓存脱敏计划（不含标签的类型缓存为 nil）
+var redactPlans sync.Map // reflect.Type -> *redactPlan
+
+// logTagAction 解析字段的 log 标签
+func logTagAction(sf reflect.StructField) PolicyAction {
+	tag, _, _ := strings.Cut(sf.Tag.Get(LogTagKey), ",")
+	switch tag {
+	case logTagOmit:
+		return PolicyDrop
+	case logTagMask:
+		return PolicyMask
+	}
+	return PolicyKeep
+}
+
+// maskValue 掩码字段值，仅保留首尾字符
+func maskValue(value any) string {
+	return string(maskTail(convert.AppendValue(nil, value), 0))
+}
+
+// redactTagged 值为含脱敏标签的结构体（或其指针）时返回脱敏后的字段映射，否则原样返回
+func redactTagged(value any) any {
+	if value == nil {
+		return value
+	}
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return value
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return value
+	}
+	plan := redactPlanFor(rv.Type(), nil)
+	if plan == nil {
+		return value
+	}
+	return plan.apply(rv)
+}
+
+// parseObjectFields 将单个对象参数解析为字段映射（InfoKV("msg", obj)），含脱敏标签的结构体按标签处理
+func parseObjectFields(obj any) map[string]any {
+	if fields, ok := redactTagged(obj).(map[string]any); ok {
+		return fields
+	}
+	return convert.ParseObjectToMap(obj)
+}
+
+// redactPlanFor 获取结构体类型的脱敏计划，visiting 用于处理递归类型
+func redactPlanFor(t reflect.Type, visiting map[reflect.Type]bool) *redactPlan {
+	if cached, ok := redactPlans.Load(t); ok {
+		return cached.(*redactPlan)
+	}
+	if visiting[t] {
+		return nil
+	}
+	if visiting == nil {
+		visiting = make(map[reflect.Type]bool)
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	plan := &redactPlan{}
+	tagged := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		field := redactField{index: i, name: name, action: logTagAction(sf)}
+		if field.action != PolicyKeep {
+			tagged = true
+		} else if ft := derefType(sf.Type); ft.Kind() == reflect.Struct {
+			if field.nested = redactPlanFor(ft, visiting); field.nested != nil {
+				tagged = true
+			}
+		}
+		plan.fields = append(plan.fields, field)
+	}
+	if !tagged {
+		plan = nil
+	}
+	redactPlans.Store(t, plan)
+	return plan
+}
+
+// derefType 解引用指针类型
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// apply 按计划将结构体值转为脱敏后的字段映射
+func (p *redactPlan) apply(rv reflect.Value) map[string]any {
+	out := make(map[string]any, len(p.fields))
+	for _, f := range p.fields {
+		fv := rv.Field(f.index)
+		switch {
+		case f.action == PolicyDrop:
+		case f.action == PolicyMask:
+			out[f.name] = maskValue(fv.Interface())
+		case f.nested != nil:
+			for fv.Kind() == reflect.Pointer && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				out[f.name] = f.nested.apply(fv)
+			} else {
+				out[f.name] = fv.Interface()
+			}
+		default:
+			out[f.name] = fv.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,168 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\replay\replay.go
+ * @Description: 日志回放：读取已写出的日志并重新经由 Logger/适配器输出，用于验证告警规则与新的输出配置
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package replay
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/tailer"
+)
+
+// OriginalLevelKey 适配器回放时 FATAL 降级为 ERROR，原始级别记录在该字段
+const OriginalLevelKey = "original_level"
+
+// batchLogger 支持保留原始时间戳的回放目标（*logger.Logger）
+type batchLogger interface {
+	ReplayBatch(entries []logger.Entry)
+}
+
+// Replayer 日志回放配置
+type Replayer struct {
+	rewrite bool
+	offset  time.Duration
+	speed   float64
+	filter  *logger.QueryCriteria
+}
+
+// Option 回放配置选项
+type Option func(*Replayer)
+
+// WithRewriteTimestamps 使用回放时刻作为日志时间（默认保留原始时间）
+func WithRewriteTimestamps() Option {
+	return func(r *Replayer) {
+		r.rewrite = true
+	}
+}
+
+// WithTimeOffset 将原始时间整体平移 d
+func WithTimeOffset(d time.Duration) Option {
+	return func(r *Replayer) {
+		r.offset = d
+	}
+}
+
+// WithSpeed 按原始时间间隔回放，speed 为倍速（2 表示两倍速，<= 0 表示不等待，默认不等待）
+func WithSpeed(speed float64) Option {
+	return func(r *Replayer) {
+		r.speed = speed
+	}
+}
+
+// WithFilter 只回放满足条件的日志
+func WithFilter(criteria logger.QueryCriteria) Option {
+	return func(r *Replayer) {
+		r.filter = &criteria
+	}
+}
+
+// New 创建回放器
+func New(opts ...Option) *Replayer {
+	r := &Replayer{}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replay 从 src 读取日志（JSON 行或文本格式）回放到 target，返回回放条数
+// target 为 *logger.Logger 时保留时间戳（经过其钩子、中间件与输出），FATAL 条目不会导致退出；
+// 其他 ILogger 实现（适配器）没有时间参数，时间戳放入 timestamp 字段，FATAL 降级为 ERROR
+func (r *Replayer) Replay(ctx context.Context, src io.Reader, target logger.ILogger) (int, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	count := 0
+	var last time.Time
+	var stopErr error
+	err := tailer.ReadEntries(src, func(e logger.Entry) bool {
+		if err := ctx.Err(); err != nil {
+			stopErr = err
+			return false
+		}
+		if r.filter != nil && !r.filter.Match(e) {
+			return true
+		}
+
+		if r.speed > 0 && !last.IsZero() && e.Time.After(last) {
+			if !sleep(ctx, time.Duration(float64(e.Time.Sub(last))/r.speed)) {
+				stopErr = ctx.Err()
+				return false
+			}
+		}
+		if !e.Time.IsZero() {
+			last = e.Time
+		}
+
+		r.emit(target, e)
+		count++
+		return true
+	})
+	if err == nil {
+		err = stopErr
+	}
+	return count, err
+}
+
+// ReplayFile 回放日志文件
+func (r *Replayer) ReplayFile(ctx context.Context, path string, target logger.ILogger) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return r.Replay(ctx, f, target)
+}
+
+// emit 输出一条日志
+func (r *Replayer) emit(target logger.ILogger, e logger.Entry) {
+	switch {
+	case r.rewrite:
+		e.Time = time.Time{}
+	case !e.Time.IsZero():
+		e.Time = e.Time.Add(r.offset)
+	}
+
+	if bl, ok := target.(batchLogger); ok {
+		bl.ReplayBatch([]logger.Entry{e})
+		return
+	}
+
+	fields := make(map[string]any, len(e.Fields)+2)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	if !e.Time.IsZero() {
+		fields["timestamp"] = e.Time.Format(time.RFC3339Nano)
+	}
+	level := e.Level
+	if level == logger.FATAL {
+		fields[OriginalLevelKey] = level.String()
+		level = logger.ERROR
+	}
+	target.LogWithFields(level, e.Message, fields)
+}
+
+// sleep 等待 d，ctx 取消时返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\replay\replay_test.go
+ * @Description: 日志回放测试（保留或平移时间戳、过滤、适配器降级 FATAL 与倍速取消）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package replay
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/ndjson"
+)
+
+var base = time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+
+// recorded 回放时记录的三条日志（JSON 行）
+const recorded = `{"level":"INFO","message":"boot","time":"2026-10-15T08:00:00Z","fields":{"node":"n1"}}
+{"level":"WARN","message":"slow","time":"2026-10-15T08:00:01Z","fields":{"ms":900}}
+{"level":"FATAL","message":"crash","time":"2026-10-15T08:00:02Z"}
+`
+
+// emitted 适配器收到的日志
+type emitted struct {
+	level  logger.LogLevel
+	msg    string
+	fields map[string]any
+}
+
+// recordingAdapter 记录收到日志的适配器
+type recordingAdapter struct {
+	*logger.BaseAdapter
+	mu   sync.Mutex
+	logs []emitted
+}
+
+func newRecordingAdapter() *recordingAdapter {
+	a := &recordingAdapter{}
+	a.BaseAdapter = logger.NewBaseAdapter("recording", "1.0.0", func(_ context.Context, level logger.LogLevel, msg string, fields map[string]any) {
+		a.mu.Lock()
+		a.logs = append(a.logs, emitted{level, msg, fields})
+		a.mu.Unlock()
+	})
+	a.SetLevel(logger.DEBUG)
+	return a
+}
+
+// decodeAll 解码 Logger 的 JSON 输出
+func decodeAll(t *testing.T, r io.Reader) []logger.Entry {
+	t.Helper()
+	var entries []logger.Entry
+	dec := ndjson.NewDecoder(r)
+	for {
+		e, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return entries
+		}
+		if err != nil {
+			t.Fatalf("output is not NDJSON: %v", err)
+		}
+		entries = append(entries, e)
+	}
+}
+
+// TestReplayToLogger 回放到 Logger 时经过其输出并保留（或平移、改写）原始时间，FATAL 不退出
+func TestReplayToLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantTimes []time.Time // nil 表示回放时刻
+		wantMsgs  []string
+	}{
+		{"keep timestamps", nil, []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second)}, []string{"boot", "slow", "crash"}},
+		{"offset", []Option{WithTimeOffset(time.Hour)}, []time.Time{base.Add(time.Hour), base.Add(time.Hour + time.Second), base.Add(time.Hour + 2*time.Second)}, []string{"boot", "slow", "crash"}},
+		{"rewrite", []Option{WithRewriteTimestamps()}, nil, []string{"boot", "slow", "crash"}},
+		{"filter", []Option{WithFilter(logger.QueryCriteria{Level: logger.WARN, Text: "slow"})}, []time.Time{base.Add(time.Second)}, []string{"slow"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			target := logger.NewLogger().WithOutput(&buf).WithFormatter(logger.NewJSONFormatter()).WithLevel(logger.DEBUG)
+			start := time.Now()
+			n, err := New(tt.opts...).Replay(context.Background(), strings.NewReader(recorded), target)
+			if err != nil {
+				t.Fatalf("Replay: %v", err)
+			}
+			if n != len(tt.wantMsgs) {
+				t.Errorf("replayed %d, want %d", n, len(tt.wantMsgs))
+			}
+
+			entries := decodeAll(t, &buf)
+			if len(entries) != len(tt.wantMsgs) {
+				t.Fatalf("got %d output lines, want %d", len(entries), len(tt.wantMsgs))
+			}
+			for i, e := range entries {
+				if e.Message != tt.wantMsgs[i] {
+					t.Errorf("entry %d message = %q, want %q", i, e.Message, tt.wantMsgs[i])
+				}
+				if tt.wantTimes == nil {
+					if e.Time.Before(start.Add(-time.Second)) {
+						t.Errorf("entry %d time = %v, want replay time", i, e.Time)
+					}
+				} else if !e.Time.Equal(tt.wantTimes[i]) {
+					t.Errorf("entry %d time = %v, want %v", i, e.Time, tt.wantTimes[i])
+				}
+			}
+		})
+	}
+}
+
+// TestReplayToAdapter 适配器没有时间参数：时间戳放入 timestamp 字段，FATAL 降级为 ERROR 并记录原始级别
+func TestReplayToAdapter(t *testing.T) {
+	a := newRecordingAdapter()
+	n, err := New().Replay(context.Background(), strings.NewReader(recorded), a)
+	if err != nil || n != 3 {
+		t.Fatalf("Replay = %d, %v", n, err)
+	}
+
+	want := []emitted{
+		{logger.INFO, "boot", map[string]any{"node": "n1", "timestamp": "2026-10-15T08:00:00Z"}},
+		{logger.WARN, "slow", map[string]any{"ms": int64(900), "timestamp": "2026-10-15T08:00:01Z"}},
+		{logger.ERROR, "crash", map[string]any{OriginalLevelKey: "FATAL", "timestamp": "2026-10-15T08:00:02Z"}},
+	}
+	if !reflect.DeepEqual(a.logs, want) {
+		t.Errorf("adapter logs =\n%v\nwant\n%v", a.logs, want)
+	}
+}
+
+// TestReplaySpeed 按倍速等待原始间隔，ctx 取消时停止并返回已回放条数
+func TestReplaySpeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		speed   float64
+		timeout time.Duration
+		wantN   int
+		wantErr error
+		minTime time.Duration
+	}{
+		{"no wait", 0, time.Second, 3, nil, 0},
+		{"fast forward", 20, 5 * time.Second, 3, nil, 100 * time.Millisecond}, // 2s 间隔 / 20 倍速
+		{"cancelled", 1, 200 * time.Millisecond, 1, context.DeadlineExceeded, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			a := newRecordingAdapter()
+			start := time.Now()
+			n, err := New(WithSpeed(tt.speed)).Replay(ctx, strings.NewReader(recorded), a)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Replay error = %v, want %v", err, tt.wantErr)
+			}
+			if n != tt.wantN {
+				t.Errorf("replayed %d, want %d", n, tt.wantN)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minTime {
+				t.Errorf("elapsed %v, want at least %v", elapsed, tt.minTime)
+			}
+		})
+	}
+}
+
+// TestReplayFile 回放文本格式的日志文件，文件不存在时返回错误
+func TestReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	text := "2026/10/15 8:00:00 ℹ️ [INFO] boot {node: n1}\n2026/10/15 8:00:01 ❌ [ERROR] panic\n\tstack line\n"
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a := newRecordingAdapter()
+	n, err := New(WithRewriteTimestamps()).ReplayFile(context.Background(), path, a)
+	if err != nil || n != 2 {
+		t.Fatalf("ReplayFile = %d, %v", n, err)
+	}
+	if a.logs[0].fields["node"] != "n1" || a.logs[1].msg != "panic\n\tstack line" || a.logs[1].level != logger.ERROR {
+		t.Errorf("adapter logs = %v", a.logs)
+	}
+	if _, ok := a.logs[0].fields["timestamp"]; ok {
+		t.Error("rewritten timestamps should not be sent as a field")
+	}
+
+	if _, err := New().ReplayFile(context.Background(), path+".missing", a); !os.IsNotExist(err) {
+		t.Errorf("ReplayFile error = %v, want not exist", err)
+	}
+}
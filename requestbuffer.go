@@ -0,0 +1,230 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\requestbuffer.go
+ * @Description: 请求级日志缓冲：低级别日志暂存在内存，请求出错或超过耗时阈值时才写出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestBufferKey context 中保存请求缓冲的 key
+type requestBufferKey struct{}
+
+// RequestBuffer 请求级日志缓冲
+// 低于 holdBelow 的日志暂存在内存；出现 escalateAt 及以上级别的日志时立即写出已缓冲内容，
+// 之后该请求的日志直接写出；请求结束时若出错或耗时超过阈值则写出缓冲，否则丢弃
+type RequestBuffer struct {
+	logger       *Logger
+	start        time.Time
+	captureLevel LogLevel
+	emitLevel    LogLevel // 原 Logger 的级别：未进入缓冲的日志低于该级别时丢弃，不因采集而直接写出
+	holdBelow    LogLevel
+	escalateAt   LogLevel
+	latency      time.Duration
+	maxEntries   int
+
+	mu        sync.Mutex
+	lines     []heldLine
+	dropped   int
+	escalated bool
+	finished  bool
+}
+
+// heldLine 缓冲中的一行日志
+type heldLine struct {
+	level LogLevel
+	data  []byte
+}
+
+// RequestBufferOption 请求缓冲配置选项
+type RequestBufferOption func(*RequestBuffer)
+
+// WithRequestCaptureLevel 设置缓冲采集的最低级别（默认 DEBUG，不受 Logger 全局级别限制）
+func WithRequestCaptureLevel(level LogLevel) RequestBufferOption {
+	return func(b *RequestBuffer) {
+		b.captureLevel = level
+	}
+}
+
+// WithRequestHoldBelow 设置暂存的级别上限，低于该级别的日志进入缓冲（默认 WARN，即缓冲 DEBUG、INFO）
+func WithRequestHoldBelow(level LogLevel) RequestBufferOption {
+	return func(b *RequestBuffer) {
+		b.holdBelow = level
+	}
+}
+
+// WithRequestEscalateAt 设置升级级别，记录该级别及以上日志时立即写出缓冲（默认 ERROR）
+func WithRequestEscalateAt(level LogLevel) RequestBufferOption {
+	return func(b *RequestBuffer) {
+		b.escalateAt = level
+	}
+}
+
+// WithRequestLatencyThreshold 设置耗时阈值，请求耗时超过阈值时写出缓冲（<= 0 表示不按耗时写出）
+func WithRequestLatencyThreshold(threshold time.Duration) RequestBufferOption {
+	return func(b *RequestBuffer) {
+		b.latency = threshold
+	}
+}
+
+// WithRequestMaxEntries 设置最多缓冲条数（默认 1000，超出时丢弃最早的日志）
+func WithRequestMaxEntries(n int) RequestBufferOption {
+	return func(b *RequestBuffer) {
+		if n > 0 {
+			b.maxEntries = n
+		}
+	}
+}
+
+// BeginRequestBuffer 开始一个请求级缓冲，返回携带缓冲的 context 与缓冲对象
+// 通过 buffer.Logger() 或 logger.FromContext(ctx) 获取绑定缓冲的子 Logger，
+// 请求结束时调用 buffer.End(err)
+func (l *Logger) BeginRequestBuffer(ctx context.Context, opts ...RequestBufferOption) (context.Context, *RequestBuffer) {
+	b := &RequestBuffer{
+		start:        time.Now(),
+		captureLevel: DEBUG,
+		holdBelow:    WARN,
+		escalateAt:   ERROR,
+		maxEntries:   1000,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	child := l.Clone().(*Logger)
+	child.reqBuffer = b
+	b.emitLevel = child.level
+	if b.captureLevel < child.level {
+		child.level = b.captureLevel
+	}
+	b.logger = child
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestBufferKey{}, b), b
+}
+
+// RequestBufferFromContext 从 context 中获取请求缓冲
+func RequestBufferFromContext(ctx context.Context) *RequestBuffer {
+	if ctx == nil {
+		return nil
+	}
+	b, _ := ctx.Value(requestBufferKey{}).(*RequestBuffer)
+	return b
+}
+
+// FromContext 返回适用于该 context 的 Logger：
+// 存在请求缓冲时使用其绑定的 Logger，命中定向调试目标时提升为 DEBUG 级别，
+// context 设置了更详细的级别（ContextWithLevel、DebugLevelGuard）时使用该级别，
+// 配置了 baggage 提取器时附加 baggage 字段，否则返回自身
+func (l *Logger) FromContext(ctx context.Context) *Logger {
+	if b := RequestBufferFromContext(ctx); b != nil {
+		l = b.logger
+	}
+	return l.debugLogger(ctx).baggageLogger(ctx)
+}
+
+// Logger 返回绑定该缓冲的 Logger
+func (b *RequestBuffer) Logger() *Logger {
+	return b.logger
+}
+
+// hold 尝试缓冲一行日志，返回 false 时由调用方直接写出
+// 只为缓冲而采集的低级别日志（低于原 Logger 级别）在请求结束或升级后直接丢弃
+func (b *RequestBuffer) hold(level LogLevel, line []byte) bool {
+	var overflowed bool
+	defer func() {
+		// 释放锁之后再上报，避免处理函数写日志时重入
+		if overflowed {
+			reportOverflow(OverflowComponentRequestBuffer, OverflowDropped, b.maxEntries)
+		}
+	}()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished || b.escalated {
+		return level < b.emitLevel
+	}
+	if level >= b.escalateAt {
+		b.escalated = true
+		b.flushLocked()
+		return level < b.emitLevel
+	}
+	if level >= b.holdBelow {
+		return level < b.emitLevel
+	}
+
+	if len(b.lines) >= b.maxEntries {
+		copy(b.lines, b.lines[1:])
+		b.lines = b.lines[:len(b.lines)-1]
+		b.dropped++
+		overflowed = true
+	}
+	b.lines = append(b.lines, heldLine{level: level, data: append([]byte(nil), line...)})
+	return true
+}
+
+// flushLocked 写出已缓冲日志（调用方持有锁）
+func (b *RequestBuffer) flushLocked() {
+	for _, line := range b.lines {
+		b.logger.writeRaw(line.level, line.data)
+	}
+	b.lines = nil
+}
+
+// End 结束请求：err 不为 nil 或耗时超过阈值时写出缓冲，否则丢弃
+// 返回是否写出了缓冲；结束后该 Logger 的日志直接写出
+func (b *RequestBuffer) End(err error) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished {
+		return false
+	}
+	b.finished = true
+
+	if err != nil || (b.latency > 0 && time.Since(b.start) >= b.latency) {
+		flushed := len(b.lines) > 0
+		b.flushLocked()
+		return flushed
+	}
+	b.lines = nil
+	return false
+}
+
+// Escalate 立即写出缓冲，之后该请求的日志直接写出
+func (b *RequestBuffer) Escalate() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.finished || b.escalated {
+		return
+	}
+	b.escalated = true
+	b.flushLocked()
+}
+
+// Buffered 返回当前缓冲条数
+func (b *RequestBuffer) Buffered() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.lines)
+}
+
+// Dropped 返回因超出上限被丢弃的条数
+func (b *RequestBuffer) Dropped() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
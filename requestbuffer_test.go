@@ -0,0 +1,154 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\requestbuffer_test.go
+ * @Description: 请求级日志缓冲测试（丢弃、出错写出、升级、耗时阈值、条数上限）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRequestBufferLogger 创建 INFO 级别、输出到 buf 的 Logger
+func newRequestBufferLogger(buf *bytes.Buffer) *Logger {
+	return NewLogger().WithOutput(buf).WithColorful(false).WithFormat(FormatText).WithLevel(INFO)
+}
+
+// outputLines 按行拆分输出
+func outputLines(buf *bytes.Buffer) []string {
+	s := strings.TrimSpace(buf.String())
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// assertOrder 检查各行依次包含 want 中的片段
+func assertOrder(t *testing.T, lines []string, want ...string) {
+	t.Helper()
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), strings.Join(lines, "\n"))
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("line %d = %q, want it to contain %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestRequestBufferDiscardOnSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	_, b := newRequestBufferLogger(&buf).BeginRequestBuffer(context.Background())
+	log := b.Logger()
+	log.Debug("debug detail")
+	log.Info("info detail")
+	log.Warn("slow downstream")
+
+	assertOrder(t, outputLines(&buf), "slow downstream")
+	if b.Buffered() != 2 {
+		t.Errorf("Buffered = %d, want 2", b.Buffered())
+	}
+	if b.End(nil) {
+		t.Error("End(nil) reported a flush")
+	}
+	assertOrder(t, outputLines(&buf), "slow downstream")
+
+	// 请求结束后日志直接写出，仅为缓冲采集的 DEBUG 日志不再写出
+	log.Info("after end")
+	log.Debug("debug after end")
+	assertOrder(t, outputLines(&buf), "slow downstream", "after end")
+}
+
+func TestRequestBufferFlushOnError(t *testing.T) {
+	var buf bytes.Buffer
+	parent := newRequestBufferLogger(&buf)
+	ctx, b := parent.BeginRequestBuffer(context.Background())
+	log := parent.FromContext(ctx)
+	log.Debug("step 1")
+	log.Info("step 2")
+	parent.Debug("parent debug is still filtered")
+
+	if len(outputLines(&buf)) != 0 {
+		t.Fatalf("buffered lines written early:\n%s", buf.String())
+	}
+	if !b.End(errors.New("boom")) {
+		t.Error("End(err) did not report a flush")
+	}
+	assertOrder(t, outputLines(&buf), "step 1", "step 2")
+	if b.End(errors.New("again")) {
+		t.Error("second End flushed again")
+	}
+}
+
+func TestRequestBufferEscalation(t *testing.T) {
+	var buf bytes.Buffer
+	_, b := newRequestBufferLogger(&buf).BeginRequestBuffer(context.Background())
+	log := b.Logger()
+	log.Debug("debug before")
+	log.Info("before")
+	log.Error("failed")
+	log.Debug("debug after escalation")
+	log.Info("after escalation")
+
+	assertOrder(t, outputLines(&buf), "debug before", "before", "failed", "after escalation")
+	if b.Buffered() != 0 {
+		t.Errorf("Buffered = %d after escalation", b.Buffered())
+	}
+	if b.End(nil) {
+		t.Error("End after escalation reported a flush")
+	}
+}
+
+func TestRequestBufferLatencyThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	_, b := newRequestBufferLogger(&buf).BeginRequestBuffer(context.Background(), WithRequestLatencyThreshold(10*time.Millisecond))
+	b.Logger().Info("slow request detail")
+	time.Sleep(15 * time.Millisecond)
+	if !b.End(nil) {
+		t.Error("slow request was not flushed")
+	}
+	assertOrder(t, outputLines(&buf), "slow request detail")
+}
+
+func TestRequestBufferMaxEntries(t *testing.T) {
+	var buf bytes.Buffer
+	_, b := newRequestBufferLogger(&buf).BeginRequestBuffer(context.Background(), WithRequestMaxEntries(2))
+	log := b.Logger()
+	for _, msg := range []string{"first", "second", "third"} {
+		log.Info("%s", msg)
+	}
+	if b.Dropped() != 1 {
+		t.Errorf("Dropped = %d, want 1", b.Dropped())
+	}
+	b.Escalate()
+	assertOrder(t, outputLines(&buf), "second", "third")
+}
+
+// TestRequestBufferRespectsLoggerLevel 只为缓冲采集的低级别日志仅随缓冲写出，不会绕过原 Logger 的级别直接写出
+func TestRequestBufferRespectsLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	parent := newRequestBufferLogger(&buf).WithLevel(ERROR)
+	_, b := parent.BeginRequestBuffer(context.Background(), WithRequestCaptureLevel(DEBUG))
+	log := b.Logger()
+	log.Debug("debug detail")
+	log.Warn("warn below logger level")
+	log.Info("info detail")
+	if len(outputLines(&buf)) != 0 {
+		t.Fatalf("lines below the logger level written directly:\n%s", buf.String())
+	}
+
+	log.Error("failed")
+	log.Warn("warn after escalation")
+	log.Debug("debug after escalation")
+	assertOrder(t, outputLines(&buf), "debug detail", "info detail", "failed")
+}
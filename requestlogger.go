@@ -0,0 +1,135 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\requestlogger.go
+ * @Description: 请求级 Logger 池：中间件入口获取、请求结束归还，跨请求复用字段 map 与预编码缓冲，降低 WithFields 的分配
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestLogger 请求级字段 Logger（实现 ILogger），由 AcquireRequestLogger 从池中获取：
+// Set 原地追加字段并重新预编码，不像 WithField 每次复制字段 map
+//
+//	rl := log.AcquireRequestLogger("request_id", id, "path", r.URL.Path)
+//	defer logger.ReleaseRequestLogger(rl)
+//	rl.Set("user_id", uid)
+//	rl.InfoKV("order created", "order_id", oid)
+type RequestLogger struct {
+	fieldLogger
+}
+
+var requestLoggerPool = newStatPool("request_logger", func() any {
+	return &RequestLogger{fieldLogger: fieldLogger{fields: make(map[string]any, 8)}}
+})
+
+// AcquireRequestLogger 从池中获取绑定 l 的请求级 Logger，keysAndValues 为初始字段，
+// 请求结束时调用 ReleaseRequestLogger 归还
+func (l *Logger) AcquireRequestLogger(keysAndValues ...any) *RequestLogger {
+	r := requestLoggerPool.Get().(*RequestLogger)
+	r.logger = l
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if key, ok := keysAndValues[i].(string); ok {
+			r.fields[key] = keysAndValues[i+1]
+		}
+	}
+	r.encode()
+	return r
+}
+
+// ReleaseRequestLogger 清空并归还请求级 Logger，归还后不得再使用（包括由其 WithFields 等返回的 ILogger）
+func ReleaseRequestLogger(r *RequestLogger) {
+	if r == nil {
+		return
+	}
+	fields, encoded := r.fields, r.encoded[:0]
+	if len(fields) > maxPooledFieldMapSize {
+		fields = make(map[string]any, 8)
+	}
+	if cap(encoded) > maxPooledBufferSize {
+		encoded = nil
+	}
+	clear(fields)
+	r.fieldLogger = fieldLogger{fields: fields, encoded: encoded}
+	requestLoggerPool.Put(r)
+}
+
+// Set 原地设置字段（后续日志生效）
+func (r *RequestLogger) Set(key string, value any) *RequestLogger {
+	r.fields[key] = value
+	r.encode()
+	return r
+}
+
+// SetFields 原地设置多个字段（后续日志生效）
+func (r *RequestLogger) SetFields(fields map[string]any) *RequestLogger {
+	for k, v := range fields {
+		r.fields[k] = v
+	}
+	r.encode()
+	return r
+}
+
+// Fields 返回当前字段（只读，归还后失效）
+func (r *RequestLogger) Fields() map[string]any {
+	return r.fields
+}
+
+// encode 复用缓冲预编码字段，规则同 newFieldLogger
+func (r *RequestLogger) encode() {
+	if r.logger.hasPipeline() || hasLevelFieldValue(r.fields) {
+		r.preEncoded = false
+		return
+	}
+	r.encoded, _ = r.logger.appendFieldsMap(r.encoded[:0], r.fields, false)
+	r.preEncoded = true
+}
+
+// requestLoggerKey context 中请求级 Logger 的键
+type requestLoggerKey struct{}
+
+// ContextWithRequestLogger 将请求级 Logger 放入 context
+func ContextWithRequestLogger(ctx context.Context, r *RequestLogger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestLoggerKey{}, r)
+}
+
+// RequestLoggerFromContext 从 context 中获取请求级 Logger
+func RequestLoggerFromContext(ctx context.Context) *RequestLogger {
+	if ctx == nil {
+		return nil
+	}
+	r, _ := ctx.Value(requestLoggerKey{}).(*RequestLogger)
+	return r
+}
+
+// RequestLoggerMiddleware 返回 HTTP 中间件：请求进入时获取请求级 Logger 并放入 context，处理结束后归还；
+// fields 返回初始字段的键值对，为 nil 时使用 method 与 path。
+// 处理函数通过 logger.RequestLoggerFromContext(r.Context()) 获取，不得在请求结束后继续持有
+//
+//	mux.Handle("/", log.RequestLoggerMiddleware(nil)(handler))
+func (l *Logger) RequestLoggerMiddleware(fields func(r *http.Request) []any) func(http.Handler) http.Handler {
+	if fields == nil {
+		fields = func(r *http.Request) []any {
+			return []any{"method", r.Method, "path", r.URL.Path}
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			rl := l.AcquireRequestLogger(fields(req)...)
+			defer ReleaseRequestLogger(rl)
+			next.ServeHTTP(w, req.WithContext(ContextWithRequestLogger(req.Context(), rl)))
+		})
+	}
+}
+
+var _ ILogger = (*RequestLogger)(nil)
@@ -0,0 +1,239 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\retention.go
+ * @Description: 历史日志保留策略：按时间、数量与总大小定期清理轮转/压缩后的日志文件
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultRetentionInterval 默认清理间隔
+const DefaultRetentionInterval = time.Hour
+
+// RetentionPolicy 单个目录的保留策略，MaxAge、MaxFiles、MaxTotalSize 为 0 时表示不限制
+// 文件按修改时间从新到旧排列，超过任一限制的文件会被删除
+type RetentionPolicy struct {
+	Dir          string        `json:"dir" yaml:"dir"`                       // 目录
+	Pattern      string        `json:"pattern" yaml:"pattern"`               // 文件名 glob 模式（默认 "*"），不应匹配正在写入的文件
+	Exclude      []string      `json:"exclude" yaml:"exclude"`               // 排除的文件名 glob 模式
+	MaxAge       time.Duration `json:"max_age" yaml:"max_age"`               // 最大保留时间
+	MaxFiles     int           `json:"max_files" yaml:"max_files"`           // 最大保留文件数
+	MaxTotalSize int64         `json:"max_total_size" yaml:"max_total_size"` // 最大总字节数
+}
+
+// RetentionReport 一次清理的结果
+type RetentionReport struct {
+	Scanned    int      // 匹配的文件数
+	Removed    []string // 删除（DryRun 时为将要删除）的文件
+	FreedBytes int64    // 释放的字节数
+}
+
+// RetentionManager 历史日志清理调度器，替代外部 cron 脚本：
+//
+//	rm := logger.NewRetentionManager(
+//		logger.WithRetentionPolicy(logger.RetentionPolicy{Dir: "/var/log/app", Pattern: "app.log.*", MaxAge: 7 * 24 * time.Hour, MaxTotalSize: 10 << 30}),
+//	)
+//	rm.Start()
+//	defer rm.Stop()
+type RetentionManager struct {
+	policies []RetentionPolicy
+	interval time.Duration
+	dryRun   bool
+	clock    Clock
+	onRemove func(path string, size int64)
+
+	mu       sync.Mutex // 串行化清理
+	stop     chan struct{}
+	stopOnce sync.Once
+	started  bool
+	wg       sync.WaitGroup
+}
+
+// RetentionOption 保留策略调度器配置选项
+type RetentionOption func(*RetentionManager)
+
+// WithRetentionPolicy 追加目录保留策略
+func WithRetentionPolicy(policies ...RetentionPolicy) RetentionOption {
+	return func(m *RetentionManager) {
+		m.policies = append(m.policies, policies...)
+	}
+}
+
+// WithRetentionInterval 设置清理间隔（默认 DefaultRetentionInterval）
+func WithRetentionInterval(interval time.Duration) RetentionOption {
+	return func(m *RetentionManager) {
+		if interval > 0 {
+			m.interval = interval
+		}
+	}
+}
+
+// WithRetentionDryRun 只统计将要删除的文件而不实际删除
+func WithRetentionDryRun(dryRun bool) RetentionOption {
+	return func(m *RetentionManager) {
+		m.dryRun = dryRun
+	}
+}
+
+// WithRetentionClock 设置判断文件年龄使用的时间源
+func WithRetentionClock(clock Clock) RetentionOption {
+	return func(m *RetentionManager) {
+		if clock != nil {
+			m.clock = clock
+		}
+	}
+}
+
+// WithRetentionOnRemove 设置删除文件后的回调（DryRun 时同样调用）
+func WithRetentionOnRemove(fn func(path string, size int64)) RetentionOption {
+	return func(m *RetentionManager) {
+		m.onRemove = fn
+	}
+}
+
+// NewRetentionManager 创建保留策略调度器，调用 Start 后开始定期清理
+func NewRetentionManager(opts ...RetentionOption) *RetentionManager {
+	m := &RetentionManager{
+		interval: DefaultRetentionInterval,
+		clock:    time.Now,
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start 立即清理一次并启动定期清理（重复调用无效果）
+func (m *RetentionManager) Start() {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return
+	}
+	m.started = true
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			m.RunOnce()
+			select {
+			case <-ticker.C:
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop 停止定期清理并等待进行中的清理结束
+func (m *RetentionManager) Stop() {
+	m.stopOnce.Do(func() { close(m.stop) })
+	m.wg.Wait()
+}
+
+// RunOnce 按所有策略执行一次清理，返回汇总结果与遇到的错误
+func (m *RetentionManager) RunOnce() (RetentionReport, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var report RetentionReport
+	var errs []error
+	for _, policy := range m.policies {
+		if err := m.apply(policy, &report); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		reportInternalError("retention", err)
+	}
+	return report, err
+}
+
+// retentionFile 待评估的文件
+type retentionFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// apply 执行单个目录的保留策略
+func (m *RetentionManager) apply(policy RetentionPolicy, report *RetentionReport) error {
+	pattern := policy.Pattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	matches, err := filepath.Glob(filepath.Join(policy.Dir, pattern))
+	if err != nil {
+		return err
+	}
+
+	files := make([]retentionFile, 0, len(matches))
+	for _, path := range matches {
+		if retentionExcluded(policy.Exclude, filepath.Base(path)) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, retentionFile{path: path, size: info.Size(), modTime: info.ModTime()})
+	}
+	report.Scanned += len(files)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	now := m.clock()
+	var total int64
+	var errs []error
+	for i, f := range files {
+		total += f.size
+		expired := policy.MaxAge > 0 && now.Sub(f.modTime) > policy.MaxAge
+		overCount := policy.MaxFiles > 0 && i >= policy.MaxFiles
+		overSize := policy.MaxTotalSize > 0 && total > policy.MaxTotalSize
+		if !expired && !overCount && !overSize {
+			continue
+		}
+		if !m.dryRun {
+			if err := os.Remove(f.path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		total -= f.size
+		report.Removed = append(report.Removed, f.path)
+		report.FreedBytes += f.size
+		if m.onRemove != nil {
+			m.onRemove(f.path, f.size)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// retentionExcluded 文件名是否匹配任一排除模式
+func retentionExcluded(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\sanitize.go
+ * @Description: 控制字符、ANSI 转义序列与换行清理（防止日志注入篡改终端显示或伪造日志行）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "github.com/kamalyes/go-toolbox/pkg/mathx"
+
+// SanitizeMode 消息与字段值中控制字符的处理方式
+// 作用于文本格式输出（JSON 编码本身会转义控制字符）；换行与回车由 NewlineMode 处理，制表符原样保留
+type SanitizeMode int
+
+const (
+	SanitizeEscape SanitizeMode = iota // 转义为可见形式，如 ESC 输出为 \x1b（默认）
+	SanitizeStrip                      // 删除控制字符与完整的 ANSI 转义序列
+	SanitizeOff                        // 原样输出（内容可信时使用）
+)
+
+// String 返回处理方式名称
+func (m SanitizeMode) String() string {
+	switch m {
+	case SanitizeStrip:
+		return "strip"
+	case SanitizeOff:
+		return "off"
+	default:
+		return "escape"
+	}
+}
+
+// WithSanitize 设置消息与字段值中控制字符的处理方式（默认 SanitizeEscape），同时作用于开发渲染
+func (l *Logger) WithSanitize(mode SanitizeMode) *Logger {
+	l.sanitize = mode
+	l.updateRenderer()
+	return l
+}
+
+// NewlineMode 消息与字段值中换行（\n、\r）的处理方式，作用于文本格式输出
+type NewlineMode int
+
+const (
+	NewlineKeep   NewlineMode = iota // 原样输出（默认，堆栈等多行内容保持可读）
+	NewlineEscape                    // 转义为 \n、\r，保证一条日志只占一行
+	NewlineIndent                    // 换行后追加缩进，续行不会被误认为新的日志行
+)
+
+// newlineIndent NewlineIndent 模式下续行的缩进
+const newlineIndent = "    "
+
+// String 返回处理方式名称
+func (m NewlineMode) String() string {
+	switch m {
+	case NewlineEscape:
+		return "escape"
+	case NewlineIndent:
+		return "indent"
+	default:
+		return "keep"
+	}
+}
+
+// WithNewline 设置消息与字段值中换行的处理方式（默认 NewlineKeep）；
+// 消息可能包含外部输入时建议使用 NewlineEscape，防止伪造日志行
+func (l *Logger) WithNewline(mode NewlineMode) *Logger {
+	l.newline = mode
+	return l
+}
+
+const hexDigits = "0123456789abcdef"
+
+// isControl 是否为需要处理的 C0 控制字符或 DEL（不含 \t \n \r）
+func isControl(c byte) bool {
+	return (c < 0x20 && c != '\t' && c != '\n' && c != '\r') || c == 0x7f
+}
+
+// isC1 p[i:] 是否以 UTF-8 编码的 C1 控制字符（U+0080 至 U+009F）开头
+func isC1(p []byte, i int) bool {
+	return p[i] == 0xc2 && i+1 < len(p) && p[i+1] >= 0x80 && p[i+1] <= 0x9f
+}
+
+// isNewline 是否为换行或回车
+func isNewline(c byte) bool {
+	return c == '\n' || c == '\r'
+}
+
+// controlIndex 返回第一个需要处理的字节位置，不存在时返回 -1
+func controlIndex(p []byte, mode SanitizeMode, newline NewlineMode) int {
+	for i, c := range p {
+		if (mode != SanitizeOff && (isControl(c) || isC1(p, i))) || (newline != NewlineKeep && isNewline(c)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// sanitizeTail 按 mode 与 newline 处理 buf[start:] 中的控制字符与换行；无需处理时原样返回，不产生分配
+func sanitizeTail(buf []byte, start int, mode SanitizeMode, newline NewlineMode) []byte {
+	if mode == SanitizeOff && newline == NewlineKeep {
+		return buf
+	}
+	i := controlIndex(buf[start:], mode, newline)
+	if i < 0 {
+		return buf
+	}
+	i += start
+
+	// 待处理部分复制到 buf 尾部，结果继续追加在其后，最后整体移回 i 处（复用同一缓冲）
+	end := len(buf)
+	buf = append(buf, buf[i:end]...)
+	srcEnd := len(buf)
+	for r := end; r < srcEnd; {
+		c := buf[r]
+		switch {
+		case newline != NewlineKeep && isNewline(c):
+			buf = appendNewline(buf, c, newline)
+			r++
+		case mode == SanitizeOff:
+			buf = append(buf, c)
+			r++
+		case c == 0x1b && mode == SanitizeStrip:
+			r = skipEscapeSequence(buf[:srcEnd], r)
+		case isControl(c):
+			if mode == SanitizeEscape {
+				buf = append(buf, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			r++
+		case isC1(buf[:srcEnd], r):
+			if mode == SanitizeEscape {
+				c = buf[r+1]
+				buf = append(buf, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			r += 2
+		default:
+			buf = append(buf, c)
+			r++
+		}
+	}
+	n := copy(buf[i:], buf[srcEnd:])
+	return buf[:i+n]
+}
+
+// appendNewline 按 newline 追加换行符 c（\r\n 在 NewlineIndent 模式下只缩进一次）
+func appendNewline(buf []byte, c byte, newline NewlineMode) []byte {
+	if newline == NewlineEscape {
+		return append(buf, '\\', mathx.IF(c == '\n', byte('n'), byte('r')))
+	}
+	buf = append(buf, c)
+	if c == '\n' {
+		buf = append(buf, newlineIndent...)
+	}
+	return buf
+}
+
+// skipEscapeSequence 跳过 p[i] 处 ESC 开头的转义序列，返回其后的位置：
+// CSI（ESC [ ... 终止字节 0x40-0x7E）、OSC（ESC ] ... BEL 或 ESC \）及其余两字节序列
+func skipEscapeSequence(p []byte, i int) int {
+	i++
+	if i >= len(p) {
+		return i
+	}
+	switch p[i] {
+	case '[':
+		for i++; i < len(p); i++ {
+			if p[i] >= 0x40 && p[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return i
+	case ']':
+		for i++; i < len(p); i++ {
+			if p[i] == 0x07 {
+				return i + 1
+			}
+			if p[i] == 0x1b && i+1 < len(p) && p[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return i
+	default:
+		return i + 1
+	}
+}
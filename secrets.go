@@ -0,0 +1,248 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\secrets.go
+ * @Description: 配置中的密钥引用（${env:DD_API_KEY}、${file:/run/secrets/token}），加载时解析，配置文件中不出现明文密钥
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrSecretNotFound 引用的密钥不存在
+	ErrSecretNotFound = errors.New("logger: secret not found")
+	// ErrUnknownSecretsProvider 引用使用了未注册的密钥来源
+	ErrUnknownSecretsProvider = errors.New("logger: unknown secrets provider")
+)
+
+// SecretsProvider 密钥来源（如 Vault、AWS Secrets Manager 的封装），name 为引用中冒号之后的部分
+type SecretsProvider interface {
+	Secret(ctx context.Context, name string) (string, error)
+}
+
+// SecretsProviderFunc 函数形式的密钥来源
+type SecretsProviderFunc func(ctx context.Context, name string) (string, error)
+
+// Secret 实现 SecretsProvider 接口
+func (f SecretsProviderFunc) Secret(ctx context.Context, name string) (string, error) {
+	return f(ctx, name)
+}
+
+var (
+	secretsMu        sync.RWMutex
+	secretsProviders = map[string]SecretsProvider{
+		"env":  SecretsProviderFunc(envSecret),
+		"file": SecretsProviderFunc(fileSecret),
+	}
+)
+
+// RegisterSecretsProvider 注册密钥来源（scheme 不区分大小写，重复注册时覆盖），内置 env 与 file：
+//
+//	logger.RegisterSecretsProvider("vault", vaultProvider)
+//	// params: {api_key: "${vault:secret/data/logging#datadog}"}
+func RegisterSecretsProvider(scheme string, provider SecretsProvider) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secretsProviders[strings.ToLower(scheme)] = provider
+}
+
+// envSecret 读取环境变量，未设置时返回 ErrSecretNotFound（设置为空字符串视为存在）
+func envSecret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: env %s", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// fileSecret 读取文件内容并去掉末尾换行（适用于 Docker / Kubernetes 挂载的密钥文件）
+func fileSecret(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: file %s", ErrSecretNotFound, name)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// ResolveSecret 展开字符串中的所有 ${scheme:name} 引用，$${ 表示字面量 ${
+// 不含引用的字符串原样返回；引用未闭合、来源未注册或密钥不存在时返回错误（错误中不包含密钥内容）
+func ResolveSecret(ctx context.Context, s string) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i])
+			b.WriteString("{")
+			s = s[i+2:]
+			continue
+		}
+		b.WriteString(s[:i])
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("logger: unterminated secret reference %q", s[i:])
+		}
+		ref := s[i+2 : i+end]
+		value, err := lookupSecret(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		s = s[i+end+1:]
+	}
+}
+
+// lookupSecret 解析单个引用 scheme:name
+func lookupSecret(ctx context.Context, ref string) (string, error) {
+	scheme, name, ok := strings.Cut(ref, ":")
+	if !ok || scheme == "" || name == "" {
+		return "", fmt.Errorf("logger: invalid secret reference ${%s}, want ${scheme:name}", ref)
+	}
+	secretsMu.RLock()
+	provider, ok := secretsProviders[strings.ToLower(scheme)]
+	secretsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownSecretsProvider, scheme)
+	}
+	value, err := provider.Secret(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("secret ${%s}: %w", ref, err)
+	}
+	return value, nil
+}
+
+// ResolveSecrets 展开 v（结构体、map 或切片的指针）中所有可设置的字符串里的密钥引用并写回，
+// map 与切片替换为展开后的副本，不修改与其他变量共享的底层数据，
+// 用于应用自定义的适配器配置结构，例如：
+//
+//	cfg := logger.S3Config{AccessKey: "${env:AWS_ACCESS_KEY_ID}", SecretKey: "${file:/run/secrets/s3}"}
+//	if err := logger.ResolveSecrets(ctx, &cfg); err != nil { ... }
+func ResolveSecrets(ctx context.Context, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("logger: ResolveSecrets needs a non-nil pointer, got %T", v)
+	}
+	return resolveValue(ctx, rv.Elem(), "")
+}
+
+// resolveValue 递归展开字符串，path 用于错误定位
+func resolveValue(ctx context.Context, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		resolved, err := ResolveSecret(ctx, v.String())
+		if err != nil {
+			return secretPathError(path, err)
+		}
+		v.SetString(resolved)
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			return resolveInterface(ctx, v, path)
+		}
+		return resolveValue(ctx, v.Elem(), path)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := resolveValue(ctx, v.Field(i), joinSecretPath(path, t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.CanSet() && !v.IsNil() {
+			v.Set(reflect.AppendSlice(reflect.MakeSlice(v.Type(), 0, v.Len()), v))
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveValue(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() || !v.CanSet() {
+			return nil
+		}
+		resolved := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := resolveValue(ctx, elem, joinSecretPath(path, fmt.Sprint(iter.Key().Interface()))); err != nil {
+				return err
+			}
+			resolved.SetMapIndex(iter.Key(), elem)
+		}
+		v.Set(resolved)
+	}
+	return nil
+}
+
+// resolveInterface 接口中保存的值不可寻址，复制一份展开后写回
+func resolveInterface(ctx context.Context, v reflect.Value, path string) error {
+	inner := v.Elem()
+	if !v.CanSet() || inner.Kind() == reflect.Pointer {
+		return resolveValue(ctx, inner, path)
+	}
+	copied := reflect.New(inner.Type()).Elem()
+	copied.Set(inner)
+	if err := resolveValue(ctx, copied, path); err != nil {
+		return err
+	}
+	v.Set(copied)
+	return nil
+}
+
+// joinSecretPath 拼接字段路径
+func joinSecretPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// secretPathError 为错误附加字段路径
+func secretPathError(path string, err error) error {
+	if path == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
+
+// ResolveSecrets 返回展开了密钥引用的参数副本（嵌套的 map 与列表同样展开），原参数不变
+func (p ComponentParams) ResolveSecrets(ctx context.Context) (ComponentParams, error) {
+	if len(p) == 0 {
+		return p, nil
+	}
+	resolved := p
+	if err := ResolveSecrets(ctx, &resolved); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
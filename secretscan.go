@@ -0,0 +1,184 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\secretscan.go
+ * @Description: 密钥泄露自检中间件（开发环境使用），检测疑似凭证并告警或拦截
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// ErrSecretDetected 严格模式下检测到疑似凭证时返回，该条日志不会输出
+var ErrSecretDetected = errors.New("possible secret detected in log entry")
+
+// SecretPattern 凭证特征
+type SecretPattern struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// SecretFinding 检测结果
+type SecretFinding struct {
+	Pattern  string // 命中的特征名
+	Location string // "message" 或字段名
+	Entry    *LogEntry
+}
+
+// DefaultSecretPatterns 默认凭证特征（AWS 密钥、JWT、私钥头、常见平台令牌）
+func DefaultSecretPatterns() []SecretPattern {
+	return []SecretPattern{
+		{Name: "aws_access_key_id", Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`)},
+		{Name: "aws_secret_access_key", Pattern: regexp.MustCompile(`(?i)aws.{0,20}(?:secret|key).{0,5}[:=]\s*["']?[0-9a-zA-Z/+]{40}\b`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+		{Name: "private_key", Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY-----`)},
+		{Name: "github_token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{Name: "slack_token", Pattern: regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+		{Name: "bearer_token", Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`)},
+	}
+}
+
+// SecretScanMiddleware 密钥泄露自检中间件
+// 默认模式下向告警输出打印醒目提示后照常输出日志，严格模式下拦截该条日志并返回 ErrSecretDetected
+type SecretScanMiddleware struct {
+	patterns []SecretPattern
+	strict   bool
+	priority int
+	output   io.Writer
+	onDetect func(SecretFinding)
+	mu       sync.Mutex
+	scratch  []byte
+}
+
+// SecretScanOption 密钥自检配置选项
+type SecretScanOption func(*SecretScanMiddleware)
+
+// WithSecretStrict 设置严格模式（检测到即拦截）
+func WithSecretStrict(strict bool) SecretScanOption {
+	return func(m *SecretScanMiddleware) {
+		m.strict = strict
+	}
+}
+
+// WithSecretPatterns 追加自定义凭证特征
+func WithSecretPatterns(patterns ...SecretPattern) SecretScanOption {
+	return func(m *SecretScanMiddleware) {
+		m.patterns = append(m.patterns, patterns...)
+	}
+}
+
+// WithSecretWarnOutput 设置告警输出（默认 os.Stderr）
+func WithSecretWarnOutput(output io.Writer) SecretScanOption {
+	return func(m *SecretScanMiddleware) {
+		m.output = output
+	}
+}
+
+// WithSecretOnDetect 设置检测回调（可用于测试中断言或上报）
+func WithSecretOnDetect(fn func(SecretFinding)) SecretScanOption {
+	return func(m *SecretScanMiddleware) {
+		m.onDetect = fn
+	}
+}
+
+// WithSecretPriority 设置中间件优先级（默认 1000，排在其他中间件之后以检查最终内容）
+func WithSecretPriority(priority int) SecretScanOption {
+	return func(m *SecretScanMiddleware) {
+		m.priority = priority
+	}
+}
+
+// NewSecretScanMiddleware 创建密钥自检中间件
+//
+//	log.WithMiddleware([]logger.IMiddleware{logger.NewSecretScanMiddleware(logger.WithSecretStrict(true))})
+func NewSecretScanMiddleware(opts ...SecretScanOption) *SecretScanMiddleware {
+	m := &SecretScanMiddleware{
+		patterns: DefaultSecretPatterns(),
+		priority: 1000,
+		output:   os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Process 实现 IMiddleware 接口
+func (m *SecretScanMiddleware) Process(entry *LogEntry, next func(*LogEntry) error) error {
+	finding, found := m.scan(entry)
+	if !found {
+		return next(entry)
+	}
+
+	if m.onDetect != nil {
+		m.onDetect(finding)
+	}
+	if m.strict {
+		m.warn(finding, "BLOCKED")
+		return fmt.Errorf("%w: %s in %s", ErrSecretDetected, finding.Pattern, finding.Location)
+	}
+	m.warn(finding, "WARNING")
+	return next(entry)
+}
+
+// scan 依次检查消息和字段值，返回第一个命中项
+func (m *SecretScanMiddleware) scan(entry *LogEntry) (SecretFinding, bool) {
+	if name, ok := m.match(convert.S2B(entry.Message)); ok {
+		return SecretFinding{Pattern: name, Location: "message", Entry: entry}, true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range entry.Fields {
+		m.scratch = convert.AppendValue(m.scratch[:0], v)
+		if name, ok := m.match(m.scratch); ok {
+			return SecretFinding{Pattern: name, Location: k, Entry: entry}, true
+		}
+	}
+	return SecretFinding{}, false
+}
+
+// match 匹配凭证特征
+func (m *SecretScanMiddleware) match(data []byte) (string, bool) {
+	for _, p := range m.patterns {
+		if p.Pattern.Match(data) {
+			return p.Name, true
+		}
+	}
+	return "", false
+}
+
+// warn 输出醒目告警（不包含疑似凭证原文）
+func (m *SecretScanMiddleware) warn(finding SecretFinding, action string) {
+	if m.output == nil {
+		return
+	}
+	caller := ""
+	if c := finding.Entry.Caller; c != nil {
+		caller = fmt.Sprintf(" at %s:%d", c.File, c.Line)
+	}
+	fmt.Fprintf(m.output, "\033[1;41m 🚨 SECRET SCAN %s \033[0m possible %s in %s of [%s] log entry%s\n",
+		action, finding.Pattern, finding.Location, finding.Entry.Level, caller)
+}
+
+// GetName 实现 IMiddleware 接口
+func (m *SecretScanMiddleware) GetName() string {
+	return "secret_scan"
+}
+
+// GetPriority 实现 IMiddleware 接口
+func (m *SecretScanMiddleware) GetPriority() int {
+	return m.priority
+}
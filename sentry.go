@@ -0,0 +1,367 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\sentry.go
+ * @Description: Sentry 钩子（基于 Envelope HTTP 接口，无需引入 SDK），将 ERROR/FATAL 日志上报聚合
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+const (
+	sentryClientName   = "go-logger/1.0"
+	sentryLoggerModule = "github.com/kamalyes/go-logger."
+	sentryMaxFrames    = 50
+)
+
+// SentryHook Sentry 上报钩子
+// 事件在 Fire 时构建（含调用栈），由后台协程异步发送，队列满时丢弃并计数
+type SentryHook struct {
+	endpoint    string
+	dsn         string
+	publicKey   string
+	levels      []LogLevel
+	release     string
+	environment string
+	serverName  string
+	tagKeys     map[string]struct{}
+	client      *http.Client
+	queueSize   int
+	sender      *hookSender
+}
+
+// SentryHookOption Sentry 钩子配置选项
+type SentryHookOption func(*SentryHook)
+
+// WithSentryLevels 设置上报级别（默认 ERROR、FATAL）
+func WithSentryLevels(levels ...LogLevel) SentryHookOption {
+	return func(h *SentryHook) {
+		h.levels = levels
+	}
+}
+
+// WithSentryRelease 设置版本号
+func WithSentryRelease(release string) SentryHookOption {
+	return func(h *SentryHook) {
+		h.release = release
+	}
+}
+
+// WithSentryEnvironment 设置环境名（production、staging 等）
+func WithSentryEnvironment(environment string) SentryHookOption {
+	return func(h *SentryHook) {
+		h.environment = environment
+	}
+}
+
+// WithSentryServerName 设置服务器名（默认主机名）
+func WithSentryServerName(name string) SentryHookOption {
+	return func(h *SentryHook) {
+		h.serverName = name
+	}
+}
+
+// WithSentryTagKeys 设置作为标签上报的字段名（可在 Sentry 中检索），其余字段作为 extra 上报
+func WithSentryTagKeys(keys ...string) SentryHookOption {
+	return func(h *SentryHook) {
+		for _, key := range keys {
+			h.tagKeys[key] = struct{}{}
+		}
+	}
+}
+
+// WithSentryHTTPClient 设置 HTTP 客户端
+func WithSentryHTTPClient(client *http.Client) SentryHookOption {
+	return func(h *SentryHook) {
+		h.client = client
+	}
+}
+
+// WithSentryQueueSize 设置发送队列长度（默认 256）
+func WithSentryQueueSize(size int) SentryHookOption {
+	return func(h *SentryHook) {
+		if size > 0 {
+			h.queueSize = size
+		}
+	}
+}
+
+// NewSentryHook 创建 Sentry 钩子，dsn 格式为 https://<key>@<host>/<project>
+func NewSentryHook(dsn string, opts ...SentryHookOption) (*SentryHook, error) {
+	endpoint, publicKey, err := parseSentryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	serverName, _ := os.Hostname()
+	h := &SentryHook{
+		endpoint:   endpoint,
+		dsn:        dsn,
+		publicKey:  publicKey,
+		levels:     []LogLevel{ERROR, FATAL},
+		serverName: serverName,
+		tagKeys:    make(map[string]struct{}),
+		client:     newHTTPClient(5 * time.Second),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.sender = newHookSender(h.client, h.queueSize)
+	return h, nil
+}
+
+// parseSentryDSN 解析 DSN，返回 Envelope 接口地址与公钥
+func parseSentryDSN(dsn string) (string, string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid sentry dsn: missing public key")
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 || idx == len(path)-1 {
+		return "", "", fmt.Errorf("invalid sentry dsn: missing project id")
+	}
+	prefix, project := path[:idx], path[idx+1:]
+	endpoint := fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, prefix, project)
+	return endpoint, u.User.Username(), nil
+}
+
+// sentryEvent Sentry 事件
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Platform    string            `json:"platform"`
+	Message     sentryMessage     `json:"message"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+	Exception   *sentryExceptions `json:"exception,omitempty"`
+}
+
+type sentryMessage struct {
+	Formatted string `json:"formatted"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// Fire 实现 IHook 接口（构建事件后入队，不阻塞日志调用）
+func (h *SentryHook) Fire(entry *LogEntry) error {
+	event := h.buildEvent(entry)
+	payload, err := h.envelope(event)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-sentry-envelope")
+	header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClientName, h.publicKey))
+	return h.sender.enqueue(hookRequest{url: h.endpoint, header: header, body: payload})
+}
+
+// Levels 实现 IHook 接口
+func (h *SentryHook) Levels() []LogLevel {
+	return h.levels
+}
+
+// buildEvent 由日志条目构建事件：标签字段转为 tags，其余字段转为 extra，error 字段作为异常值
+func (h *SentryHook) buildEvent(entry *LogEntry) *sentryEvent {
+	event := &sentryEvent{
+		EventID:     newEventID(),
+		Timestamp:   time.Unix(0, entry.Timestamp).UTC().Format(time.RFC3339Nano),
+		Level:       sentryLevel(entry.Level),
+		Logger:      "go-logger",
+		Platform:    "go",
+		Message:     sentryMessage{Formatted: entry.Message},
+		Fingerprint: []string{entryFingerprint(entry)},
+		Release:     h.release,
+		Environment: h.environment,
+		ServerName:  h.serverName,
+	}
+
+	errValue := entry.Message
+	for k, v := range entry.Fields {
+		if k == "error" {
+			errValue = string(convert.AppendValue(nil, v))
+		}
+		if _, ok := h.tagKeys[k]; ok {
+			if event.Tags == nil {
+				event.Tags = make(map[string]string)
+			}
+			event.Tags[k] = string(convert.AppendValue(nil, v))
+			continue
+		}
+		if event.Extra == nil {
+			event.Extra = make(map[string]any, len(entry.Fields))
+		}
+		event.Extra[k] = sentryExtraValue(v)
+	}
+
+	event.Exception = &sentryExceptions{Values: []sentryException{{
+		Type:       entry.Level.String(),
+		Value:      errValue,
+		Stacktrace: captureSentryStack(),
+	}}}
+	return event
+}
+
+// sentryExtraValue 将 error 和 Stringer 转为字符串（二者直接序列化通常为空对象）
+func sentryExtraValue(v any) any {
+	switch val := v.(type) {
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	}
+	return v
+}
+
+// captureSentryStack 捕获调用栈（跳过日志库内部帧，按 Sentry 约定由外到内排列）
+func captureSentryStack() *sentryStacktrace {
+	pcs := make([]uintptr, sentryMaxFrames)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var result []sentryFrame
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, sentryLoggerModule) && frame.Function != "" {
+			module, function := splitFuncName(frame.Function)
+			result = append(result, sentryFrame{
+				Function: function,
+				Module:   module,
+				Filename: shortFileName(frame.File),
+				AbsPath:  frame.File,
+				Lineno:   frame.Line,
+				InApp:    !strings.HasPrefix(frame.Function, "runtime.") && !strings.Contains(frame.File, "/pkg/mod/"),
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return &sentryStacktrace{Frames: result}
+}
+
+// splitFuncName 拆分 "pkg/path.Func" 为包路径和函数名
+func splitFuncName(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		idx := slash + 1 + dot
+		return name[:idx], name[idx+1:]
+	}
+	return "", name
+}
+
+// shortFileName 截取文件名
+func shortFileName(file string) string {
+	if idx := strings.LastIndex(file, "/"); idx != -1 {
+		return file[idx+1:]
+	}
+	return file
+}
+
+// sentryLevel 日志级别映射为 Sentry 级别（扩展级别映射为 info）
+func sentryLevel(level LogLevel) string {
+	switch level {
+	case TRACE, DEBUG:
+		return "debug"
+	case WARN:
+		return "warning"
+	case ERROR:
+		return "error"
+	case FATAL:
+		return "fatal"
+	default:
+		return "info"
+	}
+}
+
+// newEventID 生成 32 位十六进制事件 ID
+func newEventID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// envelope 编码为 Envelope 格式（头部、条目头、事件各占一行）
+func (h *SentryHook) envelope(event *sentryEvent) ([]byte, error) {
+	body, err := marshalJSON(event)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"event_id":%q,"sent_at":%q,"dsn":%q}`+"\n", event.EventID, time.Now().UTC().Format(time.RFC3339Nano), h.dsn)
+	fmt.Fprintf(&buf, `{"type":"event","length":%d}`+"\n", len(body))
+	buf.Write(body)
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// Flush 等待已入队事件发送完成
+func (h *SentryHook) Flush(ctx context.Context) error {
+	return h.sender.flush(ctx)
+}
+
+// Close 停止接收新事件并等待已入队事件发送完成
+func (h *SentryHook) Close(ctx context.Context) error {
+	return h.sender.close(ctx)
+}
+
+// Stats 返回发送失败与因队列满丢弃的事件数
+func (h *SentryHook) Stats() (failed, dropped int64) {
+	return h.sender.stats()
+}
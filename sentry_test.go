@@ -0,0 +1,58 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\sentry_test.go
+ * @Description: Sentry 钩子测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+// TestSentryLevel 基础级别一一映射，扩展级别（业务、安全、性能等）不得映射为 fatal
+func TestSentryLevel(t *testing.T) {
+	tests := []struct {
+		level LogLevel
+		want  string
+	}{
+		{TRACE, "debug"},
+		{DEBUG, "debug"},
+		{INFO, "info"},
+		{WARN, "warning"},
+		{ERROR, "error"},
+		{FATAL, "fatal"},
+		{SERVICE, "info"},
+		{BUSINESS, "info"},
+		{PROCESS, "info"},
+		{SECURITY, "info"},
+		{AUDIT, "info"},
+		{PERFORMANCE, "info"},
+		{BENCHMARK, "info"},
+	}
+	for _, tt := range tests {
+		if got := sentryLevel(tt.level); got != tt.want {
+			t.Errorf("sentryLevel(%v) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+// TestSentryEventLevel 上报的事件使用映射后的级别
+func TestSentryEventLevel(t *testing.T) {
+	h, err := NewSentryHook("https://key@sentry.example.com/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close(context.Background())
+	for level, want := range map[LogLevel]string{ERROR: "error", PERFORMANCE: "info", AUDIT: "info"} {
+		event := h.buildEvent(&LogEntry{Level: level, Message: "m", Fields: map[string]any{"k": "v"}})
+		if event.Level != want {
+			t.Errorf("event level for %v = %q, want %q", level, event.Level, want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\shutdown.go
+ * @Description: 关闭流程：注册退出前的刷新与关闭函数，收到 SIGTERM / SIGINT 时写出剩余日志后退出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout 收到信号后关闭流程的默认超时时间
+const DefaultShutdownTimeout = 5 * time.Second
+
+// processStart 进程启动时间（用于关闭日志中的 uptime）
+var processStart = time.Now()
+
+// ShutdownFunc 关闭时执行的清理函数（刷新钩子、关闭适配器等）
+type ShutdownFunc func(ctx context.Context) error
+
+var (
+	shutdownMu    sync.Mutex
+	shutdownFuncs []ShutdownFunc
+)
+
+// OnShutdown 注册关闭时执行的清理函数，Shutdown 按注册的逆序执行：
+//
+//	logger.OnShutdown(sentryHook.Flush)
+func OnShutdown(fn ShutdownFunc) {
+	if fn == nil {
+		return
+	}
+	shutdownMu.Lock()
+	shutdownFuncs = append(shutdownFuncs, fn)
+	shutdownMu.Unlock()
+}
+
+// CloseOnShutdown 关闭时依次刷新并关闭适配器
+func CloseOnShutdown(adapters ...IAdapter) {
+	for _, adapter := range adapters {
+		OnShutdown(func(context.Context) error {
+			return errors.Join(adapter.Flush(), adapter.Close())
+		})
+	}
+}
+
+// Shutdown 按注册的逆序执行清理函数，然后刷新并关闭全局 Logger，返回所有错误
+// 每个清理函数只执行一次；ctx 结束后剩余的清理函数不再执行
+func Shutdown(ctx context.Context) error {
+	shutdownMu.Lock()
+	funcs := shutdownFuncs
+	shutdownFuncs = nil
+	shutdownMu.Unlock()
+
+	var errs []error
+	for i := len(funcs) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := funcs[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	l := globalLogger()
+	errs = append(errs, l.Flush(), l.Close())
+	return errors.Join(errs...)
+}
+
+// signalHandler 信号处理配置
+type signalHandler struct {
+	signals []os.Signal
+	logger  *Logger
+	timeout time.Duration
+	entry   bool
+	exit    bool
+	onStop  func(sig os.Signal)
+}
+
+// SignalOption 信号处理配置选项
+type SignalOption func(*signalHandler)
+
+// WithShutdownSignals 设置监听的信号（默认 SIGTERM、SIGINT）
+func WithShutdownSignals(signals ...os.Signal) SignalOption {
+	return func(h *signalHandler) {
+		if len(signals) > 0 {
+			h.signals = signals
+		}
+	}
+}
+
+// WithShutdownLogger 设置写出关闭日志的 Logger（默认全局 Logger）
+func WithShutdownLogger(l *Logger) SignalOption {
+	return func(h *signalHandler) {
+		if l != nil {
+			h.logger = l
+		}
+	}
+}
+
+// WithShutdownTimeout 设置关闭流程的超时时间（默认 DefaultShutdownTimeout）
+func WithShutdownTimeout(timeout time.Duration) SignalOption {
+	return func(h *signalHandler) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// WithShutdownEntry 是否在关闭前写出一条包含信号与运行时长的日志（默认开启）
+func WithShutdownEntry(enabled bool) SignalOption {
+	return func(h *signalHandler) {
+		h.entry = enabled
+	}
+}
+
+// WithShutdownExit 关闭流程结束后是否退出进程（默认开启，退出码为 128 + 信号值）
+// 关闭后由应用自行决定退出时机，可配合 WithShutdownCallback 使用
+func WithShutdownExit(exit bool) SignalOption {
+	return func(h *signalHandler) {
+		h.exit = exit
+	}
+}
+
+// WithShutdownCallback 设置关闭流程结束后（退出前）的回调
+func WithShutdownCallback(fn func(sig os.Signal)) SignalOption {
+	return func(h *signalHandler) {
+		h.onStop = fn
+	}
+}
+
+// HandleSignals 安装信号处理：收到 SIGTERM / SIGINT 时写出关闭日志（signal、uptime），
+// 执行 Shutdown 刷新并关闭已注册的适配器与全局 Logger，然后退出进程；返回卸载信号处理的函数：
+//
+//	stop := logger.HandleSignals(logger.WithShutdownTimeout(10 * time.Second))
+//	defer stop()
+func HandleSignals(opts ...SignalOption) (stop func()) {
+	h := &signalHandler{
+		signals: []os.Signal{syscall.SIGTERM, syscall.SIGINT},
+		logger:  globalLogger(),
+		timeout: DefaultShutdownTimeout,
+		entry:   true,
+		exit:    true,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(ch, h.signals...)
+	go func() {
+		select {
+		case sig := <-ch:
+			h.handle(sig)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+// handle 执行关闭流程
+func (h *signalHandler) handle(sig os.Signal) {
+	if h.entry {
+		h.logger.InfoKV("shutting down", "signal", sig.String(), "uptime", time.Since(processStart))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	err := Shutdown(ctx)
+	cancel()
+	if h.logger != globalLogger() {
+		err = errors.Join(err, h.logger.Flush(), h.logger.Close())
+	}
+	if err != nil {
+		reportInternalError("shutdown", err)
+	}
+
+	if h.onStop != nil {
+		h.onStop(sig)
+	}
+	if h.exit {
+		code := 1
+		if s, ok := sig.(syscall.Signal); ok {
+			code = 128 + int(s)
+		}
+		os.Exit(code)
+	}
+}
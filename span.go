@@ -0,0 +1,66 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\span.go
+ * @Description: 作用域计时：开始时记录时间，结束时输出一条包含耗时、结果与错误的日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"time"
+)
+
+// 作用域计时输出的字段名与结果取值
+const (
+	DurationKey   = "duration"    // 耗时（可读格式）
+	DurationMsKey = "duration_ms" // 耗时（毫秒，浮点数）
+	OutcomeKey    = "outcome"     // 结果
+	ErrorKey      = "error"       // 错误信息
+
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Begin 开始一个计时作用域，返回的 done 在结束时调用：
+//
+//	done := log.Begin("load users", "tenant", id)
+//	users, err := loadUsers()
+//	done(err)
+//
+// done 输出一条以 op 为消息的日志，附带 duration、duration_ms、outcome 与 error 字段；
+// err 为 nil 时为 INFO 级别，否则为 ERROR 级别。done 只在首次调用时输出
+func (l *Logger) Begin(op string, keysAndValues ...any) (done func(err error)) {
+	start := time.Now()
+	finished := false
+	return func(err error) {
+		if finished {
+			return
+		}
+		finished = true
+
+		elapsed := time.Since(start)
+		level, outcome := INFO, OutcomeOK
+		if err != nil {
+			level, outcome = ERROR, OutcomeError
+		}
+		if level < l.level {
+			return
+		}
+
+		kv := make([]any, 0, len(keysAndValues)+8)
+		kv = append(kv, keysAndValues...)
+		kv = append(kv,
+			DurationKey, elapsed.String(),
+			DurationMsKey, float64(elapsed)/float64(time.Millisecond),
+			OutcomeKey, outcome,
+		)
+		if err != nil {
+			kv = append(kv, ErrorKey, err.Error())
+		}
+		l.logWithKV(level, op, kv...)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\spanrecord.go
+ * @Description: 错误日志同步记录到追踪 span：ERROR / FATAL 条目连同日志调用的 context 交给 SpanRecorder
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "context"
+
+// SpanRecorder 将 ERROR / FATAL 条目记录到 ctx 中活跃的 span（如添加 exception 事件并设置 span 状态），
+// OpenTelemetry 实现见 otellog.RecordErrors；ctx 为 *Context 方法传入的 context 或 WithContext 绑定的 context
+type SpanRecorder func(ctx context.Context, entry *LogEntry)
+
+// WithSpanRecorder 设置 span 记录器（为 nil 时关闭）
+// 仅 *Context 方法与 WithContext / FromContext 派生的 Logger 的错误日志会被记录
+func (l *Logger) WithSpanRecorder(recorder SpanRecorder) *Logger {
+	l.spanRecorder = recorder
+	return l
+}
+
+// recordsSpan 该级别的日志是否交给 span 记录器
+func recordsSpan(level LogLevel) bool {
+	return level == ERROR || level == FATAL
+}
+
+// spanScope 配置了 span 记录器且 ctx 不同于已绑定的 context 时，返回携带 ctx 的子 Logger；
+// ctx 只用于传递给 span 记录器，不影响上下文字段提取
+func (l *Logger) spanScope(ctx context.Context, level LogLevel) *Logger {
+	if l.spanRecorder == nil || !recordsSpan(level) || ctx == nil || ctx == l.context {
+		return l
+	}
+	child := l.Clone().(*Logger)
+	child.spanCtx = ctx
+	return child
+}
+
+// recordSpan 将条目交给 span 记录器
+func (l *Logger) recordSpan(entry *LogEntry) {
+	if l.spanRecorder == nil || !recordsSpan(entry.Level) {
+		return
+	}
+	ctx := l.spanCtx
+	if ctx == nil {
+		ctx = l.context
+	}
+	if ctx != nil {
+		l.spanRecorder(ctx, entry)
+	}
+}
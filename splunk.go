@@ -0,0 +1,228 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\splunk.go
+ * @Description: Splunk HTTP Event Collector（HEC）适配器
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultSplunkSourceType = "_json"
+	DefaultSplunkBatchSize  = 200
+	splunkEventPath         = "/services/collector/event"
+)
+
+// SplunkAdapter Splunk HEC 适配器（POST <url>/services/collector/event，Authorization: Splunk <token>）
+//
+//	hec := logger.NewSplunkAdapter(logger.WithSplunkURL("https://splunk:8088"), logger.WithSplunkToken(token), logger.WithSplunkIndex("app"))
+//	log := logger.NewLogger().WithAdapters(hec)
+//
+// 每条日志编码为一个 HEC 事件（time、host、source、sourcetype、index、event），一批事件依次拼接后发送；
+// token 默认读取环境变量 SPLUNK_HEC_TOKEN
+type SplunkAdapter struct {
+	*BaseAdapter
+	cfg        httpBatchConfig
+	url        string
+	token      string
+	index      string
+	source     string
+	sourceType string
+	host       string
+	sender     *httpBatchSender
+}
+
+// SplunkOption Splunk 适配器配置选项
+type SplunkOption func(*SplunkAdapter)
+
+// WithSplunkURL 设置 HEC 地址（只有主机部分时自动补全 /services/collector/event）
+func WithSplunkURL(rawURL string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		if u, err := url.Parse(rawURL); err == nil && (u.Path == "" || u.Path == "/") {
+			u.Path = splunkEventPath
+			rawURL = u.String()
+		}
+		a.url = rawURL
+	}
+}
+
+// WithSplunkToken 设置 HEC token
+func WithSplunkToken(token string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.token = token
+	}
+}
+
+// WithSplunkIndex 设置 index（为空时使用 token 的默认 index）
+func WithSplunkIndex(index string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.index = index
+	}
+}
+
+// WithSplunkSource 设置 source
+func WithSplunkSource(source string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.source = source
+	}
+}
+
+// WithSplunkSourceType 设置 sourcetype（默认 DefaultSplunkSourceType）
+func WithSplunkSourceType(sourceType string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.sourceType = sourceType
+	}
+}
+
+// WithSplunkHost 设置 host（默认主机名）
+func WithSplunkHost(host string) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.host = host
+	}
+}
+
+// WithSplunkBatch 设置每批最大事件数与刷新间隔
+func WithSplunkBatch(size int, interval time.Duration) SplunkOption {
+	return func(a *SplunkAdapter) {
+		if size > 0 {
+			a.cfg.batchSize = size
+		}
+		if interval > 0 {
+			a.cfg.flushInterval = interval
+		}
+	}
+}
+
+// WithSplunkHTTPClient 设置 HTTP 客户端（自签名证书等 TLS 配置也可通过 WithSplunkTLS 设置）
+func WithSplunkHTTPClient(client *http.Client) SplunkOption {
+	return func(a *SplunkAdapter) {
+		if client != nil {
+			a.cfg.client = client
+		}
+	}
+}
+
+// WithSplunkTLS 设置 TLS 配置（CA 证书、客户端证书等，见 TLSConfig.Build），应用到 HTTP 客户端的 Transport
+func WithSplunkTLS(conf *tls.Config) SplunkOption {
+	return func(a *SplunkAdapter) {
+		a.cfg.tls = conf
+	}
+}
+
+// WithSplunkQueueSize 设置发送队列长度（默认 DefaultHTTPBatchQueueSize）
+func WithSplunkQueueSize(size int) SplunkOption {
+	return func(a *SplunkAdapter) {
+		if size > 0 {
+			a.cfg.queueSize = size
+		}
+	}
+}
+
+// WithSplunkRetries 设置每批失败重试次数（默认 DefaultHTTPBatchRetries）
+func WithSplunkRetries(retries int) SplunkOption {
+	return func(a *SplunkAdapter) {
+		if retries >= 0 {
+			a.cfg.retries = retries
+		}
+	}
+}
+
+// NewSplunkAdapter 创建 Splunk HEC 适配器并启动发送协程
+func NewSplunkAdapter(opts ...SplunkOption) *SplunkAdapter {
+	a := &SplunkAdapter{
+		cfg:        defaultHTTPBatchConfig(DefaultSplunkBatchSize),
+		token:      os.Getenv("SPLUNK_HEC_TOKEN"),
+		sourceType: DefaultSplunkSourceType,
+	}
+	a.host, _ = os.Hostname()
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.cfg.resolveClient()
+
+	a.BaseAdapter = NewBaseAdapter("splunk", "1.0.0", a.emit)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Authorization", "Splunk "+a.token)
+	a.sender = newHTTPBatchSender(a.cfg, httpBatchSpec{
+		component: OverflowComponentSplunk,
+		url:       a.url,
+		header:    header,
+		encode:    a.encode,
+		onHealth:  a.SetHealthy,
+	})
+	return a
+}
+
+// emit 日志入队
+func (a *SplunkAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	a.sender.enqueue(httpBatchRecord{time: time.Now(), level: level, message: msg, fields: fields})
+}
+
+// encode 编码为依次拼接的 HEC 事件
+func (a *SplunkAdapter) encode(batch []httpBatchRecord) ([]byte, error) {
+	var buf []byte
+	for _, r := range batch {
+		event := make(map[string]any, len(r.fields)+2)
+		for k, v := range r.fields {
+			event[k] = v
+		}
+		event["level"] = r.level.String()
+		event["message"] = r.message
+
+		envelope := map[string]any{
+			"time":       float64(r.time.UnixMilli()) / 1000,
+			"host":       a.host,
+			"sourcetype": a.sourceType,
+			"event":      event,
+		}
+		if a.source != "" {
+			envelope["source"] = a.source
+		}
+		if a.index != "" {
+			envelope["index"] = a.index
+		}
+		data, err := marshalJSON(envelope)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	return buf, nil
+}
+
+// Flush 立即发送队列中的事件并等待结果
+func (a *SplunkAdapter) Flush() error {
+	return a.sender.flush()
+}
+
+// Close 发送剩余事件后关闭
+func (a *SplunkAdapter) Close() error {
+	a.sender.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *SplunkAdapter) QueueDepth() (depth, capacity int) {
+	return a.sender.queueDepth()
+}
+
+// Stats 返回发送统计
+func (a *SplunkAdapter) Stats() HTTPBatchStats {
+	return a.sender.stats()
+}
+
+var _ IAdapter = (*SplunkAdapter)(nil)
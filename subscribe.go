@@ -0,0 +1,129 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\subscribe.go
+ * @Description: 进程内日志订阅：应用代码（管理端 websocket、实时日志查看、异常检测）直接消费实时日志流
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSubscribeBuffer 订阅通道默认缓冲长度
+const DefaultSubscribeBuffer = 256
+
+// subscription 单个订阅
+type subscription struct {
+	criteria QueryCriteria
+	ch       chan Entry
+}
+
+// subscriberHub 日志订阅者（Logger 及其派生 Logger 共享）
+type subscriberHub struct {
+	mu     sync.RWMutex
+	subs   map[*subscription]struct{}
+	active atomic.Int32
+}
+
+// ensureSubscribers 创建订阅者注册表（派生 Logger 共享同一注册表）
+func (l *Logger) ensureSubscribers() *subscriberHub {
+	if l.subs == nil {
+		l.subs = &subscriberHub{subs: make(map[*subscription]struct{})}
+	}
+	return l.subs
+}
+
+// hasSubscribers 是否存在订阅者（无订阅者时日志仍走快速路径）
+func (h *subscriberHub) hasSubscribers() bool {
+	return h != nil && h.active.Load() > 0
+}
+
+// SubscribeOption 订阅配置选项
+type SubscribeOption func(*subscription)
+
+// WithSubscribeBuffer 设置订阅通道缓冲长度（默认 DefaultSubscribeBuffer）
+func WithSubscribeBuffer(size int) SubscribeOption {
+	return func(s *subscription) {
+		if size > 0 {
+			s.ch = make(chan Entry, size)
+		}
+	}
+}
+
+// subscribe 添加订阅，返回只读通道与取消函数
+func (h *subscriberHub) subscribe(criteria QueryCriteria, opts ...SubscribeOption) (<-chan Entry, func()) {
+	s := &subscription{criteria: criteria}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.ch == nil {
+		s.ch = make(chan Entry, DefaultSubscribeBuffer)
+	}
+
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	h.active.Add(1)
+	h.mu.Unlock()
+
+	var once sync.Once
+	return s.ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, s)
+			h.active.Add(-1)
+			close(s.ch)
+			h.mu.Unlock()
+		})
+	}
+}
+
+// publish 将条目分发给匹配的订阅者，通道已满时丢弃（不阻塞日志调用方）
+func (h *subscriberHub) publish(entry *LogEntry) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.subs) == 0 {
+		return
+	}
+
+	// 条目在写出后归还对象池，需复制字段
+	fields := make(map[string]any, len(entry.Fields)+1)
+	for k, v := range entry.Fields {
+		fields[k] = v
+	}
+	if entry.Caller != nil {
+		fields["caller"] = entry.Caller.File + ":" + strconv.Itoa(entry.Caller.Line) + ":" + entry.Caller.Function
+	}
+	e := Entry{Level: entry.Level, Message: entry.Message, Time: time.Unix(0, entry.Timestamp), Fields: fields}
+
+	for s := range h.subs {
+		if !s.criteria.Match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			reportOverflow(OverflowComponentSubscriber, OverflowDropped, cap(s.ch))
+		}
+	}
+}
+
+// Subscribe 订阅根 Logger 及其派生 Logger（租户、命名、包 Logger）的实时日志，
+// 返回满足 filter 的条目通道与取消函数（取消后通道关闭）；filter 的 Limit 不生效。
+// 订阅者消费过慢导致通道已满时丢弃条目并上报溢出事件：
+//
+//	entries, cancel := logger.DefaultManager().Subscribe(logger.QueryCriteria{Level: logger.WARN})
+//	defer cancel()
+//	for e := range entries {
+//		ws.WriteJSON(e)
+//	}
+func (m *LoggerManager) Subscribe(filter QueryCriteria, opts ...SubscribeOption) (<-chan Entry, func()) {
+	return m.root.subs.subscribe(filter, opts...)
+}
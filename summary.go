@@ -0,0 +1,117 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\summary.go
+ * @Description: 超长字段值自动摘要（类型、长度、开头若干字节与 SHA256），替代原始大载荷
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// DefaultSummaryHeadSize 摘要默认保留的开头字节数
+const DefaultSummaryHeadSize = 64
+
+// FieldSummary 超长字段值的摘要
+type FieldSummary struct {
+	Type   string `json:"type"`   // 原始值类型
+	Length int    `json:"length"` // 原始值编码后的字节数
+	Head   string `json:"head"`   // 开头若干字节
+	SHA256 string `json:"sha256"` // 完整内容的 SHA256（十六进制）
+}
+
+// String 实现 fmt.Stringer，文本输出形如 <string len=70000 sha256=ab12… head="...">
+func (s FieldSummary) String() string {
+	buf := make([]byte, 0, len(s.Head)+len(s.SHA256)+48)
+	buf = append(buf, '<')
+	buf = append(buf, s.Type...)
+	buf = append(buf, " len="...)
+	buf = strconv.AppendInt(buf, int64(s.Length), 10)
+	buf = append(buf, " sha256="...)
+	buf = append(buf, s.SHA256...)
+	buf = append(buf, " head="...)
+	buf = strconv.AppendQuote(buf, s.Head)
+	return string(append(buf, '>'))
+}
+
+// FullValue 强制完整输出的字段值，不参与自动摘要（仍受字段长度限制）
+type FullValue struct {
+	Value any
+}
+
+// Full 标记本次调用的字段值完整输出：log.InfoKV("resp", "body", logger.Full(body))
+func Full(value any) FullValue {
+	return FullValue{Value: value}
+}
+
+// String 实现 fmt.Stringer
+func (f FullValue) String() string {
+	return fmt.Sprint(f.Value)
+}
+
+// fieldSummarizer 字段摘要配置
+type fieldSummarizer struct {
+	threshold int
+	head      int
+}
+
+// WithFieldSummary 字段值编码后超过 threshold 字节时替换为摘要（FieldSummary），head 为摘要保留的开头字节数
+// threshold <= 0 表示关闭；head <= 0 时使用 DefaultSummaryHeadSize。单次调用可用 Full 强制完整输出
+func (l *Logger) WithFieldSummary(threshold, head int) *Logger {
+	if threshold <= 0 {
+		l.summarizer = nil
+		return l
+	}
+	if head <= 0 {
+		head = DefaultSummaryHeadSize
+	}
+	l.summarizer = &fieldSummarizer{threshold: threshold, head: min(head, threshold)}
+	return l
+}
+
+// summarize 超过阈值时返回摘要，否则原样返回
+func (s *fieldSummarizer) summarize(value any) any {
+	var raw []byte
+	switch v := value.(type) {
+	case string:
+		if len(v) <= s.threshold {
+			return value
+		}
+		raw = convert.S2B(v)
+	case []byte:
+		if len(v) <= s.threshold {
+			return value
+		}
+		raw = v
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	default:
+		raw = convert.AppendValue(nil, value)
+		if len(raw) <= s.threshold {
+			return value
+		}
+	}
+
+	head := s.head
+	for head > 0 && head < len(raw) && !utf8.RuneStart(raw[head]) {
+		head--
+	}
+	sum := sha256.Sum256(raw)
+	return FieldSummary{
+		Type:   fmt.Sprintf("%T", value),
+		Length: len(raw),
+		Head:   string(raw[:head]),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+}
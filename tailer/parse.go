@@ -0,0 +1,193 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tailer\parse.go
+ * @Description: 将 go-logger 的文本输出与 JSON 输出解析回日志条目
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package tailer
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+	"github.com/kamalyes/go-logger/ndjson"
+)
+
+// CallerKey 解析出的调用者信息在 Fields 中的键名
+const CallerKey = ndjson.CallerKey
+
+// timeLayout 文本输出的时间格式（月、日、时不补零）
+const timeLayout = "2006/1/2 15:04:05"
+
+var (
+	// ansiPattern 彩色输出的转义序列
+	ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+	// levelTokens 文本输出中的级别标记
+	levelTokens = map[string]logger.LogLevel{
+		"[DEBUG]": logger.DEBUG,
+		"[INFO]":  logger.INFO,
+		"[WARN]":  logger.WARN,
+		"[ERROR]": logger.ERROR,
+		"[FATAL]": logger.FATAL,
+	}
+)
+
+// Parse 解析一行日志，无法识别为日志首行时返回 false（如多行日志的后续行）
+func Parse(line []byte) (logger.Entry, bool) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) > 0 && line[0] == '{' {
+		return parseJSON(line)
+	}
+	return parseText(string(line))
+}
+
+// parseJSON 解析 LogEntry 格式的 JSON 行
+func parseJSON(line []byte) (logger.Entry, bool) {
+	entry, err := ndjson.Unmarshal(line)
+	return entry, err == nil
+}
+
+// parseText 解析 "时间 [前缀]级别图标 [LEVEL] [file:line:Func] 消息 {k: v}" 格式的文本行
+func parseText(line string) (logger.Entry, bool) {
+	line = ansiPattern.ReplaceAllString(line, "")
+
+	// 时间占前两段
+	first := strings.IndexByte(line, ' ')
+	if first < 0 {
+		return logger.Entry{}, false
+	}
+	second := strings.IndexByte(line[first+1:], ' ')
+	if second < 0 {
+		return logger.Entry{}, false
+	}
+	second += first + 1
+	t, err := time.ParseInLocation(timeLayout, line[:second], time.Local)
+	if err != nil {
+		return logger.Entry{}, false
+	}
+
+	// 定位级别标记（取最靠前的一个，消息中可能出现同样的文本）
+	var entry logger.Entry
+	pos, size := -1, 0
+	for token, level := range levelTokens {
+		if idx := strings.Index(line[second:], token); idx >= 0 && (pos < 0 || idx < pos) {
+			pos, size = idx, len(token)
+			entry.Level = level
+		}
+	}
+	if pos < 0 {
+		return logger.Entry{}, false
+	}
+	rest := strings.TrimPrefix(line[second+pos+size:], " ")
+	entry.Time = t
+
+	// 调用者信息
+	if strings.HasPrefix(rest, "[") {
+		if end := strings.Index(rest, "] "); end > 0 && strings.Count(rest[1:end], ":") >= 2 {
+			entry.Fields = map[string]any{CallerKey: rest[1:end]}
+			rest = rest[end+2:]
+		}
+	}
+
+	msg, fields := splitFieldBlock(rest)
+	entry.Message = msg
+	for k, v := range fields {
+		if entry.Fields == nil {
+			entry.Fields = make(map[string]any, len(fields))
+		}
+		entry.Fields[k] = v
+	}
+	return entry, true
+}
+
+// splitFieldBlock 拆分消息与末尾的 " {k: v, k2: v2}" 字段块
+func splitFieldBlock(s string) (string, map[string]any) {
+	if !strings.HasSuffix(s, "}") {
+		return s, nil
+	}
+
+	// 从末尾向前匹配成对的花括号
+	depth := 0
+	for i := len(s) - 1; i >= 0; i-- {
+		switch s[i] {
+		case '}':
+			depth++
+		case '{':
+			depth--
+			if depth == 0 {
+				if i == 0 || s[i-1] != ' ' {
+					return s, nil
+				}
+				return s[:i-1], parseFields(s[i+1 : len(s)-1])
+			}
+		}
+	}
+	return s, nil
+}
+
+// parseFields 解析 "k: v, k2: v2"（忽略嵌套括号内的分隔符）
+func parseFields(s string) map[string]any {
+	fields := make(map[string]any)
+	depth, start := 0, 0
+	flush := func(part string) {
+		if k, v, ok := strings.Cut(part, ": "); ok {
+			fields[k] = v
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		case ',':
+			if depth == 0 && i+1 < len(s) && s[i+1] == ' ' {
+				flush(s[start:i])
+				start = i + 2
+				i++
+			}
+		}
+	}
+	flush(s[start:])
+	return fields
+}
+
+// ReadEntries 逐条读取 r 中的日志，多行日志的后续行追加到消息；fn 返回 false 时停止
+func ReadEntries(r io.Reader, fn func(logger.Entry) bool) error {
+	reader := bufio.NewReader(r)
+	var pending *logger.Entry
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line = bytes.TrimRight(line, "\r\n")
+			if entry, ok := Parse(line); ok {
+				if pending != nil && !fn(*pending) {
+					return nil
+				}
+				pending = &entry
+			} else if pending != nil {
+				pending.Message += "\n" + string(line)
+			}
+		}
+		if err != nil {
+			if pending != nil {
+				fn(*pending)
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tailer\parse_test.go
+ * @Description: 文本与 JSON 日志行解析测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package tailer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+)
+
+// TestParse 解析文本（含彩色转义、调用者、字段块）与 JSON 行，非日志首行返回 false
+func TestParse(t *testing.T) {
+	at := time.Date(2026, 10, 15, 7, 5, 3, 0, time.Local)
+	tests := []struct {
+		name   string
+		line   string
+		ok     bool
+		level  logger.LogLevel
+		msg    string
+		fields map[string]any
+	}{
+		{"plain", "2026/10/15 7:05:03 ℹ️ [INFO] started", true, logger.INFO, "started", nil},
+		{"caller and fields", "2026/10/15 7:05:03 ⚠️ [WARN] [main.go:13:main] slow {order: o-1, ms: 900}\n", true, logger.WARN, "slow",
+			map[string]any{CallerKey: "main.go:13:main", "order": "o-1", "ms": "900"}},
+		{"nested field value", "2026/10/15 7:05:03 ❌ [ERROR] failed {user: {id: 1, name: a}, tags: [x, y]}", true, logger.ERROR, "failed",
+			map[string]any{"user": "{id: 1, name: a}", "tags": "[x, y]"}},
+		{"colored", "2026/10/15 7:05:03 \x1b[32mℹ️ [INFO]\x1b[0m c {k: v}", true, logger.INFO, "c", map[string]any{"k": "v"}},
+		{"prefix before level", "2026/10/15 7:05:03 [api] 🐛 [DEBUG] probe", true, logger.DEBUG, "probe", nil},
+		{"level text in message", "2026/10/15 7:05:03 ℹ️ [INFO] got [ERROR] from upstream", true, logger.INFO, "got [ERROR] from upstream", nil},
+		{"braces without space", "2026/10/15 7:05:03 ℹ️ [INFO] map{a: 1}", true, logger.INFO, "map{a: 1}", nil},
+		{"json", `{"level":"WARN","message":"j","timestamp":"2026-10-15T07:05:03+08:00","fields":{"k":"v","n":3}}`, true, logger.WARN, "j",
+			map[string]any{"k": "v", "n": int64(3)}},
+		{"continuation line", "    at main.go:15", false, 0, "", nil},
+		{"no level", "2026/10/15 7:05:03 plain text", false, 0, "", nil},
+		{"bad time", "yesterday noon [INFO] x", false, 0, "", nil},
+		{"broken json", `{"level":`, false, 0, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, ok := Parse([]byte(tt.line))
+			if ok != tt.ok {
+				t.Fatalf("Parse ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if entry.Level != tt.level || entry.Message != tt.msg {
+				t.Errorf("entry = %s %q, want %s %q", entry.Level, entry.Message, tt.level, tt.msg)
+			}
+			if !reflect.DeepEqual(entry.Fields, tt.fields) {
+				t.Errorf("fields = %#v, want %#v", entry.Fields, tt.fields)
+			}
+			if !strings.HasPrefix(tt.line, "{") && !entry.Time.Equal(at) {
+				t.Errorf("time = %v, want %v", entry.Time, at)
+			}
+		})
+	}
+}
+
+// TestParseLoggerOutput 解析 logger 实际输出的文本与 JSON 格式
+func TestParseLoggerOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		log  func(buf *bytes.Buffer) logger.ILogger
+	}{
+		{"text", func(buf *bytes.Buffer) logger.ILogger {
+			return logger.NewLogger().WithOutput(buf).WithColorful(false).WithShowCaller(true)
+		}},
+		{"colored text", func(buf *bytes.Buffer) logger.ILogger {
+			return logger.NewLogger().WithOutput(buf).WithColorful(true)
+		}},
+		{"json", func(buf *bytes.Buffer) logger.ILogger {
+			return logger.NewLogger().WithOutput(buf).WithFormatter(logger.NewJSONFormatter())
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			tt.log(&buf).WarnKV("paid", "order", "o-1")
+			entry, ok := Parse(buf.Bytes())
+			if !ok {
+				t.Fatalf("cannot parse %q", buf.String())
+			}
+			if entry.Level != logger.WARN || entry.Message != "paid" || entry.Fields["order"] != "o-1" {
+				t.Errorf("entry = %+v", entry)
+			}
+			if time.Since(entry.Time) > time.Minute {
+				t.Errorf("time = %v, want now", entry.Time)
+			}
+		})
+	}
+}
+
+// TestReadEntries 多行日志的后续行追加到消息，回调返回 false 时停止
+func TestReadEntries(t *testing.T) {
+	input := "2026/10/15 7:05:03 ❌ [ERROR] panic: boom\ngoroutine 1 [running]:\n\tmain.go:15\n" +
+		"2026/10/15 7:05:04 ℹ️ [INFO] recovered\n" +
+		"2026/10/15 7:05:05 ℹ️ [INFO] last without newline"
+	tests := []struct {
+		name  string
+		limit int
+		want  []string
+	}{
+		{"all", 0, []string{"panic: boom\ngoroutine 1 [running]:\n\tmain.go:15", "recovered", "last without newline"}},
+		{"stop early", 2, []string{"panic: boom\ngoroutine 1 [running]:\n\tmain.go:15", "recovered"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := ReadEntries(strings.NewReader(input), func(e logger.Entry) bool {
+				got = append(got, e.Message)
+				return tt.limit == 0 || len(got) < tt.limit
+			})
+			if err != nil {
+				t.Fatalf("ReadEntries: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
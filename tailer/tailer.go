@@ -0,0 +1,300 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tailer\tailer.go
+ * @Description: 跟随 go-logger 日志文件（支持轮转与截断），解析为日志条目并通过 channel 输出
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package tailer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	logger "github.com/kamalyes/go-logger"
+)
+
+const (
+	defaultPollInterval = 250 * time.Millisecond // 默认轮询间隔
+	defaultBufferSize   = 256                    // 默认 channel 容量
+)
+
+// Tailer 日志文件跟随器
+type Tailer struct {
+	path       string
+	poll       time.Duration
+	offset     int64 // 起始偏移（< 0 表示从文件末尾开始）
+	bufferSize int
+
+	entries  chan logger.Entry
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// Option 跟随器配置选项
+type Option func(*Tailer)
+
+// WithPollInterval 设置无新内容时的轮询间隔（默认 250ms）
+func WithPollInterval(d time.Duration) Option {
+	return func(t *Tailer) {
+		if d > 0 {
+			t.poll = d
+		}
+	}
+}
+
+// WithFromStart 从文件开头读取（默认从末尾开始，只输出新日志）
+func WithFromStart() Option {
+	return func(t *Tailer) {
+		t.offset = 0
+	}
+}
+
+// WithOffset 从指定字节偏移开始读取（可配合 logger.FileIndex.Seek 使用）
+func WithOffset(offset int64) Option {
+	return func(t *Tailer) {
+		if offset >= 0 {
+			t.offset = offset
+		}
+	}
+}
+
+// WithBufferSize 设置输出 channel 容量（默认 256）
+func WithBufferSize(n int) Option {
+	return func(t *Tailer) {
+		if n > 0 {
+			t.bufferSize = n
+		}
+	}
+}
+
+// Follow 开始跟随日志文件，文件不存在时等待其创建
+// 文件被轮转（路径指向新文件）时读完旧文件后切换到新文件，被截断时从头读取
+// ctx 取消或调用 Stop 后 Entries 返回的 channel 关闭
+func Follow(ctx context.Context, path string, opts ...Option) *Tailer {
+	t := &Tailer{
+		path:       path,
+		poll:       defaultPollInterval,
+		offset:     -1,
+		bufferSize: defaultBufferSize,
+		stop:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	t.entries = make(chan logger.Entry, t.bufferSize)
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	go t.run(ctx)
+	return t
+}
+
+// Entries 返回解析后的日志条目
+func (t *Tailer) Entries() <-chan logger.Entry {
+	return t.entries
+}
+
+// Stop 停止跟随
+func (t *Tailer) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stop)
+	})
+}
+
+// Err 返回导致跟随结束的错误（正常停止时为 nil）
+func (t *Tailer) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// setErr 记录错误
+func (t *Tailer) setErr(err error) {
+	t.mu.Lock()
+	t.err = err
+	t.mu.Unlock()
+}
+
+// follower 单次跟随状态
+type follower struct {
+	*Tailer
+	ctx     context.Context
+	file    *os.File
+	reader  *bufio.Reader
+	pos     int64
+	partial []byte
+	pending *logger.Entry
+}
+
+// run 跟随主循环
+func (t *Tailer) run(ctx context.Context) {
+	defer close(t.entries)
+
+	f := &follower{Tailer: t, ctx: ctx}
+	defer f.closeFile()
+
+	if !f.open(t.offset) {
+		return
+	}
+	for {
+		line, err := f.reader.ReadBytes('\n')
+		f.pos += int64(len(line))
+		if err == nil {
+			if len(f.partial) > 0 {
+				line = append(f.partial, line...)
+				f.partial = f.partial[:0]
+			}
+			if !f.handle(line) {
+				return
+			}
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			t.setErr(err)
+			return
+		}
+
+		// 未以换行结尾的内容等写完整后再解析
+		f.partial = append(f.partial, line...)
+		if f.pending != nil && len(f.partial) == 0 {
+			if !f.emit(*f.pending) {
+				return
+			}
+			f.pending = nil
+		}
+		if !f.checkRotation() || !f.wait() {
+			return
+		}
+	}
+}
+
+// open 打开文件并定位到 offset（< 0 表示末尾），文件不存在时等待其创建后从头读取
+func (f *follower) open(offset int64) bool {
+	for {
+		file, err := os.Open(f.path)
+		if err == nil {
+			whence := io.SeekStart
+			if offset < 0 {
+				offset, whence = 0, io.SeekEnd
+			}
+			pos, err := file.Seek(offset, whence)
+			if err != nil {
+				file.Close()
+				f.setErr(err)
+				return false
+			}
+			f.file, f.pos = file, pos
+			f.reader = bufio.NewReader(file)
+			f.partial = f.partial[:0]
+			return true
+		}
+		if !os.IsNotExist(err) {
+			f.setErr(err)
+			return false
+		}
+		// 等待期间创建的文件全部是新内容，从头读取
+		offset = 0
+		if !f.wait() {
+			return false
+		}
+	}
+}
+
+// checkRotation 检查文件是否被轮转或截断
+func (f *follower) checkRotation() bool {
+	current, err := os.Stat(f.path)
+	if err != nil {
+		// 轮转过程中路径可能短暂不存在
+		return true
+	}
+	opened, err := f.file.Stat()
+	if err != nil {
+		f.setErr(err)
+		return false
+	}
+
+	switch {
+	case !os.SameFile(current, opened):
+		// 旧文件在轮转前可能还有未读内容
+		if opened.Size() > f.pos {
+			return true
+		}
+		f.closeFile()
+		return f.open(0)
+	case current.Size() < f.pos:
+		if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+			f.setErr(err)
+			return false
+		}
+		f.pos = 0
+		f.reader.Reset(f.file)
+		f.partial = f.partial[:0]
+	}
+	return true
+}
+
+// handle 处理一整行：日志首行先暂存，后续行（多行日志）追加到消息
+func (f *follower) handle(line []byte) bool {
+	line = bytes.TrimRight(line, "\r\n")
+	entry, ok := Parse(line)
+	if !ok {
+		if f.pending != nil {
+			f.pending.Message += "\n" + string(line)
+		}
+		return true
+	}
+
+	if f.pending != nil && !f.emit(*f.pending) {
+		return false
+	}
+	f.pending = &entry
+	return true
+}
+
+// emit 输出一条日志
+func (f *follower) emit(entry logger.Entry) bool {
+	select {
+	case f.entries <- entry:
+		return true
+	case <-f.stop:
+		return false
+	case <-f.ctx.Done():
+		return false
+	}
+}
+
+// wait 等待一个轮询间隔，停止时返回 false
+func (f *follower) wait() bool {
+	timer := time.NewTimer(f.poll)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-f.stop:
+		return false
+	case <-f.ctx.Done():
+		return false
+	}
+}
+
+// closeFile 关闭当前文件
+func (f *follower) closeFile() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tailer\tailer_test.go
+ * @Description: 日志文件跟随测试（起始位置、半行写入、轮转、截断与停止）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package tailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// textLine 文本格式的日志行
+func textLine(msg string) string {
+	return fmt.Sprintf("2026/10/15 7:05:03 ℹ️ [INFO] %s\n", msg)
+}
+
+// appendFile 追加内容到文件
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// expectMessages 依次读取 n 条日志并比较消息
+func expectMessages(t *testing.T, tl *Tailer, want ...string) {
+	t.Helper()
+	for _, msg := range want {
+		select {
+		case e, ok := <-tl.Entries():
+			if !ok {
+				t.Fatalf("channel closed before %q (err=%v)", msg, tl.Err())
+			}
+			if e.Message != msg {
+				t.Fatalf("message = %q, want %q", e.Message, msg)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for %q", msg)
+		}
+	}
+}
+
+// TestFollow 跟随文件：起始位置、等待文件创建、半行写入、轮转后切换与截断后从头读取
+func TestFollow(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		run  func(t *testing.T, path string, tl *Tailer)
+	}{
+		{"from end skips existing", nil, func(t *testing.T, path string, tl *Tailer) {
+			time.Sleep(50 * time.Millisecond) // 等待定位到末尾
+			appendFile(t, path, textLine("new")+textLine("flush"))
+			expectMessages(t, tl, "new")
+		}},
+		{"from start", []Option{WithFromStart()}, func(t *testing.T, path string, tl *Tailer) {
+			expectMessages(t, tl, "old")
+		}},
+		{"offset", []Option{WithOffset(int64(len(textLine("old"))))}, func(t *testing.T, path string, tl *Tailer) {
+			appendFile(t, path, textLine("second")+textLine("flush"))
+			expectMessages(t, tl, "second")
+		}},
+		{"partial line and multiline", []Option{WithFromStart()}, func(t *testing.T, path string, tl *Tailer) {
+			expectMessages(t, tl, "old")
+			line := textLine("split")
+			appendFile(t, path, line[:10])
+			time.Sleep(30 * time.Millisecond)
+			appendFile(t, path, line[10:]+"\tcontinued\n")
+			expectMessages(t, tl, "split\n\tcontinued")
+		}},
+		{"rotation", []Option{WithFromStart()}, func(t *testing.T, path string, tl *Tailer) {
+			expectMessages(t, tl, "old")
+			appendFile(t, path, textLine("before rotate"))
+			if err := os.Rename(path, path+".1"); err != nil {
+				t.Fatal(err)
+			}
+			appendFile(t, path, textLine("after rotate"))
+			expectMessages(t, tl, "before rotate", "after rotate")
+		}},
+		{"truncation", []Option{WithFromStart()}, func(t *testing.T, path string, tl *Tailer) {
+			appendFile(t, path, textLine("long message before truncation"))
+			expectMessages(t, tl, "old", "long message before truncation")
+			if err := os.Truncate(path, 0); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(30 * time.Millisecond)
+			appendFile(t, path, textLine("fresh"))
+			expectMessages(t, tl, "fresh")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			appendFile(t, path, textLine("old"))
+
+			opts := append([]Option{WithPollInterval(10 * time.Millisecond)}, tt.opts...)
+			tl := Follow(context.Background(), path, opts...)
+			defer tl.Stop()
+			tt.run(t, path, tl)
+		})
+	}
+}
+
+// TestFollowWaitsForFile 文件不存在时等待其创建，创建后的内容从头读取
+func TestFollowWaitsForFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "later.log")
+	tl := Follow(context.Background(), path, WithPollInterval(10*time.Millisecond))
+	defer tl.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	appendFile(t, path, textLine("created"))
+	expectMessages(t, tl, "created")
+}
+
+// TestFollowStop Stop 与 ctx 取消都会关闭 channel，且不视为错误
+func TestFollowStop(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(tl *Tailer, cancel context.CancelFunc)
+	}{
+		{"stop", func(tl *Tailer, _ context.CancelFunc) { tl.Stop() }},
+		{"cancel", func(_ *Tailer, cancel context.CancelFunc) { cancel() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			tl := Follow(ctx, filepath.Join(t.TempDir(), "missing.log"), WithPollInterval(10*time.Millisecond))
+			tt.stop(tl, cancel)
+
+			select {
+			case _, ok := <-tl.Entries():
+				if ok {
+					t.Fatal("unexpected entry")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("channel not closed after stop")
+			}
+			if err := tl.Err(); err != nil {
+				t.Errorf("Err = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tasklabels.go
+ * @Description: 协程 ID 与 pprof 标签字段，无需手动传递 worker_id 即可区分并发任务的日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GoroutineIDKey 协程 ID 字段名
+const GoroutineIDKey = "goroutine"
+
+// GoroutineID 返回当前协程 ID（解析 runtime.Stack 的首行，每次约 1µs，仅用于日志关联）
+func GoroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// WithGoroutineID 为每条日志附加当前协程 ID 字段（goroutine）
+func (l *Logger) WithGoroutineID(enabled bool) *Logger {
+	l.goroutineID = enabled
+	return l
+}
+
+// pprofLabelSet pprof 标签提取配置
+type pprofLabelSet struct {
+	keys []string // 为空时提取全部标签
+}
+
+// WithPprofLabels 为 *Context 方法的日志附加 ctx 中的 pprof 标签（pprof.Do / pprof.WithLabels 设置），
+// keys 为空时附加全部标签（按键名排序），enabled 为 false 时关闭：
+//
+//	pprof.Do(ctx, pprof.Labels("worker", "3"), func(ctx context.Context) {
+//		log.InfoContext(ctx, "job done") // job done {worker: 3}
+//	})
+//
+// Go 不提供读取当前协程标签的公开接口，因此只能从 ctx 提取
+func (l *Logger) WithPprofLabels(enabled bool, keys ...string) *Logger {
+	if !enabled {
+		l.pprofLabels = nil
+		return l
+	}
+	l.pprofLabels = &pprofLabelSet{keys: keys}
+	return l
+}
+
+// extract 将 ctx 中的 pprof 标签追加为键值对
+func (s *pprofLabelSet) extract(ctx context.Context, kv []any) []any {
+	if len(s.keys) > 0 {
+		for _, key := range s.keys {
+			if value, ok := pprof.Label(ctx, key); ok {
+				kv = append(kv, key, value)
+			}
+		}
+		return kv
+	}
+
+	var labels [][2]string
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		labels = append(labels, [2]string{key, value})
+		return true
+	})
+	slices.SortFunc(labels, func(a, b [2]string) int {
+		return strings.Compare(a[0], b[0])
+	})
+	for _, label := range labels {
+		kv = append(kv, label[0], label[1])
+	}
+	return kv
+}
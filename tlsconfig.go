@@ -0,0 +1,186 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tlsconfig.go
+ * @Description: 网络适配器共用的 TLS 配置（CA 证书、客户端证书、跳过校验、最低版本），可直接写在 YAML/JSON 配置中
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// TLSConfig TLS 配置段，可嵌入应用的配置结构：
+//
+//	tls:
+//	  ca_file: /etc/ssl/logging-ca.pem
+//	  cert_file: /etc/ssl/client.pem
+//	  key_file: /etc/ssl/client-key.pem
+//	  min_version: "1.2"
+//
+// 通过 Build 得到 *tls.Config 后传给各适配器的 TLS 选项（WithDatadogTLS、WithSplunkTLS、WithCloudWatchTLS、
+// WithFluentTLS、WithNATSTLS、WithMQTTTLS），HTTPTransportConfig.TLS 同样使用该配置段；组件参数中的 tls 段由 ComponentParams.TLS 读取
+type TLSConfig struct {
+	CAFile             string `json:"ca_file,omitempty" yaml:"ca_file,omitempty"`                           // CA 证书（PEM，可含多个），为空时使用系统根证书
+	CertFile           string `json:"cert_file,omitempty" yaml:"cert_file,omitempty"`                       // 客户端证书（双向认证）
+	KeyFile            string `json:"key_file,omitempty" yaml:"key_file,omitempty"`                         // 客户端私钥
+	ServerName         string `json:"server_name,omitempty" yaml:"server_name,omitempty"`                   // 校验的服务端名称，为空时取连接地址的主机名
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"` // 跳过服务端证书校验（仅用于测试环境）
+	MinVersion         string `json:"min_version,omitempty" yaml:"min_version,omitempty"`                   // 最低版本 1.0 / 1.1 / 1.2 / 1.3（默认 1.2）
+}
+
+// tlsVersions 最低版本取值
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion 解析 TLS 版本（"1.2"、"TLS1.2"、"tls12" 均可）
+func ParseTLSVersion(s string) (uint16, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "tls"), "v")
+	if len(v) == 2 && !strings.Contains(v, ".") {
+		v = v[:1] + "." + v[1:]
+	}
+	if version, ok := tlsVersions[v]; ok {
+		return version, nil
+	}
+	return 0, fmt.Errorf("logger: unknown TLS version %q", s)
+}
+
+// Build 生成 *tls.Config，nil 接收者返回 nil（表示不启用 TLS）
+func (c *TLSConfig) Build() (*tls.Config, error) {
+	if c == nil {
+		return nil, nil
+	}
+	conf := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+	if c.MinVersion != "" {
+		version, err := ParseTLSVersion(c.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		conf.MinVersion = version
+	}
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("logger: read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("logger: no certificates found in %s", c.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("logger: cert_file and key_file must be set together")
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("logger: load client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+// TLS 读取 TLS 配置段（键名与 TLSConfig 的 json 标签一致），不存在时返回 nil
+func (p ComponentParams) TLS(key string) (*TLSConfig, error) {
+	v, ok := p[key]
+	if !ok || v == nil {
+		return nil, nil
+	}
+	section, ok := componentSection(v)
+	if !ok {
+		return nil, fmt.Errorf("param %q: want TLS section, got %T", key, v)
+	}
+	c := &TLSConfig{}
+	for name, dst := range map[string]*string{
+		"ca_file":     &c.CAFile,
+		"cert_file":   &c.CertFile,
+		"key_file":    &c.KeyFile,
+		"server_name": &c.ServerName,
+		"min_version": &c.MinVersion,
+	} {
+		s, err := section.String(name, "")
+		if err != nil {
+			return nil, fmt.Errorf("param %q: %w", key, err)
+		}
+		*dst = s
+	}
+	skip, err := section.Bool("insecure_skip_verify", false)
+	if err != nil {
+		return nil, fmt.Errorf("param %q: %w", key, err)
+	}
+	c.InsecureSkipVerify = skip
+	return c, nil
+}
+
+// httpClientWithTLS 返回使用 conf 的客户端副本（复制原有 *http.Transport 的其余设置），
+// conf 为 nil 或原客户端使用了自定义 RoundTripper 时原样返回
+func httpClientWithTLS(client *http.Client, conf *tls.Config) *http.Client {
+	if conf == nil {
+		return client
+	}
+	if client == nil {
+		client = &http.Client{}
+	}
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return client
+	}
+	transport.TLSClientConfig = conf
+	copied := *client
+	copied.Transport = transport
+	return &copied
+}
+
+// dialTLS 按 conf 建立连接，conf 为 nil 时建立明文连接
+func dialTLS(network, address string, timeout time.Duration, conf *tls.Config) (net.Conn, error) {
+	if conf == nil {
+		return net.DialTimeout(network, address, timeout)
+	}
+	return tls.DialWithDialer(&net.Dialer{Timeout: timeout}, network, address, conf)
+}
+
+// clientTLS 在已建立的连接上发起 TLS 握手（用于先明文协商再升级的协议），未设置 ServerName 时取地址中的主机名
+func clientTLS(conn net.Conn, address string, timeout time.Duration, conf *tls.Config) (net.Conn, error) {
+	if conf.ServerName == "" && !conf.InsecureSkipVerify {
+		conf = conf.Clone()
+		conf.ServerName = address
+		if host, _, err := net.SplitHostPort(address); err == nil {
+			conf.ServerName = host
+		}
+	}
+	tlsConn := tls.Client(conn, conf)
+	tlsConn.SetDeadline(time.Now().Add(timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
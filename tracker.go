@@ -0,0 +1,188 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\tracker.go
+ * @Description: 进程内错误聚合（按指纹统计次数、首末次出现时间与样例），无法外发时的轻量替代
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrorSample 错误样例
+type ErrorSample struct {
+	Time    time.Time         `json:"time"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+// ErrorOccurrence 同一指纹的错误统计
+type ErrorOccurrence struct {
+	Fingerprint string        `json:"fingerprint"`
+	Level       LogLevel      `json:"level"`
+	Message     string        `json:"message"`
+	Count       int64         `json:"count"`
+	FirstSeen   time.Time     `json:"first_seen"`
+	LastSeen    time.Time     `json:"last_seen"`
+	Samples     []ErrorSample `json:"samples,omitempty"`
+}
+
+// ErrorTracker 错误聚合钩子
+// 超过最大指纹数时淘汰最久未出现的指纹；每个指纹保留最近若干条样例
+type ErrorTracker struct {
+	mu         sync.Mutex
+	levels     []LogLevel
+	maxEntries int
+	maxSamples int
+	entries    map[string]*ErrorOccurrence
+}
+
+// ErrorTrackerOption 错误聚合配置选项
+type ErrorTrackerOption func(*ErrorTracker)
+
+// WithTrackerLevels 设置统计级别（默认 ERROR、FATAL）
+func WithTrackerLevels(levels ...LogLevel) ErrorTrackerOption {
+	return func(t *ErrorTracker) {
+		t.levels = levels
+	}
+}
+
+// WithTrackerMaxEntries 设置最大指纹数（默认 500）
+func WithTrackerMaxEntries(n int) ErrorTrackerOption {
+	return func(t *ErrorTracker) {
+		if n > 0 {
+			t.maxEntries = n
+		}
+	}
+}
+
+// WithTrackerMaxSamples 设置每个指纹保留的样例数（默认 5）
+func WithTrackerMaxSamples(n int) ErrorTrackerOption {
+	return func(t *ErrorTracker) {
+		if n >= 0 {
+			t.maxSamples = n
+		}
+	}
+}
+
+// NewErrorTracker 创建错误聚合钩子
+func NewErrorTracker(opts ...ErrorTrackerOption) *ErrorTracker {
+	t := &ErrorTracker{
+		levels:     []LogLevel{ERROR, FATAL},
+		maxEntries: 500,
+		maxSamples: 5,
+		entries:    make(map[string]*ErrorOccurrence),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Fire 实现 IHook 接口
+func (t *ErrorTracker) Fire(entry *LogEntry) error {
+	fp := entryFingerprint(entry)
+	now := time.Unix(0, entry.Timestamp)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	occ, ok := t.entries[fp]
+	if !ok {
+		if len(t.entries) >= t.maxEntries {
+			t.evictLocked()
+		}
+		occ = &ErrorOccurrence{
+			Fingerprint: fp,
+			Level:       entry.Level,
+			Message:     entry.Message,
+			FirstSeen:   now,
+		}
+		t.entries[fp] = occ
+	}
+	occ.Count++
+	occ.LastSeen = now
+	if entry.Level > occ.Level {
+		occ.Level = entry.Level
+	}
+
+	if t.maxSamples > 0 {
+		sample := ErrorSample{Time: now, Message: entry.Message}
+		if len(entry.Fields) > 0 {
+			sample.Fields = stringifyFields(entry.Fields)
+		}
+		if len(occ.Samples) >= t.maxSamples {
+			copy(occ.Samples, occ.Samples[1:])
+			occ.Samples = occ.Samples[:len(occ.Samples)-1]
+		}
+		occ.Samples = append(occ.Samples, sample)
+	}
+	return nil
+}
+
+// Levels 实现 IHook 接口
+func (t *ErrorTracker) Levels() []LogLevel {
+	return t.levels
+}
+
+// evictLocked 淘汰最久未出现的指纹（调用方持有锁）
+func (t *ErrorTracker) evictLocked() {
+	var oldest string
+	var oldestSeen time.Time
+	for fp, occ := range t.entries {
+		if oldest == "" || occ.LastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = fp, occ.LastSeen
+		}
+	}
+	delete(t.entries, oldest)
+}
+
+// Snapshot 返回所有指纹统计（按次数降序）
+func (t *ErrorTracker) Snapshot() []ErrorOccurrence {
+	t.mu.Lock()
+	result := make([]ErrorOccurrence, 0, len(t.entries))
+	for _, occ := range t.entries {
+		result = append(result, occ.clone())
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].LastSeen.After(result[j].LastSeen)
+	})
+	return result
+}
+
+// Get 获取指定指纹的统计
+func (t *ErrorTracker) Get(fingerprint string) (ErrorOccurrence, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	occ, ok := t.entries[fingerprint]
+	if !ok {
+		return ErrorOccurrence{}, false
+	}
+	return occ.clone(), true
+}
+
+// Reset 清空统计
+func (t *ErrorTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = make(map[string]*ErrorOccurrence)
+}
+
+// clone 复制统计（样例切片独立）
+func (o *ErrorOccurrence) clone() ErrorOccurrence {
+	c := *o
+	c.Samples = append([]ErrorSample(nil), o.Samples...)
+	return c
+}
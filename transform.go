@@ -0,0 +1,69 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\transform.go
+ * @Description: 字段转换器（按字段名改写或丢弃字段值），内置加盐哈希匿名化
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+)
+
+// FieldTransformer 字段转换器，在字段编码前按字段名改写值
+type FieldTransformer interface {
+	// TransformField 返回替换后的值，keep 为 false 时丢弃该字段
+	TransformField(key string, value any) (newValue any, keep bool)
+}
+
+// FieldTransformerFunc 函数式字段转换器
+type FieldTransformerFunc func(key string, value any) (any, bool)
+
+// TransformField 实现 FieldTransformer 接口
+func (f FieldTransformerFunc) TransformField(key string, value any) (any, bool) {
+	return f(key, value)
+}
+
+// WithFieldTransformers 追加字段转换器（按添加顺序依次执行）
+func (l *Logger) WithFieldTransformers(transformers ...FieldTransformer) *Logger {
+	l.fieldTransformers = append(append([]FieldTransformer(nil), l.fieldTransformers...), transformers...)
+	return l
+}
+
+// HashTransformer 将指定字段的值替换为加盐哈希，便于关联同一用户/IP 的日志而不暴露原值
+// 与 PolicyHash 使用相同算法，相同盐下两者输出一致
+type HashTransformer struct {
+	salt []byte
+	keys map[string]struct{}
+}
+
+// NewHashTransformer 创建哈希转换器，keys 为需要匿名化的字段名（如 "user_id"、"ip"）
+func NewHashTransformer(salt []byte, keys ...string) *HashTransformer {
+	t := &HashTransformer{
+		salt: append([]byte(nil), salt...),
+		keys: make(map[string]struct{}, len(keys)),
+	}
+	for _, key := range keys {
+		t.keys[key] = struct{}{}
+	}
+	return t
+}
+
+// TransformField 实现 FieldTransformer 接口
+func (t *HashTransformer) TransformField(key string, value any) (any, bool) {
+	if _, ok := t.keys[key]; !ok {
+		return value, true
+	}
+	return t.Hash(value), true
+}
+
+// Hash 计算值的加盐哈希（16 位十六进制）
+func (t *HashTransformer) Hash(value any) string {
+	var out [hashedValueLen]byte
+	hashInto(out[:], convert.AppendValue(nil, value), t.salt)
+	return string(out[:])
+}
@@ -0,0 +1,141 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\truncate.go
+ * @Description: 消息与字段值长度限制及截断策略
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"maps"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kamalyes/go-toolbox/pkg/convert"
+	"github.com/kamalyes/go-toolbox/pkg/stringx"
+)
+
+const (
+	DefaultMaxMessageSize    = 64 * 1024 // 默认单条消息最大字节数
+	DefaultMaxFieldValueSize = 16 * 1024 // 默认单个字段值最大字节数
+	maxPooledBufferSize      = 64 * 1024 // 超过此容量的缓冲不再放回池中，避免长期占用内存
+)
+
+var (
+	truncatedPrefix = []byte("…[truncated ")
+	truncatedSuffix = []byte("]")
+)
+
+// truncateTail 将 buf[start:] 限制在 limit 字节以内，超出部分替换为截断标记
+// limit <= 0 表示不限制；截断位置会回退到 UTF-8 字符边界
+func truncateTail(buf []byte, start, limit int) []byte {
+	if limit <= 0 || len(buf)-start <= limit {
+		return buf
+	}
+
+	cut := start + limit
+	for cut > start && !utf8.RuneStart(buf[cut]) {
+		cut--
+	}
+	dropped := len(buf) - cut
+
+	buf = append(buf[:cut], truncatedPrefix...)
+	buf = appendByteSize(buf, dropped)
+	return append(buf, truncatedSuffix...)
+}
+
+// limitMessage 清理并限制 buf[start:] 处的消息
+func (l *Logger) limitMessage(buf []byte, start int) []byte {
+	return truncateTail(sanitizeTail(buf, start, l.sanitize, l.newline), start, l.maxMessageSize)
+}
+
+// limitFieldValue 清理并限制 buf[start:] 处的字段值
+func (l *Logger) limitFieldValue(buf []byte, start int) []byte {
+	return truncateTail(sanitizeTail(buf, start, l.sanitize, l.newline), start, l.maxFieldValueSize)
+}
+
+// limitEntry 返回消息与字段值不超过上限的条目，供格式化器（JSON 等）使用，截断方式与文本路径一致；
+// 无需截断时返回原条目，不产生分配，需要截断时返回副本，不修改钩子与订阅者已收到的原条目。
+// 超出上限的非字符串字段值以截断后的文本形式输出
+func (l *Logger) limitEntry(entry *LogEntry) *LogEntry {
+	limited := entry
+	if l.maxMessageSize > 0 && len(entry.Message) > l.maxMessageSize {
+		c := *entry
+		c.Message = string(truncateTail([]byte(entry.Message), 0, l.maxMessageSize))
+		limited = &c
+	}
+	if l.maxFieldValueSize <= 0 || len(entry.Fields) == 0 {
+		return limited
+	}
+
+	var (
+		fields map[string]any
+		bp     *[]byte
+	)
+	for k, v := range entry.Fields {
+		switch x := v.(type) {
+		case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, time.Time, time.Duration:
+			continue
+		case string:
+			if len(x) <= l.maxFieldValueSize {
+				continue
+			}
+		case []byte:
+			if len(x) <= l.maxFieldValueSize {
+				continue
+			}
+		}
+		if bp == nil {
+			bp = bytePool.Get().(*[]byte)
+		}
+		buf := convert.AppendValue((*bp)[:0], v)
+		*bp = buf
+		if len(buf) <= l.maxFieldValueSize {
+			continue
+		}
+		if fields == nil {
+			fields = maps.Clone(entry.Fields)
+		}
+		fields[k] = string(truncateTail(buf, 0, l.maxFieldValueSize))
+	}
+	if bp != nil {
+		putLineBuf(bp, *bp)
+	}
+	if fields == nil {
+		return limited
+	}
+	if limited == entry {
+		c := *entry
+		limited = &c
+	}
+	limited.Fields = fields
+	return limited
+}
+
+// appendByteSize 以 B/KB/MB 形式追加字节数
+func appendByteSize(buf []byte, size int) []byte {
+	switch {
+	case size >= 1<<20:
+		buf = stringx.FastAppendInt(buf, size>>20)
+		return append(buf, "MB"...)
+	case size >= 1<<10:
+		buf = stringx.FastAppendInt(buf, size>>10)
+		return append(buf, "KB"...)
+	default:
+		buf = stringx.FastAppendInt(buf, size)
+		return append(buf, 'B')
+	}
+}
+
+// putLineBuf 归还日志行缓冲，过大的缓冲直接丢弃
+func putLineBuf(bp *[]byte, buf []byte) {
+	if cap(buf) > maxPooledBufferSize {
+		return
+	}
+	*bp = buf[:0]
+	bytePool.Put(bp)
+}
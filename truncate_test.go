@@ -0,0 +1,101 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\truncate_test.go
+ * @Description: 超长消息与字段值截断测试（文本与 JSON 格式化器）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// sizeHook 记录钩子收到的消息长度
+type sizeHook struct {
+	messageLen int
+}
+
+func (h *sizeHook) Fire(entry *LogEntry) error {
+	h.messageLen = len(entry.Message)
+	return nil
+}
+
+func (h *sizeHook) Levels() []LogLevel { return nil }
+
+// TestFormatterTruncation 使用格式化器（JSON）时同样限制消息与字段值大小，钩子收到的原条目不被修改
+func TestFormatterTruncation(t *testing.T) {
+	const msgLimit, fieldLimit = 100, 50
+	huge := strings.Repeat("x", 100*1024)
+
+	var buf bytes.Buffer
+	hook := &sizeHook{}
+	l := NewLogger().WithOutput(&buf).WithFormatter(NewJSONFormatter()).WithHooks([]IHook{hook}).
+		WithMaxMessageSize(msgLimit).WithMaxFieldValueSize(fieldLimit)
+	l.InfoKV(huge,
+		"payload", huge,
+		"bytes", []byte(huge),
+		"err", errors.New(huge),
+		"list", []string{huge},
+		"small", "ok",
+		"count", 42,
+	)
+
+	if buf.Len() > 2048 {
+		t.Fatalf("JSON line is %d bytes, want it bounded by the limits", buf.Len())
+	}
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if !strings.HasPrefix(entry.Message, strings.Repeat("x", msgLimit)+string(truncatedPrefix)) {
+		t.Errorf("message not truncated: %.120q", entry.Message)
+	}
+	for _, key := range []string{"payload", "bytes", "err", "list"} {
+		v, _ := entry.Fields[key].(string)
+		if !strings.Contains(v, string(truncatedPrefix)) || len(v) > fieldLimit+len(truncatedPrefix)+8 {
+			t.Errorf("field %s not truncated: %.120q", key, v)
+		}
+	}
+	if entry.Fields["small"] != "ok" || entry.Fields["count"] != float64(42) {
+		t.Errorf("small fields changed: %v %v", entry.Fields["small"], entry.Fields["count"])
+	}
+	if hook.messageLen != len(huge) {
+		t.Errorf("hook saw a %d-byte message, want the original %d bytes", hook.messageLen, len(huge))
+	}
+}
+
+// TestFormatterTruncationDisabled 上限 <= 0 时格式化器输出完整内容
+func TestFormatterTruncationDisabled(t *testing.T) {
+	huge := strings.Repeat("y", 70*1024)
+	var buf bytes.Buffer
+	l := NewLogger().WithOutput(&buf).WithFormatter(NewJSONFormatter()).WithMaxMessageSize(0).WithMaxFieldValueSize(0)
+	l.InfoKV(huge, "payload", huge)
+
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Message != huge || entry.Fields["payload"] != huge {
+		t.Error("content truncated with limits disabled")
+	}
+}
+
+// TestTextTruncation 文本格式同样截断（与 JSON 路径保持一致）
+func TestTextTruncation(t *testing.T) {
+	huge := strings.Repeat("z", 100*1024)
+	var buf bytes.Buffer
+	l := NewLogger().WithOutput(&buf).WithColorful(false).WithFormat(FormatText).
+		WithMaxMessageSize(100).WithMaxFieldValueSize(50)
+	l.InfoKV(huge, "payload", huge)
+	if buf.Len() > 1024 || strings.Count(buf.String(), string(truncatedPrefix)) != 2 {
+		t.Errorf("text line not truncated (%d bytes): %.200q", buf.Len(), buf.String())
+	}
+}
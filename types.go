@@ -17,6 +17,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kamalyes/go-toolbox/pkg/syncx"
@@ -41,6 +42,7 @@ type Logger struct {
 	// 基本配置
 	level          LogLevel
 	showCaller     bool
+	callerSkip     int // 调用位置额外跳过的层数（AddCallerSkip 设置）
 	colorful       bool
 	prefix         string
 	timeFormat     string
@@ -63,20 +65,93 @@ type Logger struct {
 
 	// 输出和同步
 	output io.Writer
-	mu     sync.Mutex // 保护并发写入
+	mu     *sync.Mutex  // 保护并发写入（子 Logger 共享，与父 Logger 写同一输出时串行）
+	async  *asyncWriter // 异步写入器（WithAsyncWrite 开启时使用，子 Logger 共享）
+	// ownsAsync 异步写入器由本 Logger 创建；共享父 Logger 写入器的子 Logger 修改输出或异步参数时改用自己的写入器，不改动共享的写入器
+	ownsAsync bool
 
 	// 内部组件
 	logger     *log.Logger
 	formatter  IFormatter
+	renderer   IFormatter    // 开发渲染器（终端输出时替代 JSON 格式化器）
+	devRender  DevRenderMode // 开发渲染模式
 	writers    []IWriter
 	hooks      []IHook
 	middleware []IMiddleware
 
+	// 静态字段（With 预编码的 "k: v" 字节，staticKV 为原始键值对供结构化管道使用）
+	staticFields []byte
+	staticKV     []any
+
+	// 按级别条件附加的字段（WithDebugField 等设置）
+	levelFields []LevelField
+
+	// 长度限制（<= 0 表示不限制）
+	maxMessageSize    int
+	maxFieldValueSize int
+
+	// 控制字符与换行处理方式（文本格式）
+	sanitize SanitizeMode
+	newline  NewlineMode
+
+	// 超长字段值摘要（为 nil 时不摘要）
+	summarizer *fieldSummarizer
+
+	// 结构体字段展开（为 nil 时结构体整体输出）
+	flattener *structFlattener
+
+	// 敏感字段处理策略与字段转换器
+	fieldPolicy       *FieldPolicy
+	fieldTransformers []FieldTransformer
+
+	// 审计通道
+	auditLogger *AuditLogger
+
+	// 是否附加消息指纹字段
+	fingerprint bool
+
+	// 日志 ID 生成函数（为 nil 时不附加 log_id）
+	logID LogIDGenerator
+
+	// 是否附加协程 ID 字段
+	goroutineID bool
+
+	// 事件校验器与路由（派生 Logger 共享）
+	events *eventRegistry
+
+	// 进程内日志订阅者（派生 Logger 共享）
+	subs *subscriberHub
+
+	// span 记录器与单次调用的 context（spanScope 创建的子 Logger 使用）
+	spanRecorder SpanRecorder
+	spanCtx      context.Context
+
+	// 请求级缓冲（BeginRequestBuffer 创建的子 Logger 使用）
+	reqBuffer *RequestBuffer
+
+	// 首错误捕获范围（BeginErrorScope 创建的子 Logger 使用）
+	errScope *ErrorScope
+
+	// 定向调试目标（FromContext 命中时提升为 DEBUG）
+	debugTargets *DebugTargets
+
+	// 写出配额（超出时丢弃）
+	quota *quotaLimiter
+
+	// 待输出的构建信息（BuildInfoFirstEntry 模式，子 Logger 共享）
+	buildInfoPending *atomic.Bool
+
+	// 时间源（为 nil 时使用 time.Now）
+	clock Clock
+
 	// 上下文支持
-	context          context.Context
-	cancel           context.CancelFunc
-	contextKeys      []compiledContextKey
-	contextExtractor ContextExtractor
+	context               context.Context
+	cancel                context.CancelFunc
+	contextKeys           []compiledContextKey
+	contextExtractor      ContextExtractor
+	contextFieldExtractor ContextFieldExtractor
+	baggage               *BaggageExtractor
+	pprofLabels           *pprofLabelSet
 
 	// 统计信息
 	stats *LoggerStats
@@ -164,28 +239,31 @@ func (s *LoggerStats) GetStats() *LoggerStats {
 // NewLogger 创建新的日志记录器（默认配置）
 func NewLogger() *Logger {
 	return &Logger{
-		level:          DEBUG,
-		showCaller:     false,
-		colorful:       true,
-		prefix:         "",
-		timeFormat:     time.DateTime,
-		format:         FormatJSON,
-		callerDepth:    2,
-		showStacktrace: false,
-		timestampKey:   "timestamp",
-		levelKey:       "level",
-		messageKey:     "message",
-		callerKey:      "caller",
-		stacktraceKey:  "stacktrace",
-		asyncWrite:     false,
-		bufferSize:     0,
-		batchSize:      100,
-		batchTimeout:   100 * time.Millisecond,
-		output:         os.Stdout,
-		logger:         log.New(os.Stdout, "", log.LstdFlags),
-		contextKeys:    append([]compiledContextKey(nil), defaultCompiledContextKeys...),
-		stats:          NewLoggerStats(),
-		mu:             sync.Mutex{},
+		level:             DEBUG,
+		showCaller:        false,
+		colorful:          true,
+		prefix:            "",
+		timeFormat:        time.DateTime,
+		format:            FormatJSON,
+		callerDepth:       2,
+		showStacktrace:    false,
+		timestampKey:      "timestamp",
+		levelKey:          "level",
+		messageKey:        "message",
+		callerKey:         "caller",
+		stacktraceKey:     "stacktrace",
+		asyncWrite:        false,
+		bufferSize:        0,
+		batchSize:         100,
+		batchTimeout:      100 * time.Millisecond,
+		output:            os.Stdout,
+		maxMessageSize:    DefaultMaxMessageSize,
+		maxFieldValueSize: DefaultMaxFieldValueSize,
+		fieldPolicy:       DefaultFieldPolicy(),
+		logger:            log.New(os.Stdout, "", log.LstdFlags),
+		contextKeys:       append([]compiledContextKey(nil), defaultCompiledContextKeys...),
+		stats:             NewLoggerStats(),
+		mu:                &sync.Mutex{},
 	}
 }
 
@@ -221,10 +299,18 @@ func (l *Logger) WithColorful(colorful bool) *Logger {
 	return l
 }
 
-// WithOutput 设置输出目标
+// WithOutput 设置输出目标（共享父 Logger 异步写入器的子 Logger 改用自己的写入器，需自行 Close）
 func (l *Logger) WithOutput(output io.Writer) *Logger {
 	l.output = output
 	l.logger = log.New(output, l.prefix, log.LstdFlags)
+	switch {
+	case l.async == nil:
+	case l.ownsAsync:
+		l.async.setOutput(output)
+	default:
+		l.async, l.ownsAsync = newAsyncWriter(output, l.bufferSize, l.batchSize, l.batchTimeout), true
+	}
+	l.updateRenderer()
 	return l
 }
 
@@ -240,6 +326,23 @@ func (l *Logger) WithFormat(format FormatType) *Logger {
 	return l
 }
 
+// Clock 时间源
+type Clock func() time.Time
+
+// WithClock 设置日志时间戳的时间源（nil 恢复为 time.Now），用于测试中固定时间或回放时模拟时钟
+func (l *Logger) WithClock(clock Clock) *Logger {
+	l.clock = clock
+	return l
+}
+
+// now 返回当前时间戳
+func (l *Logger) now() time.Time {
+	if l.clock != nil {
+		return l.clock()
+	}
+	return time.Now()
+}
+
 // WithCallerDepth 设置调用者深度
 func (l *Logger) WithCallerDepth(depth int) *Logger {
 	l.callerDepth = depth
@@ -283,32 +386,101 @@ func (l *Logger) WithStacktraceKey(key string) *Logger {
 }
 
 // WithAsyncWrite 设置是否异步写入
+// 开启后日志行通过无锁环形缓冲交给单个写入协程批量写出，消除多协程写入时的锁竞争；
+// 子 Logger 关闭异步写入时只解除与父 Logger 写入器的关联，不停止共享的写入器
 func (l *Logger) WithAsyncWrite(async bool) *Logger {
 	l.asyncWrite = async
+	switch {
+	case async && l.async == nil:
+		l.async, l.ownsAsync = newAsyncWriter(l.output, l.bufferSize, l.batchSize, l.batchTimeout), true
+	case !async && l.async != nil:
+		if l.ownsAsync {
+			l.async.Close()
+		}
+		l.async, l.ownsAsync = nil, false
+	}
 	return l
 }
 
-// WithBufferSize 设置缓冲区大小
+// WithBufferSize 设置缓冲区大小（异步模式下为环形缓冲槽位数）
 func (l *Logger) WithBufferSize(size int) *Logger {
 	l.bufferSize = size
+	l.reconfigureAsync()
 	return l
 }
 
 // WithBatchSize 设置批量写入大小
 func (l *Logger) WithBatchSize(size int) *Logger {
 	l.batchSize = size
+	l.reconfigureAsync()
 	return l
 }
 
 // WithBatchTimeout 设置批量写入超时时间
 func (l *Logger) WithBatchTimeout(timeout time.Duration) *Logger {
 	l.batchTimeout = timeout
+	l.reconfigureAsync()
+	return l
+}
+
+// WithMaxMessageSize 设置单条消息最大字节数，超出部分以 "…[truncated 12KB]" 标记截断（<= 0 不限制）
+func (l *Logger) WithMaxMessageSize(size int) *Logger {
+	l.maxMessageSize = size
+	return l
+}
+
+// WithMaxFieldValueSize 设置单个字段值最大字节数，超出部分截断（<= 0 不限制）
+func (l *Logger) WithMaxFieldValueSize(size int) *Logger {
+	l.maxFieldValueSize = size
 	return l
 }
 
+// reconfigureAsync 同步异步写入器参数（仅在写入器启动前生效），共享父 Logger 写入器时改用自己的写入器
+func (l *Logger) reconfigureAsync() {
+	switch {
+	case l.async == nil:
+	case !l.ownsAsync:
+		l.async, l.ownsAsync = newAsyncWriter(l.output, l.bufferSize, l.batchSize, l.batchTimeout), true
+	case !l.async.started.Load():
+		l.async.configure(l.bufferSize, l.batchSize, l.batchTimeout)
+	}
+}
+
+// Flush 等待异步队列写出，并刷新支持 Flush/Sync 的输出目标
+func (l *Logger) Flush() error {
+	if l.async != nil {
+		l.async.Flush()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch out := l.output.(type) {
+	case interface{ Flush() error }:
+		return out.Flush()
+	case interface{ Sync() error }:
+		if out == os.Stdout || out == os.Stderr {
+			return nil
+		}
+		return out.Sync()
+	}
+	return nil
+}
+
+// Close 写出剩余日志并停止异步写入协程（不关闭输出目标），共享父 Logger 写入器的子 Logger 只等待写出
+func (l *Logger) Close() error {
+	switch {
+	case l.async == nil:
+		return nil
+	case !l.ownsAsync:
+		return l.async.Flush()
+	}
+	return l.async.Close()
+}
+
 // WithFormatter 设置格式化器
 func (l *Logger) WithFormatter(formatter IFormatter) *Logger {
 	l.formatter = formatter
+	l.updateRenderer()
 	return l
 }
 
@@ -324,9 +496,9 @@ func (l *Logger) WithHooks(hooks []IHook) *Logger {
 	return l
 }
 
-// WithMiddleware 设置中间件列表
+// WithMiddleware 设置中间件列表（按优先级升序执行）
 func (l *Logger) WithMiddleware(middleware []IMiddleware) *Logger {
-	l.middleware = middleware
+	l.middleware = sortMiddleware(middleware)
 	return l
 }
 
@@ -346,56 +518,91 @@ func (l *Logger) IsLevelEnabled(level LogLevel) bool {
 	return level >= l.level
 }
 
+// Clone 创建共享输出、写锁与各注册表的子 Logger（逐字段浅拷贝，切片在追加时使用完整切片表达式，不会写入父 Logger 的底层数组）
+// 统计信息与控制台分组不继承
 func (l *Logger) Clone() ILogger {
-	newLogger := &Logger{
-		stats: NewLoggerStats(),
-	}
-
-	// 使用深拷贝复制数据（会自动跳过 mutex 和 sync.Once）
-	if err := syncx.DeepCopy(newLogger, l); err != nil {
-		// 如果深拷贝失败，降级为手动拷贝
-		newLogger.level = l.level
-		newLogger.showCaller = l.showCaller
-		newLogger.colorful = l.colorful
-		newLogger.prefix = l.prefix
-		newLogger.timeFormat = l.timeFormat
-		newLogger.format = l.format
-		newLogger.callerDepth = l.callerDepth
-		newLogger.showStacktrace = l.showStacktrace
-		newLogger.timestampKey = l.timestampKey
-		newLogger.levelKey = l.levelKey
-		newLogger.messageKey = l.messageKey
-		newLogger.callerKey = l.callerKey
-		newLogger.stacktraceKey = l.stacktraceKey
-		newLogger.asyncWrite = l.asyncWrite
-		newLogger.bufferSize = l.bufferSize
-		newLogger.batchSize = l.batchSize
-		newLogger.batchTimeout = l.batchTimeout
-		newLogger.output = l.output
-		newLogger.logger = l.logger
-		newLogger.formatter = l.formatter
-		newLogger.writers = l.writers
-		newLogger.contextKeys = append([]compiledContextKey(nil), l.contextKeys...)
+	return &Logger{
+		level:                 l.level,
+		showCaller:            l.showCaller,
+		callerSkip:            l.callerSkip,
+		colorful:              l.colorful,
+		prefix:                l.prefix,
+		timeFormat:            l.timeFormat,
+		format:                l.format,
+		callerDepth:           l.callerDepth,
+		showStacktrace:        l.showStacktrace,
+		timestampKey:          l.timestampKey,
+		levelKey:              l.levelKey,
+		messageKey:            l.messageKey,
+		callerKey:             l.callerKey,
+		stacktraceKey:         l.stacktraceKey,
+		asyncWrite:            l.asyncWrite,
+		bufferSize:            l.bufferSize,
+		batchSize:             l.batchSize,
+		batchTimeout:          l.batchTimeout,
+		output:                l.output,
+		mu:                    l.mu,
+		async:                 l.async,
+		logger:                l.logger,
+		formatter:             l.formatter,
+		renderer:              l.renderer,
+		devRender:             l.devRender,
+		writers:               l.writers,
+		hooks:                 l.hooks,
+		middleware:            l.middleware,
+		staticFields:          l.staticFields,
+		staticKV:              l.staticKV,
+		levelFields:           l.levelFields,
+		maxMessageSize:        l.maxMessageSize,
+		maxFieldValueSize:     l.maxFieldValueSize,
+		sanitize:              l.sanitize,
+		newline:               l.newline,
+		summarizer:            l.summarizer,
+		flattener:             l.flattener,
+		fieldPolicy:           l.fieldPolicy,
+		fieldTransformers:     l.fieldTransformers,
+		auditLogger:           l.auditLogger,
+		fingerprint:           l.fingerprint,
+		logID:                 l.logID,
+		goroutineID:           l.goroutineID,
+		events:                l.events,
+		subs:                  l.subs,
+		spanRecorder:          l.spanRecorder,
+		spanCtx:               l.spanCtx,
+		reqBuffer:             l.reqBuffer,
+		errScope:              l.errScope,
+		debugTargets:          l.debugTargets,
+		quota:                 l.quota,
+		buildInfoPending:      l.buildInfoPending,
+		clock:                 l.clock,
+		context:               l.context,
+		cancel:                l.cancel,
+		contextKeys:           append([]compiledContextKey(nil), l.contextKeys...),
+		contextExtractor:      l.contextExtractor,
+		contextFieldExtractor: l.contextFieldExtractor,
+		baggage:               l.baggage,
+		pprofLabels:           l.pprofLabels,
+		stats:                 NewLoggerStats(),
 	}
-
-	// 确保使用新的统计信息
-	newLogger.stats = NewLoggerStats()
-	newLogger.contextExtractor = l.contextExtractor
-
-	return newLogger
 }
 
-// SetGlobalLevel 设置全局日志级别
+// SetGlobalLevel 设置全局日志级别（写时复制，见 updateGlobal）
 func SetGlobalLevel(level LogLevel) {
-	defaultLogger.SetLevel(level)
+	updateGlobal(func(l *Logger) {
+		l.SetLevel(level)
+	})
 }
 
-// SetGlobalShowCaller 设置全局是否显示调用者信息
+// SetGlobalShowCaller 设置全局是否显示调用者信息（写时复制，见 updateGlobal）
 func SetGlobalShowCaller(show bool) {
-	defaultLogger.SetShowCaller(show)
+	updateGlobal(func(l *Logger) {
+		l.SetShowCaller(show)
+	})
 }
 
-// GetGlobalLogger 获取全局Logger
+// GetGlobalLogger 获取当前全局Logger
+// 返回的是当时的快照：之后的 SetGlobalXxx 与 ReplaceGlobal 发布新的 Logger，不修改已取得的 Logger，
+// 需要跟随全局设置时应每次调用 GetGlobalLogger（或使用包级日志函数），不要长期保存返回值
 func GetGlobalLogger() *Logger {
-	return defaultLogger
+	return globalLogger()
 }
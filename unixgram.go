@@ -0,0 +1,228 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\unixgram.go
+ * @Description: unix 数据报本地传输：适配器将每条日志作为一个 JSON 数据报发送给同机的日志采集边车，不经过文件系统写入
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// 默认配置
+const (
+	DefaultUnixgramPath    = "/tmp/go-logger.sock"
+	DefaultUnixgramMaxSize = 64 * 1024 // 单个数据报最大字节数（超出时截断）
+)
+
+// UnixgramAdapter unix 数据报适配器，适用于只读根文件系统的容器：
+//
+//	ug := logger.NewUnixgramAdapter(logger.WithUnixgramPath("/run/log/app.sock"))
+//	log := logger.NewLogger().WithAdapters(ug)
+//
+// 每条日志编码为一行 LogEntry JSON 作为一个数据报发送；采集端未启动或重启时自动重连，
+// 接收队列满导致发送超时的日志计入失败数（可用 logctl collect 或 ListenUnixgram 接收）
+type UnixgramAdapter struct {
+	*BaseAdapter
+	path      string
+	maxSize   int
+	timeout   time.Duration
+	queueSize int
+	retries   int
+	publisher *brokerPublisher
+}
+
+// UnixgramOption unix 数据报适配器配置选项
+type UnixgramOption func(*UnixgramAdapter)
+
+// WithUnixgramPath 设置采集端套接字路径（默认 DefaultUnixgramPath）
+func WithUnixgramPath(path string) UnixgramOption {
+	return func(a *UnixgramAdapter) {
+		a.path = path
+	}
+}
+
+// WithUnixgramMaxSize 设置单个数据报最大字节数（默认 DefaultUnixgramMaxSize）
+func WithUnixgramMaxSize(size int) UnixgramOption {
+	return func(a *UnixgramAdapter) {
+		if size > 0 {
+			a.maxSize = size
+		}
+	}
+}
+
+// WithUnixgramTimeout 设置发送超时（默认 100ms，采集端接收队列满时等待的最长时间）
+func WithUnixgramTimeout(timeout time.Duration) UnixgramOption {
+	return func(a *UnixgramAdapter) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithUnixgramQueueSize 设置发送队列长度（默认 DefaultBrokerQueueSize）
+func WithUnixgramQueueSize(size int) UnixgramOption {
+	return func(a *UnixgramAdapter) {
+		if size > 0 {
+			a.queueSize = size
+		}
+	}
+}
+
+// WithUnixgramRetries 设置发送失败重试次数（默认 1）
+func WithUnixgramRetries(retries int) UnixgramOption {
+	return func(a *UnixgramAdapter) {
+		if retries >= 0 {
+			a.retries = retries
+		}
+	}
+}
+
+// NewUnixgramAdapter 创建 unix 数据报适配器并启动发送协程
+func NewUnixgramAdapter(opts ...UnixgramOption) *UnixgramAdapter {
+	a := &UnixgramAdapter{
+		path:      DefaultUnixgramPath,
+		maxSize:   DefaultUnixgramMaxSize,
+		timeout:   100 * time.Millisecond,
+		queueSize: DefaultBrokerQueueSize,
+		retries:   1,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.BaseAdapter = NewBaseAdapter("unixgram", "1.0.0", a.emit)
+	a.publisher = newBrokerPublisher(OverflowComponentUnixgram, a.queueSize, a.retries, a.dial, a.SetHealthy)
+	return a
+}
+
+// emit 日志编码为 LogEntry JSON 行后入队（与 JSONFormatter / ndjson 格式一致，超过最大长度时截断）
+func (a *UnixgramAdapter) emit(_ context.Context, level LogLevel, msg string, fields map[string]any) {
+	payload, err := marshalJSON(&LogEntry{Level: level, Message: msg, Timestamp: time.Now().UnixNano(), Fields: fields})
+	if err != nil {
+		reportInternalError("unixgram", err)
+		return
+	}
+	payload = append(payload, '\n')
+	if len(payload) > a.maxSize {
+		payload = payload[:a.maxSize]
+	}
+	a.publisher.enqueue(brokerMessage{payload: payload})
+}
+
+// Flush 发送队列中的日志
+func (a *UnixgramAdapter) Flush() error {
+	return a.publisher.flush()
+}
+
+// Close 发送剩余日志后关闭
+func (a *UnixgramAdapter) Close() error {
+	a.publisher.close()
+	return a.BaseAdapter.Close()
+}
+
+// QueueDepth 实现 QueueReporter 接口
+func (a *UnixgramAdapter) QueueDepth() (depth, capacity int) {
+	return a.publisher.queueDepth()
+}
+
+// Stats 返回发送统计
+func (a *UnixgramAdapter) Stats() BrokerStats {
+	return a.publisher.stats()
+}
+
+// unixgramConn 已连接的数据报套接字
+type unixgramConn struct {
+	conn    *net.UnixConn
+	timeout time.Duration
+}
+
+// dial 连接采集端套接字
+func (a *UnixgramAdapter) dial() (brokerConn, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: a.path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &unixgramConn{conn: conn, timeout: a.timeout}, nil
+}
+
+// publish 发送一个数据报
+func (c *unixgramConn) publish(_ string, payload []byte, _ bool) error {
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	_, err := c.conn.Write(payload)
+	return err
+}
+
+// flush 数据报无缓冲
+func (c *unixgramConn) flush() error {
+	return nil
+}
+
+// close 关闭套接字
+func (c *unixgramConn) close() error {
+	return c.conn.Close()
+}
+
+// UnixgramCollector unix 数据报接收端（日志采集边车使用）
+type UnixgramCollector struct {
+	conn      *net.UnixConn
+	path      string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// ListenUnixgram 在 path 上接收数据报，每个数据报调用一次 handler（数据在调用返回后失效），
+// 已存在的旧套接字文件会被删除
+//
+//	c, err := logger.ListenUnixgram("/run/log/app.sock", func(b []byte) { os.Stdout.Write(b) })
+//	defer c.Close()
+func ListenUnixgram(path string, handler func(datagram []byte)) (*UnixgramCollector, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	c := &UnixgramCollector{conn: conn, path: path, done: make(chan struct{})}
+	go c.run(handler)
+	return c, nil
+}
+
+// run 接收循环
+func (c *UnixgramCollector) run(handler func([]byte)) {
+	defer close(c.done)
+	buf := make([]byte, 256*1024)
+	for {
+		n, _, err := c.conn.ReadFromUnix(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				reportInternalError("unixgram", err)
+			}
+			return
+		}
+		handler(buf[:n])
+	}
+}
+
+// Close 停止接收并删除套接字文件
+func (c *UnixgramCollector) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.conn.Close()
+		<-c.done
+		os.Remove(c.path)
+	})
+	return err
+}
+
+var _ IAdapter = (*UnixgramAdapter)(nil)
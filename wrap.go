@@ -0,0 +1,80 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\wrap.go
+ * @Description: 通用适配器装饰器（统计、过滤、监控等横切逻辑只需实现一个 around 函数）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// AroundFunc 装饰函数：entry 可在调用 next 前修改（消息与字段），不调用 next 即丢弃该条日志
+type AroundFunc func(level LogLevel, entry *LogEntry, next func())
+
+// wrappedAdapter Wrap 返回的装饰适配器
+type wrappedAdapter struct {
+	ILogger
+	inner IAdapter
+}
+
+// wrapCore 将派生的日志调用转为 LogEntry 交给 around，next 转发给被装饰的适配器
+type wrapCore struct {
+	inner  IAdapter
+	around AroundFunc
+}
+
+func (c *wrapCore) Emit(ctx context.Context, level LogLevel, msg string, fields map[string]any) {
+	entry := &LogEntry{
+		Level:     level,
+		Message:   msg,
+		Timestamp: time.Now().UnixNano(),
+		Fields:    fields,
+	}
+	c.around(level, entry, func() {
+		target := ILogger(c.inner)
+		if ctx != nil {
+			target = target.WithContext(ctx)
+		}
+		target.LogWithFields(entry.Level, entry.Message, entry.Fields)
+	})
+}
+func (c *wrapCore) SetLevel(level LogLevel) { c.inner.SetLevel(level) }
+func (c *wrapCore) GetLevel() LogLevel      { return c.inner.GetLevel() }
+func (c *wrapCore) Flush() error            { return c.inner.Flush() }
+
+// Wrap 用 around 装饰适配器，所有日志方法都会经过 around；生命周期与适配器信息方法转发给 inner：
+//
+//	counted := logger.Wrap(adapter, func(level logger.LogLevel, e *logger.LogEntry, next func()) {
+//		counts[level]++
+//		next()
+//	})
+//
+// 级别判断由 inner 的 GetLevel 决定，被过滤的日志不会进入 around
+func Wrap(inner IAdapter, around AroundFunc) IAdapter {
+	if around == nil {
+		return inner
+	}
+	return &wrappedAdapter{
+		ILogger: Extend(&wrapCore{inner: inner, around: around}),
+		inner:   inner,
+	}
+}
+
+func (w *wrappedAdapter) Initialize() error         { return w.inner.Initialize() }
+func (w *wrappedAdapter) Close() error              { return w.inner.Close() }
+func (w *wrappedAdapter) Flush() error              { return w.inner.Flush() }
+func (w *wrappedAdapter) GetAdapterName() string    { return w.inner.GetAdapterName() }
+func (w *wrappedAdapter) GetAdapterVersion() string { return w.inner.GetAdapterVersion() }
+func (w *wrappedAdapter) IsHealthy() bool           { return w.inner.IsHealthy() }
+
+// Unwrap 返回被装饰的适配器
+func (w *wrappedAdapter) Unwrap() IAdapter {
+	return w.inner
+}
@@ -0,0 +1,151 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writeerror.go
+ * @Description: 写入失败处理策略（重试、死信文件、丢弃计数与回调）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WriteErrorPolicy 写入失败处理策略
+// 写入失败时先重试 Retries 次；仍失败则写入死信文件（DeadLetterPath 非空时），
+// 死信也失败或未配置死信时丢弃并计数。每次最终失败都会调用 OnWriteError
+type WriteErrorPolicy struct {
+	Retries        int                          // 重试次数（0 表示不重试）
+	RetryInterval  time.Duration                // 重试间隔（重试在写入协程内同步等待）
+	DeadLetterPath string                       // 死信文件路径
+	OnWriteError   func(err error, data []byte) // 最终失败回调（data 仅在回调期间有效）
+}
+
+// WriteErrorStats 写入失败统计
+type WriteErrorStats struct {
+	Errors       int64 `json:"errors"`        // 最终失败次数
+	Retries      int64 `json:"retries"`       // 重试次数
+	DeadLettered int64 `json:"dead_lettered"` // 写入死信文件的次数
+	Dropped      int64 `json:"dropped"`       // 丢弃次数
+}
+
+// ErrorPolicyWriter 按策略处理写入失败的输出包装
+// 包装多输出器时重试会重复写入已成功的输出器，建议分别包装每个输出器
+type ErrorPolicyWriter struct {
+	output io.Writer
+	policy WriteErrorPolicy
+
+	deadMu     sync.Mutex
+	deadLetter *os.File
+
+	errors       atomic.Int64
+	retries      atomic.Int64
+	deadLettered atomic.Int64
+	dropped      atomic.Int64
+}
+
+// NewErrorPolicyWriter 创建按策略处理写入失败的输出包装
+func NewErrorPolicyWriter(output io.Writer, policy WriteErrorPolicy) *ErrorPolicyWriter {
+	return &ErrorPolicyWriter{output: output, policy: policy}
+}
+
+// Write 实现 io.Writer 接口（按策略处理后始终视为写入完成）
+func (w *ErrorPolicyWriter) Write(p []byte) (int, error) {
+	n, err := w.output.Write(p)
+	for attempt := 0; err != nil && attempt < w.policy.Retries; attempt++ {
+		w.retries.Add(1)
+		if w.policy.RetryInterval > 0 {
+			time.Sleep(w.policy.RetryInterval)
+		}
+		// 部分写入时只重试剩余部分
+		var m int
+		m, err = w.output.Write(p[n:])
+		n += m
+	}
+	if err == nil {
+		return len(p), nil
+	}
+
+	w.errors.Add(1)
+	reportInternalError("writer", err)
+	if w.policy.OnWriteError != nil {
+		w.policy.OnWriteError(err, p[n:])
+	}
+	if w.writeDeadLetter(p[n:]) {
+		w.deadLettered.Add(1)
+	} else {
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// writeDeadLetter 写入死信文件
+func (w *ErrorPolicyWriter) writeDeadLetter(p []byte) bool {
+	if w.policy.DeadLetterPath == "" {
+		return false
+	}
+
+	w.deadMu.Lock()
+	defer w.deadMu.Unlock()
+
+	if w.deadLetter == nil {
+		f, err := os.OpenFile(w.policy.DeadLetterPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, DefaultFilePermission)
+		if err != nil {
+			return false
+		}
+		w.deadLetter = f
+	}
+	_, err := w.deadLetter.Write(p)
+	return err == nil
+}
+
+// Stats 返回写入失败统计
+func (w *ErrorPolicyWriter) Stats() WriteErrorStats {
+	return WriteErrorStats{
+		Errors:       w.errors.Load(),
+		Retries:      w.retries.Load(),
+		DeadLettered: w.deadLettered.Load(),
+		Dropped:      w.dropped.Load(),
+	}
+}
+
+// Unwrap 返回被包装的输出
+func (w *ErrorPolicyWriter) Unwrap() io.Writer {
+	return w.output
+}
+
+// Close 关闭死信文件（不关闭被包装的输出）
+func (w *ErrorPolicyWriter) Close() error {
+	w.deadMu.Lock()
+	defer w.deadMu.Unlock()
+	if w.deadLetter == nil {
+		return nil
+	}
+	err := w.deadLetter.Close()
+	w.deadLetter = nil
+	return err
+}
+
+// WithWriteErrorPolicy 为当前输出设置写入失败处理策略（在 WithOutput 之后调用）
+func (l *Logger) WithWriteErrorPolicy(policy WriteErrorPolicy) *Logger {
+	output := l.output
+	if pw, ok := output.(*ErrorPolicyWriter); ok {
+		output = pw.Unwrap()
+	}
+	return l.WithOutput(NewErrorPolicyWriter(output, policy))
+}
+
+// WriteErrorStats 返回输出的写入失败统计（未设置策略时为零值）
+func (l *Logger) WriteErrorStats() WriteErrorStats {
+	if pw, ok := l.output.(*ErrorPolicyWriter); ok {
+		return pw.Stats()
+	}
+	return WriteErrorStats{}
+}
@@ -29,6 +29,7 @@ type baseWriter struct {
 	permission os.FileMode   // 文件权限（适用于文件类输出器）
 	maxAge     time.Duration // 最大保留时间（适用于轮转输出器）
 	compress   bool          // 是否压缩旧文件（适用于轮转输出器）
+	durability fileSync      // 落盘策略（适用于文件类输出器）
 	mutex      sync.RWMutex  // 读写锁保护并发访问
 }
 
@@ -38,6 +39,10 @@ type writerStats struct {
 	linesWritten int64        // 已写入行数（atomic 计数器）
 	errorCount   int64        // 错误次数（atomic 计数器）
 	lastWrite    int64        // 最后写入时间（atomic unix nano）
+	syncCount    int64        // fsync 次数（atomic 计数器）
+	syncNanos    int64        // fsync 累计耗时（atomic 纳秒）
+	syncMaxNanos int64        // fsync 最大耗时（atomic 纳秒）
+	lastSync     int64        // 最近一次 fsync 耗时（atomic 纳秒）
 	startTime    time.Time    // 启动时间（不可变）
 	mu           sync.RWMutex // 保护同步操作的锁
 }
@@ -56,6 +61,20 @@ func (ws *writerStats) addBytes(bytes int64) {
 	atomic.StoreInt64(&ws.lastWrite, time.Now().UnixNano())
 }
 
+// addSync 记录一次 fsync 耗时
+func (ws *writerStats) addSync(d time.Duration) {
+	nanos := int64(d)
+	atomic.AddInt64(&ws.syncCount, 1)
+	atomic.AddInt64(&ws.syncNanos, nanos)
+	atomic.StoreInt64(&ws.lastSync, nanos)
+	for {
+		prev := atomic.LoadInt64(&ws.syncMaxNanos)
+		if nanos <= prev || atomic.CompareAndSwapInt64(&ws.syncMaxNanos, prev, nanos) {
+			return
+		}
+	}
+}
+
 // addError 增加错误统计（使用 atomic 快速更新）
 func (ws *writerStats) addError() {
 	atomic.AddInt64(&ws.errorCount, 1)
@@ -69,6 +88,11 @@ type WriterStatsSnapshot struct {
 	LastWrite    time.Time     `json:"last_write"`    // 最后一次写入时间
 	StartTime    time.Time     `json:"start_time"`    // 输出器启动时间
 	Uptime       time.Duration `json:"uptime"`        // 运行时长
+
+	SyncCount       int64         `json:"sync_count"`        // fsync 次数
+	SyncLatencyAvg  time.Duration `json:"sync_latency_avg"`  // fsync 平均耗时
+	SyncLatencyMax  time.Duration `json:"sync_latency_max"`  // fsync 最大耗时
+	LastSyncLatency time.Duration `json:"last_sync_latency"` // 最近一次 fsync 耗时
 }
 
 // getSnapshot 获取统计信息快照
@@ -83,13 +107,23 @@ func (ws *writerStats) getSnapshot() WriterStatsSnapshot {
 		lastWrite = time.Unix(0, lastWriteNano)
 	}
 
+	var syncAvg time.Duration
+	syncCount := atomic.LoadInt64(&ws.syncCount)
+	if syncCount > 0 {
+		syncAvg = time.Duration(atomic.LoadInt64(&ws.syncNanos) / syncCount)
+	}
+
 	return WriterStatsSnapshot{
-		BytesWritten: bytesWritten,
-		LinesWritten: linesWritten,
-		ErrorCount:   errorCount,
-		LastWrite:    lastWrite,
-		StartTime:    ws.startTime,
-		Uptime:       time.Since(ws.startTime),
+		BytesWritten:    bytesWritten,
+		LinesWritten:    linesWritten,
+		ErrorCount:      errorCount,
+		LastWrite:       lastWrite,
+		StartTime:       ws.startTime,
+		Uptime:          time.Since(ws.startTime),
+		SyncCount:       syncCount,
+		SyncLatencyAvg:  syncAvg,
+		SyncLatencyMax:  time.Duration(atomic.LoadInt64(&ws.syncMaxNanos)),
+		LastSyncLatency: time.Duration(atomic.LoadInt64(&ws.lastSync)),
 	}
 }
 
@@ -166,6 +200,24 @@ func (w *consoleLogWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// WriteVectors 批量写入多个缓冲区（一次加锁）
+func (w *consoleLogWriter) WriteVectors(bufs [][]byte) (int64, error) {
+	if atomic.LoadInt32(&w.healthyAtomic) == 0 {
+		return 0, fmt.Errorf("console writer is not healthy")
+	}
+
+	w.mutex.Lock()
+	n, err := WriteVectors(w.output, bufs)
+	w.mutex.Unlock()
+
+	if err != nil {
+		w.stats.addError()
+		return n, err
+	}
+	w.stats.addBytes(n)
+	return n, nil
+}
+
 // WriteLevel 按级别写入
 func (w *consoleLogWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
 	if level < w.level {
@@ -207,12 +259,17 @@ func (w *consoleLogWriter) GetStats() WriterStatsSnapshot {
 
 // FileLogWriter 文件输出器（支持可配置缓冲区大小）
 type FileLogWriter struct {
-	baseWriter                  // 继承基础输出器字段
-	filePath      string        // 日志文件路径
-	file          *os.File      // 文件句柄
-	buffer        *bufio.Writer // 内部创建的缓冲区
-	bufferSize    int           // 缓冲区大小（字节，默认 64KB）
-	healthyAtomic int32         // 健康状态（atomic bool: 0=false, 1=true）
+	baseWriter                     // 继承基础输出器字段
+	filePath      string           // 日志文件路径
+	file          *os.File         // 文件句柄
+	buffer        *bufio.Writer    // 内部创建的缓冲区
+	bufferSize    int              // 缓冲区大小（字节，默认 64KB）
+	healthyAtomic int32            // 健康状态（atomic bool: 0=false, 1=true）
+	keyProvider   KeyProvider      // 加密密钥提供者（为 nil 时明文写入）
+	encryptor     *recordEncryptor // 记录加密器（首次打开文件时创建）
+	sealBuf       []byte           // 加密记录复用缓冲
+	index         *fileIndex       // 偏移量索引（WithFileIndex 开启时使用）
+	shareMode     FileShareMode    // 多进程共享方式
 }
 
 // FileWriterOption 文件输出器配置选项
@@ -263,6 +320,7 @@ func NewFileWriter(opts ...FileWriterOption) IWriter {
 	for _, opt := range opts {
 		opt(w)
 	}
+	w.durability.start(w.Flush)
 
 	return w
 }
@@ -273,6 +331,14 @@ func (w *FileLogWriter) ensureFile() error {
 		return nil
 	}
 
+	if w.keyProvider != nil && w.encryptor == nil {
+		encryptor, err := newRecordEncryptor(w.keyProvider)
+		if err != nil {
+			return err
+		}
+		w.encryptor = encryptor
+	}
+
 	// 创建目录
 	dir := filepath.Dir(w.filePath)
 	if err := os.MkdirAll(dir, DefaultDirPermission); err != nil {
@@ -285,6 +351,20 @@ func (w *FileLogWriter) ensureFile() error {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 
+	if w.index != nil {
+		if err := w.index.open(w.filePath, file, w.permission); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if w.encryptor != nil {
+		if err := w.encryptor.newStream(); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
 	w.file = file
 	w.buffer = bufio.NewWriterSize(file, w.bufferSize)
 	w.healthy = true
@@ -294,6 +374,11 @@ func (w *FileLogWriter) ensureFile() error {
 
 // Write 实现io.Writer接口（写入缓冲区）
 func (w *FileLogWriter) Write(p []byte) (n int, err error) {
+	return w.write(DEBUG, false, p)
+}
+
+// write 写入缓冲区，leveled 为 true 时索引记录级别
+func (w *FileLogWriter) write(level LogLevel, leveled bool, p []byte) (n int, err error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
@@ -302,7 +387,20 @@ func (w *FileLogWriter) Write(p []byte) (n int, err error) {
 		return 0, err
 	}
 
-	n, err = w.buffer.Write(p)
+	if w.index != nil {
+		w.index.mark(time.Now(), level, leveled)
+	}
+	if w.encryptor != nil {
+		n, err = w.writeEncrypted(p)
+	} else {
+		n, err = w.writeRecord(p)
+		if w.index != nil {
+			w.index.advance(n)
+		}
+	}
+	if err == nil && w.durability.written() {
+		err = syncFile(w.stats, w.buffer, w.file)
+	}
 	if err != nil {
 		w.stats.addError()
 		w.healthy = false
@@ -314,12 +412,74 @@ func (w *FileLogWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// WriteVectors 批量写入多个缓冲区
+// 缓冲区放得下时写入缓冲，否则先刷新缓冲再以 writev 直接写入文件
+func (w *FileLogWriter) WriteVectors(bufs [][]byte) (int64, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.ensureFile(); err != nil {
+		w.stats.addError()
+		return 0, err
+	}
+
+	total := 0
+	for _, b := range bufs {
+		total += len(b)
+	}
+
+	if w.index != nil {
+		w.index.mark(time.Now(), DEBUG, false)
+	}
+	var n int64
+	var err error
+	if w.encryptor != nil {
+		for _, b := range bufs {
+			written, werr := w.writeEncrypted(b)
+			n += int64(written)
+			if werr != nil {
+				err = werr
+				break
+			}
+		}
+	} else if w.shareMode != FileShareNone {
+		n, err = writeMerged(writerFunc(w.writeRecord), bufs)
+	} else if total <= w.buffer.Available() {
+		for _, b := range bufs {
+			written, werr := w.buffer.Write(b)
+			n += int64(written)
+			if werr != nil {
+				err = werr
+				break
+			}
+		}
+	} else if err = w.buffer.Flush(); err == nil {
+		n, err = writevFile(w.file, bufs)
+	}
+	if w.index != nil && w.encryptor == nil {
+		w.index.advance(int(n))
+	}
+	if err == nil && w.durability.written() {
+		err = syncFile(w.stats, w.buffer, w.file)
+	}
+
+	if err != nil {
+		w.stats.addError()
+		w.healthy = false
+		atomic.StoreInt32(&w.healthyAtomic, 0)
+		return n, err
+	}
+
+	w.stats.addBytes(n)
+	return n, nil
+}
+
 // WriteLevel 按级别写入
 func (w *FileLogWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
 	if level < w.level {
 		return len(data), nil
 	}
-	return w.Write(data)
+	return w.write(level, true, data)
 }
 
 // Flush 刷新文件缓冲区（先刷新缓冲再同步文件）
@@ -327,19 +487,13 @@ func (w *FileLogWriter) Flush() error {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	if w.buffer != nil {
-		if err := w.buffer.Flush(); err != nil {
-			return err
-		}
-	}
-	if w.file != nil {
-		return w.file.Sync()
-	}
-	return nil
+	return syncFile(w.stats, w.buffer, w.file)
 }
 
 // Close 关闭文件（确保缓冲刷新）
 func (w *FileLogWriter) Close() error {
+	w.durability.close()
+
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
@@ -351,6 +505,10 @@ func (w *FileLogWriter) Close() error {
 		w.buffer = nil
 	}
 
+	if w.index != nil {
+		w.index.close()
+	}
+
 	if w.file != nil {
 		err := w.file.Close()
 		w.file = nil
@@ -371,15 +529,33 @@ func (w *FileLogWriter) GetStats() WriterStatsSnapshot {
 
 // RotateLogWriter 轮转文件输出器（支持按大小自动轮转，支持可配置缓冲区）
 type RotateLogWriter struct {
-	baseWriter                  // 继承基础输出器字段
-	filePath      string        // 日志文件路径
-	maxSize       int64         // 单个文件最大字节数（超过后轮转）
-	maxFiles      int           // 最大保留文件数（旧文件会被删除）
-	currentFile   *os.File      // 当前文件句柄
-	currentSize   int64         // 当前文件已写入字节数
-	buffer        *bufio.Writer // 内部创建的缓冲区
-	bufferSize    int           // 缓冲区大小（字节，默认 64KB）
-	healthyAtomic int32         // 健康状态（atomic bool: 0=false, 1=true）
+	baseWriter                   // 继承基础输出器字段
+	filePath      string         // 日志文件路径
+	maxSize       int64          // 单个文件最大字节数（超过后轮转）
+	maxFiles      int            // 最大保留文件数（旧文件会被删除）
+	currentFile   *os.File       // 当前文件句柄
+	currentSize   int64          // 当前文件已写入字节数
+	buffer        *bufio.Writer  // 内部创建的缓冲区
+	bufferSize    int            // 缓冲区大小（字节，默认 64KB）
+	healthyAtomic int32          // 健康状态（atomic bool: 0=false, 1=true）
+	codec         string         // 轮转文件压缩算法（compress 开启时生效，默认 gzip）
+	compressLevel int            // 压缩级别
+	archiving     sync.WaitGroup // 进行中的后台归档任务（序号调整、压缩、校验和与后置钩子）
+	lastArchive   chan struct{}  // 最近一次归档任务完成时关闭，后一任务等待前一任务，保证按轮转顺序编号
+	checksum      bool           // 是否为轮转文件生成 SHA256 校验和文件
+	onArchived    []RotateHook   // 轮转文件压缩与生成校验和之后执行的钩子
+	configErr     error          // 配置错误（如压缩算法未注册），非空时拒绝所有写入
+}
+
+// RotateHook 轮转后置钩子，参数为最终归档文件路径（压缩后的文件或原始轮转文件）
+// 在后台归档任务中同步执行，不阻塞写入；之后轮转出的文件在其完成后才调整序号，Close 会等待其完成，因此执行期间文件不会被重命名
+type RotateHook func(path string)
+
+// WithRotateHook 追加轮转后置钩子（如 Archiver.Hook 上传到对象存储）
+func WithRotateHook(hooks ...RotateHook) RotateWriterOption {
+	return func(w *RotateLogWriter) {
+		w.onArchived = append(w.onArchived, hooks...)
+	}
 }
 
 // RotateWriterOption 轮转文件输出器配置选项
@@ -427,6 +603,16 @@ func WithCompress(compress bool) RotateWriterOption {
 	}
 }
 
+// WithCompression 设置旧文件压缩算法与级别（同时开启压缩）
+// 除 gzip 外的算法须先通过 RegisterCompressor 注册，否则输出器拒绝写入并返回 ErrCompressorNotRegistered
+func WithCompression(codec string, level int) RotateWriterOption {
+	return func(w *RotateLogWriter) {
+		w.compress = true
+		w.codec = codec
+		w.compressLevel = level
+	}
+}
+
 // WithRotatePermission 设置文件权限
 func WithRotatePermission(permission os.FileMode) RotateWriterOption {
 	return func(w *RotateLogWriter) {
@@ -454,14 +640,24 @@ func NewRotateWriter(opts ...RotateWriterOption) IWriter {
 			maxAge:     DefaultMaxAge,
 			compress:   false,
 		},
-		maxSize:    DefaultMaxSize,
-		maxFiles:   DefaultMaxFiles,
-		bufferSize: 64 * 1024, // 默认 64KB
+		maxSize:       DefaultMaxSize,
+		maxFiles:      DefaultMaxFiles,
+		bufferSize:    64 * 1024, // 默认 64KB
+		codec:         CompressionGzip,
+		compressLevel: CompressionDefault,
 	}
 
 	for _, opt := range opts {
 		opt(w)
 	}
+	if w.compress {
+		if _, err := getCompressor(w.codec); err != nil {
+			// 不静默降级为不压缩：记录配置错误，之后每次写入都返回该错误
+			w.configErr = err
+			reportInternalError("writer", err)
+		}
+	}
+	w.durability.start(w.Flush)
 
 	return w
 }
@@ -471,7 +667,12 @@ func (w *RotateLogWriter) shouldRotate(dataSize int) bool {
 	return w.currentSize+int64(dataSize) > w.maxSize
 }
 
+// pendingRotateSuffix 等待后台归档任务编号的轮转文件后缀（app.log.<纳秒时间戳>.rotating）
+const pendingRotateSuffix = ".rotating"
+
 // rotate 执行文件轮转（刷新缓冲后轮转）
+// 需要压缩、校验和或后置钩子时，当前文件先移到待归档路径，序号调整与归档由后台任务按轮转顺序执行，
+// 写入不等待压缩与钩子（如上传对象存储）完成
 func (w *RotateLogWriter) rotate() error {
 	// 刷新并关闭缓冲
 	if w.buffer != nil {
@@ -483,7 +684,28 @@ func (w *RotateLogWriter) rotate() error {
 		w.currentFile = nil
 	}
 
-	// 重命名现有文件
+	ext := w.compressExt()
+	if ext == "" && !w.checksum && len(w.onArchived) == 0 {
+		// 无后台任务，直接调整序号并移动当前文件
+		w.shiftRotated("")
+		if _, err := os.Stat(w.filePath); err == nil {
+			os.Rename(w.filePath, w.filePath+".1")
+		}
+	} else if _, err := os.Stat(w.filePath); err == nil {
+		pending := fmt.Sprintf("%s.%d%s", w.filePath, time.Now().UnixNano(), pendingRotateSuffix)
+		if err := os.Rename(w.filePath, pending); err == nil {
+			w.enqueueArchive(pending, ext)
+		}
+	}
+
+	// 重置大小
+	w.currentSize = 0
+
+	return w.ensureFile()
+}
+
+// shiftRotated 已轮转文件（含压缩文件与校验和文件）序号依次加一，空出 .1
+func (w *RotateLogWriter) shiftRotated(ext string) {
 	for i := w.maxFiles - 1; i > 0; i-- {
 		oldPath := fmt.Sprintf("%s.%d", w.filePath, i)
 		newPath := fmt.Sprintf("%s.%d", w.filePath, i+1)
@@ -491,21 +713,81 @@ func (w *RotateLogWriter) rotate() error {
 		if _, err := os.Stat(oldPath); err == nil {
 			os.Rename(oldPath, newPath)
 		}
+		if ext != "" {
+			if _, err := os.Stat(oldPath + ext); err == nil {
+				os.Rename(oldPath+ext, newPath+ext)
+			}
+		}
+		if w.checksum {
+			if _, err := os.Stat(oldPath + ext + ChecksumExt); err == nil {
+				moveChecksumFile(oldPath+ext, newPath+ext)
+			}
+		}
 	}
+}
 
-	// 移动当前文件
-	if _, err := os.Stat(w.filePath); err == nil {
-		os.Rename(w.filePath, w.filePath+".1")
-	}
+// enqueueArchive 启动后台归档任务（调用方持有写锁）：等待前一任务完成后调整序号，
+// 将待归档文件移为 .1，再压缩、生成校验和并执行后置钩子
+func (w *RotateLogWriter) enqueueArchive(pending, ext string) {
+	prev, done := w.lastArchive, make(chan struct{})
+	w.lastArchive = done
+	w.archiving.Add(1)
+	go func() {
+		defer w.archiving.Done()
+		defer close(done)
+		if prev != nil {
+			<-prev
+		}
 
-	// 重置大小
-	w.currentSize = 0
+		w.shiftRotated(ext)
+		rotated := w.filePath + ".1"
+		if err := os.Rename(pending, rotated); err != nil {
+			w.stats.addError()
+			reportInternalError("rotate", err)
+			return
+		}
+		w.archive(rotated, ext)
+	}()
+}
 
-	return w.ensureFile()
+// archive 压缩轮转后的文件、生成校验和文件并执行后置钩子（后台执行）
+func (w *RotateLogWriter) archive(rotated, ext string) {
+	if ext != "" {
+		if err := compressFile(rotated, rotated+ext, w.codec, w.compressLevel, w.permission); err != nil {
+			w.stats.addError()
+			reportInternalError("compress", err)
+			return
+		}
+		rotated += ext
+	}
+	if w.checksum {
+		if _, err := WriteChecksumFile(rotated); err != nil {
+			w.stats.addError()
+			reportInternalError("checksum", err)
+		}
+	}
+	for _, hook := range w.onArchived {
+		hook(rotated)
+	}
+}
+
+// compressExt 获取压缩文件扩展名（未开启压缩或算法未注册时为空）
+func (w *RotateLogWriter) compressExt() string {
+	if !w.compress {
+		return ""
+	}
+	entry, err := getCompressor(w.codec)
+	if err != nil {
+		return ""
+	}
+	return entry.ext
 }
 
 // ensureFile 确保文件已打开（添加缓冲层）
 func (w *RotateLogWriter) ensureFile() error {
+	if w.configErr != nil {
+		return w.configErr
+	}
 	if w.currentFile != nil && w.buffer != nil {
 		return nil
 	}
@@ -551,6 +833,9 @@ func (w *RotateLogWriter) Write(p []byte) (n int, err error) {
 	}
 
 	n, err = w.buffer.Write(p)
+	if err == nil && w.durability.written() {
+		err = syncFile(w.stats, w.buffer, w.currentFile)
+	}
 	if err != nil {
 		w.stats.addError()
 		w.healthy = false
@@ -576,19 +861,13 @@ func (w *RotateLogWriter) Flush() error {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	if w.buffer != nil {
-		if err := w.buffer.Flush(); err != nil {
-			return err
-		}
-	}
-	if w.currentFile != nil {
-		return w.currentFile.Sync()
-	}
-	return nil
+	return syncFile(w.stats, w.buffer, w.currentFile)
 }
 
 // Close 关闭输出器（确保缓冲刷新）
 func (w *RotateLogWriter) Close() error {
+	w.durability.close()
+
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
@@ -600,6 +879,9 @@ func (w *RotateLogWriter) Close() error {
 		w.buffer = nil
 	}
 
+	// 等待后台归档任务完成，避免进程退出时留下未压缩或半成品文件
+	w.archiving.Wait()
+
 	if w.currentFile != nil {
 		err := w.currentFile.Close()
 		w.currentFile = nil
@@ -692,6 +974,29 @@ func (w *BufferedWriter) Write(p []byte) (n int, err error) {
 	return n, nil
 }
 
+// WriteVectors 批量写入多个缓冲区（一次加锁）
+func (w *BufferedWriter) WriteVectors(bufs [][]byte) (int64, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.healthy {
+		return 0, fmt.Errorf("buffered writer is not healthy")
+	}
+
+	var total int64
+	for _, b := range bufs {
+		n, err := w.buffer.Write(b)
+		total += int64(n)
+		if err != nil {
+			w.stats.addError()
+			w.healthy = false
+			return total, err
+		}
+	}
+	w.stats.addBytes(total)
+	return total, nil
+}
+
 // WriteLevel 按级别写入
 func (w *BufferedWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
 	if level < w.level {
@@ -804,6 +1109,36 @@ func (w *MultiLogWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// WriteVectors 批量写入多个缓冲区（每个输出器各写一次）
+func (w *MultiLogWriter) WriteVectors(bufs [][]byte) (int64, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var total int64
+	for _, b := range bufs {
+		total += int64(len(b))
+	}
+
+	var lastErr error
+	for _, writer := range w.writers {
+		if !writer.IsHealthy() {
+			continue
+		}
+		// WriteVectors 可能推进 bufs[0]，每个输出器使用独立的切片头
+		vecs := append([][]byte(nil), bufs...)
+		if _, err := WriteVectors(writer, vecs); err != nil {
+			lastErr = err
+			w.stats.addError()
+		}
+	}
+
+	if lastErr != nil {
+		return 0, lastErr
+	}
+	w.stats.addBytes(total)
+	return total, nil
+}
+
 // WriteLevel 按级别写入
 func (w *MultiLogWriter) WriteLevel(level LogLevel, data []byte) (n int, err error) {
 	if level < w.level {
@@ -0,0 +1,126 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writer_test.go
+ * @Description: 轮转文件输出器测试（后台归档任务与后置钩子）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readRotated 读取轮转文件内容（.gz 文件解压后返回）
+func readRotated(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if filepath.Ext(path) == ".gz" {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			t.Fatalf("gzip %s: %v", path, err)
+		}
+		r = zr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+// TestRotateSlowHook 后置钩子阻塞时写入与轮转不被阻塞，钩子完成后文件按轮转顺序编号，钩子执行期间文件不被重命名
+func TestRotateSlowHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []RotateWriterOption
+		ext      string
+		checksum bool
+	}{
+		{name: "hook only"},
+		{name: "gzip and checksum", opts: []RotateWriterOption{WithCompress(true), WithRotateChecksum(true)}, ext: ".gz", checksum: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "app.log")
+			release := make(chan struct{})
+			var (
+				mu    sync.Mutex
+				calls []string
+			)
+			hook := func(p string) {
+				// 钩子执行期间文件应保持在该路径
+				content := readRotated(t, p)
+				<-release
+				if got := readRotated(t, p); got != content {
+					t.Errorf("%s changed while the hook was running", p)
+				}
+				mu.Lock()
+				calls = append(calls, content)
+				mu.Unlock()
+			}
+			opts := append([]RotateWriterOption{WithFilePath(path), WithMaxSize(16), WithRotateHook(hook)}, tt.opts...)
+			w := NewRotateWriter(opts...)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 1; i <= 7; i++ {
+					if _, err := fmt.Fprintf(w, "line-%02d\n", i); err != nil {
+						t.Errorf("write %d: %v", i, err)
+					}
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				close(release)
+				t.Fatal("writes blocked behind a slow rotate hook")
+			}
+
+			close(release)
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			want := map[string]string{
+				path:                 "line-07\n",
+				path + ".1" + tt.ext: "line-05\nline-06\n",
+				path + ".2" + tt.ext: "line-03\nline-04\n",
+				path + ".3" + tt.ext: "line-01\nline-02\n",
+			}
+			for p, content := range want {
+				if got := readRotated(t, p); got != content {
+					t.Errorf("%s = %q, want %q", filepath.Base(p), got, content)
+				}
+				if tt.checksum && p != path {
+					if _, err := os.Stat(p + ChecksumExt); err != nil {
+						t.Errorf("missing checksum for %s", filepath.Base(p))
+					}
+				}
+			}
+			if pending, _ := filepath.Glob(path + ".*" + pendingRotateSuffix); len(pending) != 0 {
+				t.Errorf("pending files left after Close: %v", pending)
+			}
+			wantCalls := []string{"line-01\nline-02\n", "line-03\nline-04\n", "line-05\nline-06\n"}
+			if fmt.Sprint(calls) != fmt.Sprint(wantCalls) {
+				t.Errorf("hook calls = %q, want %q", calls, wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writev.go
+ * @Description: 向量化批量写入（writev），一次系统调用写出多条日志
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"net"
+	"os"
+)
+
+// maxWriteVectors 单次 writev 的最大缓冲区数量（对应 IOV_MAX）
+const maxWriteVectors = 1024
+
+// IVectorWriter 支持一次写入多个缓冲区的写入器
+type IVectorWriter interface {
+	WriteVectors(bufs [][]byte) (int64, error)
+}
+
+// WriteVectors 将多个缓冲区批量写入 w
+// 文件使用 writev（启用 iouring 构建标签时使用 io_uring），网络连接使用 net.Buffers，
+// 其他写入器合并为一次 Write 调用
+func WriteVectors(w io.Writer, bufs [][]byte) (int64, error) {
+	switch out := w.(type) {
+	case IVectorWriter:
+		return out.WriteVectors(bufs)
+	case *os.File:
+		return writevFile(out, bufs)
+	case net.Conn:
+		nb := net.Buffers(bufs)
+		return nb.WriteTo(out)
+	}
+	return writeMerged(w, bufs)
+}
+
+// supportsVectors 检查写入器能否从向量化写入中获益
+func supportsVectors(w io.Writer) bool {
+	switch w.(type) {
+	case IVectorWriter, *os.File, net.Conn:
+		return true
+	}
+	return false
+}
+
+// writeMerged 合并缓冲区后一次写出
+func writeMerged(w io.Writer, bufs [][]byte) (int64, error) {
+	total := 0
+	for _, b := range bufs {
+		total += len(b)
+	}
+
+	bp := bytePool.Get().(*[]byte)
+	buf := (*bp)[:0]
+	for _, b := range bufs {
+		buf = append(buf, b...)
+	}
+	n, err := w.Write(buf)
+	putLineBuf(bp, buf)
+
+	if err == nil && n < total {
+		err = io.ErrShortWrite
+	}
+	return int64(n), err
+}
+
+// nonEmptyVectors 复制切片头并去掉长度为 0 的缓冲区（全部为空的批次 writev 返回 0，不去掉会导致循环无法推进）
+func nonEmptyVectors(bufs [][]byte) [][]byte {
+	pending := make([][]byte, 0, len(bufs))
+	for _, b := range bufs {
+		if len(b) > 0 {
+			pending = append(pending, b)
+		}
+	}
+	return pending
+}
+
+// advanceVectors 跳过已写入的 n 字节，返回剩余缓冲区
+func advanceVectors(bufs [][]byte, n int) [][]byte {
+	for len(bufs) > 0 && n >= len(bufs[0]) {
+		n -= len(bufs[0])
+		bufs = bufs[1:]
+	}
+	if len(bufs) > 0 && n > 0 {
+		bufs[0] = bufs[0][n:]
+	}
+	return bufs
+}
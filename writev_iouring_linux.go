@@ -0,0 +1,281 @@
+//go:build linux && iouring
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writev_iouring_linux.go
+ * @Description: 实验性 io_uring 批量写入后端（go build -tags iouring 启用）
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioUringEntries       = 8          // 提交队列深度（同一时刻只有一个请求在途）
+	ioUringOpWritev      = 2          // IORING_OP_WRITEV
+	ioUringEnterGetEvent = 1          // IORING_ENTER_GETEVENTS
+	ioUringFeatSingleMap = 1          // IORING_FEAT_SINGLE_MMAP
+	ioUringOffSQRing     = 0          // IORING_OFF_SQ_RING
+	ioUringOffCQRing     = 0x8000000  // IORING_OFF_CQ_RING
+	ioUringOffSQEs       = 0x10000000 // IORING_OFF_SQES
+)
+
+// ioUringParams 对应 struct io_uring_params
+type ioUringParams struct {
+	sqEntries    uint32
+	cqEntries    uint32
+	flags        uint32
+	sqThreadCPU  uint32
+	sqThreadIdle uint32
+	features     uint32
+	wqFd         uint32
+	resv         [3]uint32
+	sqOff        ioSQRingOffsets
+	cqOff        ioCQRingOffsets
+}
+
+// ioSQRingOffsets 对应 struct io_sqring_offsets
+type ioSQRingOffsets struct {
+	head, tail, ringMask, ringEntries, flags, dropped, array, resv1 uint32
+	userAddr                                                        uint64
+}
+
+// ioCQRingOffsets 对应 struct io_cqring_offsets
+type ioCQRingOffsets struct {
+	head, tail, ringMask, ringEntries, overflow, cqes, flags, resv1 uint32
+	userAddr                                                        uint64
+}
+
+// ioUringSQE 对应 struct io_uring_sqe（64 字节）
+type ioUringSQE struct {
+	opcode      uint8
+	flags       uint8
+	ioprio      uint16
+	fd          int32
+	off         uint64
+	addr        uint64
+	len         uint32
+	rwFlags     uint32
+	userData    uint64
+	bufIndex    uint16
+	personality uint16
+	spliceFdIn  int32
+	addr3       uint64
+	pad         uint64
+}
+
+// ioUringCQE 对应 struct io_uring_cqe（16 字节）
+type ioUringCQE struct {
+	userData uint64
+	res      int32
+	flags    uint32
+}
+
+// ioUring 最小化的 io_uring 实例，只支持串行提交 WRITEV
+type ioUring struct {
+	mu     sync.Mutex
+	fd     int
+	sqRing []byte
+	cqRing []byte
+	sqes   []byte
+
+	sqTail  *uint32
+	sqMask  uint32
+	sqArray unsafe.Pointer
+	cqHead  *uint32
+	cqTail  *uint32
+	cqMask  uint32
+	cqes    unsafe.Pointer
+
+	iovecs []unix.Iovec
+}
+
+var (
+	ioUringOnce     sync.Once
+	ioUringInstance *ioUring
+)
+
+// getIOUring 获取全局 io_uring 实例，内核不支持时返回 nil
+func getIOUring() *ioUring {
+	ioUringOnce.Do(func() {
+		ring, err := newIOUring(ioUringEntries)
+		if err == nil {
+			ioUringInstance = ring
+		}
+	})
+	return ioUringInstance
+}
+
+// newIOUring 创建并映射 io_uring 队列
+func newIOUring(entries uint32) (*ioUring, error) {
+	var p ioUringParams
+	fd, _, errno := unix.Syscall(unix.SYS_IO_URING_SETUP, uintptr(entries), uintptr(unsafe.Pointer(&p)), 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	ring := &ioUring{fd: int(fd)}
+
+	sqSize := int(p.sqOff.array + p.sqEntries*4)
+	cqSize := int(p.cqOff.cqes + p.cqEntries*uint32(unsafe.Sizeof(ioUringCQE{})))
+	singleMap := p.features&ioUringFeatSingleMap != 0
+	if singleMap && cqSize > sqSize {
+		sqSize = cqSize
+	}
+
+	var err error
+	if ring.sqRing, err = unix.Mmap(ring.fd, ioUringOffSQRing, sqSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE); err != nil {
+		ring.close()
+		return nil, err
+	}
+	if singleMap {
+		ring.cqRing = ring.sqRing
+	} else if ring.cqRing, err = unix.Mmap(ring.fd, ioUringOffCQRing, cqSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE); err != nil {
+		ring.close()
+		return nil, err
+	}
+	sqesSize := int(p.sqEntries) * int(unsafe.Sizeof(ioUringSQE{}))
+	if ring.sqes, err = unix.Mmap(ring.fd, ioUringOffSQEs, sqesSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED|unix.MAP_POPULATE); err != nil {
+		ring.close()
+		return nil, err
+	}
+
+	ring.sqTail = (*uint32)(unsafe.Pointer(&ring.sqRing[p.sqOff.tail]))
+	ring.sqMask = *(*uint32)(unsafe.Pointer(&ring.sqRing[p.sqOff.ringMask]))
+	ring.sqArray = unsafe.Pointer(&ring.sqRing[p.sqOff.array])
+	ring.cqHead = (*uint32)(unsafe.Pointer(&ring.cqRing[p.cqOff.head]))
+	ring.cqTail = (*uint32)(unsafe.Pointer(&ring.cqRing[p.cqOff.tail]))
+	ring.cqMask = *(*uint32)(unsafe.Pointer(&ring.cqRing[p.cqOff.ringMask]))
+	ring.cqes = unsafe.Pointer(&ring.cqRing[p.cqOff.cqes])
+	return ring, nil
+}
+
+// close 释放映射和文件描述符
+func (r *ioUring) close() {
+	if r.sqes != nil {
+		unix.Munmap(r.sqes)
+	}
+	if r.cqRing != nil && len(r.cqRing) > 0 && &r.cqRing[0] != &r.sqRing[0] {
+		unix.Munmap(r.cqRing)
+	}
+	if r.sqRing != nil {
+		unix.Munmap(r.sqRing)
+	}
+	unix.Close(r.fd)
+}
+
+// writev 提交一个 WRITEV 请求并等待完成，返回写入字节数
+func (r *ioUring) writev(fd int, bufs [][]byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.iovecs = r.iovecs[:0]
+	for _, b := range bufs {
+		if len(b) == 0 {
+			continue
+		}
+		iov := unix.Iovec{Base: &b[0]}
+		iov.SetLen(len(b))
+		r.iovecs = append(r.iovecs, iov)
+	}
+	if len(r.iovecs) == 0 {
+		return 0, nil
+	}
+
+	tail := atomic.LoadUint32(r.sqTail)
+	idx := tail & r.sqMask
+	sqe := (*ioUringSQE)(unsafe.Pointer(&r.sqes[uintptr(idx)*unsafe.Sizeof(ioUringSQE{})]))
+	*sqe = ioUringSQE{
+		opcode: ioUringOpWritev,
+		fd:     int32(fd),
+		off:    ^uint64(0), // -1：使用并推进文件当前偏移
+		addr:   uint64(uintptr(unsafe.Pointer(&r.iovecs[0]))),
+		len:    uint32(len(r.iovecs)),
+	}
+	*(*uint32)(unsafe.Add(r.sqArray, uintptr(idx)*4)) = idx
+	atomic.StoreUint32(r.sqTail, tail+1)
+
+	for {
+		_, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), 1, 1, ioUringEnterGetEvent, 0, 0)
+		if errno == unix.EINTR {
+			continue
+		}
+		if errno != 0 {
+			return 0, errno
+		}
+		break
+	}
+
+	head := atomic.LoadUint32(r.cqHead)
+	for head == atomic.LoadUint32(r.cqTail) {
+		if _, _, errno := unix.Syscall6(unix.SYS_IO_URING_ENTER, uintptr(r.fd), 0, 1, ioUringEnterGetEvent, 0, 0); errno != 0 && errno != unix.EINTR {
+			return 0, errno
+		}
+	}
+	cqe := (*ioUringCQE)(unsafe.Add(r.cqes, uintptr(head&r.cqMask)*unsafe.Sizeof(ioUringCQE{})))
+	res := cqe.res
+	atomic.StoreUint32(r.cqHead, head+1)
+
+	if res < 0 {
+		return 0, syscall.Errno(-res)
+	}
+	return int(res), nil
+}
+
+// writevFile 使用 io_uring 提交 WRITEV，内核不支持时退回 writev 系统调用
+func writevFile(f *os.File, bufs [][]byte) (int64, error) {
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		return writeMerged(f, bufs)
+	}
+	ring := getIOUring()
+
+	pending := nonEmptyVectors(bufs)
+	var written int64
+	var werr error
+
+	for len(pending) > 0 && werr == nil {
+		chunk := pending
+		if len(chunk) > maxWriteVectors {
+			chunk = chunk[:maxWriteVectors]
+		}
+
+		var n int
+		err := rawConn.Write(func(fd uintptr) bool {
+			if ring != nil {
+				n, werr = ring.writev(int(fd), chunk)
+			} else {
+				n, werr = unix.Writev(int(fd), chunk)
+			}
+			return werr != unix.EAGAIN
+		})
+		if err != nil && werr == nil {
+			werr = err
+		}
+		if n > 0 {
+			written += int64(n)
+			pending = advanceVectors(pending, n)
+		}
+		if werr == unix.EINTR {
+			werr = nil
+			continue
+		}
+		if n == 0 && werr == nil {
+			werr = io.ErrShortWrite
+		}
+	}
+
+	return written, werr
+}
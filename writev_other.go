@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writev_other.go
+ * @Description: 不支持 writev 的平台合并后一次写入
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import "os"
+
+// writevFile 合并缓冲区后一次写入文件
+func writevFile(f *os.File, bufs [][]byte) (int64, error) {
+	return writeMerged(f, bufs)
+}
@@ -0,0 +1,54 @@
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writev_test.go
+ * @Description: 向量化批量写入测试
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestWriteVectorsEmptyBuffers 含空缓冲区（或全部为空）的批次正常返回，不会在 writev 返回 0 时空转
+func TestWriteVectorsEmptyBuffers(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "writev.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cases := [][][]byte{
+		{{}, {}},
+		{[]byte("a\n"), {}, []byte("b\n"), {}},
+	}
+	for _, bufs := range cases {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if _, err := WriteVectors(f, bufs); err != nil {
+				t.Errorf("WriteVectors: %v", err)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("WriteVectors did not return")
+		}
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "a\nb\n" {
+		t.Errorf("got %q, want %q", data, "a\nb\n")
+	}
+}
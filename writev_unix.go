@@ -0,0 +1,62 @@
+//go:build (linux && !iouring) || darwin
+
+/*
+ * @Author: kamalyes 501893067@qq.com
+ * @Date: 2026-10-15 00:00:00
+ * @LastEditors: kamalyes 501893067@qq.com
+ * @LastEditTime: 2026-10-15 00:00:00
+ * @FilePath: \go-logger\writev_unix.go
+ * @Description: 基于 writev 系统调用的文件批量写入
+ *
+ * Copyright (c) 2026 by kamalyes, All Rights Reserved.
+ */
+package logger
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// writevFile 使用 writev 将多个缓冲区写入文件，自动处理部分写入和 IOV_MAX 限制
+func writevFile(f *os.File, bufs [][]byte) (int64, error) {
+	rawConn, err := f.SyscallConn()
+	if err != nil {
+		return writeMerged(f, bufs)
+	}
+
+	// 复制切片头，避免修改调用方的切片
+	pending := nonEmptyVectors(bufs)
+	var written int64
+	var werr error
+
+	for len(pending) > 0 && werr == nil {
+		chunk := pending
+		if len(chunk) > maxWriteVectors {
+			chunk = chunk[:maxWriteVectors]
+		}
+
+		var n int
+		err := rawConn.Write(func(fd uintptr) bool {
+			n, werr = unix.Writev(int(fd), chunk)
+			return werr != unix.EAGAIN
+		})
+		if err != nil && werr == nil {
+			werr = err
+		}
+		if n > 0 {
+			written += int64(n)
+			pending = advanceVectors(pending, n)
+		}
+		if werr == unix.EINTR {
+			werr = nil
+			continue
+		}
+		if n == 0 && werr == nil {
+			werr = io.ErrShortWrite
+		}
+	}
+
+	return written, werr
+}